@@ -10,7 +10,6 @@ import (
 
 	"github.com/mbeka02/ticketing-service/config"
 	"github.com/mbeka02/ticketing-service/internal/api"
-	"github.com/mbeka02/ticketing-service/internal/auth"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -48,9 +47,6 @@ func setupServer() (*http.Server, error) {
 		return nil, fmt.Errorf("failed to setup server: %w", err)
 	}
 
-	callbackURL := fmt.Sprintf("%s/api/v1/auth/google/callback", cfg.BaseURL)
-	auth.NewAuth(callbackURL, cfg.IsProduction())
-
 	logger.Info("completed server setup",
 		zap.String("port", cfg.ServerPort),
 		zap.String("env", cfg.ServerEnv),
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -19,15 +21,102 @@ type Config struct {
 	ServerIdleTimeout  time.Duration `mapstructure:"SERVER_IDLETIMEOUT"`
 
 	// Auth config
+	// TokenMaker selects how tokens are issued: jwt-hs256 or paseto-v4-local with
+	// SymmetricKey, jwt-asymmetric with the rotating keys listed in TokenKeysFile, or
+	// paseto-v4-public with the Ed25519 key in PasetoKeyFile. For paseto-v4-local,
+	// SymmetricKey is a 32-byte key encoded as hex or base64
+	TokenMaker           string        `mapstructure:"TOKEN_MAKER"`
+	TokenKeysFile        string        `mapstructure:"TOKEN_KEYS_FILE"`
+	PasetoKeyFile        string        `mapstructure:"PASETO_KEY_FILE"`
 	SymmetricKey         string        `mapstructure:"SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	// UserCacheTTL bounds how long a role change or deleted account takes to apply to
+	// signed-in users
+	UserCacheTTL time.Duration `mapstructure:"USER_CACHE_TTL"`
 
 	// Database config
 	DatabaseURI             string        `mapstructure:"DATABASE_URI"`
 	DatabaseMaxConnections  int           `mapstructure:"DATABASE_MAXCONNECTIONS"`
 	DatabaseMinConnections  int           `mapstructure:"DATABASE_MINCONNECTIONS"`
 	DatabaseMaxConnLifetime time.Duration `mapstructure:"DATABASE_MAXCONNLIFETIME"`
+
+	// Media storage config
+	MediaStorage           string `mapstructure:"MEDIA_STORAGE"`
+	MediaLocalDir          string `mapstructure:"MEDIA_LOCAL_DIR"`
+	MediaS3Endpoint        string `mapstructure:"MEDIA_S3_ENDPOINT"`
+	MediaS3Region          string `mapstructure:"MEDIA_S3_REGION"`
+	MediaS3Bucket          string `mapstructure:"MEDIA_S3_BUCKET"`
+	MediaS3AccessKeyID     string `mapstructure:"MEDIA_S3_ACCESS_KEY_ID"`
+	MediaS3SecretAccessKey string `mapstructure:"MEDIA_S3_SECRET_ACCESS_KEY"`
+
+	// Catalogue provider config
+	CatalogProvider    string `mapstructure:"CATALOG_PROVIDER"`
+	CatalogFixturePath string `mapstructure:"CATALOG_FIXTURE_PATH"`
+	TMDBAPIKey         string `mapstructure:"TMDB_API_KEY"`
+	TMDBRegion         string `mapstructure:"TMDB_REGION"`
+
+	// AgeRatingRegion selects the age rating scheme movies are classified with
+	AgeRatingRegion string `mapstructure:"AGE_RATING_REGION"`
+
+	// RecommendationsRefreshHour is the hour of the day (UTC) recommendation scores are recomputed
+	RecommendationsRefreshHour int `mapstructure:"RECOMMENDATIONS_REFRESH_HOUR"`
+
+	// TrendingRefreshInterval is how often trending rankings are recomputed
+	TrendingRefreshInterval time.Duration `mapstructure:"TRENDING_REFRESH_INTERVAL"`
+
+	// Mail config
+	Mailer       string `mapstructure:"MAILER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailFileDir  string `mapstructure:"MAIL_FILE_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+
+	// Email verification config
+	EmailVerificationTTL            time.Duration `mapstructure:"EMAIL_VERIFICATION_TTL"`
+	EmailVerificationResendInterval time.Duration `mapstructure:"EMAIL_VERIFICATION_RESEND_INTERVAL"`
+	RequireEmailVerification        bool          `mapstructure:"REQUIRE_EMAIL_VERIFICATION"`
+
+	// PasswordResetTTL is how long a password reset link works for
+	PasswordResetTTL time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+
+	// Two-factor authentication config
+	// MFAEncryptionKey encrypts TOTP secrets at rest; changing it disables every enrolment.
+	// Leaving it unset turns off authenticator apps, which REQUIRE_ADMIN_MFA needs
+	MFAEncryptionKey string `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAIssuer        string `mapstructure:"MFA_ISSUER"`
+	// RequireAdminMFA keeps admins out of admin routes until they enable two-factor
+	// authentication
+	RequireAdminMFA bool `mapstructure:"REQUIRE_ADMIN_MFA"`
+
+	// Passkey config; the relying party ID and origins default to FRONTEND_URL's
+	WebAuthnRPName string `mapstructure:"WEBAUTHN_RP_NAME"`
+	WebAuthnRPID   string `mapstructure:"WEBAUTHN_RP_ID"`
+	// WebAuthnRPOrigins is a comma-separated list of origins passkeys may be used from
+	WebAuthnRPOrigins string `mapstructure:"WEBAUTHN_RP_ORIGINS"`
+
+	// Social sign in config
+	// OAuthProviders is a comma-separated list of the providers to offer: google, github,
+	// apple, microsoft and oidc, a generic OpenID Connect provider found through
+	// OIDCDiscoveryURL and served under OIDCName
+	OAuthProviders     string `mapstructure:"OAUTH_PROVIDERS"`
+	SessionSecret      string `mapstructure:"SESSION_SECRET"`
+	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GitHubClientID     string `mapstructure:"GITHUB_CLIENT_ID"`
+	GitHubClientSecret string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	AppleClientID      string `mapstructure:"APPLE_CLIENT_ID"`
+	// AppleClientSecret is the JWT client secret generated from the Sign in with Apple key
+	AppleClientSecret     string `mapstructure:"APPLE_CLIENT_SECRET"`
+	MicrosoftClientID     string `mapstructure:"MICROSOFT_CLIENT_ID"`
+	MicrosoftClientSecret string `mapstructure:"MICROSOFT_CLIENT_SECRET"`
+	OIDCName              string `mapstructure:"OIDC_NAME"`
+	OIDCDisplayName       string `mapstructure:"OIDC_DISPLAY_NAME"`
+	OIDCDiscoveryURL      string `mapstructure:"OIDC_DISCOVERY_URL"`
+	OIDCClientID          string `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret      string `mapstructure:"OIDC_CLIENT_SECRET"`
 }
 
 type DatabaseConfig struct {
@@ -98,11 +187,61 @@ func bindEnvVars(v *viper.Viper) {
 		"DATABASE_MAXCONNECTIONS",
 		"DATABASE_MINCONNECTIONS",
 		"DATABASE_MAXCONNLIFETIME",
+		"TOKEN_MAKER",
+		"TOKEN_KEYS_FILE",
+		"PASETO_KEY_FILE",
 		"SYMMETRIC_KEY",
 		"ACCESS_TOKEN_DURATION",
 		"REFRESH_TOKEN_DURATION",
+		"USER_CACHE_TTL",
 		"BASE_URL",
 		"FRONTEND_URL",
+		"MEDIA_STORAGE",
+		"MEDIA_LOCAL_DIR",
+		"MEDIA_S3_ENDPOINT",
+		"MEDIA_S3_REGION",
+		"MEDIA_S3_BUCKET",
+		"MEDIA_S3_ACCESS_KEY_ID",
+		"MEDIA_S3_SECRET_ACCESS_KEY",
+		"CATALOG_PROVIDER",
+		"CATALOG_FIXTURE_PATH",
+		"TMDB_API_KEY",
+		"TMDB_REGION",
+		"AGE_RATING_REGION",
+		"RECOMMENDATIONS_REFRESH_HOUR",
+		"TRENDING_REFRESH_INTERVAL",
+		"MAILER",
+		"MAIL_FROM",
+		"MAIL_FILE_DIR",
+		"SMTP_HOST",
+		"SMTP_PORT",
+		"SMTP_USERNAME",
+		"SMTP_PASSWORD",
+		"EMAIL_VERIFICATION_TTL",
+		"EMAIL_VERIFICATION_RESEND_INTERVAL",
+		"REQUIRE_EMAIL_VERIFICATION",
+		"PASSWORD_RESET_TTL",
+		"MFA_ENCRYPTION_KEY",
+		"MFA_ISSUER",
+		"REQUIRE_ADMIN_MFA",
+		"WEBAUTHN_RP_NAME",
+		"WEBAUTHN_RP_ID",
+		"WEBAUTHN_RP_ORIGINS",
+		"OAUTH_PROVIDERS",
+		"SESSION_SECRET",
+		"GOOGLE_CLIENT_ID",
+		"GOOGLE_CLIENT_SECRET",
+		"GITHUB_CLIENT_ID",
+		"GITHUB_CLIENT_SECRET",
+		"APPLE_CLIENT_ID",
+		"APPLE_CLIENT_SECRET",
+		"MICROSOFT_CLIENT_ID",
+		"MICROSOFT_CLIENT_SECRET",
+		"OIDC_NAME",
+		"OIDC_DISPLAY_NAME",
+		"OIDC_DISCOVERY_URL",
+		"OIDC_CLIENT_ID",
+		"OIDC_CLIENT_SECRET",
 	}
 
 	for _, envVar := range envVars {
@@ -127,8 +266,50 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("DATABASE_MAXCONNLIFETIME", 30*time.Minute)
 
 	// Auth defaults
+	v.SetDefault("TOKEN_MAKER", "jwt-hs256")
 	v.SetDefault("ACCESS_TOKEN_DURATION", 15*time.Minute)
 	v.SetDefault("REFRESH_TOKEN_DURATION", 7*24*time.Hour)
+	v.SetDefault("USER_CACHE_TTL", 30*time.Second)
+
+	// Media defaults
+	v.SetDefault("MEDIA_STORAGE", "local")
+	v.SetDefault("MEDIA_LOCAL_DIR", "./uploads")
+	v.SetDefault("MEDIA_S3_REGION", "us-east-1")
+
+	// Catalogue defaults
+	v.SetDefault("CATALOG_PROVIDER", "fixture")
+	v.SetDefault("TMDB_REGION", "US")
+
+	// Age rating defaults
+	v.SetDefault("AGE_RATING_REGION", "US")
+
+	// Recommendation defaults
+	v.SetDefault("RECOMMENDATIONS_REFRESH_HOUR", 3)
+	v.SetDefault("TRENDING_REFRESH_INTERVAL", 10*time.Minute)
+
+	// Mail defaults
+	v.SetDefault("MAILER", "file")
+	v.SetDefault("MAIL_FROM", "Mobo <no-reply@localhost>")
+	v.SetDefault("MAIL_FILE_DIR", "./mail")
+	v.SetDefault("SMTP_PORT", 587)
+
+	// Email verification defaults
+	v.SetDefault("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+	v.SetDefault("EMAIL_VERIFICATION_RESEND_INTERVAL", time.Minute)
+	v.SetDefault("REQUIRE_EMAIL_VERIFICATION", false)
+	v.SetDefault("PASSWORD_RESET_TTL", time.Hour)
+
+	// Two-factor authentication defaults
+	v.SetDefault("MFA_ISSUER", "Mobo")
+	v.SetDefault("REQUIRE_ADMIN_MFA", false)
+
+	// Passkey defaults
+	v.SetDefault("WEBAUTHN_RP_NAME", "Mobo")
+
+	// Social sign in defaults
+	v.SetDefault("OAUTH_PROVIDERS", "google")
+	v.SetDefault("OIDC_NAME", "oidc")
+	v.SetDefault("OIDC_DISPLAY_NAME", "Single sign-on")
 }
 
 func (c *Config) Validate() error {
@@ -137,8 +318,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DATABASE_URI is required but not set")
 	}
 
-	if len(c.SymmetricKey) < 32 {
-		return fmt.Errorf("SYMMETRIC_KEY must be at least 32 characters")
+	switch c.TokenMaker {
+	case "jwt-hs256":
+		if len(c.SymmetricKey) < 32 {
+			return fmt.Errorf("SYMMETRIC_KEY must be at least 32 characters")
+		}
+	case "jwt-asymmetric":
+		if c.TokenKeysFile == "" {
+			return fmt.Errorf("TOKEN_KEYS_FILE is required when TOKEN_MAKER is jwt-asymmetric")
+		}
+	case "paseto-v4-local":
+		// The token maker checks that the key decodes to 32 bytes
+		if c.SymmetricKey == "" {
+			return fmt.Errorf("SYMMETRIC_KEY is required when TOKEN_MAKER is paseto-v4-local; set it to a 32-byte key encoded as hex or base64")
+		}
+	case "paseto-v4-public":
+		if c.PasetoKeyFile == "" {
+			return fmt.Errorf("PASETO_KEY_FILE is required when TOKEN_MAKER is paseto-v4-public")
+		}
+	default:
+		return fmt.Errorf("TOKEN_MAKER must be one of: jwt-hs256, jwt-asymmetric, paseto-v4-local, paseto-v4-public")
 	}
 
 	// Constraints
@@ -150,15 +349,126 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DATABASE_MAXCONNECTIONS must be at least 1")
 	}
 
+	if c.UserCacheTTL < 0 || c.UserCacheTTL > c.AccessTokenDuration {
+		return fmt.Errorf("USER_CACHE_TTL must be between 0 and ACCESS_TOKEN_DURATION")
+	}
+
 	// Validate environment
 	validEnvs := map[string]bool{"development": true, "staging": true, "production": true}
 	if !validEnvs[c.ServerEnv] {
 		return fmt.Errorf("SERVER_ENV must be one of: development, staging, production")
 	}
 
+	switch c.MediaStorage {
+	case "local":
+	case "s3":
+		if c.MediaS3Endpoint == "" || c.MediaS3Bucket == "" || c.MediaS3AccessKeyID == "" || c.MediaS3SecretAccessKey == "" {
+			return fmt.Errorf("MEDIA_S3_ENDPOINT, MEDIA_S3_BUCKET, MEDIA_S3_ACCESS_KEY_ID and MEDIA_S3_SECRET_ACCESS_KEY are required when MEDIA_STORAGE is s3")
+		}
+	default:
+		return fmt.Errorf("MEDIA_STORAGE must be one of: local, s3")
+	}
+
+	switch c.CatalogProvider {
+	case "fixture":
+	case "tmdb":
+		if c.TMDBAPIKey == "" {
+			return fmt.Errorf("TMDB_API_KEY is required when CATALOG_PROVIDER is tmdb")
+		}
+	default:
+		return fmt.Errorf("CATALOG_PROVIDER must be one of: fixture, tmdb")
+	}
+
+	if c.RecommendationsRefreshHour < 0 || c.RecommendationsRefreshHour > 23 {
+		return fmt.Errorf("RECOMMENDATIONS_REFRESH_HOUR must be between 0 and 23")
+	}
+
+	if c.TrendingRefreshInterval < time.Minute {
+		return fmt.Errorf("TRENDING_REFRESH_INTERVAL must be at least 1m")
+	}
+
+	switch c.Mailer {
+	case "memory", "file":
+	case "smtp":
+		if c.SMTPHost == "" {
+			return fmt.Errorf("SMTP_HOST is required when MAILER is smtp")
+		}
+	default:
+		return fmt.Errorf("MAILER must be one of: memory, file, smtp")
+	}
+
+	if c.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("EMAIL_VERIFICATION_TTL must be positive")
+	}
+
+	if c.PasswordResetTTL <= 0 {
+		return fmt.Errorf("PASSWORD_RESET_TTL must be positive")
+	}
+
+	if c.MFAEncryptionKey != "" && len(c.MFAEncryptionKey) < 32 {
+		return fmt.Errorf("MFA_ENCRYPTION_KEY must be at least 32 characters")
+	}
+	if c.RequireAdminMFA && c.MFAEncryptionKey == "" {
+		return fmt.Errorf("MFA_ENCRYPTION_KEY is required when REQUIRE_ADMIN_MFA is set")
+	}
+
+	if c.WebAuthnRPName == "" {
+		return fmt.Errorf("WEBAUTHN_RP_NAME is required")
+	}
+
+	providers := c.EnabledOAuthProviders()
+	if len(providers) > 0 && c.SessionSecret == "" {
+		return fmt.Errorf("SESSION_SECRET is required when OAUTH_PROVIDERS is set")
+	}
+	for _, provider := range providers {
+		var id, secret string
+		switch provider {
+		case "google":
+			id, secret = c.GoogleClientID, c.GoogleClientSecret
+		case "github":
+			id, secret = c.GitHubClientID, c.GitHubClientSecret
+		case "apple":
+			id, secret = c.AppleClientID, c.AppleClientSecret
+		case "microsoft":
+			id, secret = c.MicrosoftClientID, c.MicrosoftClientSecret
+		case "oidc":
+			id, secret = c.OIDCClientID, c.OIDCClientSecret
+			if c.OIDCDiscoveryURL == "" {
+				return fmt.Errorf("OIDC_DISCOVERY_URL is required when OAUTH_PROVIDERS includes oidc")
+			}
+			if c.OIDCName == "" || slices.Contains(reservedOIDCNames, c.OIDCName) {
+				return fmt.Errorf("OIDC_NAME must be set and must not be one of: %s", strings.Join(reservedOIDCNames, ", "))
+			}
+		default:
+			return fmt.Errorf("OAUTH_PROVIDERS must only list: google, github, apple, microsoft, oidc")
+		}
+		if id == "" || secret == "" {
+			upper := strings.ToUpper(provider)
+			return fmt.Errorf("%s_CLIENT_ID and %s_CLIENT_SECRET are required when OAUTH_PROVIDERS includes %s", upper, upper, provider)
+		}
+	}
+
 	return nil
 }
 
+// reservedOIDCNames are the other providers and the static /auth/* routes, which would
+// shadow an OIDC provider served under the same name.
+var reservedOIDCNames = []string{
+	"google", "github", "apple", "microsoft",
+	"providers", "login", "signup", "logout", "refresh", "verify", "password", "mfa", "passkey",
+}
+
+// EnabledOAuthProviders returns the providers listed in OAUTH_PROVIDERS.
+func (c *Config) EnabledOAuthProviders() []string {
+	var providers []string
+	for _, p := range strings.Split(c.OAuthProviders, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" && !slices.Contains(providers, p) {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
 // IsProduction returns true if running in production
 func (c *Config) IsProduction() bool {
 	return c.ServerEnv == "production"
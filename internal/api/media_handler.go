@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/mbeka02/ticketing-service/internal/media"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MediaHandler handles HTTP requests for the media domain.
+type MediaHandler struct {
+	svc media.Service
+}
+
+// NewMediaHandler creates a new MediaHandler.
+func NewMediaHandler(svc media.Service) *MediaHandler {
+	return &MediaHandler{svc: svc}
+}
+
+// UploadMediaHandler accepts a multipart form with an image in the "file" field and
+// its kind (poster, backdrop or still) in the "kind" field.
+func (h *MediaHandler) UploadMediaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	movieId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Leave room for the multipart envelope and the other form fields.
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxUploadSize+(1<<20))
+	if err := r.ParseMultipartForm(media.MaxUploadSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("uploads are limited to %d MB", media.MaxUploadSize>>20))
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, errors.New("a file is required"))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > media.MaxUploadSize {
+		respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("uploads are limited to %d MB", media.MaxUploadSize>>20))
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.svc.Upload(ctx, media.UploadRequest{
+		MovieID:  movieId,
+		Kind:     r.FormValue("kind"),
+		Filename: header.Filename,
+		Data:     data,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, movie.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, media.ErrInvalidKind),
+			errors.Is(err, media.ErrImageTooSmall):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, media.ErrUnsupportedFormat):
+			respondWithError(w, http.StatusUnsupportedMediaType, err)
+		case errors.Is(err, media.ErrImageTooLarge):
+			respondWithError(w, http.StatusRequestEntityTooLarge, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to upload media", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "media uploaded successfully",
+		Data:    asset.ToResponse(),
+	})
+}
+
+func (h *MediaHandler) ListMovieMediaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	movieId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	assets, err := h.svc.ListAssets(ctx, movieId)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list media", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]media.AssetResponse, 0, len(assets))
+	for _, a := range assets {
+		res = append(res, a.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *MediaHandler) DeleteMediaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "assetId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.DeleteAsset(ctx, id); err != nil {
+		if errors.Is(err, media.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete media", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "media deleted successfully",
+	})
+}
+
+// ServeMediaHandler serves a variant image. A variant never changes once stored, so it
+// can be cached indefinitely.
+func (h *MediaHandler) ServeMediaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "assetId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := chi.URLParam(r, "variant")
+
+	// The ETag is derived from the URL alone, so a revalidation never touches storage.
+	etag := fmt.Sprintf(`"%d-%s"`, id, name)
+	setCacheHeaders := func() {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", etag)
+	}
+	if r.Header.Get("If-None-Match") == etag {
+		setCacheHeaders()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	_, data, err := h.svc.GetVariant(ctx, id, name)
+	if err != nil {
+		if errors.Is(err, media.ErrVariantNotFound) || errors.Is(err, media.ErrObjectNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to serve media", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	setCacheHeaders()
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if err := respondWithImage(w, data); err != nil {
+		logger.WarnCtx(ctx, "failed to write media response", zap.Error(err))
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MFAHandler handles HTTP requests for the mfa domain.
+type MFAHandler struct {
+	svc mfa.Service
+}
+
+// NewMFAHandler creates a new MFAHandler.
+func NewMFAHandler(svc mfa.Service) *MFAHandler {
+	return &MFAHandler{svc: svc}
+}
+
+// GetMFAStatusHandler describes the current user's two-factor authentication.
+func (h *MFAHandler) GetMFAStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	role, _ := middleware.RoleFromContext(ctx)
+
+	status, err := h.svc.Status(ctx, userID, role)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to get two-factor status", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    status,
+	})
+}
+
+// BeginTOTPHandler starts enrolling an authenticator app. Calling it again before
+// confirming replaces the secret.
+func (h *MFAHandler) BeginTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	email, _ := ctx.Value(middleware.EmailKey).(string)
+
+	enrolment, err := h.svc.BeginTOTP(ctx, userID, email)
+	if err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrAlreadyEnrolled):
+			respondWithError(w, http.StatusConflict, err)
+			return
+		case errors.Is(err, mfa.ErrUnavailable):
+			respondWithError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to begin TOTP enrolment", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "scan the QR code with your authenticator app, then confirm with a code",
+		Data:    enrolment,
+	})
+}
+
+// ConfirmTOTPHandler enables the authenticator with a code from it and returns the
+// recovery codes, which are not shown again.
+func (h *MFAHandler) ConfirmTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req mfa.CodeRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	codes, err := h.svc.ConfirmTOTP(ctx, userID, req.Code)
+	if err != nil {
+		h.respondWithCodeError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "two-factor authentication enabled, store your recovery codes somewhere safe",
+		Data:    mfa.RecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+// DisableTOTPHandler removes the current user's authenticator after checking a code.
+func (h *MFAHandler) DisableTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	role, _ := middleware.RoleFromContext(ctx)
+
+	var req mfa.CodeRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.DisableTOTP(ctx, userID, role, req.Code); err != nil {
+		h.respondWithCodeError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodesHandler replaces the current user's recovery codes after checking
+// a code. The old codes stop working.
+func (h *MFAHandler) RegenerateRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req mfa.CodeRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	codes, err := h.svc.RegenerateRecoveryCodes(ctx, userID, req.Code)
+	if err != nil {
+		h.respondWithCodeError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "recovery codes regenerated",
+		Data:    mfa.RecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+// respondWithCodeError maps the errors of operations that check a code.
+func (h *MFAHandler) respondWithCodeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, mfa.ErrInvalidCode):
+		respondWithError(w, http.StatusBadRequest, err)
+	case errors.Is(err, mfa.ErrLocked):
+		respondWithError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, mfa.ErrNotEnrolled):
+		respondWithError(w, http.StatusNotFound, err)
+	case errors.Is(err, mfa.ErrAlreadyEnrolled), errors.Is(err, mfa.ErrRequired):
+		respondWithError(w, http.StatusConflict, err)
+	case errors.Is(err, mfa.ErrUnavailable):
+		respondWithError(w, http.StatusServiceUnavailable, err)
+	default:
+		logger.ErrorCtx(r.Context(), "two-factor operation failed", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+	}
+}
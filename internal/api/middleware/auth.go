@@ -3,11 +3,14 @@ package middleware
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mbeka02/ticketing-service/internal/auth"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+	"github.com/mbeka02/ticketing-service/internal/session"
 	"github.com/mbeka02/ticketing-service/internal/user"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
@@ -16,18 +19,27 @@ import (
 type contextKey string
 
 const (
-	UserIDKey contextKey = "user_id"
-	EmailKey  contextKey = "email"
-	RoleKey   contextKey = "role"
+	UserIDKey    contextKey = "user_id"
+	SessionIDKey contextKey = "session_id"
+	EmailKey     contextKey = "email"
+	RoleKey      contextKey = "role"
 )
 
-func AuthMiddleware(maker auth.Maker, isProd bool, accessDuration, refreshDuration time.Duration) func(http.Handler) http.Handler {
+// UserLoader returns a user by ID, or user.ErrNotFound if they no longer exist.
+type UserLoader interface {
+	Get(ctx context.Context, id uuid.UUID) (*user.User, error)
+}
+
+// AuthMiddleware authenticates requests with the access token cookie, rotating both cookies
+// with the refresh token once the access token expires. Refreshing goes through the session
+// store, so a revoked session stops working when its access token expires. The user's
+// email and role are read through users on every request instead of being taken from the
+// token, so deleting or demoting a user takes effect as soon as users sees the change.
+func AuthMiddleware(maker auth.Maker, sessions session.Service, users UserLoader, isProd bool, accessDuration, refreshDuration time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
-			var userID uuid.UUID
-			var email string
-			var role string
+			var userID, sessionID uuid.UUID
 			var found bool
 
 			// 1. Try access token cookie
@@ -41,8 +53,7 @@ func AuthMiddleware(maker auth.Maker, isProd bool, accessDuration, refreshDurati
 						return
 					}
 					userID = claims.UserID
-					email = claims.Email
-					role = claims.Role
+					sessionID = claims.SessionID
 					found = true
 					logger.DebugCtx(ctx, "authenticated via access token",
 						zap.String("user_id", userID.String()),
@@ -59,18 +70,39 @@ func AuthMiddleware(maker auth.Maker, isProd bool, accessDuration, refreshDurati
 								http.Error(w, "unauthorized", http.StatusUnauthorized)
 								return
 							}
-							// Silently rotate both cookies
-							if err := auth.SetTokenCookies(w, maker, refreshClaims.UserID, refreshClaims.Email, refreshClaims.Role, isProd, accessDuration, refreshDuration); err != nil {
-								logger.ErrorCtx(ctx, "failed to rotate token cookies", zap.Error(err))
+							u, err := users.Get(ctx, refreshClaims.UserID)
+							if err != nil {
+								rejectUser(w, r, refreshClaims.UserID, err)
+								return
+							}
+							tokens, err := auth.NewTokenPair(maker, u.ID, refreshClaims.SessionID, u.Email, u.Role, accessDuration, refreshDuration)
+							if err != nil {
+								logger.ErrorCtx(ctx, "failed to create tokens", zap.Error(err))
 								http.Error(w, "unauthorized", http.StatusUnauthorized)
 								return
 							}
+							rotated, err := sessions.Refresh(ctx, refreshClaims.SessionID, refresh.Value, tokens.Refresh, ClientFromRequest(r), time.Now().Add(refreshDuration))
+							if err != nil {
+								logger.WarnCtx(ctx, "refresh token rejected",
+									zap.Error(err),
+									zap.String("user_id", refreshClaims.UserID.String()),
+									zap.String("session_id", refreshClaims.SessionID.String()),
+								)
+								auth.ClearTokenCookies(w)
+								http.Error(w, "unauthorized", http.StatusUnauthorized)
+								return
+							}
+							// A concurrent request already rotated the session and set the
+							// new cookies, so this one only needs authenticating.
+							if rotated {
+								auth.SetTokenCookies(w, tokens, isProd, accessDuration, refreshDuration)
+							}
 							userID = refreshClaims.UserID
-							email = refreshClaims.Email
-							role = refreshClaims.Role
+							sessionID = refreshClaims.SessionID
 							found = true
 							logger.InfoCtx(ctx, "token rotation completed",
 								zap.String("user_id", userID.String()),
+								zap.Bool("rotated", rotated),
 							)
 						} else {
 							logger.WarnCtx(ctx, "refresh token verification failed", zap.Error(err))
@@ -89,14 +121,37 @@ func AuthMiddleware(maker auth.Maker, isProd bool, accessDuration, refreshDurati
 				return
 			}
 
+			u, err := users.Get(ctx, userID)
+			if err != nil {
+				rejectUser(w, r, userID, err)
+				return
+			}
+
 			ctx = context.WithValue(ctx, UserIDKey, userID)
-			ctx = context.WithValue(ctx, EmailKey, email)
-			ctx = context.WithValue(ctx, RoleKey, role)
+			ctx = context.WithValue(ctx, SessionIDKey, sessionID)
+			ctx = context.WithValue(ctx, EmailKey, u.Email)
+			ctx = context.WithValue(ctx, RoleKey, u.Role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// rejectUser responds to a request whose user could not be loaded. Users who no longer
+// exist are signed out; other failures are server errors.
+func rejectUser(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	ctx := r.Context()
+	if errors.Is(err, user.ErrNotFound) {
+		logger.WarnCtx(ctx, "authentication failed: user no longer exists",
+			zap.String("user_id", userID.String()),
+		)
+		auth.ClearTokenCookies(w)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	logger.ErrorCtx(ctx, "failed to load authenticated user", zap.Error(err))
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
 func AdminMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		role, ok := RoleFromContext(r.Context())
@@ -108,12 +163,56 @@ func AdminMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// MFAPolicy decides whether a user may use their role's privileges without two-factor
+// authentication.
+type MFAPolicy interface {
+	CheckPolicy(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+// MFAPolicyMiddleware refuses requests from users whose role requires two-factor
+// authentication until they enable it. It runs after AuthMiddleware.
+func MFAPolicyMiddleware(policy MFAPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			userID, _ := UserIDFromContext(ctx)
+			role, _ := RoleFromContext(ctx)
+
+			if err := policy.CheckPolicy(ctx, userID, role); err != nil {
+				if errors.Is(err, mfa.ErrRequired) {
+					http.Error(w, "forbidden: enable two-factor authentication to continue", http.StatusForbidden)
+					return
+				}
+				logger.ErrorCtx(ctx, "failed to check two-factor policy", zap.Error(err))
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Helper for handlers to pull the user ID back out
 func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	id, ok := ctx.Value(UserIDKey).(uuid.UUID)
 	return id, ok
 }
 
+// Helper for handlers to pull the session ID back out
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(SessionIDKey).(uuid.UUID)
+	return id, ok
+}
+
+// ClientFromRequest describes the client a request came from, for recording sessions.
+func ClientFromRequest(r *http.Request) session.Client {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	return session.Client{IPAddress: ip, UserAgent: r.UserAgent()}
+}
+
 // Helper for handlers to pull the role back out
 func RoleFromContext(ctx context.Context) (string, bool) {
 	role, ok := ctx.Value(RoleKey).(string)
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi"
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
 	"github.com/mbeka02/ticketing-service/internal/movie"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
@@ -30,6 +32,14 @@ func (h *MovieHandler) AddMovieHandler(w http.ResponseWriter, r *http.Request) {
 
 	m, err := h.svc.AddMovie(ctx, req)
 	if err != nil {
+		if errors.Is(err, movie.ErrAlreadyImported) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, movie.ErrUnknownAgeRating) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to add movie", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -87,12 +97,28 @@ func (h *MovieHandler) ListMoviesAdminHandler(w http.ResponseWriter, r *http.Req
 	})
 }
 
+// ListMoviesPublicHandler lists movies that are currently showing. When any of q, genre,
+// age_rating, release_year or status is given it searches the whole catalogue instead and
+// returns the results together with facets. sort=rating orders either by average rating.
 func (h *MovieHandler) ListMoviesPublicHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	limit, offset := parsePagination(r)
+	filter, err := parseMovieSearchFilter(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !filter.IsEmpty() {
+		h.searchMovies(w, r, filter)
+		return
+	}
+	limit, offset := filter.Limit, filter.Offset
 
-	movies, err := h.svc.ListMoviesPublic(ctx, limit, offset)
+	movies, err := h.svc.ListMoviesPublic(ctx, limit, offset, filter.Sort)
 	if err != nil {
+		if errors.Is(err, movie.ErrInvalidSort) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to list public movies", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -127,6 +153,18 @@ func (h *MovieHandler) UpdateMovieHandler(w http.ResponseWriter, r *http.Request
 
 	m, err := h.svc.UpdateMovie(ctx, id, req)
 	if err != nil {
+		if errors.Is(err, movie.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, movie.ErrAlreadyImported) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, movie.ErrUnknownAgeRating) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to update movie", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -139,6 +177,7 @@ func (h *MovieHandler) UpdateMovieHandler(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// DeleteMovieHandler soft-deletes a movie. Pass ?cascade=true to also cancel its future showtimes.
 func (h *MovieHandler) DeleteMovieHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "movieId")
@@ -148,7 +187,17 @@ func (h *MovieHandler) DeleteMovieHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := h.svc.DeleteMovie(ctx, id); err != nil {
+	cascade := NewQueryParamExtractor(r).GetBool("cascade", false)
+
+	if err := h.svc.DeleteMovie(ctx, id, cascade); err != nil {
+		if errors.Is(err, movie.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, movie.ErrShowtimesScheduled) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to delete movie", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -159,3 +208,570 @@ func (h *MovieHandler) DeleteMovieHandler(w http.ResponseWriter, r *http.Request
 		Message: "movie deleted successfully",
 	})
 }
+
+// ListDeletedMoviesHandler lists movies in the trash, most recently deleted first.
+func (h *MovieHandler) ListDeletedMoviesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset := parsePagination(r)
+
+	movies, err := h.svc.ListDeletedMovies(ctx, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list deleted movies", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]movie.MovieResponse, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, m.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RestoreMovieHandler takes a movie out of the trash, together with the future showtimes
+// deleted with it. Reservations cancelled by the delete stay cancelled.
+func (h *MovieHandler) RestoreMovieHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	m, err := h.svc.RestoreMovie(ctx, id)
+	if err != nil {
+		if errors.Is(err, movie.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, movie.ErrAlreadyImported) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to restore movie", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "movie restored successfully",
+		Data:    m.ToResponse(),
+	})
+}
+
+// ListMoviesComingSoonHandler lists announced and coming-soon movies, soonest release first.
+func (h *MovieHandler) ListMoviesComingSoonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset := parsePagination(r)
+
+	movies, err := h.svc.ListMoviesComingSoon(ctx, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list coming-soon movies", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]movie.MovieResponse, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, m.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RegisterInterestHandler asks to notify the current user when tickets for an upcoming movie go on sale.
+func (h *MovieHandler) RegisterInterestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.RegisterInterest(ctx, userID, id); err != nil {
+		switch {
+		case errors.Is(err, movie.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, movie.ErrNotUpcoming):
+			respondWithError(w, http.StatusConflict, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to register movie interest", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "you will be notified when tickets go on sale",
+	})
+}
+
+func (h *MovieHandler) WithdrawInterestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.WithdrawInterest(ctx, userID, id); err != nil {
+		if errors.Is(err, movie.ErrInterestNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to withdraw movie interest", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "interest withdrawn",
+	})
+}
+
+func (h *MovieHandler) searchMovies(w http.ResponseWriter, r *http.Request, filter movie.SearchFilter) {
+	ctx := r.Context()
+
+	result, err := h.svc.SearchMovies(ctx, filter)
+	if err != nil {
+		if errors.Is(err, movie.ErrInvalidStatus) || errors.Is(err, movie.ErrInvalidSort) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to search movies", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    result.ToResponse(),
+	})
+}
+
+func parseMovieSearchFilter(r *http.Request) (movie.SearchFilter, error) {
+	extractor := NewQueryParamExtractor(r)
+	limit, offset := parsePagination(r)
+	filter := movie.SearchFilter{
+		Query:     extractor.GetOptionalString("q"),
+		Genre:     extractor.GetOptionalString("genre"),
+		AgeRating: extractor.GetOptionalString("age_rating"),
+		Status:    extractor.GetOptionalString("status"),
+		Sort:      extractor.GetOptionalString("sort"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	var err error
+	if filter.ReleaseYear, err = extractor.GetOptionalInt32("release_year"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func (h *MovieHandler) ListGenresHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	genres, err := h.svc.ListGenres(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list genres", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]movie.GenreResponse, 0, len(genres))
+	for _, g := range genres {
+		res = append(res, g.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *MovieHandler) CreateGenreHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req movie.CreateGenreRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	g, err := h.svc.CreateGenre(ctx, req)
+	if err != nil {
+		if errors.Is(err, movie.ErrGenreExists) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to create genre", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "genre created successfully",
+		Data:    g.ToResponse(),
+	})
+}
+
+func (h *MovieHandler) DeleteGenreHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "genreId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.DeleteGenre(ctx, int32(id)); err != nil {
+		if errors.Is(err, movie.ErrGenreNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete genre", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "genre deleted successfully",
+	})
+}
+
+func (h *MovieHandler) SetMovieGenresHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req movie.SetGenresRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	m, err := h.svc.SetMovieGenres(ctx, id, req)
+	if err != nil {
+		if errors.Is(err, movie.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, movie.ErrGenreNotFound) || errors.Is(err, movie.ErrDuplicateGenre) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to set movie genres", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "movie genres updated successfully",
+		Data:    m.ToResponse(),
+	})
+}
+
+func (h *MovieHandler) SetMovieCreditsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req movie.SetCreditsRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	m, err := h.svc.SetMovieCredits(ctx, id, req)
+	if err != nil {
+		if errors.Is(err, movie.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, movie.ErrPersonNotFound) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to set movie credits", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "movie credits updated successfully",
+		Data:    m.ToResponse(),
+	})
+}
+
+// GetPersonHandler returns a person and their filmography, e.g. for "more from this director".
+func (h *MovieHandler) GetPersonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "personId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := h.svc.GetPerson(ctx, id)
+	if err != nil {
+		if errors.Is(err, movie.ErrPersonNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to get person", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    p.ToResponse(),
+	})
+}
+
+func (h *MovieHandler) SearchPeopleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset := parsePagination(r)
+	query := NewQueryParamExtractor(r).GetOptionalString("q")
+
+	people, err := h.svc.SearchPeople(ctx, query, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to search people", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]movie.PersonResponse, 0, len(people))
+	for _, p := range people {
+		res = append(res, p.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *MovieHandler) CreatePersonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req movie.CreatePersonRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := h.svc.CreatePerson(ctx, req)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to create person", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "person created successfully",
+		Data:    p.ToResponse(),
+	})
+}
+
+func (h *MovieHandler) UpdatePersonHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "personId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req movie.UpdatePersonRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := h.svc.UpdatePerson(ctx, id, req)
+	if err != nil {
+		if errors.Is(err, movie.ErrPersonNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to update person", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "person updated successfully",
+		Data:    p.ToResponse(),
+	})
+}
+
+// SearchCatalogHandler searches the external catalogue by title.
+func (h *MovieHandler) SearchCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := NewQueryParamExtractor(r).GetOptionalString("q")
+	if query == nil {
+		respondWithError(w, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+
+	matches, err := h.svc.SearchCatalog(ctx, *query)
+	if err != nil {
+		if errors.Is(err, movie.ErrCatalogUnavailable) {
+			respondWithError(w, http.StatusBadGateway, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to search catalogue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    matches,
+	})
+}
+
+// PrepareImportHandler returns an add-movie request pre-filled from a catalogue entry,
+// for the admin to review and submit.
+func (h *MovieHandler) PrepareImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	externalID := chi.URLParam(r, "externalId")
+
+	req, err := h.svc.PrepareImport(ctx, externalID)
+	if err != nil {
+		switch {
+		case errors.Is(err, movie.ErrCatalogEntryNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, movie.ErrAlreadyImported):
+			respondWithError(w, http.StatusConflict, err)
+		case errors.Is(err, movie.ErrCatalogUnavailable):
+			respondWithError(w, http.StatusBadGateway, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to prepare movie import", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    req,
+	})
+}
+
+// CatalogDiffHandler shows how a movie differs from its catalogue entry. An external_id
+// query parameter links an unlinked movie to an entry.
+func (h *MovieHandler) CatalogDiffHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	diff, err := h.svc.DiffCatalog(ctx, id, NewQueryParamExtractor(r).GetOptionalString("external_id"))
+	if err != nil {
+		h.respondWithCatalogRefreshError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    diff,
+	})
+}
+
+// ApplyCatalogHandler refreshes a movie from its catalogue entry.
+func (h *MovieHandler) ApplyCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req movie.ApplyCatalogRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	m, err := h.svc.ApplyCatalog(ctx, id, req)
+	if err != nil {
+		h.respondWithCatalogRefreshError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "movie refreshed successfully",
+		Data:    m.ToResponse(),
+	})
+}
+
+func (h *MovieHandler) respondWithCatalogRefreshError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, movie.ErrNotFound), errors.Is(err, movie.ErrCatalogEntryNotFound):
+		respondWithError(w, http.StatusNotFound, err)
+	case errors.Is(err, movie.ErrNotLinked), errors.Is(err, movie.ErrUnknownAgeRating):
+		respondWithError(w, http.StatusBadRequest, err)
+	case errors.Is(err, movie.ErrAlreadyImported):
+		respondWithError(w, http.StatusConflict, err)
+	case errors.Is(err, movie.ErrCatalogUnavailable):
+		respondWithError(w, http.StatusBadGateway, err)
+	default:
+		logger.ErrorCtx(r.Context(), "failed to refresh movie from catalogue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/auth"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+	"github.com/mbeka02/ticketing-service/internal/passkey"
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// PasskeyHandler handles HTTP requests for managing the current user's passkeys. Signing
+// in with a passkey is handled by UserHandler.
+type PasskeyHandler struct {
+	svc          passkey.Service
+	isProduction bool
+}
+
+// NewPasskeyHandler creates a new PasskeyHandler.
+func NewPasskeyHandler(svc passkey.Service, isProduction bool) *PasskeyHandler {
+	return &PasskeyHandler{svc: svc, isProduction: isProduction}
+}
+
+// BeginRegistrationHandler returns the options for navigator.credentials.create and sets
+// the ceremony cookie that FinishRegistrationHandler reads. Users with two-factor
+// authentication enabled have to include a code.
+func (h *PasskeyHandler) BeginRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req passkey.BeginRegistrationRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ceremonyID, creation, err := h.svc.BeginRegistration(ctx, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, passkey.ErrCodeRequired):
+			respondWithError(w, http.StatusForbidden, err)
+		case errors.Is(err, mfa.ErrInvalidCode):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, mfa.ErrLocked):
+			respondWithError(w, http.StatusTooManyRequests, err)
+		case errors.Is(err, mfa.ErrUnavailable):
+			// Without the TOTP key only recovery codes can be checked.
+			respondWithError(w, http.StatusServiceUnavailable, errors.New("authenticator codes cannot be checked right now, use a recovery code"))
+		default:
+			logger.ErrorCtx(ctx, "failed to begin passkey registration", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	auth.SetWebAuthnCeremonyCookie(w, ceremonyID.String(), h.isProduction, passkey.CeremonyTTL)
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    creation,
+	})
+}
+
+// FinishRegistrationHandler stores the passkey from the browser's
+// navigator.credentials.create response, which is the request body.
+func (h *PasskeyHandler) FinishRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	ceremonyID, ok := ceremonyFromCookie(r)
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, passkey.ErrCeremonyExpired)
+		return
+	}
+	auth.ClearWebAuthnCeremonyCookie(w)
+
+	response, err := protocol.ParseCredentialCreationResponseBody(r.Body)
+	if err != nil {
+		logger.WarnCtx(ctx, "invalid passkey registration response", zap.Error(err))
+		respondWithError(w, http.StatusBadRequest, passkey.ErrVerificationFailed)
+		return
+	}
+
+	cred, err := h.svc.FinishRegistration(ctx, userID, ceremonyID, response)
+	if err != nil {
+		switch {
+		case errors.Is(err, passkey.ErrCeremonyExpired), errors.Is(err, passkey.ErrVerificationFailed):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, passkey.ErrAlreadyRegistered):
+			respondWithError(w, http.StatusConflict, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to finish passkey registration", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "passkey registered",
+		Data:    cred,
+	})
+}
+
+// ListPasskeysHandler lists the current user's passkeys.
+func (h *PasskeyHandler) ListPasskeysHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	creds, err := h.svc.List(ctx, userID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list passkeys", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    creds,
+	})
+}
+
+// DeletePasskeyHandler removes one of the current user's passkeys.
+func (h *PasskeyHandler) DeletePasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, chi.URLParam(r, "credentialId")); err != nil {
+		if errors.Is(err, passkey.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, user.ErrLastIdentity) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete passkey", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "passkey removed",
+	})
+}
+
+// ceremonyFromCookie returns the ID of the passkey ceremony the browser is answering.
+func ceremonyFromCookie(r *http.Request) (uuid.UUID, bool) {
+	cookie, err := r.Cookie(auth.WebAuthnCeremonyCookie)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(cookie.Value)
+	return id, err == nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/recommendation"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RecommendationHandler handles HTTP requests for the recommendation domain.
+type RecommendationHandler struct {
+	svc recommendation.Service
+}
+
+// NewRecommendationHandler creates a new RecommendationHandler.
+func NewRecommendationHandler(svc recommendation.Service) *RecommendationHandler {
+	return &RecommendationHandler{svc: svc}
+}
+
+// GetRecommendationsHandler lists movies now showing ranked for the current user. Pass
+// ?limit= for up to 50; customers without history get the most popular movies.
+func (h *RecommendationHandler) GetRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	limit := NewQueryParamExtractor(r).GetInt32("limit", 20)
+	if limit < 1 || limit > recommendation.MaxLimit {
+		limit = 20
+	}
+
+	recs, err := h.svc.Recommend(ctx, userID, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to get recommendations", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]recommendation.RecommendationResponse, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, rec.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RefreshRecommendationsHandler recomputes recommendation scores now rather than waiting
+// for the nightly run.
+func (h *RecommendationHandler) RefreshRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	n, err := h.svc.Refresh(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to refresh recommendations", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "recommendations refreshed successfully",
+		Data:    map[string]int{"scores": n},
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -124,6 +125,72 @@ func (q *QueryParamExtractor) GetBool(key string, defaultVal ...bool) bool {
 	return result
 }
 
+// GetOptionalString returns a pointer to a string parameter, or nil if it is absent
+func (q *QueryParamExtractor) GetOptionalString(key string) *string {
+	value := q.query.Get(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// GetOptionalInt32 returns a pointer to an int32 parameter, or nil if it is absent
+func (q *QueryParamExtractor) GetOptionalInt32(key string) (*int32, error) {
+	value := q.query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	v := int32(result)
+	return &v, nil
+}
+
+// GetOptionalInt64 returns a pointer to an int64 parameter, or nil if it is absent
+func (q *QueryParamExtractor) GetOptionalInt64(key string) (*int64, error) {
+	value := q.query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return &result, nil
+}
+
+// GetOptionalBool returns a pointer to a boolean parameter, or nil if it is absent
+func (q *QueryParamExtractor) GetOptionalBool(key string) (*bool, error) {
+	value := q.query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return &result, nil
+}
+
+// GetOptionalDate returns a pointer to a YYYY-MM-DD date parameter, or nil if it is absent
+func (q *QueryParamExtractor) GetOptionalDate(key string) (*time.Time, error) {
+	value := q.query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected YYYY-MM-DD: %w", key, err)
+	}
+	return &result, nil
+}
+
 // parsePagination extracts page and limit from the query string and returns limit and offset for SQL
 func parsePagination(r *http.Request) (limit, offset int32) {
 	extractor := NewQueryParamExtractor(r)
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/internal/review"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ReviewHandler handles HTTP requests for the review domain.
+type ReviewHandler struct {
+	svc review.Service
+}
+
+// NewReviewHandler creates a new ReviewHandler.
+func NewReviewHandler(svc review.Service) *ReviewHandler {
+	return &ReviewHandler{svc: svc}
+}
+
+func (h *ReviewHandler) CreateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	idStr := chi.URLParam(r, "movieId")
+	movieId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req review.CreateReviewRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := h.svc.CreateReview(ctx, userID, movieId, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, movie.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, review.ErrNotAttended):
+			respondWithError(w, http.StatusForbidden, err)
+		case errors.Is(err, review.ErrAlreadyReviewed):
+			respondWithError(w, http.StatusConflict, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to create review", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "review created successfully",
+		Data:    created.ToResponse(),
+	})
+}
+
+func (h *ReviewHandler) ListMovieReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "movieId")
+	movieId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, offset := parsePagination(r)
+
+	reviews, err := h.svc.ListMovieReviews(ctx, movieId, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list reviews", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]review.ReviewResponse, 0, len(reviews))
+	for _, rv := range reviews {
+		res = append(res, rv.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *ReviewHandler) UpdateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	idStr := chi.URLParam(r, "reviewId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req review.UpdateReviewRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := h.svc.UpdateReview(ctx, userID, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, review.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, review.ErrNotAuthor):
+			respondWithError(w, http.StatusForbidden, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to update review", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "review updated successfully",
+		Data:    updated.ToResponse(),
+	})
+}
+
+func (h *ReviewHandler) DeleteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	idStr := chi.URLParam(r, "reviewId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.DeleteReview(ctx, userID, id); err != nil {
+		switch {
+		case errors.Is(err, review.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, review.ErrNotAuthor):
+			respondWithError(w, http.StatusForbidden, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to delete review", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "review deleted successfully",
+	})
+}
+
+// ListReviewsAdminHandler lists reviews for moderation, flagged reviews first. It accepts
+// movie_id, hidden and flagged filters.
+func (h *ReviewHandler) ListReviewsAdminHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	extractor := NewQueryParamExtractor(r)
+	limit, offset := parsePagination(r)
+	filter := review.AdminFilter{Limit: limit, Offset: offset}
+
+	var err error
+	if filter.MovieID, err = extractor.GetOptionalInt64("movie_id"); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if filter.Hidden, err = extractor.GetOptionalBool("hidden"); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if filter.Flagged, err = extractor.GetOptionalBool("flagged"); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reviews, err := h.svc.ListReviewsAdmin(ctx, filter)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list admin reviews", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]review.ReviewAdminResponse, 0, len(reviews))
+	for _, rv := range reviews {
+		res = append(res, rv.ToAdminResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// ModerateReviewHandler hides, unhides, flags or unflags a review.
+func (h *ReviewHandler) ModerateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "reviewId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req review.ModerateReviewRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	moderated, err := h.svc.ModerateReview(ctx, id, req)
+	if err != nil {
+		if errors.Is(err, review.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to moderate review", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "review moderated successfully",
+		Data:    moderated.ToAdminResponse(),
+	})
+}
+
+func (h *ReviewHandler) DeleteReviewAdminHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "reviewId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.DeleteReviewAdmin(ctx, id); err != nil {
+		if errors.Is(err, review.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete review", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "review deleted successfully",
+	})
+}
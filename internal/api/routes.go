@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/go-chi/httprate"
 	customMiddleware "github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/auth"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -41,52 +42,144 @@ func (s *Server) RegisterRoutes() http.Handler {
 		MaxAge:           300,
 	}))
 
+	// Public keys for verifying tokens signed by an asymmetric token maker
+	r.Get("/.well-known/jwks.json", s.jwksHandler)
+
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/", s.testHandler)
 		// Health Check
 		r.Get("/health", s.healthHandler)
 
 		// OAuth routes
+		r.Get("/auth/providers", s.handlers.User.ListOAuthProvidersHandler)
 		r.Get("/auth/{provider}", s.handlers.User.BeginAuthHandler)
 		r.Get("/auth/{provider}/callback", s.handlers.User.GetAuthCallbackHandler)
+		// Apple posts the callback as a form
+		r.Post("/auth/{provider}/callback", s.handlers.User.GetAuthCallbackHandler)
 
 		// Traditional auth routes (public)
 		r.Post("/auth/signup", s.handlers.User.SignupHandler)
 		r.Post("/auth/login", s.handlers.User.LoginHandler)
 		r.Post("/auth/logout", s.handlers.User.LogoutHandler)
+		r.Get("/auth/verify", s.handlers.User.VerifyEmailHandler)
+		r.Post("/auth/password/forgot", s.handlers.User.ForgotPasswordHandler)
+		r.Post("/auth/password/reset", s.handlers.User.ResetPasswordHandler)
+		r.Post("/auth/mfa/verify", s.handlers.User.VerifyMFAHandler)
+		r.Post("/auth/passkey/login/begin", s.handlers.User.BeginPasskeyLoginHandler)
+		r.Post("/auth/passkey/login/finish", s.handlers.User.FinishPasskeyLoginHandler)
 
 		// Public listings
 		r.Get("/movies", s.handlers.Movie.ListMoviesPublicHandler)
+		r.Get("/movies/coming-soon", s.handlers.Movie.ListMoviesComingSoonHandler)
+		r.Get("/movies/trending", s.handlers.Trending.ListTrendingMoviesHandler)
 		r.Get("/movies/{movieId}", s.handlers.Movie.GetMovieHandler)
 		r.Get("/movies/{movieId}/showtimes", s.handlers.Showtime.ListShowtimesByMovieHandler)
+		r.Get("/movies/{movieId}/media", s.handlers.Media.ListMovieMediaHandler)
+		r.Get("/movies/{movieId}/reviews", s.handlers.Review.ListMovieReviewsHandler)
+		r.Get("/media/{assetId}/{variant}", s.handlers.Media.ServeMediaHandler)
+		r.Get("/genres", s.handlers.Movie.ListGenresHandler)
+		r.Get("/people/{personId}", s.handlers.Movie.GetPersonHandler)
 		r.Get("/venues", s.handlers.Venue.ListVenuesHandler)
 		r.Get("/venues/{venueId}", s.handlers.Venue.GetVenueHandler)
+		r.Get("/venues/{venueId}/auditoriums", s.handlers.Venue.ListAuditoriumsHandler)
+		r.Get("/venues/{venueId}/showtimes", s.handlers.Showtime.ListVenueProgrammeHandler)
+		r.Get("/showtimes", s.handlers.Showtime.ListShowtimesHandler)
 		r.Get("/showtimes/{showtimeId}", s.handlers.Showtime.GetShowtimeHandler)
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(customMiddleware.AuthMiddleware(s.tokenMaker, s.config.IsProduction(), s.config.AccessTokenDuration, s.config.RefreshTokenDuration))
+			r.Use(customMiddleware.AuthMiddleware(s.tokenMaker, s.sessions, s.users, s.config.IsProduction(), s.config.AccessTokenDuration, s.config.RefreshTokenDuration))
 
 			r.Get("/me", s.handlers.User.GetCurrentUser)
+			r.Patch("/me", s.handlers.User.UpdateProfileHandler)
+			r.Post("/auth/verify/resend", s.handlers.User.ResendVerificationHandler)
+			r.Put("/me/password", s.handlers.User.ChangePasswordHandler)
+			r.Get("/me/sessions", s.handlers.Session.ListSessionsHandler)
+			r.Delete("/me/sessions/{sessionId}", s.handlers.Session.RevokeSessionHandler)
+			r.Get("/me/mfa", s.handlers.MFA.GetMFAStatusHandler)
+			r.Post("/me/mfa/totp", s.handlers.MFA.BeginTOTPHandler)
+			r.Post("/me/mfa/totp/confirm", s.handlers.MFA.ConfirmTOTPHandler)
+			r.Delete("/me/mfa/totp", s.handlers.MFA.DisableTOTPHandler)
+			r.Post("/me/mfa/recovery-codes", s.handlers.MFA.RegenerateRecoveryCodesHandler)
+			r.Get("/auth/{provider}/link", s.handlers.User.LinkIdentityHandler)
+			r.Get("/me/identities", s.handlers.User.ListIdentitiesHandler)
+			r.Delete("/me/identities/{identityId}", s.handlers.User.UnlinkIdentityHandler)
+			r.Get("/me/passkeys", s.handlers.Passkey.ListPasskeysHandler)
+			r.Post("/me/passkeys/register/begin", s.handlers.Passkey.BeginRegistrationHandler)
+			r.Post("/me/passkeys/register/finish", s.handlers.Passkey.FinishRegistrationHandler)
+			r.Delete("/me/passkeys/{credentialId}", s.handlers.Passkey.DeletePasskeyHandler)
+			r.Get("/me/recommendations", s.handlers.Recommend.GetRecommendationsHandler)
+			r.Post("/showtimes/{showtimeId}/admission-check", s.handlers.Showtime.CheckAdmissionHandler)
+			r.Post("/movies/{movieId}/interest", s.handlers.Movie.RegisterInterestHandler)
+			r.Delete("/movies/{movieId}/interest", s.handlers.Movie.WithdrawInterestHandler)
+			r.Post("/movies/{movieId}/reviews", s.handlers.Review.CreateReviewHandler)
+			r.Patch("/reviews/{reviewId}", s.handlers.Review.UpdateReviewHandler)
+			r.Delete("/reviews/{reviewId}", s.handlers.Review.DeleteReviewHandler)
 
 			// Admin only routes
 			r.Group(func(r chi.Router) {
 				r.Use(customMiddleware.AdminMiddleware)
+				r.Use(customMiddleware.MFAPolicyMiddleware(s.mfa))
 
 				// Admin Movies
 				r.Get("/admin/movies", s.handlers.Movie.ListMoviesAdminHandler)
 				r.Post("/admin/movies", s.handlers.Movie.AddMovieHandler)
 				r.Patch("/admin/movies/{movieId}", s.handlers.Movie.UpdateMovieHandler)
 				r.Delete("/admin/movies/{movieId}", s.handlers.Movie.DeleteMovieHandler)
+				r.Put("/admin/movies/{movieId}/genres", s.handlers.Movie.SetMovieGenresHandler)
+				r.Put("/admin/movies/{movieId}/credits", s.handlers.Movie.SetMovieCreditsHandler)
+				r.Post("/admin/movies/{movieId}/media", s.handlers.Media.UploadMediaHandler)
+				r.Get("/admin/movies/{movieId}/catalog-diff", s.handlers.Movie.CatalogDiffHandler)
+				r.Post("/admin/movies/{movieId}/catalog-refresh", s.handlers.Movie.ApplyCatalogHandler)
+				r.Post("/admin/movies/{movieId}/restore", s.handlers.Movie.RestoreMovieHandler)
+
+				// Admin Catalogue Import
+				r.Get("/admin/catalog/search", s.handlers.Movie.SearchCatalogHandler)
+				r.Get("/admin/catalog/{externalId}", s.handlers.Movie.PrepareImportHandler)
+				r.Delete("/admin/media/{assetId}", s.handlers.Media.DeleteMediaHandler)
+
+				// Admin Genres & People
+				r.Post("/admin/genres", s.handlers.Movie.CreateGenreHandler)
+				r.Delete("/admin/genres/{genreId}", s.handlers.Movie.DeleteGenreHandler)
+				r.Get("/admin/people", s.handlers.Movie.SearchPeopleHandler)
+				r.Post("/admin/people", s.handlers.Movie.CreatePersonHandler)
+				r.Patch("/admin/people/{personId}", s.handlers.Movie.UpdatePersonHandler)
 
 				// Admin Showtimes
 				r.Get("/admin/showtimes", s.handlers.Showtime.ListShowtimesAdminHandler)
 				r.Post("/admin/showtimes", s.handlers.Showtime.CreateShowtimeHandler)
 				r.Patch("/admin/showtimes/{showtimeId}", s.handlers.Showtime.UpdateShowtimeHandler)
 				r.Delete("/admin/showtimes/{showtimeId}", s.handlers.Showtime.DeleteShowtimeHandler)
+				r.Get("/admin/showtimes/{showtimeId}/check-in", s.handlers.Showtime.GetCheckInHandler)
+				r.Post("/admin/showtimes/{showtimeId}/restore", s.handlers.Showtime.RestoreShowtimeHandler)
 
 				// Admin Venues
+				r.Get("/admin/venues", s.handlers.Venue.ListVenuesAdminHandler)
 				r.Post("/admin/venues", s.handlers.Venue.CreateVenueHandler)
+				r.Patch("/admin/venues/{venueId}", s.handlers.Venue.UpdateVenueHandler)
+				r.Delete("/admin/venues/{venueId}", s.handlers.Venue.DeleteVenueHandler)
+				r.Post("/admin/venues/{venueId}/restore", s.handlers.Venue.RestoreVenueHandler)
+				r.Post("/admin/venues/{venueId}/auditoriums", s.handlers.Venue.CreateAuditoriumHandler)
+				r.Put("/admin/venues/{venueId}/opening-hours", s.handlers.Venue.SetOpeningHoursHandler)
+				r.Get("/admin/venues/{venueId}/closures", s.handlers.Venue.ListClosuresHandler)
+				r.Post("/admin/venues/{venueId}/closures", s.handlers.Venue.CreateClosureHandler)
+				r.Delete("/admin/venues/{venueId}/closures/{closureId}", s.handlers.Venue.DeleteClosureHandler)
+
+				// Admin Reviews
+				r.Get("/admin/reviews", s.handlers.Review.ListReviewsAdminHandler)
+				r.Post("/admin/reviews/{reviewId}/moderation", s.handlers.Review.ModerateReviewHandler)
+				r.Delete("/admin/reviews/{reviewId}", s.handlers.Review.DeleteReviewAdminHandler)
+
+				// Admin Trash
+				r.Get("/admin/trash/movies", s.handlers.Movie.ListDeletedMoviesHandler)
+				r.Get("/admin/trash/showtimes", s.handlers.Showtime.ListDeletedShowtimesHandler)
+				r.Get("/admin/trash/venues", s.handlers.Venue.ListDeletedVenuesHandler)
+
+				// Admin Users
+				r.Post("/admin/users/{userId}/logout", s.handlers.Session.RevokeUserSessionsHandler)
+
+				// Admin Recommendations
+				r.Post("/admin/recommendations/refresh", s.handlers.Recommend.RefreshRecommendationsHandler)
 
 				// Admin Dashboard
 				r.Get("/admin/dashboard/stats", s.handlers.Analytics.GetDashboardStatsHandler)
@@ -120,3 +213,15 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(200)
 	json.NewEncoder(w).Encode(stats)
 }
+
+func (s *Server) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	provider, ok := s.tokenMaker.(auth.JWKSProvider)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(provider.JWKS())
+}
@@ -4,14 +4,27 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/mbeka02/ticketing-service/config"
 	"github.com/mbeka02/ticketing-service/internal/analytics"
 	"github.com/mbeka02/ticketing-service/internal/auth"
+	"github.com/mbeka02/ticketing-service/internal/catalog"
+	"github.com/mbeka02/ticketing-service/internal/media"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
 	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/internal/notify"
+	"github.com/mbeka02/ticketing-service/internal/passkey"
 	"github.com/mbeka02/ticketing-service/internal/postgres"
+	"github.com/mbeka02/ticketing-service/internal/recommendation"
+	"github.com/mbeka02/ticketing-service/internal/review"
+	"github.com/mbeka02/ticketing-service/internal/session"
 	"github.com/mbeka02/ticketing-service/internal/showtime"
+	"github.com/mbeka02/ticketing-service/internal/storage"
+	"github.com/mbeka02/ticketing-service/internal/trending"
 	"github.com/mbeka02/ticketing-service/internal/user"
 	"github.com/mbeka02/ticketing-service/internal/venue"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
@@ -25,6 +38,13 @@ type Handlers struct {
 	Showtime  *ShowtimeHandler
 	Venue     *VenueHandler
 	Analytics *AnalyticsHandler
+	Media     *MediaHandler
+	Review    *ReviewHandler
+	Recommend *RecommendationHandler
+	Trending  *TrendingHandler
+	Session   *SessionHandler
+	MFA       *MFAHandler
+	Passkey   *PasskeyHandler
 }
 
 // Server holds dependencies for the HTTP server.
@@ -33,6 +53,9 @@ type Server struct {
 	config     *config.Config
 	handlers   *Handlers
 	tokenMaker auth.Maker
+	sessions   session.Service
+	users      *user.Cache
+	mfa        mfa.Service
 }
 
 // NewServer creates and configures a new HTTP server.
@@ -60,8 +83,8 @@ func NewServer(cfg *config.Config) (*http.Server, error) {
 
 	logger.Info("database connection established successfully")
 
-	// Create JWT token maker
-	tokenMaker, err := auth.NewJWTMaker(cfg.SymmetricKey)
+	// Create token maker
+	tokenMaker, err := newTokenMaker(cfg)
 	if err != nil {
 		logger.Error("failed to create token maker", zap.Error(err))
 		return nil, fmt.Errorf("failed to create token maker: %w", err)
@@ -69,27 +92,106 @@ func NewServer(cfg *config.Config) (*http.Server, error) {
 
 	logger.Info("token maker initialized successfully")
 
+	mediaStorage, err := newMediaStorage(cfg)
+	if err != nil {
+		logger.Error("failed to create media storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to create media storage: %w", err)
+	}
+
+	catalogProvider, err := newCatalogProvider(cfg)
+	if err != nil {
+		logger.Error("failed to create catalogue provider", zap.Error(err))
+		return nil, fmt.Errorf("failed to create catalogue provider: %w", err)
+	}
+
+	mailer, err := newMailer(cfg)
+	if err != nil {
+		logger.Error("failed to create mailer", zap.Error(err))
+		return nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+
+	ratingScheme, err := movie.RatingSchemeFor(cfg.AgeRatingRegion)
+	if err != nil {
+		logger.Error("failed to load age rating scheme", zap.Error(err))
+		return nil, fmt.Errorf("failed to load age rating scheme: %w", err)
+	}
+
 	// Initialize repositories (postgres adapters)
 	userRepo := postgres.NewUserRepository(store)
 	movieRepo := postgres.NewMovieRepository(store)
 	showtimeRepo := postgres.NewShowtimeRepository(store)
 	venueRepo := postgres.NewVenueRepository(store)
 	analyticsRepo := postgres.NewAnalyticsRepository(store)
+	mediaRepo := postgres.NewMediaRepository(store)
+	reviewRepo := postgres.NewReviewRepository(store)
+	recommendationRepo := postgres.NewRecommendationRepository(store)
+	trendingRepo := postgres.NewTrendingRepository(store)
+	sessionRepo := postgres.NewSessionRepository(store)
+	mfaRepo := postgres.NewMFARepository(store)
+	passkeyRepo := postgres.NewPasskeyRepository(store)
 
 	// Initialize domain services
-	userSvc := user.NewService(userRepo)
-	movieSvc := movie.NewService(movieRepo)
-	showtimeSvc := showtime.NewService(showtimeRepo)
+	userSvc := user.NewService(userRepo, mailer, user.VerificationOptions{
+		LinkURL:        cfg.FrontendURL + "/verify-email",
+		TTL:            cfg.EmailVerificationTTL,
+		ResendInterval: cfg.EmailVerificationResendInterval,
+		Required:       cfg.RequireEmailVerification,
+	}, user.PasswordResetOptions{
+		LinkURL:        cfg.FrontendURL + "/reset-password",
+		TTL:            cfg.PasswordResetTTL,
+		ResendInterval: cfg.EmailVerificationResendInterval,
+	})
+	movieSvc := movie.NewService(movieRepo, notify.NewLogNotifier(), catalogProvider, ratingScheme)
 	venueSvc := venue.NewService(venueRepo)
+	showtimeSvc := showtime.NewService(showtimeRepo, venueSvc, movieSvc, userSvc)
 	analyticsSvc := analytics.NewService(analyticsRepo)
+	mediaSvc := media.NewService(mediaRepo, mediaStorage, movieSvc, cfg.BaseURL)
+	reviewSvc := review.NewService(reviewRepo, movieSvc)
+	recommendationSvc := recommendation.NewService(recommendationRepo, movieSvc)
+	trendingSvc := trending.NewService(trendingRepo, movieSvc, cfg.TrendingRefreshInterval)
+	sessionSvc := session.NewService(sessionRepo)
+
+	mfaOpts := mfa.Options{Issuer: cfg.MFAIssuer}
+	if cfg.RequireAdminMFA {
+		mfaOpts.RequiredRoles = []string{user.RoleAdmin}
+	}
+	mfaSvc, err := mfa.NewService(mfaRepo, cfg.MFAEncryptionKey, mfaOpts)
+	if err != nil {
+		logger.Error("failed to create mfa service", zap.Error(err))
+		return nil, fmt.Errorf("failed to create mfa service: %w", err)
+	}
+
+	passkeyOpts, err := newPasskeyOptions(cfg)
+	if err != nil {
+		logger.Error("failed to configure passkeys", zap.Error(err))
+		return nil, fmt.Errorf("failed to configure passkeys: %w", err)
+	}
+	passkeySvc, err := passkey.NewService(passkeyRepo, userSvc, mfaSvc, passkeyOpts)
+	if err != nil {
+		logger.Error("failed to create passkey service", zap.Error(err))
+		return nil, fmt.Errorf("failed to create passkey service: %w", err)
+	}
+
+	oauthRegistry, err := newOAuthRegistry(cfg)
+	if err != nil {
+		logger.Error("failed to configure social sign in", zap.Error(err))
+		return nil, fmt.Errorf("failed to configure social sign in: %w", err)
+	}
 
 	// Initialize handlers
 	handlers := &Handlers{
-		User:      NewUserHandler(userSvc, tokenMaker, cfg.IsProduction(), cfg.AccessTokenDuration, cfg.RefreshTokenDuration, cfg.FrontendURL),
+		User:      NewUserHandler(userSvc, sessionSvc, mfaSvc, passkeySvc, oauthRegistry, tokenMaker, cfg.IsProduction(), cfg.AccessTokenDuration, cfg.RefreshTokenDuration, cfg.FrontendURL),
 		Movie:     NewMovieHandler(movieSvc),
 		Showtime:  NewShowtimeHandler(showtimeSvc),
 		Venue:     NewVenueHandler(venueSvc),
 		Analytics: NewAnalyticsHandler(analyticsSvc),
+		Media:     NewMediaHandler(mediaSvc),
+		Review:    NewReviewHandler(reviewSvc),
+		Recommend: NewRecommendationHandler(recommendationSvc),
+		Trending:  NewTrendingHandler(trendingSvc),
+		Session:   NewSessionHandler(sessionSvc),
+		MFA:       NewMFAHandler(mfaSvc),
+		Passkey:   NewPasskeyHandler(passkeySvc, cfg.IsProduction()),
 	}
 
 	srv := &Server{
@@ -97,13 +199,141 @@ func NewServer(cfg *config.Config) (*http.Server, error) {
 		config:     cfg,
 		handlers:   handlers,
 		tokenMaker: tokenMaker,
+		sessions:   sessionSvc,
+		users:      user.NewCache(userRepo, cfg.UserCacheTTL),
+		mfa:        mfaSvc,
 	}
 
-	return &http.Server{
+	httpServer := &http.Server{
 		Handler:      srv.RegisterRoutes(),
 		Addr:         cfg.ServerPort,
 		IdleTimeout:  cfg.ServerIdleTimeout,
 		ReadTimeout:  cfg.ServerReadTimeout,
 		WriteTimeout: cfg.ServerWriteTimeout,
-	}, nil
+	}
+
+	// Background jobs run until the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	httpServer.RegisterOnShutdown(stopJobs)
+	go recommendationSvc.RunNightly(jobsCtx, cfg.RecommendationsRefreshHour)
+	go trendingSvc.RunRefresher(jobsCtx)
+
+	return httpServer, nil
+}
+
+// newTokenMaker creates the token maker selected by TOKEN_MAKER.
+func newTokenMaker(cfg *config.Config) (auth.Maker, error) {
+	switch cfg.TokenMaker {
+	case "jwt-asymmetric":
+		// A superseded key signed refresh tokens too, so it verifies for that long
+		keys, err := auth.LoadKeySet(cfg.TokenKeysFile, cfg.RefreshTokenDuration)
+		if err != nil {
+			return nil, err
+		}
+		return auth.NewAsymmetricJWTMaker(keys)
+	case "paseto-v4-local":
+		return auth.NewPasetoLocalMaker(cfg.SymmetricKey)
+	case "paseto-v4-public":
+		pemData, err := os.ReadFile(cfg.PasetoKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read PASETO key: %w", err)
+		}
+		return auth.NewPasetoPublicMaker(pemData)
+	}
+	return auth.NewJWTMaker(cfg.SymmetricKey)
+}
+
+// newMediaStorage creates the storage backend selected by MEDIA_STORAGE.
+func newMediaStorage(cfg *config.Config) (media.Storage, error) {
+	if cfg.MediaStorage == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.MediaS3Endpoint,
+			Region:          cfg.MediaS3Region,
+			Bucket:          cfg.MediaS3Bucket,
+			AccessKeyID:     cfg.MediaS3AccessKeyID,
+			SecretAccessKey: cfg.MediaS3SecretAccessKey,
+		}), nil
+	}
+	return storage.NewLocalStorage(cfg.MediaLocalDir)
+}
+
+// newMailer creates the mailer selected by MAILER.
+func newMailer(cfg *config.Config) (user.Mailer, error) {
+	switch cfg.Mailer {
+	case "smtp":
+		return notify.NewSMTPMailer(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}), nil
+	case "memory":
+		return notify.NewMemoryMailer(), nil
+	}
+	return notify.NewFileMailer(cfg.MailFileDir, cfg.MailFrom)
+}
+
+// newOAuthRegistry registers the social sign in providers listed in OAUTH_PROVIDERS.
+func newOAuthRegistry(cfg *config.Config) (*auth.OAuthRegistry, error) {
+	var providers []auth.OAuthProviderConfig
+	for _, kind := range cfg.EnabledOAuthProviders() {
+		p := auth.OAuthProviderConfig{Kind: kind}
+		switch kind {
+		case auth.OAuthGoogle:
+			p.ClientID, p.ClientSecret = cfg.GoogleClientID, cfg.GoogleClientSecret
+		case auth.OAuthGitHub:
+			p.ClientID, p.ClientSecret = cfg.GitHubClientID, cfg.GitHubClientSecret
+		case auth.OAuthApple:
+			p.ClientID, p.ClientSecret = cfg.AppleClientID, cfg.AppleClientSecret
+		case auth.OAuthMicrosoft:
+			p.ClientID, p.ClientSecret = cfg.MicrosoftClientID, cfg.MicrosoftClientSecret
+		case auth.OAuthOIDC:
+			p.Name, p.DisplayName = cfg.OIDCName, cfg.OIDCDisplayName
+			p.ClientID, p.ClientSecret = cfg.OIDCClientID, cfg.OIDCClientSecret
+			p.DiscoveryURL = cfg.OIDCDiscoveryURL
+		}
+		providers = append(providers, p)
+	}
+
+	return auth.NewOAuthRegistry(auth.OAuthConfig{
+		BaseURL:       cfg.BaseURL,
+		SessionSecret: cfg.SessionSecret,
+		IsProd:        cfg.IsProduction(),
+		Providers:     providers,
+	})
+}
+
+// newPasskeyOptions describes the relying party passkeys are registered with. The ID and
+// origins default to those of FRONTEND_URL, where the React app runs the ceremonies.
+func newPasskeyOptions(cfg *config.Config) (passkey.Options, error) {
+	frontend, err := url.Parse(cfg.FrontendURL)
+	if err != nil {
+		return passkey.Options{}, fmt.Errorf("invalid FRONTEND_URL: %w", err)
+	}
+
+	opts := passkey.Options{
+		RPID:          cfg.WebAuthnRPID,
+		RPDisplayName: cfg.WebAuthnRPName,
+	}
+	if opts.RPID == "" {
+		opts.RPID = frontend.Hostname()
+	}
+	for _, origin := range strings.Split(cfg.WebAuthnRPOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			opts.RPOrigins = append(opts.RPOrigins, origin)
+		}
+	}
+	if len(opts.RPOrigins) == 0 {
+		opts.RPOrigins = []string{frontend.Scheme + "://" + frontend.Host}
+	}
+	return opts, nil
+}
+
+// newCatalogProvider creates the catalogue provider selected by CATALOG_PROVIDER.
+func newCatalogProvider(cfg *config.Config) (movie.CatalogProvider, error) {
+	if cfg.CatalogProvider == "tmdb" {
+		return catalog.NewTMDBProvider(cfg.TMDBAPIKey, cfg.TMDBRegion), nil
+	}
+	return catalog.NewFixtureProvider(cfg.CatalogFixturePath)
 }
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/auth"
+	"github.com/mbeka02/ticketing-service/internal/session"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SessionHandler handles HTTP requests for the session domain.
+type SessionHandler struct {
+	svc session.Service
+}
+
+// NewSessionHandler creates a new SessionHandler.
+func NewSessionHandler(svc session.Service) *SessionHandler {
+	return &SessionHandler{svc: svc}
+}
+
+// ListSessionsHandler lists the current user's active sessions, marking the one the
+// request was made with.
+func (h *SessionHandler) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	currentID, _ := middleware.SessionIDFromContext(ctx)
+
+	sessions, err := h.svc.List(ctx, userID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list sessions", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]session.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, s.ToResponse(s.ID == currentID))
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RevokeSessionHandler signs the current user out of one of their sessions. Its access
+// token keeps working until it expires. Revoking the current session also clears the
+// cookies.
+func (h *SessionHandler) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "sessionId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to revoke session", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if currentID, _ := middleware.SessionIDFromContext(ctx); currentID == id {
+		auth.ClearTokenCookies(w)
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "session revoked successfully",
+	})
+}
+
+// RevokeUserSessionsHandler signs a user out everywhere.
+func (h *SessionHandler) RevokeUserSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	n, err := h.svc.RevokeAll(ctx, userID, session.ReasonAdmin)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to revoke user sessions", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "user logged out everywhere",
+		Data:    map[string]int64{"sessions_revoked": n},
+	})
+}
@@ -1,11 +1,16 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi"
+	"github.com/mbeka02/ticketing-service/internal/api/middleware"
+	"github.com/mbeka02/ticketing-service/internal/movie"
 	"github.com/mbeka02/ticketing-service/internal/showtime"
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/mbeka02/ticketing-service/internal/venue"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -30,6 +35,19 @@ func (h *ShowtimeHandler) CreateShowtimeHandler(w http.ResponseWriter, r *http.R
 
 	s, err := h.svc.CreateShowtime(ctx, req)
 	if err != nil {
+		if errors.Is(err, showtime.ErrInvalidTimeRange) || errors.Is(err, showtime.ErrUnsupportedFormat) ||
+			errors.Is(err, showtime.ErrSeatsExceedCapacity) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, movie.ErrNotFound) || errors.Is(err, venue.ErrAuditoriumNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrClosed) || errors.Is(err, venue.ErrOutsideOpeningHours) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to create showtime", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -115,6 +133,78 @@ func (h *ShowtimeHandler) ListShowtimesAdminHandler(w http.ResponseWriter, r *ht
 	})
 }
 
+// ListShowtimesHandler serves the public showtime listing.
+// Supported filters: venue_id, city, date_from, date_to (inclusive, YYYY-MM-DD in the venue's time zone), genre, age_rating, min_seats,
+// format, audio_language, subtitle_language, audio_described and relaxed_screening.
+func (h *ShowtimeHandler) ListShowtimesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := parseShowtimeFilter(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	showtimes, err := h.svc.ListShowtimes(ctx, filter)
+	if err != nil {
+		if errors.Is(err, showtime.ErrInvalidFilterRange) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to list showtimes", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]showtime.ShowtimeResponse, 0, len(showtimes))
+	for _, s := range showtimes {
+		res = append(res, s.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// ListVenueProgrammeHandler returns a venue's showtimes for one local day (?date=YYYY-MM-DD, default today) grouped by movie.
+func (h *ShowtimeHandler) ListVenueProgrammeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	date := NewQueryParamExtractor(r).GetOptionalString("date")
+	programme, err := h.svc.GetVenueProgramme(ctx, int32(id), date)
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, showtime.ErrInvalidDate) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to get venue programme", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]showtime.MovieProgrammeResponse, 0, len(programme))
+	for _, p := range programme {
+		res = append(res, p.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
 func (h *ShowtimeHandler) UpdateShowtimeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "showtimeId")
@@ -132,6 +222,19 @@ func (h *ShowtimeHandler) UpdateShowtimeHandler(w http.ResponseWriter, r *http.R
 
 	s, err := h.svc.UpdateShowtime(ctx, id, req)
 	if err != nil {
+		if errors.Is(err, showtime.ErrInvalidTimeRange) || errors.Is(err, showtime.ErrUnsupportedFormat) ||
+			errors.Is(err, showtime.ErrSeatsExceedCapacity) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, venue.ErrAuditoriumNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrClosed) || errors.Is(err, venue.ErrOutsideOpeningHours) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to update showtime", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -144,6 +247,7 @@ func (h *ShowtimeHandler) UpdateShowtimeHandler(w http.ResponseWriter, r *http.R
 	})
 }
 
+// DeleteShowtimeHandler soft-deletes a showtime and cancels its reservations.
 func (h *ShowtimeHandler) DeleteShowtimeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "showtimeId")
@@ -154,6 +258,10 @@ func (h *ShowtimeHandler) DeleteShowtimeHandler(w http.ResponseWriter, r *http.R
 	}
 
 	if err := h.svc.DeleteShowtime(ctx, id); err != nil {
+		if errors.Is(err, showtime.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to delete showtime", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -164,3 +272,183 @@ func (h *ShowtimeHandler) DeleteShowtimeHandler(w http.ResponseWriter, r *http.R
 		Message: "showtime deleted successfully",
 	})
 }
+
+// ListDeletedShowtimesHandler lists showtimes in the trash, most recently deleted first.
+func (h *ShowtimeHandler) ListDeletedShowtimesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset := parsePagination(r)
+
+	showtimes, err := h.svc.ListDeletedShowtimes(ctx, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list deleted showtimes", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]showtime.ShowtimeResponse, 0, len(showtimes))
+	for _, s := range showtimes {
+		res = append(res, s.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RestoreShowtimeHandler takes a showtime out of the trash. Its movie and venue must not be
+// deleted, and a future showtime must still fall within the venue's opening hours.
+func (h *ShowtimeHandler) RestoreShowtimeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "showtimeId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s, err := h.svc.RestoreShowtime(ctx, id)
+	if err != nil {
+		if errors.Is(err, showtime.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, showtime.ErrParentDeleted) || errors.Is(err, venue.ErrClosed) ||
+			errors.Is(err, venue.ErrOutsideOpeningHours) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to restore showtime", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "showtime restored successfully",
+		Data:    s.ToResponse(),
+	})
+}
+
+// parseShowtimeFilter builds a showtime.ListFilter from the query string.
+func parseShowtimeFilter(r *http.Request) (showtime.ListFilter, error) {
+	extractor := NewQueryParamExtractor(r)
+	limit, offset := parsePagination(r)
+	filter := showtime.ListFilter{
+		City:             extractor.GetOptionalString("city"),
+		Genre:            extractor.GetOptionalString("genre"),
+		AgeRating:        extractor.GetOptionalString("age_rating"),
+		Format:           extractor.GetOptionalString("format"),
+		AudioLanguage:    extractor.GetOptionalString("audio_language"),
+		SubtitleLanguage: extractor.GetOptionalString("subtitle_language"),
+		Limit:            limit,
+		Offset:           offset,
+	}
+
+	var err error
+	if filter.VenueID, err = extractor.GetOptionalInt32("venue_id"); err != nil {
+		return filter, err
+	}
+	if filter.MinSeats, err = extractor.GetOptionalInt32("min_seats"); err != nil {
+		return filter, err
+	}
+	if filter.AudioDescribed, err = extractor.GetOptionalBool("audio_described"); err != nil {
+		return filter, err
+	}
+	if filter.RelaxedScreening, err = extractor.GetOptionalBool("relaxed_screening"); err != nil {
+		return filter, err
+	}
+	// Only the calendar date is used; it is matched in each venue's own time zone.
+	if filter.DateFrom, err = extractor.GetOptionalDate("date_from"); err != nil {
+		return filter, err
+	}
+	if filter.DateTo, err = extractor.GetOptionalDate("date_to"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// CheckAdmissionHandler checks the tickets a customer intends to book against the movie's
+// age rating. Bookings the rating does not permit are refused with 403; permitted bookings
+// may still carry warnings to show the customer.
+func (h *ShowtimeHandler) CheckAdmissionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "showtimeId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req showtime.AdmissionRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	check, err := h.svc.CheckAdmission(ctx, id, userID, req)
+	if err != nil {
+		if errors.Is(err, showtime.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, user.ErrEmailNotVerified) {
+			respondWithError(w, http.StatusForbidden, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to check admission", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !check.Allowed {
+		respondWithJSON(w, http.StatusForbidden, APIResponse{
+			Status:  http.StatusForbidden,
+			Message: "booking not permitted by the movie's age rating",
+			Data:    check.ToResponse(),
+		})
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "booking permitted",
+		Data:    check.ToResponse(),
+	})
+}
+
+// GetCheckInHandler returns a showtime together with the age check staff should make when
+// admitting customers.
+func (h *ShowtimeHandler) GetCheckInHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "showtimeId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	checkIn, err := h.svc.GetCheckIn(ctx, id)
+	if err != nil {
+		if errors.Is(err, showtime.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to get showtime check-in", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    checkIn.ToResponse(),
+	})
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/mbeka02/ticketing-service/internal/trending"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TrendingHandler handles HTTP requests for the trending domain.
+type TrendingHandler struct {
+	svc trending.Service
+}
+
+// NewTrendingHandler creates a new TrendingHandler.
+func NewTrendingHandler(svc trending.Service) *TrendingHandler {
+	return &TrendingHandler{svc: svc}
+}
+
+// ListTrendingMoviesHandler lists movies now showing ranked by recent ticket sales and
+// occupancy. Pass ?limit= for up to 50. Last-Modified is when the ranking was computed.
+func (h *TrendingHandler) ListTrendingMoviesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := NewQueryParamExtractor(r).GetInt32("limit", 10)
+	if limit < 1 || limit > trending.MaxLimit {
+		limit = 10
+	}
+
+	movies, computedAt, err := h.svc.Trending(ctx, limit)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list trending movies", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]trending.TrendingMovieResponse, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, m.ToResponse())
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.Header().Set("Last-Modified", computedAt.UTC().Format(http.TimeFormat))
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
@@ -7,10 +7,14 @@ import (
 	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 	"github.com/markbates/goth/gothic"
 	"github.com/mbeka02/ticketing-service/internal/api/middleware"
 	"github.com/mbeka02/ticketing-service/internal/auth"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+	"github.com/mbeka02/ticketing-service/internal/passkey"
+	"github.com/mbeka02/ticketing-service/internal/session"
 	"github.com/mbeka02/ticketing-service/internal/user"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
@@ -19,6 +23,10 @@ import (
 // UserHandler handles HTTP requests for the user domain.
 type UserHandler struct {
 	userService     user.Service
+	sessions        session.Service
+	mfa             mfa.Service
+	passkeys        passkey.Service
+	oauth           *auth.OAuthRegistry
 	tokenMaker      auth.Maker
 	isProduction    bool
 	accessDuration  time.Duration
@@ -27,9 +35,13 @@ type UserHandler struct {
 }
 
 // NewUserHandler creates a new UserHandler.
-func NewUserHandler(svc user.Service, maker auth.Maker, isProduction bool, accessDuration, refreshDuration time.Duration, frontendURL string) *UserHandler {
+func NewUserHandler(svc user.Service, sessions session.Service, mfaSvc mfa.Service, passkeys passkey.Service, oauth *auth.OAuthRegistry, maker auth.Maker, isProduction bool, accessDuration, refreshDuration time.Duration, frontendURL string) *UserHandler {
 	return &UserHandler{
 		userService:     svc,
+		sessions:        sessions,
+		mfa:             mfaSvc,
+		passkeys:        passkeys,
+		oauth:           oauth,
 		tokenMaker:      maker,
 		isProduction:    isProduction,
 		accessDuration:  accessDuration,
@@ -59,9 +71,9 @@ func (h *UserHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set JWT token cookies
-	if err := auth.SetTokenCookies(w, h.tokenMaker, u.ID, u.Email, u.Role, h.isProduction, h.accessDuration, h.refreshDuration); err != nil {
-		logger.ErrorCtx(ctx, "failed to set token cookies", zap.Error(err))
+	// Start a session and set its JWT token cookies
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -98,9 +110,25 @@ func (h *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set JWT token cookies
-	if err := auth.SetTokenCookies(w, h.tokenMaker, u.ID, u.Email, u.Role, h.isProduction, h.accessDuration, h.refreshDuration); err != nil {
-		logger.ErrorCtx(ctx, "failed to set token cookies", zap.Error(err))
+	// Users with an authenticator finish signing in at VerifyMFAHandler
+	challenge, err := h.challengeMFA(w, r, u)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to start two-factor challenge", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if challenge != nil {
+		respondWithJSON(w, http.StatusOK, APIResponse{
+			Status:  http.StatusOK,
+			Message: "two-factor authentication required",
+			Data:    challenge,
+		})
+		return
+	}
+
+	// Start a session and set its JWT token cookies
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -112,10 +140,22 @@ func (h *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// LogoutHandler ends the session of the refresh token cookie, if there is one, and clears
+// the cookies. The refresh token may have expired; its session is ended all the same.
 func (h *UserHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if cookie, err := r.Cookie(auth.RefreshTokenCookie); err == nil {
+		claims, err := h.tokenMaker.Verify(cookie.Value)
+		if claims != nil && (err == nil || errors.Is(err, auth.ErrExpiredToken)) {
+			if err := h.sessions.Logout(ctx, claims.SessionID); err != nil {
+				logger.ErrorCtx(ctx, "failed to end session", zap.Error(err))
+			}
+		}
+	}
+
 	auth.ClearTokenCookies(w)
+	auth.ClearOAuthLinkCookie(w)
 
 	logger.InfoCtx(ctx, "user logged out")
 
@@ -125,15 +165,24 @@ func (h *UserHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ListOAuthProvidersHandler lists the social sign in providers the login page can offer.
+func (h *UserHandler) ListOAuthProvidersHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    h.oauth.Providers(),
+	})
+}
+
 func (h *UserHandler) BeginAuthHandler(w http.ResponseWriter, r *http.Request) {
 	requestID := uuid.New().String()
 	ctx := logger.WithRequestID(r.Context(), requestID)
 	r = r.WithContext(ctx)
 
 	provider := chi.URLParam(r, "provider")
-	if provider == "" {
-		logger.WarnCtx(ctx, "auth attempt with missing provider")
-		http.Error(w, "Provider is required", http.StatusBadRequest)
+	if !h.oauth.Has(provider) {
+		logger.WarnCtx(ctx, "auth attempt with unknown provider", zap.String("provider", provider))
+		respondWithError(w, http.StatusNotFound, errors.New("unknown sign in provider"))
 		return
 	}
 
@@ -147,8 +196,8 @@ func (h *UserHandler) GetAuthCallbackHandler(w http.ResponseWriter, r *http.Requ
 	r = r.WithContext(ctx)
 
 	provider := chi.URLParam(r, "provider")
-	if provider == "" {
-		logger.WarnCtx(ctx, "auth callback with invalid provider")
+	if !h.oauth.Has(provider) {
+		logger.WarnCtx(ctx, "auth callback with unknown provider", zap.String("provider", provider))
 		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_provider", http.StatusFound)
 		return
 	}
@@ -175,9 +224,21 @@ func (h *UserHandler) GetAuthCallbackHandler(w http.ResponseWriter, r *http.Requ
 		Provider:       gothUser.Provider,
 		ProviderUserID: gothUser.UserID,
 		AvatarURL:      gothUser.AvatarURL,
+		EmailVerified:  h.oauth.EmailVerified(gothUser),
+	}
+
+	// A link cookie means a signed in user started this from LinkIdentityHandler.
+	if cookie, err := r.Cookie(auth.OAuthLinkCookie); err == nil {
+		auth.ClearOAuthLinkCookie(w)
+		h.finishLinkIdentity(w, r, cookie.Value, oauthUser)
+		return
 	}
 
 	u, err := h.userService.CreateOrLoginOAuthUser(ctx, oauthUser)
+	if errors.Is(err, user.ErrAccountExists) {
+		http.Redirect(w, r, h.frontendURL+"/login?error=account_exists", http.StatusFound)
+		return
+	}
 	if err != nil {
 		logger.ErrorCtx(ctx, "failed to create or login OAuth user",
 			zap.Error(err),
@@ -188,9 +249,23 @@ func (h *UserHandler) GetAuthCallbackHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Set JWT token cookies for unified auth
-	if err := auth.SetTokenCookies(w, h.tokenMaker, u.ID, u.Email, u.Role, h.isProduction, h.accessDuration, h.refreshDuration); err != nil {
-		logger.ErrorCtx(ctx, "failed to set token cookies after OAuth",
+	challenge, err := h.challengeMFA(w, r, u)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to start two-factor challenge after OAuth",
+			zap.Error(err),
+			zap.String("user_id", u.ID.String()),
+		)
+		http.Redirect(w, r, h.frontendURL+"/login?error=token_failed", http.StatusFound)
+		return
+	}
+	if challenge != nil {
+		http.Redirect(w, r, h.frontendURL+"/login/mfa", http.StatusFound)
+		return
+	}
+
+	// Start a session and set its JWT token cookies for unified auth
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session after OAuth",
 			zap.Error(err),
 			zap.String("user_id", u.ID.String()),
 		)
@@ -201,6 +276,269 @@ func (h *UserHandler) GetAuthCallbackHandler(w http.ResponseWriter, r *http.Requ
 	http.Redirect(w, r, h.frontendURL+"/home", http.StatusFound)
 }
 
+// finishLinkIdentity links the provider account in data to the user who started linking
+// with the link token, then sends them back to the frontend.
+func (h *UserHandler) finishLinkIdentity(w http.ResponseWriter, r *http.Request, token string, data user.OAuthUserData) {
+	ctx := r.Context()
+
+	claims, err := h.tokenMaker.Verify(token)
+	if err != nil || claims.TokenType != auth.OAuthLinkToken {
+		http.Redirect(w, r, h.frontendURL+"/home?error=link_expired", http.StatusFound)
+		return
+	}
+	// Only link for the session that started linking, in case the user has since signed
+	// out and someone else is signing in on the same browser.
+	if err := h.sessions.Check(ctx, claims.SessionID); err != nil {
+		if !errors.Is(err, session.ErrRevoked) {
+			logger.ErrorCtx(ctx, "failed to check session for linking", zap.Error(err))
+		}
+		http.Redirect(w, r, h.frontendURL+"/login?error=link_expired", http.StatusFound)
+		return
+	}
+
+	if err := h.userService.LinkOAuthIdentity(ctx, claims.UserID, data); err != nil {
+		if errors.Is(err, user.ErrIdentityInUse) {
+			http.Redirect(w, r, h.frontendURL+"/home?error=identity_in_use", http.StatusFound)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to link OAuth identity",
+			zap.Error(err),
+			zap.String("provider", data.Provider),
+			zap.String("user_id", claims.UserID.String()),
+		)
+		http.Redirect(w, r, h.frontendURL+"/home?error=link_failed", http.StatusFound)
+		return
+	}
+
+	http.Redirect(w, r, h.frontendURL+"/home?linked="+data.Provider, http.StatusFound)
+}
+
+// LinkIdentityHandler starts signing in to a provider to add it to the signed in user's
+// ways to sign in. GetAuthCallbackHandler finishes it.
+func (h *UserHandler) LinkIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+	if !h.oauth.Has(provider) {
+		respondWithError(w, http.StatusNotFound, errors.New("unknown sign in provider"))
+		return
+	}
+
+	sessionID, _ := middleware.SessionIDFromContext(ctx)
+	email, _ := ctx.Value(middleware.EmailKey).(string)
+	role, _ := middleware.RoleFromContext(ctx)
+	token, err := h.tokenMaker.Create(userID, sessionID, email, role, auth.OAuthLinkToken, auth.OAuthLinkDuration)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to create link token", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auth.SetOAuthLinkCookie(w, token, h.isProduction, auth.OAuthLinkDuration)
+	r = r.WithContext(context.WithValue(ctx, "provider", provider))
+	gothic.BeginAuthHandler(w, r)
+}
+
+// ListIdentitiesHandler lists the ways the signed in user can sign in.
+func (h *UserHandler) ListIdentitiesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	identities, err := h.userService.ListIdentities(ctx, userID)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list identities", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    identities,
+	})
+}
+
+// UnlinkIdentityHandler removes one of the signed in user's ways to sign in, as long as
+// another is left.
+func (h *UserHandler) UnlinkIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	identityID, err := uuid.Parse(chi.URLParam(r, "identityId"))
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, user.ErrIdentityNotFound)
+		return
+	}
+
+	if err := h.userService.UnlinkIdentity(ctx, userID, identityID); err != nil {
+		switch {
+		case errors.Is(err, user.ErrIdentityNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		case errors.Is(err, user.ErrLastIdentity):
+			respondWithError(w, http.StatusConflict, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to unlink identity", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "sign in method removed",
+	})
+}
+
+// VerifyMFAHandler finishes a sign in that was challenged for a second factor, with a code
+// from the user's authenticator app or a recovery code.
+func (h *UserHandler) VerifyMFAHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req mfa.CodeRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cookie, err := r.Cookie(auth.MFAPendingCookie)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, errors.New("no sign in is awaiting a code, please log in again"))
+		return
+	}
+	claims, err := h.tokenMaker.Verify(cookie.Value)
+	if err != nil || claims.TokenType != auth.MFAPendingToken {
+		auth.ClearMFAPendingCookie(w)
+		respondWithError(w, http.StatusUnauthorized, errors.New("the sign in has expired, please log in again"))
+		return
+	}
+
+	u, err := h.userService.GetUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			auth.ClearMFAPendingCookie(w)
+			respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to load user", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.mfa.Verify(ctx, u.ID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			respondWithError(w, http.StatusUnauthorized, err)
+		case errors.Is(err, mfa.ErrLocked):
+			respondWithError(w, http.StatusTooManyRequests, err)
+		case errors.Is(err, mfa.ErrNotEnrolled):
+			auth.ClearMFAPendingCookie(w)
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, mfa.ErrUnavailable):
+			respondWithError(w, http.StatusServiceUnavailable, errors.New("authenticator codes cannot be checked right now, use a recovery code"))
+		default:
+			logger.ErrorCtx(ctx, "failed to verify two-factor code", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	auth.ClearMFAPendingCookie(w)
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "login successful",
+		Data:    u.ToResponse(),
+	})
+}
+
+// BeginPasskeyLoginHandler returns the options for navigator.credentials.get and sets the
+// ceremony cookie that FinishPasskeyLoginHandler reads.
+func (h *UserHandler) BeginPasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ceremonyID, assertion, err := h.passkeys.BeginLogin(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to begin passkey login", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auth.SetWebAuthnCeremonyCookie(w, ceremonyID.String(), h.isProduction, passkey.CeremonyTTL)
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    assertion,
+	})
+}
+
+// FinishPasskeyLoginHandler signs in with the browser's navigator.credentials.get
+// response, which is the request body. Passkeys are registered and used with user
+// verification, so they already count as two factors and are not challenged for a code.
+func (h *UserHandler) FinishPasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ceremonyID, ok := ceremonyFromCookie(r)
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, passkey.ErrCeremonyExpired)
+		return
+	}
+	auth.ClearWebAuthnCeremonyCookie(w)
+
+	response, err := protocol.ParseCredentialRequestResponseBody(r.Body)
+	if err != nil {
+		logger.WarnCtx(ctx, "invalid passkey login response", zap.Error(err))
+		respondWithError(w, http.StatusBadRequest, passkey.ErrVerificationFailed)
+		return
+	}
+
+	u, err := h.passkeys.FinishLogin(ctx, ceremonyID, response)
+	if err != nil {
+		switch {
+		case errors.Is(err, passkey.ErrCeremonyExpired):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, passkey.ErrVerificationFailed):
+			respondWithError(w, http.StatusUnauthorized, err)
+		default:
+			logger.ErrorCtx(ctx, "passkey login failed", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "login successful",
+		Data:    u.ToResponse(),
+	})
+}
+
 func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -227,3 +565,234 @@ func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 }
+
+// VerifyEmailHandler verifies the email address a ?token= from a verification email was
+// sent to.
+func (h *UserHandler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respondWithError(w, http.StatusBadRequest, errors.New("token is required"))
+		return
+	}
+
+	u, err := h.userService.VerifyEmail(ctx, token)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidToken) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to verify email", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "email verified successfully",
+		Data:    u.ToResponse(),
+	})
+}
+
+// ResendVerificationHandler emails the current user a new verification link.
+func (h *UserHandler) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	if err := h.userService.ResendVerification(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, user.ErrAlreadyVerified):
+			respondWithError(w, http.StatusConflict, err)
+		case errors.Is(err, user.ErrVerificationThrottled):
+			respondWithError(w, http.StatusTooManyRequests, err)
+		case errors.Is(err, user.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to resend verification email", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "verification email sent",
+	})
+}
+
+// ForgotPasswordHandler emails a password reset link. It responds the same way whether or
+// not the account exists.
+func (h *UserHandler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req user.ForgotPasswordRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.userService.RequestPasswordReset(ctx, req.Email); err != nil {
+		logger.ErrorCtx(ctx, "failed to request password reset", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "if an account exists for that email, a password reset link has been sent",
+	})
+}
+
+// ResetPasswordHandler sets a new password with a token from a reset email. The user is
+// signed out everywhere and has to log in with the new password.
+func (h *UserHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req user.ResetPasswordRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := h.userService.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, user.ErrInvalidToken) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to reset password", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auth.ClearTokenCookies(w)
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "password reset successfully",
+	})
+}
+
+// ChangePasswordHandler changes the current user's password. Other sessions are signed out;
+// this one gets new tokens.
+func (h *UserHandler) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req user.ChangePasswordRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	u, err := h.userService.ChangePassword(ctx, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrIncorrectPassword):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, user.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to change password", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// The password change signed out every session, this one included, so start afresh.
+	if err := h.startSession(w, r, u); err != nil {
+		logger.ErrorCtx(ctx, "failed to start session", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "password changed successfully",
+		Data:    u.ToResponse(),
+	})
+}
+
+// UpdateProfileHandler updates the current user's name, telephone number or date of birth.
+func (h *UserHandler) UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+
+	var req user.UpdateProfileRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	u, err := h.userService.UpdateProfile(ctx, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidDateOfBirth):
+			respondWithError(w, http.StatusBadRequest, err)
+		case errors.Is(err, user.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, err)
+		default:
+			logger.ErrorCtx(ctx, "failed to update profile", zap.Error(err))
+			respondWithError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "profile updated successfully",
+		Data:    u.ToResponse(),
+	})
+}
+
+// startSession records a new session for u on the requesting client and sets its token
+// cookies.
+func (h *UserHandler) startSession(w http.ResponseWriter, r *http.Request, u *user.User) error {
+	sessionID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	tokens, err := auth.NewTokenPair(h.tokenMaker, u.ID, sessionID, u.Email, u.Role, h.accessDuration, h.refreshDuration)
+	if err != nil {
+		return err
+	}
+	if _, err := h.sessions.Start(r.Context(), sessionID, u.ID, tokens.Refresh, middleware.ClientFromRequest(r), time.Now().Add(h.refreshDuration)); err != nil {
+		return err
+	}
+
+	auth.SetTokenCookies(w, tokens, h.isProduction, h.accessDuration, h.refreshDuration)
+	return nil
+}
+
+// challengeMFA sets a pending sign in cookie and returns the challenge if u has to give a
+// second factor before a session starts. It returns nil if they do not.
+func (h *UserHandler) challengeMFA(w http.ResponseWriter, r *http.Request, u *user.User) (*mfa.ChallengeResponse, error) {
+	enabled, err := h.mfa.Enabled(r.Context(), u.ID)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	token, err := h.tokenMaker.Create(u.ID, uuid.Nil, u.Email, u.Role, auth.MFAPendingToken, mfa.PendingTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.SetMFAPendingCookie(w, token, h.isProduction, mfa.PendingTokenDuration)
+	return &mfa.ChallengeResponse{
+		MFARequired: true,
+		ExpiresAt:   time.Now().Add(mfa.PendingTokenDuration),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -30,6 +31,10 @@ func (h *VenueHandler) CreateVenueHandler(w http.ResponseWriter, r *http.Request
 
 	v, err := h.svc.CreateVenue(ctx, req)
 	if err != nil {
+		if errors.Is(err, venue.ErrLayoutCapacityMismatch) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
 		logger.ErrorCtx(ctx, "failed to create venue", zap.Error(err))
 		respondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -53,7 +58,12 @@ func (h *VenueHandler) GetVenueHandler(w http.ResponseWriter, r *http.Request) {
 
 	v, err := h.svc.GetVenue(ctx, int32(id))
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err)
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to get venue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -85,3 +95,345 @@ func (h *VenueHandler) ListVenuesHandler(w http.ResponseWriter, r *http.Request)
 		Data:    res,
 	})
 }
+
+func (h *VenueHandler) CreateAuditoriumHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req venue.CreateAuditoriumRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	a, err := h.svc.CreateAuditorium(ctx, int32(id), req)
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrLayoutCapacityMismatch) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to create auditorium", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "auditorium created successfully",
+		Data:    a.ToResponse(),
+	})
+}
+
+func (h *VenueHandler) ListAuditoriumsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	auditoriums, err := h.svc.ListAuditoriums(ctx, int32(id))
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list auditoriums", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]venue.AuditoriumResponse, 0, len(auditoriums))
+	for _, a := range auditoriums {
+		res = append(res, a.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *VenueHandler) ListVenuesAdminHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	venues, err := h.svc.ListVenuesAdmin(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list venues", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]venue.VenueResponse, 0, len(venues))
+	for _, v := range venues {
+		res = append(res, v.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *VenueHandler) UpdateVenueHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req venue.UpdateVenueRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	v, err := h.svc.UpdateVenue(ctx, int32(id), req)
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to update venue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "venue updated successfully",
+		Data:    v.ToResponse(),
+	})
+}
+
+// DeleteVenueHandler soft-deletes a venue. Pass ?cascade=true to also cancel its future showtimes.
+func (h *VenueHandler) DeleteVenueHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	cascade := NewQueryParamExtractor(r).GetBool("cascade", false)
+
+	if err := h.svc.DeleteVenue(ctx, int32(id), cascade); err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrShowtimesScheduled) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete venue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "venue deleted successfully",
+	})
+}
+
+// CreateClosureHandler closes a venue for a period. Pass ?cascade=true to cancel showtimes in that period.
+func (h *VenueHandler) CreateClosureHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	cascade := NewQueryParamExtractor(r).GetBool("cascade", false)
+
+	var req venue.CreateClosureRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	c, err := h.svc.CloseVenue(ctx, int32(id), req, cascade)
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrInvalidClosureRange) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, venue.ErrShowtimesScheduled) {
+			respondWithError(w, http.StatusConflict, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to create venue closure", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, APIResponse{
+		Status:  http.StatusCreated,
+		Message: "venue closure created successfully",
+		Data:    c.ToResponse(),
+	})
+}
+
+func (h *VenueHandler) ListClosuresHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	closures, err := h.svc.ListClosures(ctx, int32(id))
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list venue closures", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]venue.ClosureResponse, 0, len(closures))
+	for _, c := range closures {
+		res = append(res, c.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+func (h *VenueHandler) DeleteClosureHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	venueId, err := strconv.ParseInt(chi.URLParam(r, "venueId"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	closureId, err := strconv.ParseInt(chi.URLParam(r, "closureId"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.svc.ReopenVenue(ctx, int32(venueId), int32(closureId)); err != nil {
+		if errors.Is(err, venue.ErrClosureNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to delete venue closure", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "venue closure removed successfully",
+	})
+}
+
+// SetOpeningHoursHandler replaces a venue's weekly opening hours.
+func (h *VenueHandler) SetOpeningHoursHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req venue.SetOpeningHoursRequest
+	if err := parseAndValidateRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	v, err := h.svc.SetOpeningHours(ctx, int32(id), req)
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, venue.ErrDuplicateOpeningDay) {
+			respondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to set venue opening hours", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "opening hours updated successfully",
+		Data:    v.ToResponse(),
+	})
+}
+
+// ListDeletedVenuesHandler lists venues in the trash, most recently deleted first.
+func (h *VenueHandler) ListDeletedVenuesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset := parsePagination(r)
+
+	venues, err := h.svc.ListDeletedVenues(ctx, limit, offset)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to list deleted venues", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := make([]venue.VenueResponse, 0, len(venues))
+	for _, v := range venues {
+		res = append(res, v.ToResponse())
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "success",
+		Data:    res,
+	})
+}
+
+// RestoreVenueHandler takes a venue out of the trash, together with the auditoriums and
+// future showtimes deleted with it. Reservations cancelled by the delete stay cancelled.
+func (h *VenueHandler) RestoreVenueHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "venueId")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	v, err := h.svc.RestoreVenue(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, venue.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, err)
+			return
+		}
+		logger.ErrorCtx(ctx, "failed to restore venue", zap.Error(err))
+		respondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, APIResponse{
+		Status:  http.StatusOK,
+		Message: "venue restored successfully",
+		Data:    v.ToResponse(),
+	})
+}
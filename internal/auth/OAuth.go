@@ -1,33 +1,202 @@
 package auth
 
 import (
+	"fmt"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/gorilla/sessions"
-	_ "github.com/joho/godotenv/autoload"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
+	"github.com/markbates/goth/providers/apple"
+	"github.com/markbates/goth/providers/github"
 	"github.com/markbates/goth/providers/google"
+	"github.com/markbates/goth/providers/microsoftonline"
+	"github.com/markbates/goth/providers/openidConnect"
 )
 
-var (
-	GOOGLE_CLIENT_ID     = os.Getenv("GOOGLE_CLIENT_ID")
-	GOOGLE_CLIENT_SECRET = os.Getenv("GOOGLE_CLIENT_SECRET")
-	SESSION_SECRET       = os.Getenv("SESSION_SECRET")
+// Kinds of social sign in provider.
+const (
+	OAuthGoogle    = "google"
+	OAuthGitHub    = "github"
+	OAuthApple     = "apple"
+	OAuthMicrosoft = "microsoft"
+	// OAuthOIDC is any OpenID Connect provider with a discovery document.
+	OAuthOIDC = "oidc"
 )
 
-func NewAuth(callbackURL string, isProd bool) {
-	maxAge := 86400 * 30 // 30 days
+// OAuthLinkDuration is how long a user has at the provider when linking it to their account.
+const OAuthLinkDuration = 10 * time.Minute
 
-	store := sessions.NewCookieStore([]byte(SESSION_SECRET))
-	store.MaxAge(maxAge)
+// OAuthProviderConfig configures one social sign in provider.
+type OAuthProviderConfig struct {
+	Kind string
+	// Name is the {provider} path segment and the provider recorded on the user's
+	// identity. It defaults to Kind.
+	Name         string
+	DisplayName  string
+	ClientID     string
+	ClientSecret string
+	// DiscoveryURL is the .well-known/openid-configuration URL of an OIDC provider.
+	DiscoveryURL string
+}
+
+// OAuthConfig configures social sign in.
+type OAuthConfig struct {
+	// BaseURL is where the API is served. Providers redirect back to
+	// BaseURL/api/v1/auth/{provider}/callback.
+	BaseURL string
+	// SessionSecret signs the cookie that carries the OAuth state between the redirects.
+	SessionSecret string
+	IsProd        bool
+	Providers     []OAuthProviderConfig
+}
+
+// OAuthProvider describes an enabled provider to the frontend.
+type OAuthProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+// OAuthRegistry holds the enabled social sign in providers.
+type OAuthRegistry struct {
+	providers []OAuthProvider
+	kinds     map[string]string
+}
+
+// NewOAuthRegistry builds the configured providers and registers them with gothic, which
+// runs the redirects, replacing any registered before. OIDC providers fetch their discovery
+// document here, so an unreachable provider fails startup rather than the first sign in.
+func NewOAuthRegistry(cfg OAuthConfig) (*OAuthRegistry, error) {
+	registry := &OAuthRegistry{kinds: make(map[string]string)}
+	var postsCallback bool
+	gothProviders := make([]goth.Provider, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		if p.Name == "" {
+			p.Name = p.Kind
+		}
+		if p.DisplayName == "" {
+			p.DisplayName = defaultDisplayNames[p.Kind]
+		}
+		if registry.Has(p.Name) {
+			return nil, fmt.Errorf("oauth provider %q is configured twice", p.Name)
+		}
+
+		provider, err := newGothProvider(p, fmt.Sprintf("%s/api/v1/auth/%s/callback", cfg.BaseURL, p.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure oauth provider %q: %w", p.Name, err)
+		}
+		gothProviders = append(gothProviders, provider)
+		postsCallback = postsCallback || p.Kind == OAuthApple
+		registry.kinds[p.Name] = p.Kind
+		registry.providers = append(registry.providers, OAuthProvider{
+			Name:        p.Name,
+			DisplayName: p.DisplayName,
+			LoginURL:    "/api/v1/auth/" + p.Name,
+		})
+	}
+
+	store := sessions.NewCookieStore([]byte(cfg.SessionSecret))
+	store.MaxAge(86400 * 30) // 30 days
 	store.Options.Path = "/"
 	store.Options.HttpOnly = true // HttpOnly should always be enabled
-	store.Options.Secure = isProd
+	store.Options.Secure = cfg.IsProd
 	store.Options.SameSite = http.SameSiteLaxMode
+	// Browsers leave Lax cookies off cross-site form posts, so the state cookie would be
+	// missing from Apple's callback. Apple only redirects to HTTPS, so it can be Secure.
+	if postsCallback {
+		store.Options.SameSite = http.SameSiteNoneMode
+		store.Options.Secure = true
+	}
 	gothic.Store = store
-	goth.UseProviders(
-		google.New(GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET, callbackURL, "email", "profile"),
-	)
+
+	goth.ClearProviders()
+	goth.UseProviders(gothProviders...)
+	return registry, nil
+}
+
+// Providers lists the enabled providers in the order they were configured.
+func (r *OAuthRegistry) Providers() []OAuthProvider {
+	return r.providers
+}
+
+// Has reports whether name is an enabled provider.
+func (r *OAuthRegistry) Has(name string) bool {
+	for _, p := range r.providers {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailVerified reports whether the provider vouches that u owns u.Email. GitHub only
+// hands out verified addresses and Apple only verified Apple IDs; Google and OIDC
+// providers say so in a claim. Microsoft accounts can carry any address, so they never count.
+func (r *OAuthRegistry) EmailVerified(u goth.User) bool {
+	if u.Email == "" {
+		return false
+	}
+	switch r.kinds[u.Provider] {
+	case OAuthGitHub, OAuthApple:
+		return true
+	case OAuthGoogle:
+		return claimTrue(u.RawData["verified_email"])
+	case OAuthOIDC:
+		return claimTrue(u.RawData["email_verified"])
+	}
+	return false
+}
+
+// claimTrue reads a boolean claim, which some providers send as a string.
+func claimTrue(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+var defaultDisplayNames = map[string]string{
+	OAuthGoogle:    "Google",
+	OAuthGitHub:    "GitHub",
+	OAuthApple:     "Apple",
+	OAuthMicrosoft: "Microsoft",
+	OAuthOIDC:      "Single sign-on",
+}
+
+func newGothProvider(p OAuthProviderConfig, callbackURL string) (goth.Provider, error) {
+	switch p.Kind {
+	case OAuthGoogle:
+		provider := google.New(p.ClientID, p.ClientSecret, callbackURL, "email", "profile")
+		provider.SetName(p.Name)
+		return provider, nil
+	case OAuthGitHub:
+		provider := github.New(p.ClientID, p.ClientSecret, callbackURL, "read:user", "user:email")
+		provider.SetName(p.Name)
+		return provider, nil
+	case OAuthApple:
+		// Apple posts the callback as a form, and only sends the user's name the first time.
+		provider := apple.New(p.ClientID, p.ClientSecret, callbackURL, nil, apple.ScopeName, apple.ScopeEmail)
+		provider.SetName(p.Name)
+		return provider, nil
+	case OAuthMicrosoft:
+		provider := microsoftonline.New(p.ClientID, p.ClientSecret, callbackURL)
+		provider.SetName(p.Name)
+		return provider, nil
+	case OAuthOIDC:
+		if p.DiscoveryURL == "" {
+			return nil, fmt.Errorf("a discovery URL is required")
+		}
+		provider, err := openidConnect.New(p.ClientID, p.ClientSecret, callbackURL, p.DiscoveryURL, "email", "profile")
+		if err != nil {
+			return nil, err
+		}
+		provider.SetName(p.Name)
+		return provider, nil
+	}
+	return nil, fmt.Errorf("unknown provider kind %q", p.Kind)
 }
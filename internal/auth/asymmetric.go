@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AsymmetricJWTMaker signs tokens with the active key of a KeySet and verifies them with
+// whichever key the token's kid header names, so other services can verify tokens from
+// the published JWKS without holding a secret.
+type AsymmetricJWTMaker struct {
+	keys *KeySet
+	now  func() time.Time
+}
+
+// JWK is a public key in JSON Web Key form.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKSProvider is implemented by makers whose tokens can be verified with public keys.
+type JWKSProvider interface {
+	JWKS() JWKSet
+}
+
+func NewAsymmetricJWTMaker(keys *KeySet) (*AsymmetricJWTMaker, error) {
+	if keys == nil {
+		return nil, errors.New("a key set is required")
+	}
+	return &AsymmetricJWTMaker{keys: keys, now: time.Now}, nil
+}
+
+func (maker *AsymmetricJWTMaker) Create(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error) {
+	key, err := maker.keys.signingKey(maker.now())
+	if err != nil {
+		return "", err
+	}
+	payload := NewPayload(userId, sessionId, email, role, tokenType, duration)
+
+	token := jwt.NewWithClaims(key.method, payload)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+func (maker *AsymmetricJWTMaker) Verify(tokenString string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := maker.keys.verificationKey(kid, maker.now())
+		if !ok {
+			return nil, errors.New("unknown or retired signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.public, nil
+	}
+	return parseToken(tokenString, keyFunc)
+}
+
+// JWKS lists the public keys that verify current tokens, plus upcoming keys so verifiers
+// can fetch them before they start signing.
+func (maker *AsymmetricJWTMaker) JWKS() JWKSet {
+	now := maker.now()
+	set := JWKSet{Keys: []JWK{}}
+	for i, key := range maker.keys.keys {
+		if maker.keys.retired(i, now) {
+			continue
+		}
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.method.Alg()}
+		switch pub := key.public.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, dir, name string, key any) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+func TestAsymmetricJWTMakerRotation(t *testing.T) {
+	dir := t.TempDir()
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "old.pem", oldKey)
+	writePrivateKey(t, dir, "new.pem", newKey)
+
+	rotation := time.Now().Add(time.Hour)
+	manifest := fmt.Sprintf(`{"keys": [
+		{"kid": "old", "file": "old.pem", "active_from": %q},
+		{"kid": "new", "file": "new.pem", "active_from": %q}
+	]}`, rotation.Add(-24*time.Hour).Format(time.RFC3339), rotation.Format(time.RFC3339))
+	manifestPath := filepath.Join(dir, "keys.json")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(manifest), 0o600))
+
+	keys, err := LoadKeySet(manifestPath, 2*time.Hour)
+	require.NoError(t, err)
+	maker, err := NewAsymmetricJWTMaker(keys)
+	require.NoError(t, err)
+
+	// Before the rotation the old key signs and the upcoming key is already published
+	userId := utils.RandUUID()
+	oldToken, err := maker.Create(userId, utils.RandUUID(), utils.RandEmail(), "customer", RefreshToken, 24*time.Hour)
+	require.NoError(t, err)
+	claims, err := maker.Verify(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, userId, claims.UserID)
+
+	jwks := maker.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	require.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	require.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	require.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+
+	// After the rotation the new key signs while the old key still verifies
+	maker.now = func() time.Time { return rotation.Add(time.Minute) }
+	newToken, err := maker.Create(userId, utils.RandUUID(), utils.RandEmail(), "customer", AccessToken, time.Hour)
+	require.NoError(t, err)
+	_, err = maker.Verify(newToken)
+	require.NoError(t, err)
+	_, err = maker.Verify(oldToken)
+	require.NoError(t, err)
+
+	// Once every token it signed has expired, the old key is retired
+	maker.now = func() time.Time { return rotation.Add(3 * time.Hour) }
+	_, err = maker.Verify(oldToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Len(t, maker.JWKS().Keys, 1)
+}
+
+func TestAsymmetricJWTMakerRejectsForeignTokens(t *testing.T) {
+	key, err := ParseSigningKey("only", time.Now().Add(-time.Hour), testEd25519PEM(t))
+	require.NoError(t, err)
+	keys, err := NewKeySet([]SigningKey{*key}, time.Hour)
+	require.NoError(t, err)
+	maker, err := NewAsymmetricJWTMaker(keys)
+	require.NoError(t, err)
+
+	symmetric, err := NewJWTMaker(utils.RandString(32))
+	require.NoError(t, err)
+	token, err := symmetric.Create(utils.RandUUID(), utils.RandUUID(), utils.RandEmail(), "customer", AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	_, err = maker.Verify(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
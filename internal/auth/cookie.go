@@ -10,21 +10,35 @@ import (
 const (
 	AccessTokenCookie  = "access_token"
 	RefreshTokenCookie = "refresh_token"
+	MFAPendingCookie   = "mfa_pending"
+	// WebAuthnCeremonyCookie holds the ID of the passkey registration or login in progress.
+	WebAuthnCeremonyCookie = "webauthn_ceremony"
+	// OAuthLinkCookie holds the OAuthLinkToken while the user is away at the provider.
+	OAuthLinkCookie = "oauth_link"
 )
 
-func SetTokenCookies(w http.ResponseWriter, maker Maker, userID uuid.UUID, email, role string, isSecure bool, accessDuration, refreshDuration time.Duration) error {
-	access, err := maker.Create(userID, email, role, AccessToken, accessDuration)
+// TokenPair is an access token and the refresh token that renews it.
+type TokenPair struct {
+	Access  string
+	Refresh string
+}
+
+// NewTokenPair creates an access and a refresh token for a session.
+func NewTokenPair(maker Maker, userID, sessionID uuid.UUID, email, role string, accessDuration, refreshDuration time.Duration) (*TokenPair, error) {
+	access, err := maker.Create(userID, sessionID, email, role, AccessToken, accessDuration)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	refresh, err := maker.Create(userID, email, role, RefreshToken, refreshDuration)
+	refresh, err := maker.Create(userID, sessionID, email, role, RefreshToken, refreshDuration)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &TokenPair{Access: access, Refresh: refresh}, nil
+}
 
-	setCookie(w, AccessTokenCookie, access, accessDuration, isSecure)
-	setCookie(w, RefreshTokenCookie, refresh, refreshDuration, isSecure)
-	return nil
+func SetTokenCookies(w http.ResponseWriter, tokens *TokenPair, isSecure bool, accessDuration, refreshDuration time.Duration) {
+	setCookie(w, AccessTokenCookie, tokens.Access, accessDuration, isSecure)
+	setCookie(w, RefreshTokenCookie, tokens.Refresh, refreshDuration, isSecure)
 }
 
 func ClearTokenCookies(w http.ResponseWriter) {
@@ -32,6 +46,46 @@ func ClearTokenCookies(w http.ResponseWriter) {
 	setCookie(w, RefreshTokenCookie, "", -time.Hour, false)
 }
 
+// SetMFAPendingCookie stores the token that lets the user finish signing in with a code.
+func SetMFAPendingCookie(w http.ResponseWriter, token string, isSecure bool, duration time.Duration) {
+	setCookie(w, MFAPendingCookie, token, duration, isSecure)
+}
+
+func ClearMFAPendingCookie(w http.ResponseWriter) {
+	setCookie(w, MFAPendingCookie, "", -time.Hour, false)
+}
+
+// SetWebAuthnCeremonyCookie stores the ID of a passkey ceremony until the browser answers it.
+func SetWebAuthnCeremonyCookie(w http.ResponseWriter, id string, isSecure bool, duration time.Duration) {
+	setCookie(w, WebAuthnCeremonyCookie, id, duration, isSecure)
+}
+
+func ClearWebAuthnCeremonyCookie(w http.ResponseWriter) {
+	setCookie(w, WebAuthnCeremonyCookie, "", -time.Hour, false)
+}
+
+// SetOAuthLinkCookie stores the token that turns the next provider callback into linking.
+// Over HTTPS it is SameSite=None so it reaches providers that post the callback, like Apple.
+func SetOAuthLinkCookie(w http.ResponseWriter, token string, isSecure bool, duration time.Duration) {
+	cookie := &http.Cookie{
+		Name:     OAuthLinkCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(duration.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if isSecure {
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(w, cookie)
+}
+
+func ClearOAuthLinkCookie(w http.ResponseWriter) {
+	setCookie(w, OAuthLinkCookie, "", -time.Hour, false)
+}
+
 func setCookie(w http.ResponseWriter, name, value string, dur time.Duration, secure bool) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
@@ -31,8 +31,8 @@ func NewJWTMaker(secret string) (Maker, error) {
 }
 
 // This method is used to create a new JWT token , it implements the Maker interface
-func (maker *JWTMaker) Create(userId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error) {
-	payload := NewPayload(userId, email, role, tokenType, duration)
+func (maker *JWTMaker) Create(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error) {
+	payload := NewPayload(userId, sessionId, email, role, tokenType, duration)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	return token.SignedString([]byte(maker.secret))
@@ -48,6 +48,11 @@ func (maker *JWTMaker) Verify(tokenString string) (*Payload, error) {
 		}
 		return []byte(maker.secret), nil
 	}
+	return parseToken(tokenString, keyFunc)
+}
+
+// parseToken verifies a JWT with keyFunc and maps the result onto ErrExpiredToken and ErrInvalidToken.
+func parseToken(tokenString string, keyFunc jwt.Keyfunc) (*Payload, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Payload{}, keyFunc)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minimumRSABits is the smallest RSA key accepted for signing tokens.
+const minimumRSABits = 2048
+
+var ErrNoSigningKey = errors.New("no signing key is active")
+
+// SigningKey is one key of a KeySet. Keys without a private part can only verify.
+type SigningKey struct {
+	ID string
+	// ActiveFrom is when the key starts signing tokens, taking over from the key before it.
+	ActiveFrom time.Time
+
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet is a rotation schedule of asymmetric signing keys. The key with the latest
+// ActiveFrom that has passed signs new tokens; keys it took over from keep verifying until
+// every token they signed has expired.
+type KeySet struct {
+	keys []SigningKey
+	// maxTokenLifetime is the longest a token lives, and so how long a superseded key
+	// must keep verifying.
+	maxTokenLifetime time.Duration
+}
+
+// keyManifest is the JSON file listing a KeySet's keys.
+type keyManifest struct {
+	Keys []struct {
+		ID         string    `json:"kid"`
+		File       string    `json:"file"`
+		ActiveFrom time.Time `json:"active_from"`
+	} `json:"keys"`
+}
+
+// LoadKeySet reads a key manifest such as
+//
+//	{"keys": [
+//	  {"kid": "2026-09", "file": "2026-09.pem", "active_from": "2026-09-01T00:00:00Z"},
+//	  {"kid": "2026-10", "file": "2026-10.pem", "active_from": "2026-10-01T00:00:00Z"}
+//	]}
+//
+// Files are PEM encoded, relative to the manifest, and hold a PKCS#8 Ed25519 or RSA
+// private key, or a PKIX public key for a retired key kept only to verify. A new key can
+// be generated with `openssl genpkey -algorithm ed25519`. To rotate, add the next key with
+// an active_from in the future; verifiers fetching the JWKS see it before it signs.
+func LoadKeySet(path string, maxTokenLifetime time.Duration) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key manifest: %w", err)
+	}
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse key manifest: %w", err)
+	}
+
+	keys := make([]SigningKey, 0, len(manifest.Keys))
+	seen := make(map[string]bool)
+	for _, entry := range manifest.Keys {
+		if entry.ID == "" || seen[entry.ID] {
+			return nil, fmt.Errorf("key manifest: missing or duplicate kid %q", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		file := entry.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		pemData, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read key %s: %w", entry.ID, err)
+		}
+		key, err := ParseSigningKey(entry.ID, entry.ActiveFrom, pemData)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return NewKeySet(keys, maxTokenLifetime)
+}
+
+// NewKeySet creates a KeySet from keys in any order.
+func NewKeySet(keys []SigningKey, maxTokenLifetime time.Duration) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("a key set needs at least one key")
+	}
+	sorted := append([]SigningKey(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom) })
+	return &KeySet{keys: sorted, maxTokenLifetime: maxTokenLifetime}, nil
+}
+
+// ParseSigningKey parses a PEM encoded private or public key.
+func ParseSigningKey(id string, activeFrom time.Time, pemData []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", id)
+	}
+
+	key := &SigningKey{ID: id, ActiveFrom: activeFrom}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("key %s: unsupported private key type %T", id, parsed)
+		}
+		key.private = signer
+		key.public = signer.Public()
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		key.public = parsed
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", id, block.Type)
+	}
+
+	switch pub := key.public.(type) {
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minimumRSABits {
+			return nil, fmt.Errorf("key %s: RSA keys must be at least %d bits", id, minimumRSABits)
+		}
+		key.method = jwt.SigningMethodRS256
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T, use Ed25519 or RSA", id, key.public)
+	}
+	return key, nil
+}
+
+// signingKey returns the key that signs tokens at now.
+func (ks *KeySet) signingKey(now time.Time) (*SigningKey, error) {
+	for i := len(ks.keys) - 1; i >= 0; i-- {
+		k := &ks.keys[i]
+		if k.ActiveFrom.After(now) {
+			continue
+		}
+		if k.private == nil {
+			return nil, fmt.Errorf("%w: key %s has no private key", ErrNoSigningKey, k.ID)
+		}
+		return k, nil
+	}
+	return nil, ErrNoSigningKey
+}
+
+// verificationKey returns the key with id if it may have signed a token still alive at now.
+func (ks *KeySet) verificationKey(id string, now time.Time) (*SigningKey, bool) {
+	for i := range ks.keys {
+		if ks.keys[i].ID == id {
+			k := &ks.keys[i]
+			return k, !k.ActiveFrom.After(now) && !ks.retired(i, now)
+		}
+	}
+	return nil, false
+}
+
+// retired reports whether every token the i-th key signed has expired by now.
+func (ks *KeySet) retired(i int, now time.Time) bool {
+	if i+1 >= len(ks.keys) {
+		return false
+	}
+	return now.After(ks.keys[i+1].ActiveFrom.Add(ks.maxTokenLifetime))
+}
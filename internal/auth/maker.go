@@ -7,6 +7,6 @@ import (
 )
 
 type Maker interface {
-	Create(userId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error)
+	Create(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error)
 	Verify(tokenString string) (*Payload, error)
 }
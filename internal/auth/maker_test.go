@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaker is the conformance suite every Maker implementation must pass.
+func testMaker(t *testing.T, maker Maker) {
+	t.Run("valid token", func(t *testing.T) {
+		email := utils.RandEmail()
+		userId := utils.RandUUID()
+		sessionId := utils.RandUUID()
+		duration := time.Minute
+		issuedAt := time.Now()
+		expiresAt := time.Now().Add(duration)
+
+		token, err := maker.Create(userId, sessionId, email, "customer", AccessToken, duration)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+
+		claims, err := maker.Verify(token)
+		require.NoError(t, err)
+		require.NotEmpty(t, claims)
+		require.Equal(t, email, claims.Email)
+		require.Equal(t, userId, claims.UserID)
+		require.Equal(t, sessionId, claims.SessionID)
+		require.Equal(t, "customer", claims.Role)
+		require.Equal(t, AccessToken, claims.TokenType)
+		require.NotEmpty(t, claims.ID)
+		require.WithinDuration(t, issuedAt, claims.IssuedAt, time.Second)
+		require.WithinDuration(t, expiresAt, claims.ExpiresAt, time.Second)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		email := utils.RandEmail()
+		userId := utils.RandUUID()
+		token, err := maker.Create(userId, utils.RandUUID(), email, "customer", RefreshToken, -time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+
+		claims, err := maker.Verify(token)
+		require.Error(t, err)
+		require.EqualError(t, err, ErrExpiredToken.Error())
+		// Claims should still be returned for expired tokens (for refresh flow)
+		require.NotNil(t, claims)
+		require.Equal(t, email, claims.Email)
+		require.Equal(t, userId, claims.UserID)
+		require.Equal(t, RefreshToken, claims.TokenType)
+	})
+
+	t.Run("distinct tokens", func(t *testing.T) {
+		userId, sessionId := utils.RandUUID(), utils.RandUUID()
+		first, err := maker.Create(userId, sessionId, utils.RandEmail(), "customer", AccessToken, time.Minute)
+		require.NoError(t, err)
+		second, err := maker.Create(userId, sessionId, utils.RandEmail(), "customer", AccessToken, time.Minute)
+		require.NoError(t, err)
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		token, err := maker.Create(utils.RandUUID(), utils.RandUUID(), utils.RandEmail(), "admin", AccessToken, time.Minute)
+		require.NoError(t, err)
+
+		// Flip a character in the middle of the token, well clear of any padding bits
+		middle := len(token) / 2
+		replacement := "A"
+		if token[middle] == 'A' {
+			replacement = "B"
+		}
+		claims, err := maker.Verify(token[:middle] + replacement + token[middle+1:])
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.Nil(t, claims)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		for _, token := range []string{"", "not-a-token", strings.Repeat("a.", 3)} {
+			claims, err := maker.Verify(token)
+			require.ErrorIs(t, err, ErrInvalidToken)
+			require.Nil(t, claims)
+		}
+	})
+}
+
+func testEd25519PEM(t *testing.T) []byte {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestJWTMaker(t *testing.T) {
+	maker, err := NewJWTMaker(utils.RandString(32))
+	require.NoError(t, err)
+	testMaker(t, maker)
+}
+
+func TestAsymmetricJWTMaker(t *testing.T) {
+	key, err := ParseSigningKey("test", time.Now().Add(-time.Hour), testEd25519PEM(t))
+	require.NoError(t, err)
+	keys, err := NewKeySet([]SigningKey{*key}, time.Hour)
+	require.NoError(t, err)
+	maker, err := NewAsymmetricJWTMaker(keys)
+	require.NoError(t, err)
+	testMaker(t, maker)
+}
+
+// randKey returns n random bytes.
+func randKey(t *testing.T, n int) []byte {
+	key := make([]byte, n)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestPasetoLocalMaker(t *testing.T) {
+	key := randKey(t, 32)
+	maker, err := NewPasetoLocalMaker(hex.EncodeToString(key))
+	require.NoError(t, err)
+	testMaker(t, maker)
+
+	// The same key in base64 decrypts tokens made with the hex form
+	token, err := maker.Create(utils.RandUUID(), utils.RandUUID(), utils.RandEmail(), "customer", AccessToken, time.Minute)
+	require.NoError(t, err)
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString(key),
+		base64.RawURLEncoding.EncodeToString(key),
+		" " + hex.EncodeToString(key) + "\n",
+	} {
+		other, err := NewPasetoLocalMaker(encoded)
+		require.NoError(t, err)
+		_, err = other.Verify(token)
+		require.NoError(t, err)
+	}
+
+	for _, encoded := range []string{
+		utils.RandString(32), // raw characters are not accepted
+		hex.EncodeToString(randKey(t, 31)),
+		base64.StdEncoding.EncodeToString(randKey(t, 33)),
+		"",
+	} {
+		_, err := NewPasetoLocalMaker(encoded)
+		require.ErrorIs(t, err, errPasetoLocalKey)
+	}
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(testEd25519PEM(t))
+	require.NoError(t, err)
+	testMaker(t, maker)
+}
+
+func TestMakersRejectEachOthersTokens(t *testing.T) {
+	secret := hex.EncodeToString(randKey(t, 32))
+	jwtMaker, err := NewJWTMaker(secret)
+	require.NoError(t, err)
+	localMaker, err := NewPasetoLocalMaker(secret)
+	require.NoError(t, err)
+	publicMaker, err := NewPasetoPublicMaker(testEd25519PEM(t))
+	require.NoError(t, err)
+
+	makers := []Maker{jwtMaker, localMaker, publicMaker}
+	for i, issuer := range makers {
+		token, err := issuer.Create(utils.RandUUID(), utils.RandUUID(), utils.RandEmail(), "customer", AccessToken, time.Minute)
+		require.NoError(t, err)
+		for j, verifier := range makers {
+			if i == j {
+				continue
+			}
+			_, err := verifier.Verify(token)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/gothic"
+	"github.com/mbeka02/ticketing-service/internal/auth/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthRegistryProviders(t *testing.T) {
+	registry, err := NewOAuthRegistry(OAuthConfig{
+		BaseURL:       "http://localhost:3000",
+		SessionSecret: "secret",
+		Providers: []OAuthProviderConfig{
+			{Kind: OAuthGoogle, ClientID: "id", ClientSecret: "secret"},
+			{Kind: OAuthGitHub, ClientID: "id", ClientSecret: "secret", DisplayName: "GitHub Enterprise"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []OAuthProvider{
+		{Name: "google", DisplayName: "Google", LoginURL: "/api/v1/auth/google"},
+		{Name: "github", DisplayName: "GitHub Enterprise", LoginURL: "/api/v1/auth/github"},
+	}, registry.Providers())
+	require.True(t, registry.Has("github"))
+	require.False(t, registry.Has("apple"))
+	require.False(t, registry.Has(""))
+
+	_, err = NewOAuthRegistry(OAuthConfig{Providers: []OAuthProviderConfig{{Kind: "myspace"}}})
+	require.Error(t, err)
+
+	_, err = NewOAuthRegistry(OAuthConfig{Providers: []OAuthProviderConfig{
+		{Kind: OAuthGoogle, ClientID: "id", ClientSecret: "secret"},
+		{Kind: OAuthGoogle, ClientID: "id", ClientSecret: "secret"},
+	}})
+	require.Error(t, err)
+}
+
+func TestOAuthRegistryOIDCSignIn(t *testing.T) {
+	idp := oidctest.NewServer(t, oidctest.User{
+		Subject:       "user-123",
+		Email:         "ada@example.com",
+		EmailVerified: true,
+		GivenName:     "Ada",
+		FamilyName:    "Lovelace",
+	})
+
+	// The app stands in for the API's OAuth routes.
+	var registry *OAuthRegistry
+	mux := http.NewServeMux()
+	withProvider := func(r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), "provider", r.PathValue("provider")))
+	}
+	mux.HandleFunc("GET /api/v1/auth/{provider}", func(w http.ResponseWriter, r *http.Request) {
+		gothic.BeginAuthHandler(w, withProvider(r))
+	})
+	mux.HandleFunc("GET /api/v1/auth/{provider}/callback", func(w http.ResponseWriter, r *http.Request) {
+		u, err := gothic.CompleteUserAuth(w, withProvider(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"user": u, "email_verified": registry.EmailVerified(u)})
+	})
+	app := httptest.NewServer(mux)
+	t.Cleanup(app.Close)
+
+	var err error
+	registry, err = NewOAuthRegistry(OAuthConfig{
+		BaseURL:       app.URL,
+		SessionSecret: "secret",
+		Providers: []OAuthProviderConfig{{
+			Kind:         OAuthOIDC,
+			Name:         "corp",
+			ClientID:     idp.ClientID,
+			ClientSecret: idp.ClientSecret,
+			DiscoveryURL: idp.DiscoveryURL(),
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Single sign-on", registry.Providers()[0].DisplayName)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Jar: jar}
+
+	res, err := client.Get(app.URL + "/api/v1/auth/corp")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var signedIn struct {
+		User struct {
+			Provider  string
+			UserID    string
+			Email     string
+			FirstName string
+			LastName  string
+		}
+		EmailVerified bool `json:"email_verified"`
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&signedIn))
+	require.Equal(t, "corp", signedIn.User.Provider)
+	require.Equal(t, "user-123", signedIn.User.UserID)
+	require.Equal(t, "ada@example.com", signedIn.User.Email)
+	require.Equal(t, "Ada", signedIn.User.FirstName)
+	require.Equal(t, "Lovelace", signedIn.User.LastName)
+	require.True(t, signedIn.EmailVerified)
+}
+
+func TestOAuthRegistryEmailVerified(t *testing.T) {
+	registry := &OAuthRegistry{kinds: map[string]string{
+		"google":    OAuthGoogle,
+		"github":    OAuthGitHub,
+		"microsoft": OAuthMicrosoft,
+		"corp":      OAuthOIDC,
+	}}
+
+	tests := []struct {
+		name string
+		user goth.User
+		want bool
+	}{
+		{"google verified", goth.User{Provider: "google", Email: "a@example.com", RawData: map[string]any{"verified_email": true}}, true},
+		{"google unverified", goth.User{Provider: "google", Email: "a@example.com", RawData: map[string]any{"verified_email": false}}, false},
+		{"github", goth.User{Provider: "github", Email: "a@example.com"}, true},
+		{"microsoft", goth.User{Provider: "microsoft", Email: "a@example.com"}, false},
+		{"oidc string claim", goth.User{Provider: "corp", Email: "a@example.com", RawData: map[string]any{"email_verified": "true"}}, true},
+		{"oidc without claim", goth.User{Provider: "corp", Email: "a@example.com"}, false},
+		{"no email", goth.User{Provider: "github"}, false},
+		{"unknown provider", goth.User{Provider: "myspace", Email: "a@example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, registry.EmailVerified(tt.user))
+		})
+	}
+}
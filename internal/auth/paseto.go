@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// PasetoLocalMaker creates v4.local tokens, which are encrypted as well as authenticated
+// so clients cannot read the claims.
+type PasetoLocalMaker struct {
+	key paseto.V4SymmetricKey
+}
+
+// PasetoPublicMaker creates v4.public tokens, signed with an Ed25519 key so any service
+// holding the public key can verify them.
+type PasetoPublicMaker struct {
+	secret paseto.V4AsymmetricSecretKey
+	public paseto.V4AsymmetricPublicKey
+}
+
+// The parser skips the expiry rule so Verify can still return the claims of an expired
+// token for the refresh path.
+var pasetoParser = paseto.NewParserWithoutExpiryCheck()
+
+// NewPasetoLocalMaker creates a maker from a 32-byte key encoded as hex or base64, such as
+// the output of `openssl rand -hex 32`.
+func NewPasetoLocalMaker(encodedKey string) (Maker, error) {
+	key, err := decodePasetoLocalKey(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, err
+	}
+	return &PasetoLocalMaker{key}, nil
+}
+
+var errPasetoLocalKey = errors.New("invalid key, it must be 32 bytes encoded as hex (64 characters) or base64")
+
+// decodePasetoLocalKey tries hex first, since a 64 character hex key is also valid base64.
+func decodePasetoLocalKey(encodedKey string) (paseto.V4SymmetricKey, error) {
+	if key, err := paseto.V4SymmetricKeyFromHex(encodedKey); err == nil {
+		return key, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if raw, err := enc.DecodeString(encodedKey); err == nil {
+			if key, err := paseto.V4SymmetricKeyFromBytes(raw); err == nil {
+				return key, nil
+			}
+		}
+	}
+	return paseto.V4SymmetricKey{}, errPasetoLocalKey
+}
+
+func (maker *PasetoLocalMaker) Create(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error) {
+	token := newPasetoToken(NewPayload(userId, sessionId, email, role, tokenType, duration))
+	return token.V4Encrypt(maker.key, nil), nil
+}
+
+func (maker *PasetoLocalMaker) Verify(tokenString string) (*Payload, error) {
+	token, err := pasetoParser.ParseV4Local(maker.key, tokenString, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return payloadFromPaseto(token)
+}
+
+// NewPasetoPublicMaker creates a maker from a PEM encoded PKCS#8 Ed25519 private key, the
+// same format the asymmetric JWT maker reads.
+func NewPasetoPublicMaker(pemData []byte) (Maker, error) {
+	key, err := ParseSigningKey("paseto", time.Time{}, pemData)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := key.private.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("PASETO v4.public requires an Ed25519 private key")
+	}
+	secret, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(private)
+	if err != nil {
+		return nil, err
+	}
+	return &PasetoPublicMaker{secret: secret, public: secret.Public()}, nil
+}
+
+func (maker *PasetoPublicMaker) Create(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) (string, error) {
+	token := newPasetoToken(NewPayload(userId, sessionId, email, role, tokenType, duration))
+	return token.V4Sign(maker.secret, nil), nil
+}
+
+func (maker *PasetoPublicMaker) Verify(tokenString string) (*Payload, error) {
+	token, err := pasetoParser.ParseV4Public(maker.public, tokenString, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return payloadFromPaseto(token)
+}
+
+// newPasetoToken encodes a payload using the registered PASETO claims where one exists.
+func newPasetoToken(payload *Payload) paseto.Token {
+	token := paseto.NewToken()
+	token.SetJti(payload.ID)
+	token.SetSubject(payload.UserID.String())
+	token.SetIssuedAt(payload.IssuedAt)
+	token.SetExpiration(payload.ExpiresAt)
+	token.SetString("email", payload.Email)
+	token.SetString("session_id", payload.SessionID.String())
+	token.SetString("token_type", string(payload.TokenType))
+	token.SetString("role", payload.Role)
+	return token
+}
+
+// payloadFromPaseto decodes a verified token, returning the payload with ErrExpiredToken
+// once it has expired.
+func payloadFromPaseto(token *paseto.Token) (*Payload, error) {
+	var (
+		payload Payload
+		claims  = make(map[string]string)
+	)
+	for _, key := range []string{"jti", "sub", "email", "session_id", "token_type", "role"} {
+		value, err := token.GetString(key)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		claims[key] = value
+	}
+	issuedAt, err := token.GetIssuedAt()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	expiresAt, err := token.GetExpiration()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if payload.UserID, err = uuid.Parse(claims["sub"]); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if payload.SessionID, err = uuid.Parse(claims["session_id"]); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload.Email = claims["email"]
+	payload.TokenType = TokenType(claims["token_type"])
+	payload.Role = claims["role"]
+	payload.IssuedAt = issuedAt
+	payload.ExpiresAt = expiresAt
+	payload.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        claims["jti"],
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	if time.Now().After(expiresAt) {
+		return &payload, ErrExpiredToken
+	}
+	return &payload, nil
+}
@@ -12,6 +12,12 @@ type TokenType string
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+	// MFAPendingToken proves a password was checked while a second factor is awaited. It
+	// has no session and does not authenticate requests.
+	MFAPendingToken TokenType = "mfa_pending"
+	// OAuthLinkToken marks a provider sign in as linking the provider to the signed in
+	// user, rather than signing in with it. It does not authenticate requests either.
+	OAuthLinkToken TokenType = "oauth_link"
 )
 
 type Payload struct {
@@ -19,15 +25,18 @@ type Payload struct {
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 	UserID    uuid.UUID `json:"user_id"`
+	// SessionID is the login session the token belongs to; refreshing keeps it.
+	SessionID uuid.UUID `json:"session_id"`
 	TokenType TokenType `json:"token_type"`
 	Role      string    `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func NewPayload(userId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) *Payload {
+func NewPayload(userId, sessionId uuid.UUID, email, role string, tokenType TokenType, duration time.Duration) *Payload {
 	now := time.Now()
 	return &Payload{
 		UserID:    userId,
+		SessionID: sessionId,
 		Email:     email,
 		TokenType: tokenType,
 		Role:      role,
@@ -35,6 +44,8 @@ func NewPayload(userId uuid.UUID, email, role string, tokenType TokenType, durat
 		IssuedAt:  now,
 		ExpiresAt: now.Add(duration),
 		RegisteredClaims: jwt.RegisteredClaims{
+			// A unique ID keeps two tokens issued in the same instant distinct.
+			ID:        uuid.NewString(),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 		},
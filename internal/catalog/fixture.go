@@ -0,0 +1,129 @@
+// Package catalog contains movie.CatalogProvider adapters.
+package catalog
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// fixtureMovie is the JSON form of a catalogue entry in a fixture file.
+type fixtureMovie struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Overview    string   `json:"overview"`
+	Runtime     int32    `json:"runtime_minutes"`
+	Genres      []string `json:"genres"`
+	AgeRating   string   `json:"age_rating"`
+	Directors   []string `json:"directors"`
+	Cast        []string `json:"cast"`
+	PosterUrl   string   `json:"poster_url"`
+	ReleaseDate string   `json:"release_date"`
+}
+
+// FixtureProvider serves catalogue entries from a JSON file, so imports work offline
+// in development and tests.
+type FixtureProvider struct {
+	entries []movie.CatalogEntry
+}
+
+// NewFixtureProvider loads the fixtures at path, or the built-in fixtures if path is empty.
+func NewFixtureProvider(path string) (*FixtureProvider, error) {
+	data := defaultFixtures
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("unable to read catalogue fixtures: %w", err)
+		}
+	}
+
+	var fixtures []fixtureMovie
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("invalid catalogue fixtures: %w", err)
+	}
+
+	p := &FixtureProvider{entries: make([]movie.CatalogEntry, 0, len(fixtures))}
+	for _, f := range fixtures {
+		entry := movie.CatalogEntry{
+			ExternalID:  f.ID,
+			Title:       f.Title,
+			Description: f.Overview,
+			Runtime:     f.Runtime * 60,
+			Genres:      f.Genres,
+			AgeRating:   f.AgeRating,
+			Directors:   f.Directors,
+			Cast:        f.Cast,
+			PosterUrl:   f.PosterUrl,
+		}
+		if f.ReleaseDate != "" {
+			releaseDate, err := time.Parse("2006-01-02", f.ReleaseDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid release date for fixture %s: %w", f.ID, err)
+			}
+			entry.ReleaseDate = &releaseDate
+		}
+		p.entries = append(p.entries, entry)
+	}
+	return p, nil
+}
+
+// Search matches titles case-insensitively, exact matches first, then titles starting
+// with the query, then titles containing it.
+func (p *FixtureProvider) Search(ctx context.Context, query string) ([]movie.CatalogMatch, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	type ranked struct {
+		match movie.CatalogMatch
+		rank  int
+	}
+	var results []ranked
+	for _, e := range p.entries {
+		title := strings.ToLower(e.Title)
+		rank := 0
+		switch {
+		case title == query:
+			rank = 3
+		case strings.HasPrefix(title, query):
+			rank = 2
+		case strings.Contains(title, query):
+			rank = 1
+		default:
+			continue
+		}
+		results = append(results, ranked{
+			match: movie.CatalogMatch{
+				ExternalID:  e.ExternalID,
+				Title:       e.Title,
+				ReleaseDate: e.ReleaseDate,
+				PosterUrl:   e.PosterUrl,
+			},
+			rank: rank,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].rank > results[j].rank })
+
+	matches := make([]movie.CatalogMatch, 0, len(results))
+	for _, r := range results {
+		matches = append(matches, r.match)
+	}
+	return matches, nil
+}
+
+func (p *FixtureProvider) Lookup(ctx context.Context, externalID string) (*movie.CatalogEntry, error) {
+	for _, e := range p.entries {
+		if e.ExternalID == externalID {
+			return &e, nil
+		}
+	}
+	return nil, movie.ErrCatalogEntryNotFound
+}
@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureProviderSearch(t *testing.T) {
+	provider, err := NewFixtureProvider("")
+	require.NoError(t, err)
+
+	matches, err := provider.Search(context.Background(), "the matrix")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	// The exact title match ranks above titles that merely start with the query.
+	require.Equal(t, "603", matches[0].ExternalID)
+	require.Equal(t, "604", matches[1].ExternalID)
+
+	matches, err = provider.Search(context.Background(), "no such film")
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
+
+func TestFixtureProviderLookup(t *testing.T) {
+	provider, err := NewFixtureProvider("")
+	require.NoError(t, err)
+
+	entry, err := provider.Lookup(context.Background(), "27205")
+	require.NoError(t, err)
+	require.Equal(t, "Inception", entry.Title)
+	require.Equal(t, int32(148*60), entry.Runtime)
+
+	req := entry.ToAddMovieRequest()
+	require.Equal(t, "Action", req.Genre)
+	require.Equal(t, "Christopher Nolan", req.Director)
+	require.Equal(t, "2010-07-15", req.ReleaseDate)
+	require.Equal(t, "27205", *req.ExternalID)
+	require.NoError(t, validator.New().Struct(req))
+
+	_, err = provider.Lookup(context.Background(), "missing")
+	require.ErrorIs(t, err, movie.ErrCatalogEntryNotFound)
+}
@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+const (
+	tmdbBaseURL      = "https://api.themoviedb.org/3"
+	tmdbImageBaseURL = "https://image.tmdb.org/t/p/original"
+)
+
+// TMDBProvider looks up movies in The Movie Database using a v3 API key.
+type TMDBProvider struct {
+	apiKey string
+	// region selects which country's certification is used as the age rating.
+	region string
+	client *http.Client
+}
+
+// NewTMDBProvider creates a new TMDBProvider. region is an ISO 3166-1 country code, e.g. "US".
+func NewTMDBProvider(apiKey, region string) *TMDBProvider {
+	return &TMDBProvider{
+		apiKey: apiKey,
+		region: region,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type tmdbSearchResponse struct {
+	Results []struct {
+		ID          int64  `json:"id"`
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+		PosterPath  string `json:"poster_path"`
+	} `json:"results"`
+}
+
+type tmdbMovie struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Overview    string `json:"overview"`
+	Runtime     int32  `json:"runtime"`
+	ReleaseDate string `json:"release_date"`
+	PosterPath  string `json:"poster_path"`
+	Genres      []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Credits struct {
+		Cast []struct {
+			Name  string `json:"name"`
+			Order int    `json:"order"`
+		} `json:"cast"`
+		Crew []struct {
+			Name string `json:"name"`
+			Job  string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+	ReleaseDates struct {
+		Results []struct {
+			Country      string `json:"iso_3166_1"`
+			ReleaseDates []struct {
+				Certification string `json:"certification"`
+			} `json:"release_dates"`
+		} `json:"results"`
+	} `json:"release_dates"`
+}
+
+func (p *TMDBProvider) Search(ctx context.Context, query string) ([]movie.CatalogMatch, error) {
+	var res tmdbSearchResponse
+	if err := p.get(ctx, "/search/movie", url.Values{"query": {query}}, &res); err != nil {
+		return nil, err
+	}
+
+	matches := make([]movie.CatalogMatch, 0, len(res.Results))
+	for _, r := range res.Results {
+		matches = append(matches, movie.CatalogMatch{
+			ExternalID:  fmt.Sprint(r.ID),
+			Title:       r.Title,
+			ReleaseDate: parseDate(r.ReleaseDate),
+			PosterUrl:   posterURL(r.PosterPath),
+		})
+	}
+	return matches, nil
+}
+
+func (p *TMDBProvider) Lookup(ctx context.Context, externalID string) (*movie.CatalogEntry, error) {
+	var m tmdbMovie
+	params := url.Values{"append_to_response": {"credits,release_dates"}}
+	if err := p.get(ctx, "/movie/"+url.PathEscape(externalID), params, &m); err != nil {
+		return nil, err
+	}
+
+	entry := &movie.CatalogEntry{
+		ExternalID:  fmt.Sprint(m.ID),
+		Title:       m.Title,
+		Description: m.Overview,
+		Runtime:     m.Runtime * 60,
+		PosterUrl:   posterURL(m.PosterPath),
+		ReleaseDate: parseDate(m.ReleaseDate),
+	}
+	for _, g := range m.Genres {
+		entry.Genres = append(entry.Genres, g.Name)
+	}
+	for _, c := range m.Credits.Crew {
+		if c.Job == "Director" {
+			entry.Directors = append(entry.Directors, c.Name)
+		}
+	}
+
+	cast := m.Credits.Cast
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	for _, c := range cast {
+		entry.Cast = append(entry.Cast, c.Name)
+	}
+
+	for _, r := range m.ReleaseDates.Results {
+		if r.Country != p.region {
+			continue
+		}
+		for _, d := range r.ReleaseDates {
+			if d.Certification != "" {
+				entry.AgeRating = d.Certification
+				break
+			}
+		}
+	}
+	return entry, nil
+}
+
+func (p *TMDBProvider) get(ctx context.Context, path string, params url.Values, v any) error {
+	params.Set("api_key", p.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tmdbBaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", movie.ErrCatalogUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return movie.ErrCatalogEntryNotFound
+	default:
+		return fmt.Errorf("%w: unexpected status %d", movie.ErrCatalogUnavailable, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid response: %v", movie.ErrCatalogUnavailable, err)
+	}
+	return nil
+}
+
+func parseDate(s string) *time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func posterURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	return tmdbImageBaseURL + path
+}
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: auditoriums.sql
+
+package dbgen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuditorium = `-- name: CreateAuditorium :one
+INSERT INTO auditoriums (venue_id, name, capacity, layout, supported_formats)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, venue_id, name, capacity, layout, supported_formats, created_at, updated_at, deleted_at
+`
+
+type CreateAuditoriumParams struct {
+	VenueID          int32    `json:"venue_id"`
+	Name             string   `json:"name"`
+	Capacity         int32    `json:"capacity"`
+	Layout           []byte   `json:"layout"`
+	SupportedFormats []string `json:"supported_formats"`
+}
+
+func (q *Queries) CreateAuditorium(ctx context.Context, arg CreateAuditoriumParams) (Auditorium, error) {
+	row := q.db.QueryRow(ctx, createAuditorium,
+		arg.VenueID,
+		arg.Name,
+		arg.Capacity,
+		arg.Layout,
+		arg.SupportedFormats,
+	)
+	var i Auditorium
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		&i.Name,
+		&i.Capacity,
+		&i.Layout,
+		&i.SupportedFormats,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteAuditoriumsByVenue = `-- name: DeleteAuditoriumsByVenue :exec
+UPDATE auditoriums SET deleted_at = now() WHERE venue_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) DeleteAuditoriumsByVenue(ctx context.Context, venueID int32) error {
+	_, err := q.db.Exec(ctx, deleteAuditoriumsByVenue, venueID)
+	return err
+}
+
+const getAuditoriumById = `-- name: GetAuditoriumById :one
+SELECT a.id, a.venue_id, a.name, a.capacity, a.layout, a.supported_formats, a.created_at, a.updated_at, a.deleted_at FROM auditoriums a
+JOIN venues v ON v.id = a.venue_id
+WHERE a.id = $1
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
+`
+
+func (q *Queries) GetAuditoriumById(ctx context.Context, id int32) (Auditorium, error) {
+	row := q.db.QueryRow(ctx, getAuditoriumById, id)
+	var i Auditorium
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		&i.Name,
+		&i.Capacity,
+		&i.Layout,
+		&i.SupportedFormats,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getAuditoriumsByVenue = `-- name: GetAuditoriumsByVenue :many
+SELECT id, venue_id, name, capacity, layout, supported_formats, created_at, updated_at, deleted_at FROM auditoriums
+WHERE venue_id = $1 AND deleted_at IS NULL
+ORDER BY name
+`
+
+func (q *Queries) GetAuditoriumsByVenue(ctx context.Context, venueID int32) ([]Auditorium, error) {
+	rows, err := q.db.Query(ctx, getAuditoriumsByVenue, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Auditorium{}
+	for rows.Next() {
+		var i Auditorium
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			&i.Name,
+			&i.Capacity,
+			&i.Layout,
+			&i.SupportedFormats,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreAuditoriumsByVenue = `-- name: RestoreAuditoriumsByVenue :exec
+UPDATE auditoriums SET deleted_at = NULL
+WHERE venue_id = $1 AND deleted_at = $2
+`
+
+type RestoreAuditoriumsByVenueParams struct {
+	VenueID   int32              `json:"venue_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+// restores the auditoriums deleted together with the venue
+func (q *Queries) RestoreAuditoriumsByVenue(ctx context.Context, arg RestoreAuditoriumsByVenueParams) error {
+	_, err := q.db.Exec(ctx, restoreAuditoriumsByVenue, arg.VenueID, arg.DeletedAt)
+	return err
+}
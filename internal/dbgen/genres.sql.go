@@ -0,0 +1,217 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: genres.sql
+
+package dbgen
+
+import (
+	"context"
+)
+
+const addMovieGenre = `-- name: AddMovieGenre :exec
+INSERT INTO movie_genres (movie_id, genre_id, position)
+VALUES ($1, $2, $3)
+`
+
+type AddMovieGenreParams struct {
+	MovieID  int64 `json:"movie_id"`
+	GenreID  int32 `json:"genre_id"`
+	Position int32 `json:"position"`
+}
+
+func (q *Queries) AddMovieGenre(ctx context.Context, arg AddMovieGenreParams) error {
+	_, err := q.db.Exec(ctx, addMovieGenre, arg.MovieID, arg.GenreID, arg.Position)
+	return err
+}
+
+const createGenre = `-- name: CreateGenre :one
+INSERT INTO genres (name, slug)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING
+RETURNING id, name, slug, created_at
+`
+
+type CreateGenreParams struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+func (q *Queries) CreateGenre(ctx context.Context, arg CreateGenreParams) (Genre, error) {
+	row := q.db.QueryRow(ctx, createGenre, arg.Name, arg.Slug)
+	var i Genre
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteGenre = `-- name: DeleteGenre :execrows
+DELETE FROM genres WHERE id = $1
+`
+
+func (q *Queries) DeleteGenre(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteGenre, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMovieGenres = `-- name: DeleteMovieGenres :exec
+DELETE FROM movie_genres WHERE movie_id = $1
+`
+
+func (q *Queries) DeleteMovieGenres(ctx context.Context, movieID int64) error {
+	_, err := q.db.Exec(ctx, deleteMovieGenres, movieID)
+	return err
+}
+
+const getGenres = `-- name: GetGenres :many
+SELECT id, name, slug, created_at FROM genres ORDER BY name
+`
+
+func (q *Queries) GetGenres(ctx context.Context) ([]Genre, error) {
+	rows, err := q.db.Query(ctx, getGenres)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Genre{}
+	for rows.Next() {
+		var i Genre
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGenresByIds = `-- name: GetGenresByIds :many
+SELECT id, name, slug, created_at FROM genres WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) GetGenresByIds(ctx context.Context, ids []int32) ([]Genre, error) {
+	rows, err := q.db.Query(ctx, getGenresByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Genre{}
+	for rows.Next() {
+		var i Genre
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGenresByMovies = `-- name: GetGenresByMovies :many
+SELECT mg.movie_id, g.id, g.name, g.slug FROM movie_genres mg
+JOIN genres g ON g.id = mg.genre_id
+WHERE mg.movie_id = ANY($1::bigint[])
+ORDER BY mg.movie_id, mg.position
+`
+
+type GetGenresByMoviesRow struct {
+	MovieID int64  `json:"movie_id"`
+	ID      int32  `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+}
+
+func (q *Queries) GetGenresByMovies(ctx context.Context, movieIds []int64) ([]GetGenresByMoviesRow, error) {
+	rows, err := q.db.Query(ctx, getGenresByMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetGenresByMoviesRow{}
+	for rows.Next() {
+		var i GetGenresByMoviesRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMovieIdsByGenre = `-- name: GetMovieIdsByGenre :many
+SELECT movie_id FROM movie_genres WHERE genre_id = $1
+`
+
+func (q *Queries) GetMovieIdsByGenre(ctx context.Context, genreID int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getMovieIdsByGenre, genreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var movie_id int64
+		if err := rows.Scan(&movie_id); err != nil {
+			return nil, err
+		}
+		items = append(items, movie_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrCreateGenre = `-- name: GetOrCreateGenre :one
+INSERT INTO genres (name, slug)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+RETURNING id, name, slug, created_at
+`
+
+type GetOrCreateGenreParams struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// returns the genre with the given slug, creating it if needed
+func (q *Queries) GetOrCreateGenre(ctx context.Context, arg GetOrCreateGenreParams) (Genre, error) {
+	row := q.db.QueryRow(ctx, getOrCreateGenre, arg.Name, arg.Slug)
+	var i Genre
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,207 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: media.sql
+
+package dbgen
+
+import (
+	"context"
+)
+
+const createMediaAsset = `-- name: CreateMediaAsset :one
+INSERT INTO media_assets (movie_id, kind, original_filename, original_content_type, width, height)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, movie_id, kind, original_filename, original_content_type, width, height, created_at
+`
+
+type CreateMediaAssetParams struct {
+	MovieID             int64  `json:"movie_id"`
+	Kind                string `json:"kind"`
+	OriginalFilename    string `json:"original_filename"`
+	OriginalContentType string `json:"original_content_type"`
+	Width               int32  `json:"width"`
+	Height              int32  `json:"height"`
+}
+
+func (q *Queries) CreateMediaAsset(ctx context.Context, arg CreateMediaAssetParams) (MediaAsset, error) {
+	row := q.db.QueryRow(ctx, createMediaAsset,
+		arg.MovieID,
+		arg.Kind,
+		arg.OriginalFilename,
+		arg.OriginalContentType,
+		arg.Width,
+		arg.Height,
+	)
+	var i MediaAsset
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.Kind,
+		&i.OriginalFilename,
+		&i.OriginalContentType,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createMediaVariant = `-- name: CreateMediaVariant :one
+INSERT INTO media_variants (asset_id, name, storage_key, width, height, size_bytes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING asset_id, name, storage_key, width, height, size_bytes
+`
+
+type CreateMediaVariantParams struct {
+	AssetID    int64  `json:"asset_id"`
+	Name       string `json:"name"`
+	StorageKey string `json:"storage_key"`
+	Width      int32  `json:"width"`
+	Height     int32  `json:"height"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+func (q *Queries) CreateMediaVariant(ctx context.Context, arg CreateMediaVariantParams) (MediaVariant, error) {
+	row := q.db.QueryRow(ctx, createMediaVariant,
+		arg.AssetID,
+		arg.Name,
+		arg.StorageKey,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+	)
+	var i MediaVariant
+	err := row.Scan(
+		&i.AssetID,
+		&i.Name,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const deleteMediaAsset = `-- name: DeleteMediaAsset :execrows
+DELETE FROM media_assets WHERE id = $1
+`
+
+func (q *Queries) DeleteMediaAsset(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMediaAsset, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getMediaAssetById = `-- name: GetMediaAssetById :one
+SELECT id, movie_id, kind, original_filename, original_content_type, width, height, created_at FROM media_assets WHERE id = $1
+`
+
+func (q *Queries) GetMediaAssetById(ctx context.Context, id int64) (MediaAsset, error) {
+	row := q.db.QueryRow(ctx, getMediaAssetById, id)
+	var i MediaAsset
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.Kind,
+		&i.OriginalFilename,
+		&i.OriginalContentType,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMediaAssetsByMovie = `-- name: GetMediaAssetsByMovie :many
+SELECT id, movie_id, kind, original_filename, original_content_type, width, height, created_at FROM media_assets
+WHERE movie_id = $1
+ORDER BY kind, created_at DESC
+`
+
+func (q *Queries) GetMediaAssetsByMovie(ctx context.Context, movieID int64) ([]MediaAsset, error) {
+	rows, err := q.db.Query(ctx, getMediaAssetsByMovie, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MediaAsset{}
+	for rows.Next() {
+		var i MediaAsset
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.Kind,
+			&i.OriginalFilename,
+			&i.OriginalContentType,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMediaVariant = `-- name: GetMediaVariant :one
+SELECT asset_id, name, storage_key, width, height, size_bytes FROM media_variants WHERE asset_id = $1 AND name = $2
+`
+
+type GetMediaVariantParams struct {
+	AssetID int64  `json:"asset_id"`
+	Name    string `json:"name"`
+}
+
+func (q *Queries) GetMediaVariant(ctx context.Context, arg GetMediaVariantParams) (MediaVariant, error) {
+	row := q.db.QueryRow(ctx, getMediaVariant, arg.AssetID, arg.Name)
+	var i MediaVariant
+	err := row.Scan(
+		&i.AssetID,
+		&i.Name,
+		&i.StorageKey,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const getMediaVariantsByAssets = `-- name: GetMediaVariantsByAssets :many
+SELECT asset_id, name, storage_key, width, height, size_bytes FROM media_variants
+WHERE asset_id = ANY($1::bigint[])
+ORDER BY asset_id, width
+`
+
+func (q *Queries) GetMediaVariantsByAssets(ctx context.Context, assetIds []int64) ([]MediaVariant, error) {
+	rows, err := q.db.Query(ctx, getMediaVariantsByAssets, assetIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MediaVariant{}
+	for rows.Next() {
+		var i MediaVariant
+		if err := rows.Scan(
+			&i.AssetID,
+			&i.Name,
+			&i.StorageKey,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,184 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: mfa.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :execrows
+UPDATE user_totp
+SET confirmed_at = now()
+WHERE user_id = $1 AND confirmed_at IS NULL
+`
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, confirmUserTOTP, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countUnusedRecoveryCodes = `-- name: CountUnusedRecoveryCodes :one
+SELECT count(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnusedRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT user_id, secret_ciphertext, confirmed_at, last_used_step, failed_attempts, locked_until, created_at FROM user_totp WHERE user_id = $1
+`
+
+func (q *Queries) GetUserTOTP(ctx context.Context, userID uuid.UUID) (UserTotp, error) {
+	row := q.db.QueryRow(ctx, getUserTOTP, userID)
+	var i UserTotp
+	err := row.Scan(
+		&i.UserID,
+		&i.SecretCiphertext,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertRecoveryCodes = `-- name: InsertRecoveryCodes :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+SELECT $1::uuid, unnest($2::varchar[])
+`
+
+type InsertRecoveryCodesParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CodeHashes []string  `json:"code_hashes"`
+}
+
+func (q *Queries) InsertRecoveryCodes(ctx context.Context, arg InsertRecoveryCodesParams) error {
+	_, err := q.db.Exec(ctx, insertRecoveryCodes, arg.UserID, arg.CodeHashes)
+	return err
+}
+
+const recordTOTPFailure = `-- name: RecordTOTPFailure :exec
+UPDATE user_totp
+SET failed_attempts = CASE
+        WHEN failed_attempts + 1 >= $1::int THEN 0
+        ELSE failed_attempts + 1
+    END,
+    locked_until = CASE
+        WHEN failed_attempts + 1 >= $1::int THEN $2::timestamptz
+        ELSE locked_until
+    END
+WHERE user_id = $3
+`
+
+type RecordTOTPFailureParams struct {
+	MaxAttempts int32     `json:"max_attempts"`
+	LockUntil   time.Time `json:"lock_until"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+// counts a wrong code, locking the authenticator once max_attempts are reached in a row
+func (q *Queries) RecordTOTPFailure(ctx context.Context, arg RecordTOTPFailureParams) error {
+	_, err := q.db.Exec(ctx, recordTOTPFailure, arg.MaxAttempts, arg.LockUntil, arg.UserID)
+	return err
+}
+
+const upsertPendingTOTP = `-- name: UpsertPendingTOTP :execrows
+INSERT INTO user_totp (user_id, secret_ciphertext)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_ciphertext = EXCLUDED.secret_ciphertext,
+    last_used_step = 0,
+    failed_attempts = 0,
+    locked_until = NULL,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL
+`
+
+type UpsertPendingTOTPParams struct {
+	UserID           uuid.UUID `json:"user_id"`
+	SecretCiphertext []byte    `json:"secret_ciphertext"`
+}
+
+// starts or restarts an enrolment, leaving a confirmed authenticator alone
+func (q *Queries) UpsertPendingTOTP(ctx context.Context, arg UpsertPendingTOTPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertPendingTOTP, arg.UserID, arg.SecretCiphertext)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = $1,
+    failed_attempts = 0,
+    locked_until = NULL
+WHERE user_id = $2 AND last_used_step < $1
+`
+
+type UseTOTPStepParams struct {
+	Step   int64     `json:"step"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// accepts a code's time step only if it is later than the last one accepted, so two
+// requests racing with the same code cannot both succeed
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.Step, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
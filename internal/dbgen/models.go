@@ -55,19 +55,91 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type Auditorium struct {
+	ID               int32              `json:"id"`
+	VenueID          int32              `json:"venue_id"`
+	Name             string             `json:"name"`
+	Capacity         int32              `json:"capacity"`
+	Layout           []byte             `json:"layout"`
+	SupportedFormats []string           `json:"supported_formats"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Genre struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MediaAsset struct {
+	ID                  int64     `json:"id"`
+	MovieID             int64     `json:"movie_id"`
+	Kind                string    `json:"kind"`
+	OriginalFilename    string    `json:"original_filename"`
+	OriginalContentType string    `json:"original_content_type"`
+	Width               int32     `json:"width"`
+	Height              int32     `json:"height"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type MediaVariant struct {
+	AssetID    int64  `json:"asset_id"`
+	Name       string `json:"name"`
+	StorageKey string `json:"storage_key"`
+	Width      int32  `json:"width"`
+	Height     int32  `json:"height"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+type MfaRecoveryCode struct {
+	ID        int64              `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
 type Movie struct {
-	ID          int64              `json:"id"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	Runtime     int32              `json:"runtime"`
-	Genre       string             `json:"genre"`
-	AgeRating   string             `json:"age_rating"`
-	Director    string             `json:"director"`
-	PosterUrl   string             `json:"poster_url"`
-	ReleaseDate pgtype.Date        `json:"release_date"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	CastMembers   []string           `json:"cast_members"`
+	ExternalID    *string            `json:"external_id"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+}
+
+type MovieCredit struct {
+	MovieID       int64   `json:"movie_id"`
+	PersonID      int64   `json:"person_id"`
+	Role          string  `json:"role"`
+	CharacterName *string `json:"character_name"`
+	Position      int32   `json:"position"`
+}
+
+type MovieGenre struct {
+	MovieID  int64 `json:"movie_id"`
+	GenreID  int32 `json:"genre_id"`
+	Position int32 `json:"position"`
+}
+
+type MovieInterest struct {
+	UserID     uuid.UUID          `json:"user_id"`
+	MovieID    int64              `json:"movie_id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	NotifiedAt pgtype.Timestamptz `json:"notified_at"`
 }
 
 type Payment struct {
@@ -83,6 +155,13 @@ type Payment struct {
 	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type Person struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Reservation struct {
 	ID            int64              `json:"id"`
 	ShowtimeID    int64              `json:"showtime_id"`
@@ -97,6 +176,21 @@ type Reservation struct {
 	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type Review struct {
+	ID           int64              `json:"id"`
+	MovieID      int64              `json:"movie_id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Rating       int16              `json:"rating"`
+	Title        *string            `json:"title"`
+	Body         string             `json:"body"`
+	HiddenAt     pgtype.Timestamptz `json:"hidden_at"`
+	HiddenReason *string            `json:"hidden_reason"`
+	FlaggedAt    pgtype.Timestamptz `json:"flagged_at"`
+	FlagReason   *string            `json:"flag_reason"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Seat struct {
 	ID            int64              `json:"id"`
 	ShowtimeID    int64              `json:"showtime_id"`
@@ -109,17 +203,39 @@ type Seat struct {
 	CreatedAt     time.Time          `json:"created_at"`
 }
 
+type Session struct {
+	ID                uuid.UUID          `json:"id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	CreatedAt         time.Time          `json:"created_at"`
+	ExpiresAt         time.Time          `json:"expires_at"`
+	RevokedAt         pgtype.Timestamptz `json:"revoked_at"`
+	RevokedReason     *string            `json:"revoked_reason"`
+	CurrentTokenHash  string             `json:"current_token_hash"`
+	PreviousTokenHash *string            `json:"previous_token_hash"`
+	RotatedAt         pgtype.Timestamptz `json:"rotated_at"`
+	Device            string             `json:"device"`
+	IpAddress         string             `json:"ip_address"`
+	UserAgent         string             `json:"user_agent"`
+	LastSeenAt        time.Time          `json:"last_seen_at"`
+}
+
 type Showtime struct {
-	ID             int64              `json:"id"`
-	MovieID        int64              `json:"movie_id"`
-	StartTime      time.Time          `json:"start_time"`
-	EndTime        time.Time          `json:"end_time"`
-	AvailableSeats int32              `json:"available_seats"`
-	PricePerSeat   pgtype.Numeric     `json:"price_per_seat"`
-	VenueID        int32              `json:"venue_id"`
-	CreatedAt      time.Time          `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
 }
 
 type User struct {
@@ -135,6 +251,7 @@ type User struct {
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 	VerifiedAt      pgtype.Timestamptz `json:"verified_at"`
 	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	DateOfBirth     pgtype.Date        `json:"date_of_birth"`
 }
 
 type UserIdentity struct {
@@ -145,13 +262,76 @@ type UserIdentity struct {
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+type UserRecommendation struct {
+	UserID     uuid.UUID `json:"user_id"`
+	MovieID    int64     `json:"movie_id"`
+	Score      float64   `json:"score"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+type UserToken struct {
+	ID        int64              `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Purpose   string             `json:"purpose"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type UserTotp struct {
+	UserID           uuid.UUID          `json:"user_id"`
+	SecretCiphertext []byte             `json:"secret_ciphertext"`
+	ConfirmedAt      pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep     int64              `json:"last_used_step"`
+	FailedAttempts   int32              `json:"failed_attempts"`
+	LockedUntil      pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
 type Venue struct {
-	ID         int32              `json:"id"`
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	Address   string             `json:"address"`
+	City      string             `json:"city"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Timezone  string             `json:"timezone"`
+	Amenities []string           `json:"amenities"`
+}
+
+type VenueClosure struct {
+	ID        int32     `json:"id"`
+	VenueID   int32     `json:"venue_id"`
+	Reason    string    `json:"reason"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+	Kind      string    `json:"kind"`
+}
+
+type VenueOpeningHour struct {
+	VenueID   int32       `json:"venue_id"`
+	DayOfWeek int16       `json:"day_of_week"`
+	OpensAt   pgtype.Time `json:"opens_at"`
+	ClosesAt  pgtype.Time `json:"closes_at"`
+}
+
+type WebauthnCeremony struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         pgtype.UUID `json:"user_id"`
+	Kind           string      `json:"kind"`
+	SessionData    []byte      `json:"session_data"`
+	CredentialName string      `json:"credential_name"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+}
+
+type WebauthnCredential struct {
+	ID         []byte             `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
 	Name       string             `json:"name"`
-	Address    string             `json:"address"`
-	City       string             `json:"city"`
-	TotalSeats int32              `json:"total_seats"`
+	Credential []byte             `json:"credential"`
 	CreatedAt  time.Time          `json:"created_at"`
-	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
 }
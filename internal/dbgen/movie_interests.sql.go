@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: movie_interests.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createMovieInterest = `-- name: CreateMovieInterest :exec
+INSERT INTO movie_interests (user_id, movie_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, movie_id) DO NOTHING
+`
+
+type CreateMovieInterestParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MovieID int64     `json:"movie_id"`
+}
+
+func (q *Queries) CreateMovieInterest(ctx context.Context, arg CreateMovieInterestParams) error {
+	_, err := q.db.Exec(ctx, createMovieInterest, arg.UserID, arg.MovieID)
+	return err
+}
+
+const deleteMovieInterest = `-- name: DeleteMovieInterest :execrows
+DELETE FROM movie_interests WHERE user_id = $1 AND movie_id = $2
+`
+
+type DeleteMovieInterestParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MovieID int64     `json:"movie_id"`
+}
+
+func (q *Queries) DeleteMovieInterest(ctx context.Context, arg DeleteMovieInterestParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMovieInterest, arg.UserID, arg.MovieID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getPendingMovieInterests = `-- name: GetPendingMovieInterests :many
+SELECT i.user_id, i.movie_id, u.email, u.full_name, i.created_at
+FROM movie_interests i
+JOIN users u ON u.id = i.user_id
+WHERE i.movie_id = $1
+  AND i.notified_at IS NULL
+  AND u.deleted_at IS NULL
+ORDER BY i.created_at
+`
+
+type GetPendingMovieInterestsRow struct {
+	UserID    uuid.UUID `json:"user_id"`
+	MovieID   int64     `json:"movie_id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// interested customers who have not yet been told that tickets are on sale
+func (q *Queries) GetPendingMovieInterests(ctx context.Context, movieID int64) ([]GetPendingMovieInterestsRow, error) {
+	rows, err := q.db.Query(ctx, getPendingMovieInterests, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPendingMovieInterestsRow{}
+	for rows.Next() {
+		var i GetPendingMovieInterestsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.MovieID,
+			&i.Email,
+			&i.FullName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markMovieInterestsNotified = `-- name: MarkMovieInterestsNotified :exec
+UPDATE movie_interests SET notified_at = now()
+WHERE movie_id = $1
+  AND user_id = ANY($2::uuid[])
+`
+
+type MarkMovieInterestsNotifiedParams struct {
+	MovieID int64       `json:"movie_id"`
+	UserIds []uuid.UUID `json:"user_ids"`
+}
+
+func (q *Queries) MarkMovieInterestsNotified(ctx context.Context, arg MarkMovieInterestsNotifiedParams) error {
+	_, err := q.db.Exec(ctx, markMovieInterestsNotified, arg.MovieID, arg.UserIds)
+	return err
+}
@@ -14,9 +14,9 @@ import (
 
 const addMovie = `-- name: AddMovie :one
 INSERT INTO movies (
-title,description,runtime,genre,age_rating,director,poster_url,release_date 
-) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
-RETURNING id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at
+title,description,runtime,genre,age_rating,director,poster_url,release_date,cast_members,external_id
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+RETURNING id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at, cast_members, external_id, rating_average, rating_count
 `
 
 type AddMovieParams struct {
@@ -26,8 +26,10 @@ type AddMovieParams struct {
 	Genre       string      `json:"genre"`
 	AgeRating   string      `json:"age_rating"`
 	Director    string      `json:"director"`
-	PosterUrl   string      `json:"poster_url"`
+	PosterUrl   *string     `json:"poster_url"`
 	ReleaseDate pgtype.Date `json:"release_date"`
+	CastMembers []string    `json:"cast_members"`
+	ExternalID  *string     `json:"external_id"`
 }
 
 func (q *Queries) AddMovie(ctx context.Context, arg AddMovieParams) (Movie, error) {
@@ -40,6 +42,8 @@ func (q *Queries) AddMovie(ctx context.Context, arg AddMovieParams) (Movie, erro
 		arg.Director,
 		arg.PosterUrl,
 		arg.ReleaseDate,
+		arg.CastMembers,
+		arg.ExternalID,
 	)
 	var i Movie
 	err := row.Scan(
@@ -55,38 +59,143 @@ func (q *Queries) AddMovie(ctx context.Context, arg AddMovieParams) (Movie, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.CastMembers,
+		&i.ExternalID,
+		&i.RatingAverage,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const clearMoviePoster = `-- name: ClearMoviePoster :execrows
+UPDATE movies SET poster_url = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) ClearMoviePoster(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, clearMoviePoster, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMovie = `-- name: DeleteMovie :execrows
+UPDATE movies SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL
+`
+
+func (q *Queries) DeleteMovie(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMovie, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDeletedMovieById = `-- name: GetDeletedMovieById :one
+SELECT id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at, cast_members, external_id, rating_average, rating_count FROM movies WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedMovieById(ctx context.Context, id int64) (Movie, error) {
+	row := q.db.QueryRow(ctx, getDeletedMovieById, id)
+	var i Movie
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Runtime,
+		&i.Genre,
+		&i.AgeRating,
+		&i.Director,
+		&i.PosterUrl,
+		&i.ReleaseDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CastMembers,
+		&i.ExternalID,
+		&i.RatingAverage,
+		&i.RatingCount,
 	)
 	return i, err
 }
 
-const deleteMovie = `-- name: DeleteMovie :exec
-UPDATE movies SET deleted_at=now() WHERE id=$1
+const getDeletedMovies = `-- name: GetDeletedMovies :many
+SELECT id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at, cast_members, external_id, rating_average, rating_count FROM movies
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT $1 OFFSET $2
 `
 
-func (q *Queries) DeleteMovie(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteMovie, id)
-	return err
+type GetDeletedMoviesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// trash: soft-deleted movies, most recently deleted first
+func (q *Queries) GetDeletedMovies(ctx context.Context, arg GetDeletedMoviesParams) ([]Movie, error) {
+	rows, err := q.db.Query(ctx, getDeletedMovies, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Movie{}
+	for rows.Next() {
+		var i Movie
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Runtime,
+			&i.Genre,
+			&i.AgeRating,
+			&i.Director,
+			&i.PosterUrl,
+			&i.ReleaseDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CastMembers,
+			&i.ExternalID,
+			&i.RatingAverage,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getMovieById = `-- name: GetMovieById :one
 SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
-,created_at,updated_at
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
 FROM movies 
 WHERE id = $1 AND deleted_at IS NULL
 `
 
 type GetMovieByIdRow struct {
-	ID          int64              `json:"id"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	Runtime     int32              `json:"runtime"`
-	Genre       string             `json:"genre"`
-	AgeRating   string             `json:"age_rating"`
-	Director    string             `json:"director"`
-	PosterUrl   string             `json:"poster_url"`
-	ReleaseDate pgtype.Date        `json:"release_date"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CastMembers   []string           `json:"cast_members"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Status        string             `json:"status"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+	ExternalID    *string            `json:"external_id"`
 }
 
 func (q *Queries) GetMovieById(ctx context.Context, id int64) (GetMovieByIdRow, error) {
@@ -102,15 +211,33 @@ func (q *Queries) GetMovieById(ctx context.Context, id int64) (GetMovieByIdRow,
 		&i.Director,
 		&i.PosterUrl,
 		&i.ReleaseDate,
+		&i.CastMembers,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.RatingAverage,
+		&i.RatingCount,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
+const getMovieIdByExternalId = `-- name: GetMovieIdByExternalId :one
+SELECT id FROM movies WHERE external_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetMovieIdByExternalId(ctx context.Context, externalID *string) (int64, error) {
+	row := q.db.QueryRow(ctx, getMovieIdByExternalId, externalID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getMoviesAdmin = `-- name: GetMoviesAdmin :many
 SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
-,created_at,updated_at
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
 FROM movies 
 WHERE  deleted_at IS NULL
 ORDER BY release_date DESC 
@@ -123,17 +250,22 @@ type GetMoviesAdminParams struct {
 }
 
 type GetMoviesAdminRow struct {
-	ID          int64              `json:"id"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	Runtime     int32              `json:"runtime"`
-	Genre       string             `json:"genre"`
-	AgeRating   string             `json:"age_rating"`
-	Director    string             `json:"director"`
-	PosterUrl   string             `json:"poster_url"`
-	ReleaseDate pgtype.Date        `json:"release_date"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CastMembers   []string           `json:"cast_members"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Status        string             `json:"status"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+	ExternalID    *string            `json:"external_id"`
 }
 
 // admin listing , gets all the movies even if they aren't showing.
@@ -156,8 +288,13 @@ func (q *Queries) GetMoviesAdmin(ctx context.Context, arg GetMoviesAdminParams)
 			&i.Director,
 			&i.PosterUrl,
 			&i.ReleaseDate,
+			&i.CastMembers,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.RatingAverage,
+			&i.RatingCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -169,24 +306,167 @@ func (q *Queries) GetMoviesAdmin(ctx context.Context, arg GetMoviesAdminParams)
 	return items, nil
 }
 
-const getMoviesPublic = `-- name: GetMoviesPublic :many
-SELECT DISTINCT m.id, m.title, m.description, m.runtime, m.genre, m.age_rating, m.director, m.poster_url, m.release_date, m.created_at, m.updated_at, m.deleted_at FROM movies m
-JOIN showtimes s ON s.movie_id = m.id
-WHERE m.deleted_at IS NULL
-  AND s.start_time > now()
-  AND s.deleted_at IS NULL
-ORDER BY s.start_time ASC
+const getMoviesByIds = `-- name: GetMoviesByIds :many
+SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
+FROM movies
+WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+`
+
+type GetMoviesByIdsRow struct {
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CastMembers   []string           `json:"cast_members"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Status        string             `json:"status"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+	ExternalID    *string            `json:"external_id"`
+}
+
+func (q *Queries) GetMoviesByIds(ctx context.Context, ids []int64) ([]GetMoviesByIdsRow, error) {
+	rows, err := q.db.Query(ctx, getMoviesByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMoviesByIdsRow{}
+	for rows.Next() {
+		var i GetMoviesByIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Runtime,
+			&i.Genre,
+			&i.AgeRating,
+			&i.Director,
+			&i.PosterUrl,
+			&i.ReleaseDate,
+			&i.CastMembers,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RatingAverage,
+			&i.RatingCount,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMoviesComingSoon = `-- name: GetMoviesComingSoon :many
+SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+FROM movies
+WHERE deleted_at IS NULL
+  AND movie_status(id, release_date) IN ('announced', 'coming_soon')
+ORDER BY release_date ASC, id
 LIMIT $1 OFFSET $2
 `
 
-type GetMoviesPublicParams struct {
+type GetMoviesComingSoonParams struct {
 	Limit  int32 `json:"limit"`
 	Offset int32 `json:"offset"`
 }
 
-// public listing: only movies with at least one future showtime
+type GetMoviesComingSoonRow struct {
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CastMembers   []string           `json:"cast_members"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Status        string             `json:"status"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+}
+
+// announced and coming-soon movies, earliest release first
+func (q *Queries) GetMoviesComingSoon(ctx context.Context, arg GetMoviesComingSoonParams) ([]GetMoviesComingSoonRow, error) {
+	rows, err := q.db.Query(ctx, getMoviesComingSoon, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMoviesComingSoonRow{}
+	for rows.Next() {
+		var i GetMoviesComingSoonRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Runtime,
+			&i.Genre,
+			&i.AgeRating,
+			&i.Director,
+			&i.PosterUrl,
+			&i.ReleaseDate,
+			&i.CastMembers,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RatingAverage,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMoviesPublic = `-- name: GetMoviesPublic :many
+SELECT m.id, m.title, m.description, m.runtime, m.genre, m.age_rating, m.director, m.poster_url, m.release_date, m.created_at, m.updated_at, m.deleted_at, m.cast_members, m.external_id, m.rating_average, m.rating_count FROM movies m
+JOIN (
+  SELECT movie_id, MIN(start_time) AS next_start FROM showtimes
+  WHERE start_time > now() AND deleted_at IS NULL
+  GROUP BY movie_id
+) s ON s.movie_id = m.id
+WHERE m.deleted_at IS NULL
+ORDER BY
+  CASE WHEN $1::text = 'rating' THEN m.rating_average END DESC NULLS LAST,
+  CASE WHEN $1::text = 'rating' THEN m.rating_count END DESC,
+  s.next_start ASC, m.id
+LIMIT $3 OFFSET $2
+`
+
+type GetMoviesPublicParams struct {
+	Sort   *string `json:"sort"`
+	Offset int32   `json:"offset"`
+	Limit  int32   `json:"limit"`
+}
+
+// public listing: only movies with at least one future showtime, soonest screening first
+// unless sorted by rating
 func (q *Queries) GetMoviesPublic(ctx context.Context, arg GetMoviesPublicParams) ([]Movie, error) {
-	rows, err := q.db.Query(ctx, getMoviesPublic, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getMoviesPublic, arg.Sort, arg.Offset, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +487,220 @@ func (q *Queries) GetMoviesPublic(ctx context.Context, arg GetMoviesPublicParams
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.CastMembers,
+			&i.ExternalID,
+			&i.RatingAverage,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreMovie = `-- name: RestoreMovie :one
+UPDATE movies SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at, cast_members, external_id, rating_average, rating_count
+`
+
+func (q *Queries) RestoreMovie(ctx context.Context, id int64) (Movie, error) {
+	row := q.db.QueryRow(ctx, restoreMovie, id)
+	var i Movie
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Runtime,
+		&i.Genre,
+		&i.AgeRating,
+		&i.Director,
+		&i.PosterUrl,
+		&i.ReleaseDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CastMembers,
+		&i.ExternalID,
+		&i.RatingAverage,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const searchMovieFacets = `-- name: SearchMovieFacets :many
+WITH matches AS (
+  SELECT m.id, m.age_rating,
+    EXTRACT(YEAR FROM m.release_date)::int AS release_year,
+    movie_status(m.id, m.release_date)::text AS status
+  FROM movies m
+  WHERE m.deleted_at IS NULL
+    AND ($1::text IS NULL
+      OR movie_search_document(m.title, m.description, m.director, m.cast_members)
+        @@ websearch_to_tsquery('english', $1::text)
+      OR m.title % $1::text)
+    AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+      WHERE mg.movie_id = m.id AND (g.slug = $2 OR g.name = $2)))
+    AND ($3::text IS NULL OR m.age_rating = $3)
+    AND ($4::int IS NULL
+      OR EXTRACT(YEAR FROM m.release_date)::int = $4)
+    AND ($5::text IS NULL OR movie_status(m.id, m.release_date) = $5)
+)
+SELECT 'genre'::text AS facet, g.name::text AS value, COUNT(*) AS count FROM matches
+JOIN movie_genres mg ON mg.movie_id = matches.id
+JOIN genres g ON g.id = mg.genre_id
+GROUP BY g.name
+UNION ALL
+SELECT 'age_rating', age_rating::text, COUNT(*) FROM matches GROUP BY age_rating
+UNION ALL
+SELECT 'release_year', release_year::text, COUNT(*) FROM matches GROUP BY release_year
+UNION ALL
+SELECT 'status', status, COUNT(*) FROM matches GROUP BY status
+ORDER BY facet, count DESC, value
+`
+
+type SearchMovieFacetsParams struct {
+	Query       *string `json:"query"`
+	Genre       *string `json:"genre"`
+	AgeRating   *string `json:"age_rating"`
+	ReleaseYear *int32  `json:"release_year"`
+	Status      *string `json:"status"`
+}
+
+type SearchMovieFacetsRow struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// facet counts over the whole result set of a catalogue search, ignoring pagination
+func (q *Queries) SearchMovieFacets(ctx context.Context, arg SearchMovieFacetsParams) ([]SearchMovieFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchMovieFacets,
+		arg.Query,
+		arg.Genre,
+		arg.AgeRating,
+		arg.ReleaseYear,
+		arg.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchMovieFacetsRow{}
+	for rows.Next() {
+		var i SearchMovieFacetsRow
+		if err := rows.Scan(&i.Facet, &i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchMovies = `-- name: SearchMovies :many
+SELECT m.id, m.title, m.description, m.runtime, m.genre, m.age_rating, m.director,
+  m.poster_url, m.release_date, m.cast_members, m.created_at, m.updated_at,
+  movie_status(m.id, m.release_date)::text AS status, m.rating_average, m.rating_count,
+  (CASE WHEN $1::text IS NULL THEN 0
+    ELSE ts_rank(movie_search_document(m.title, m.description, m.director, m.cast_members),
+        websearch_to_tsquery('english', $1::text))
+      + similarity(m.title, $1::text)
+  END)::real AS rank
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND ($1::text IS NULL
+    OR movie_search_document(m.title, m.description, m.director, m.cast_members)
+      @@ websearch_to_tsquery('english', $1::text)
+    OR m.title % $1::text)
+  AND ($2::text IS NULL OR EXISTS (
+    SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND (g.slug = $2 OR g.name = $2)))
+  AND ($3::text IS NULL OR m.age_rating = $3)
+  AND ($4::int IS NULL
+    OR EXTRACT(YEAR FROM m.release_date)::int = $4)
+  AND ($5::text IS NULL OR movie_status(m.id, m.release_date) = $5)
+ORDER BY
+  CASE WHEN $6::text = 'rating' THEN m.rating_average END DESC NULLS LAST,
+  CASE WHEN $6::text = 'rating' THEN m.rating_count END DESC,
+  rank DESC, m.release_date DESC, m.id
+LIMIT $8 OFFSET $7
+`
+
+type SearchMoviesParams struct {
+	Query       *string `json:"query"`
+	Genre       *string `json:"genre"`
+	AgeRating   *string `json:"age_rating"`
+	ReleaseYear *int32  `json:"release_year"`
+	Status      *string `json:"status"`
+	Sort        *string `json:"sort"`
+	Offset      int32   `json:"offset"`
+	Limit       int32   `json:"limit"`
+}
+
+type SearchMoviesRow struct {
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Runtime       int32              `json:"runtime"`
+	Genre         string             `json:"genre"`
+	AgeRating     string             `json:"age_rating"`
+	Director      string             `json:"director"`
+	PosterUrl     *string            `json:"poster_url"`
+	ReleaseDate   pgtype.Date        `json:"release_date"`
+	CastMembers   []string           `json:"cast_members"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Status        string             `json:"status"`
+	RatingAverage *float64           `json:"rating_average"`
+	RatingCount   int32              `json:"rating_count"`
+	Rank          float32            `json:"rank"`
+}
+
+// catalogue search: full-text match on the search document, or a fuzzy trigram match on
+// the title to tolerate typos. Without a query, results are ordered by release date.
+func (q *Queries) SearchMovies(ctx context.Context, arg SearchMoviesParams) ([]SearchMoviesRow, error) {
+	rows, err := q.db.Query(ctx, searchMovies,
+		arg.Query,
+		arg.Genre,
+		arg.AgeRating,
+		arg.ReleaseYear,
+		arg.Status,
+		arg.Sort,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchMoviesRow{}
+	for rows.Next() {
+		var i SearchMoviesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Runtime,
+			&i.Genre,
+			&i.AgeRating,
+			&i.Director,
+			&i.PosterUrl,
+			&i.ReleaseDate,
+			&i.CastMembers,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RatingAverage,
+			&i.RatingCount,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -223,38 +717,36 @@ UPDATE movies SET
   title = COALESCE($1, title),
   description = COALESCE($2, description),
   runtime = COALESCE($3, runtime),
-  genre = COALESCE($4, genre),
-  age_rating = COALESCE($5, age_rating),
-  director = COALESCE($6, director),
-  poster_url = COALESCE($7, poster_url),
-  release_date = COALESCE($8, release_date),
+  age_rating = COALESCE($4, age_rating),
+  poster_url = COALESCE($5, poster_url),
+  release_date = COALESCE($6, release_date),
+  external_id = COALESCE($7, external_id),
   updated_at = now()
-WHERE id = $9 AND deleted_at IS NULL
-RETURNING id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at
+WHERE id = $8 AND deleted_at IS NULL
+RETURNING id, title, description, runtime, genre, age_rating, director, poster_url, release_date, created_at, updated_at, deleted_at, cast_members, external_id, rating_average, rating_count
 `
 
 type UpdateMovieParams struct {
 	Title       *string     `json:"title"`
 	Description *string     `json:"description"`
 	Runtime     *int32      `json:"runtime"`
-	Genre       *string     `json:"genre"`
 	AgeRating   *string     `json:"age_rating"`
-	Director    *string     `json:"director"`
 	PosterUrl   *string     `json:"poster_url"`
 	ReleaseDate pgtype.Date `json:"release_date"`
+	ExternalID  *string     `json:"external_id"`
 	ID          int64       `json:"id"`
 }
 
+// genre, director and cast_members are maintained by SyncMovieCatalogFields
 func (q *Queries) UpdateMovie(ctx context.Context, arg UpdateMovieParams) (Movie, error) {
 	row := q.db.QueryRow(ctx, updateMovie,
 		arg.Title,
 		arg.Description,
 		arg.Runtime,
-		arg.Genre,
 		arg.AgeRating,
-		arg.Director,
 		arg.PosterUrl,
 		arg.ReleaseDate,
+		arg.ExternalID,
 		arg.ID,
 	)
 	var i Movie
@@ -271,6 +763,10 @@ func (q *Queries) UpdateMovie(ctx context.Context, arg UpdateMovieParams) (Movie
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.CastMembers,
+		&i.ExternalID,
+		&i.RatingAverage,
+		&i.RatingCount,
 	)
 	return i, err
 }
@@ -0,0 +1,338 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: people.sql
+
+package dbgen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addMovieCredit = `-- name: AddMovieCredit :exec
+INSERT INTO movie_credits (movie_id, person_id, role, character_name, position)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (movie_id, person_id, role) DO NOTHING
+`
+
+type AddMovieCreditParams struct {
+	MovieID       int64   `json:"movie_id"`
+	PersonID      int64   `json:"person_id"`
+	Role          string  `json:"role"`
+	CharacterName *string `json:"character_name"`
+	Position      int32   `json:"position"`
+}
+
+func (q *Queries) AddMovieCredit(ctx context.Context, arg AddMovieCreditParams) error {
+	_, err := q.db.Exec(ctx, addMovieCredit,
+		arg.MovieID,
+		arg.PersonID,
+		arg.Role,
+		arg.CharacterName,
+		arg.Position,
+	)
+	return err
+}
+
+const createPerson = `-- name: CreatePerson :one
+INSERT INTO people (name)
+VALUES ($1)
+RETURNING id, name, created_at, updated_at
+`
+
+func (q *Queries) CreatePerson(ctx context.Context, name string) (Person, error) {
+	row := q.db.QueryRow(ctx, createPerson, name)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteMovieCredits = `-- name: DeleteMovieCredits :exec
+DELETE FROM movie_credits WHERE movie_id = $1
+`
+
+func (q *Queries) DeleteMovieCredits(ctx context.Context, movieID int64) error {
+	_, err := q.db.Exec(ctx, deleteMovieCredits, movieID)
+	return err
+}
+
+const deleteMovieCreditsByRole = `-- name: DeleteMovieCreditsByRole :exec
+DELETE FROM movie_credits WHERE movie_id = $1 AND role = $2
+`
+
+type DeleteMovieCreditsByRoleParams struct {
+	MovieID int64  `json:"movie_id"`
+	Role    string `json:"role"`
+}
+
+func (q *Queries) DeleteMovieCreditsByRole(ctx context.Context, arg DeleteMovieCreditsByRoleParams) error {
+	_, err := q.db.Exec(ctx, deleteMovieCreditsByRole, arg.MovieID, arg.Role)
+	return err
+}
+
+const getCreditsByMovies = `-- name: GetCreditsByMovies :many
+SELECT c.movie_id, c.person_id, p.name, c.role, c.character_name, c.position
+FROM movie_credits c
+JOIN people p ON p.id = c.person_id
+WHERE c.movie_id = ANY($1::bigint[])
+ORDER BY c.movie_id, c.role, c.position
+`
+
+type GetCreditsByMoviesRow struct {
+	MovieID       int64   `json:"movie_id"`
+	PersonID      int64   `json:"person_id"`
+	Name          string  `json:"name"`
+	Role          string  `json:"role"`
+	CharacterName *string `json:"character_name"`
+	Position      int32   `json:"position"`
+}
+
+func (q *Queries) GetCreditsByMovies(ctx context.Context, movieIds []int64) ([]GetCreditsByMoviesRow, error) {
+	rows, err := q.db.Query(ctx, getCreditsByMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCreditsByMoviesRow{}
+	for rows.Next() {
+		var i GetCreditsByMoviesRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.PersonID,
+			&i.Name,
+			&i.Role,
+			&i.CharacterName,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilmography = `-- name: GetFilmography :many
+SELECT m.id, m.title, m.poster_url, m.release_date, c.role, c.character_name
+FROM movie_credits c
+JOIN movies m ON m.id = c.movie_id
+WHERE c.person_id = $1 AND m.deleted_at IS NULL
+ORDER BY m.release_date DESC, c.role
+`
+
+type GetFilmographyRow struct {
+	ID            int64       `json:"id"`
+	Title         string      `json:"title"`
+	PosterUrl     *string     `json:"poster_url"`
+	ReleaseDate   pgtype.Date `json:"release_date"`
+	Role          string      `json:"role"`
+	CharacterName *string     `json:"character_name"`
+}
+
+func (q *Queries) GetFilmography(ctx context.Context, personID int64) ([]GetFilmographyRow, error) {
+	rows, err := q.db.Query(ctx, getFilmography, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetFilmographyRow{}
+	for rows.Next() {
+		var i GetFilmographyRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.PosterUrl,
+			&i.ReleaseDate,
+			&i.Role,
+			&i.CharacterName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMovieIdsByPerson = `-- name: GetMovieIdsByPerson :many
+SELECT DISTINCT movie_id FROM movie_credits WHERE person_id = $1
+`
+
+func (q *Queries) GetMovieIdsByPerson(ctx context.Context, personID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getMovieIdsByPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var movie_id int64
+		if err := rows.Scan(&movie_id); err != nil {
+			return nil, err
+		}
+		items = append(items, movie_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPeopleByIds = `-- name: GetPeopleByIds :many
+SELECT id, name, created_at, updated_at FROM people WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) GetPeopleByIds(ctx context.Context, ids []int64) ([]Person, error) {
+	rows, err := q.db.Query(ctx, getPeopleByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Person{}
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonById = `-- name: GetPersonById :one
+SELECT id, name, created_at, updated_at FROM people WHERE id = $1
+`
+
+func (q *Queries) GetPersonById(ctx context.Context, id int64) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonById, id)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPersonByName = `-- name: GetPersonByName :one
+SELECT id, name, created_at, updated_at FROM people WHERE name = $1 ORDER BY id LIMIT 1
+`
+
+// used when free-text names are given for a movie's director or cast
+func (q *Queries) GetPersonByName(ctx context.Context, name string) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonByName, name)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const searchPeople = `-- name: SearchPeople :many
+SELECT id, name, created_at, updated_at FROM people
+WHERE $1::text IS NULL OR name ILIKE '%' || $1::text || '%'
+ORDER BY name
+LIMIT $3 OFFSET $2
+`
+
+type SearchPeopleParams struct {
+	Query  *string `json:"query"`
+	Offset int32   `json:"offset"`
+	Limit  int32   `json:"limit"`
+}
+
+func (q *Queries) SearchPeople(ctx context.Context, arg SearchPeopleParams) ([]Person, error) {
+	rows, err := q.db.Query(ctx, searchPeople, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Person{}
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncMovieCatalogFields = `-- name: SyncMovieCatalogFields :exec
+UPDATE movies m SET
+  genre = COALESCE((
+    SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id ORDER BY mg.position LIMIT 1), ''),
+  director = COALESCE((
+    SELECT string_agg(p.name, ', ' ORDER BY c.position) FROM movie_credits c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.movie_id = m.id AND c.role = 'director'), ''),
+  cast_members = COALESCE((
+    SELECT array_agg(p.name ORDER BY c.position) FROM movie_credits c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.movie_id = m.id AND c.role = 'cast'), '{}'),
+  updated_at = now()
+WHERE m.id = ANY($1::bigint[])
+`
+
+// refreshes the denormalized genre, director and cast_members columns from genres and credits
+func (q *Queries) SyncMovieCatalogFields(ctx context.Context, movieIds []int64) error {
+	_, err := q.db.Exec(ctx, syncMovieCatalogFields, movieIds)
+	return err
+}
+
+const updatePerson = `-- name: UpdatePerson :one
+UPDATE people SET
+  name = COALESCE($1, name),
+  updated_at = now()
+WHERE id = $2
+RETURNING id, name, created_at, updated_at
+`
+
+type UpdatePersonParams struct {
+	Name *string `json:"name"`
+	ID   int64   `json:"id"`
+}
+
+func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) (Person, error) {
+	row := q.db.QueryRow(ctx, updatePerson, arg.Name, arg.ID)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,307 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: recommendations.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteAllRecommendations = `-- name: DeleteAllRecommendations :exec
+DELETE FROM user_recommendations
+`
+
+func (q *Queries) DeleteAllRecommendations(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteAllRecommendations)
+	return err
+}
+
+const getBookingHistory = `-- name: GetBookingHistory :many
+SELECT r.user_id, s.movie_id, SUM(r.number_of_seats)::int AS seats
+FROM reservations r
+JOIN showtimes s ON s.id = r.showtime_id
+WHERE r.status = 'confirmed'
+  AND r.deleted_at IS NULL
+GROUP BY r.user_id, s.movie_id
+`
+
+type GetBookingHistoryRow struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MovieID int64     `json:"movie_id"`
+	Seats   int32     `json:"seats"`
+}
+
+// confirmed bookings per customer and movie
+func (q *Queries) GetBookingHistory(ctx context.Context) ([]GetBookingHistoryRow, error) {
+	rows, err := q.db.Query(ctx, getBookingHistory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetBookingHistoryRow{}
+	for rows.Next() {
+		var i GetBookingHistoryRow
+		if err := rows.Scan(&i.UserID, &i.MovieID, &i.Seats); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMovieFeatures = `-- name: GetMovieFeatures :many
+SELECT movie_id, 'genre'::text AS kind, genre_id::bigint AS feature_id
+FROM movie_genres
+WHERE movie_id = ANY($1::bigint[])
+UNION ALL
+SELECT movie_id, 'director'::text AS kind, person_id AS feature_id
+FROM movie_credits
+WHERE role = 'director' AND movie_id = ANY($1::bigint[])
+`
+
+type GetMovieFeaturesRow struct {
+	MovieID   int64  `json:"movie_id"`
+	Kind      string `json:"kind"`
+	FeatureID int64  `json:"feature_id"`
+}
+
+// genres and directors of the given movies, the features recommendations are scored on
+func (q *Queries) GetMovieFeatures(ctx context.Context, movieIds []int64) ([]GetMovieFeaturesRow, error) {
+	rows, err := q.db.Query(ctx, getMovieFeatures, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMovieFeaturesRow{}
+	for rows.Next() {
+		var i GetMovieFeaturesRow
+		if err := rows.Scan(&i.MovieID, &i.Kind, &i.FeatureID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPopularMovies = `-- name: GetPopularMovies :many
+SELECT m.id AS movie_id, m.rating_average,
+  COALESCE((
+    SELECT SUM(r.number_of_seats) FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+      AND r.confirmed_at > now() - INTERVAL '30 days'
+  ), 0)::bigint AS tickets_sold
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.user_id = $1
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM reviews rv
+    WHERE rv.movie_id = m.id AND rv.user_id = $1 AND rv.hidden_at IS NULL
+  )
+ORDER BY tickets_sold DESC, m.rating_average DESC NULLS LAST, m.id
+LIMIT $2
+`
+
+type GetPopularMoviesParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+}
+
+type GetPopularMoviesRow struct {
+	MovieID       int64    `json:"movie_id"`
+	RatingAverage *float64 `json:"rating_average"`
+	TicketsSold   int64    `json:"tickets_sold"`
+}
+
+// cold-start fallback: movies now showing by tickets sold over the last 30 days, leaving
+// out those the customer already booked or reviewed, as scoring does
+func (q *Queries) GetPopularMovies(ctx context.Context, arg GetPopularMoviesParams) ([]GetPopularMoviesRow, error) {
+	rows, err := q.db.Query(ctx, getPopularMovies, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPopularMoviesRow{}
+	for rows.Next() {
+		var i GetPopularMoviesRow
+		if err := rows.Scan(&i.MovieID, &i.RatingAverage, &i.TicketsSold); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecommendationCandidates = `-- name: GetRecommendationCandidates :many
+SELECT m.id, m.rating_average,
+  COALESCE((
+    SELECT SUM(r.number_of_seats) FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+      AND r.confirmed_at > now() - INTERVAL '30 days'
+  ), 0)::bigint AS tickets_sold
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+`
+
+type GetRecommendationCandidatesRow struct {
+	ID            int64    `json:"id"`
+	RatingAverage *float64 `json:"rating_average"`
+	TicketsSold   int64    `json:"tickets_sold"`
+}
+
+// movies now showing, with tickets sold for them over the last 30 days
+func (q *Queries) GetRecommendationCandidates(ctx context.Context) ([]GetRecommendationCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, getRecommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRecommendationCandidatesRow{}
+	for rows.Next() {
+		var i GetRecommendationCandidatesRow
+		if err := rows.Scan(&i.ID, &i.RatingAverage, &i.TicketsSold); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecommendationsComputedAt = `-- name: GetRecommendationsComputedAt :one
+SELECT computed_at FROM user_recommendations ORDER BY computed_at DESC LIMIT 1
+`
+
+func (q *Queries) GetRecommendationsComputedAt(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getRecommendationsComputedAt)
+	var computed_at time.Time
+	err := row.Scan(&computed_at)
+	return computed_at, err
+}
+
+const getReviewHistory = `-- name: GetReviewHistory :many
+SELECT user_id, movie_id, rating FROM reviews WHERE hidden_at IS NULL
+`
+
+type GetReviewHistoryRow struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MovieID int64     `json:"movie_id"`
+	Rating  int16     `json:"rating"`
+}
+
+func (q *Queries) GetReviewHistory(ctx context.Context) ([]GetReviewHistoryRow, error) {
+	rows, err := q.db.Query(ctx, getReviewHistory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReviewHistoryRow{}
+	for rows.Next() {
+		var i GetReviewHistoryRow
+		if err := rows.Scan(&i.UserID, &i.MovieID, &i.Rating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRecommendations = `-- name: GetUserRecommendations :many
+SELECT ur.movie_id, ur.score
+FROM user_recommendations ur
+JOIN movies m ON m.id = ur.movie_id
+WHERE ur.user_id = $1
+  AND m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+ORDER BY ur.score DESC, ur.movie_id
+LIMIT $2
+`
+
+type GetUserRecommendationsParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+}
+
+type GetUserRecommendationsRow struct {
+	MovieID int64   `json:"movie_id"`
+	Score   float64 `json:"score"`
+}
+
+// a customer's scored movies that are still showing, best first
+func (q *Queries) GetUserRecommendations(ctx context.Context, arg GetUserRecommendationsParams) ([]GetUserRecommendationsRow, error) {
+	rows, err := q.db.Query(ctx, getUserRecommendations, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserRecommendationsRow{}
+	for rows.Next() {
+		var i GetUserRecommendationsRow
+		if err := rows.Scan(&i.MovieID, &i.Score); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertRecommendations = `-- name: InsertRecommendations :exec
+INSERT INTO user_recommendations (user_id, movie_id, score)
+SELECT unnest($1::uuid[]), unnest($2::bigint[]), unnest($3::float8[])
+`
+
+type InsertRecommendationsParams struct {
+	UserIds  []uuid.UUID `json:"user_ids"`
+	MovieIds []int64     `json:"movie_ids"`
+	Scores   []float64   `json:"scores"`
+}
+
+func (q *Queries) InsertRecommendations(ctx context.Context, arg InsertRecommendationsParams) error {
+	_, err := q.db.Exec(ctx, insertRecommendations, arg.UserIds, arg.MovieIds, arg.Scores)
+	return err
+}
@@ -0,0 +1,420 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reviews.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createReview = `-- name: CreateReview :one
+INSERT INTO reviews (movie_id, user_id, rating, title, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, movie_id, user_id, rating, title, body, hidden_at, hidden_reason, flagged_at, flag_reason, created_at, updated_at
+`
+
+type CreateReviewParams struct {
+	MovieID int64     `json:"movie_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	Rating  int16     `json:"rating"`
+	Title   *string   `json:"title"`
+	Body    string    `json:"body"`
+}
+
+func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error) {
+	row := q.db.QueryRow(ctx, createReview,
+		arg.MovieID,
+		arg.UserID,
+		arg.Rating,
+		arg.Title,
+		arg.Body,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.HiddenAt,
+		&i.HiddenReason,
+		&i.FlaggedAt,
+		&i.FlagReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteReview = `-- name: DeleteReview :one
+DELETE FROM reviews WHERE id = $1
+RETURNING movie_id
+`
+
+func (q *Queries) DeleteReview(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteReview, id)
+	var movie_id int64
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const flagReview = `-- name: FlagReview :one
+UPDATE reviews SET flagged_at = now(), flag_reason = $2 WHERE id = $1
+RETURNING movie_id
+`
+
+type FlagReviewParams struct {
+	ID         int64   `json:"id"`
+	FlagReason *string `json:"flag_reason"`
+}
+
+func (q *Queries) FlagReview(ctx context.Context, arg FlagReviewParams) (int64, error) {
+	row := q.db.QueryRow(ctx, flagReview, arg.ID, arg.FlagReason)
+	var movie_id int64
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const getReviewById = `-- name: GetReviewById :one
+SELECT r.id, r.movie_id, r.user_id, r.rating, r.title, r.body, r.hidden_at, r.hidden_reason, r.flagged_at, r.flag_reason, r.created_at, r.updated_at, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE r.id = $1
+`
+
+type GetReviewByIdRow struct {
+	ID           int64              `json:"id"`
+	MovieID      int64              `json:"movie_id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Rating       int16              `json:"rating"`
+	Title        *string            `json:"title"`
+	Body         string             `json:"body"`
+	HiddenAt     pgtype.Timestamptz `json:"hidden_at"`
+	HiddenReason *string            `json:"hidden_reason"`
+	FlaggedAt    pgtype.Timestamptz `json:"flagged_at"`
+	FlagReason   *string            `json:"flag_reason"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	AuthorName   string             `json:"author_name"`
+}
+
+func (q *Queries) GetReviewById(ctx context.Context, id int64) (GetReviewByIdRow, error) {
+	row := q.db.QueryRow(ctx, getReviewById, id)
+	var i GetReviewByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.HiddenAt,
+		&i.HiddenReason,
+		&i.FlaggedAt,
+		&i.FlagReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AuthorName,
+	)
+	return i, err
+}
+
+const getReviewByMovieAndUser = `-- name: GetReviewByMovieAndUser :one
+SELECT id, movie_id, user_id, rating, title, body, hidden_at, hidden_reason, flagged_at, flag_reason, created_at, updated_at FROM reviews WHERE movie_id = $1 AND user_id = $2
+`
+
+type GetReviewByMovieAndUserParams struct {
+	MovieID int64     `json:"movie_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetReviewByMovieAndUser(ctx context.Context, arg GetReviewByMovieAndUserParams) (Review, error) {
+	row := q.db.QueryRow(ctx, getReviewByMovieAndUser, arg.MovieID, arg.UserID)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.HiddenAt,
+		&i.HiddenReason,
+		&i.FlaggedAt,
+		&i.FlagReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getReviewsAdmin = `-- name: GetReviewsAdmin :many
+SELECT r.id, r.movie_id, r.user_id, r.rating, r.title, r.body, r.hidden_at, r.hidden_reason, r.flagged_at, r.flag_reason, r.created_at, r.updated_at, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE ($1::bigint IS NULL OR r.movie_id = $1)
+  AND ($2::boolean IS NULL OR (r.hidden_at IS NOT NULL) = $2)
+  AND ($3::boolean IS NULL OR (r.flagged_at IS NOT NULL) = $3)
+ORDER BY r.flagged_at DESC NULLS LAST, r.created_at DESC, r.id DESC
+LIMIT $5 OFFSET $4
+`
+
+type GetReviewsAdminParams struct {
+	MovieID *int64 `json:"movie_id"`
+	Hidden  *bool  `json:"hidden"`
+	Flagged *bool  `json:"flagged"`
+	Offset  int32  `json:"offset"`
+	Limit   int32  `json:"limit"`
+}
+
+type GetReviewsAdminRow struct {
+	ID           int64              `json:"id"`
+	MovieID      int64              `json:"movie_id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Rating       int16              `json:"rating"`
+	Title        *string            `json:"title"`
+	Body         string             `json:"body"`
+	HiddenAt     pgtype.Timestamptz `json:"hidden_at"`
+	HiddenReason *string            `json:"hidden_reason"`
+	FlaggedAt    pgtype.Timestamptz `json:"flagged_at"`
+	FlagReason   *string            `json:"flag_reason"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	AuthorName   string             `json:"author_name"`
+}
+
+// moderation queue: all reviews, optionally narrowed to a movie, hidden or flagged reviews
+func (q *Queries) GetReviewsAdmin(ctx context.Context, arg GetReviewsAdminParams) ([]GetReviewsAdminRow, error) {
+	rows, err := q.db.Query(ctx, getReviewsAdmin,
+		arg.MovieID,
+		arg.Hidden,
+		arg.Flagged,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReviewsAdminRow{}
+	for rows.Next() {
+		var i GetReviewsAdminRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.UserID,
+			&i.Rating,
+			&i.Title,
+			&i.Body,
+			&i.HiddenAt,
+			&i.HiddenReason,
+			&i.FlaggedAt,
+			&i.FlagReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewsByMovie = `-- name: GetReviewsByMovie :many
+SELECT r.id, r.movie_id, r.user_id, r.rating, r.title, r.body, r.hidden_at, r.hidden_reason, r.flagged_at, r.flag_reason, r.created_at, r.updated_at, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE r.movie_id = $1 AND r.hidden_at IS NULL
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetReviewsByMovieParams struct {
+	MovieID int64 `json:"movie_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+type GetReviewsByMovieRow struct {
+	ID           int64              `json:"id"`
+	MovieID      int64              `json:"movie_id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Rating       int16              `json:"rating"`
+	Title        *string            `json:"title"`
+	Body         string             `json:"body"`
+	HiddenAt     pgtype.Timestamptz `json:"hidden_at"`
+	HiddenReason *string            `json:"hidden_reason"`
+	FlaggedAt    pgtype.Timestamptz `json:"flagged_at"`
+	FlagReason   *string            `json:"flag_reason"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	AuthorName   string             `json:"author_name"`
+}
+
+// visible reviews of a movie, newest first
+func (q *Queries) GetReviewsByMovie(ctx context.Context, arg GetReviewsByMovieParams) ([]GetReviewsByMovieRow, error) {
+	rows, err := q.db.Query(ctx, getReviewsByMovie, arg.MovieID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReviewsByMovieRow{}
+	for rows.Next() {
+		var i GetReviewsByMovieRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.UserID,
+			&i.Rating,
+			&i.Title,
+			&i.Body,
+			&i.HiddenAt,
+			&i.HiddenReason,
+			&i.FlaggedAt,
+			&i.FlagReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hasAttendedMovie = `-- name: HasAttendedMovie :one
+SELECT EXISTS (
+  SELECT 1 FROM reservations r
+  JOIN showtimes s ON s.id = r.showtime_id
+  WHERE r.user_id = $1
+    AND s.movie_id = $2
+    AND r.status = 'confirmed'
+    AND r.deleted_at IS NULL
+    AND s.end_time < now()
+)
+`
+
+type HasAttendedMovieParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MovieID int64     `json:"movie_id"`
+}
+
+// a customer attended a movie if they hold a confirmed reservation for a showtime that has ended
+func (q *Queries) HasAttendedMovie(ctx context.Context, arg HasAttendedMovieParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasAttendedMovie, arg.UserID, arg.MovieID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const hideReview = `-- name: HideReview :one
+UPDATE reviews SET hidden_at = now(), hidden_reason = $2 WHERE id = $1
+RETURNING movie_id
+`
+
+type HideReviewParams struct {
+	ID           int64   `json:"id"`
+	HiddenReason *string `json:"hidden_reason"`
+}
+
+func (q *Queries) HideReview(ctx context.Context, arg HideReviewParams) (int64, error) {
+	row := q.db.QueryRow(ctx, hideReview, arg.ID, arg.HiddenReason)
+	var movie_id int64
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const syncMovieRating = `-- name: SyncMovieRating :exec
+UPDATE movies m SET
+  rating_average = (SELECT AVG(r.rating)::float8 FROM reviews r
+    WHERE r.movie_id = m.id AND r.hidden_at IS NULL),
+  rating_count = (SELECT COUNT(*) FROM reviews r
+    WHERE r.movie_id = m.id AND r.hidden_at IS NULL)
+WHERE m.id = $1
+`
+
+func (q *Queries) SyncMovieRating(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, syncMovieRating, id)
+	return err
+}
+
+const unflagReview = `-- name: UnflagReview :one
+UPDATE reviews SET flagged_at = NULL, flag_reason = NULL WHERE id = $1
+RETURNING movie_id
+`
+
+func (q *Queries) UnflagReview(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, unflagReview, id)
+	var movie_id int64
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const unhideReview = `-- name: UnhideReview :one
+UPDATE reviews SET hidden_at = NULL, hidden_reason = NULL WHERE id = $1
+RETURNING movie_id
+`
+
+func (q *Queries) UnhideReview(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, unhideReview, id)
+	var movie_id int64
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const updateReview = `-- name: UpdateReview :one
+UPDATE reviews SET
+  rating = COALESCE($1, rating),
+  title = COALESCE($2, title),
+  body = COALESCE($3, body),
+  updated_at = now()
+WHERE id = $4
+RETURNING id, movie_id, user_id, rating, title, body, hidden_at, hidden_reason, flagged_at, flag_reason, created_at, updated_at
+`
+
+type UpdateReviewParams struct {
+	Rating *int16  `json:"rating"`
+	Title  *string `json:"title"`
+	Body   *string `json:"body"`
+	ID     int64   `json:"id"`
+}
+
+func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Review, error) {
+	row := q.db.QueryRow(ctx, updateReview,
+		arg.Rating,
+		arg.Title,
+		arg.Body,
+		arg.ID,
+	)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.UserID,
+		&i.Rating,
+		&i.Title,
+		&i.Body,
+		&i.HiddenAt,
+		&i.HiddenReason,
+		&i.FlaggedAt,
+		&i.FlagReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
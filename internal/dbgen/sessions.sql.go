@@ -0,0 +1,221 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: sessions.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, current_token_hash, device, ip_address, user_agent, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, created_at, expires_at, revoked_at, revoked_reason, current_token_hash, previous_token_hash, rotated_at, device, ip_address, user_agent, last_seen_at
+`
+
+type CreateSessionParams struct {
+	ID               uuid.UUID `json:"id"`
+	UserID           uuid.UUID `json:"user_id"`
+	CurrentTokenHash string    `json:"current_token_hash"`
+	Device           string    `json:"device"`
+	IpAddress        string    `json:"ip_address"`
+	UserAgent        string    `json:"user_agent"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.CurrentTokenHash,
+		arg.Device,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.ExpiresAt,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.RevokedReason,
+		&i.CurrentTokenHash,
+		&i.PreviousTokenHash,
+		&i.RotatedAt,
+		&i.Device,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const getActiveSessionsByUser = `-- name: GetActiveSessionsByUser :many
+SELECT id, user_id, created_at, expires_at, revoked_at, revoked_reason, current_token_hash, previous_token_hash, rotated_at, device, ip_address, user_agent, last_seen_at FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_seen_at DESC
+`
+
+func (q *Queries) GetActiveSessionsByUser(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	rows, err := q.db.Query(ctx, getActiveSessionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.RevokedReason,
+			&i.CurrentTokenHash,
+			&i.PreviousTokenHash,
+			&i.RotatedAt,
+			&i.Device,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSessionById = `-- name: GetSessionById :one
+SELECT id, user_id, created_at, expires_at, revoked_at, revoked_reason, current_token_hash, previous_token_hash, rotated_at, device, ip_address, user_agent, last_seen_at FROM sessions WHERE id = $1
+`
+
+func (q *Queries) GetSessionById(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionById, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.RevokedReason,
+		&i.CurrentTokenHash,
+		&i.PreviousTokenHash,
+		&i.RotatedAt,
+		&i.Device,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $2
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+type RevokeSessionParams struct {
+	ID            uuid.UUID `json:"id"`
+	RevokedReason *string   `json:"revoked_reason"`
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeSession, arg.ID, arg.RevokedReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserSession = `-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeUserSessionParams struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	RevokedReason *string   `json:"revoked_reason"`
+}
+
+func (q *Queries) RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserSession, arg.ID, arg.UserID, arg.RevokedReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $2
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+type RevokeUserSessionsParams struct {
+	UserID        uuid.UUID `json:"user_id"`
+	RevokedReason *string   `json:"revoked_reason"`
+}
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserSessions, arg.UserID, arg.RevokedReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateSession = `-- name: RotateSession :execrows
+UPDATE sessions
+SET previous_token_hash = current_token_hash,
+    current_token_hash = $1,
+    rotated_at = now(),
+    last_seen_at = now(),
+    ip_address = $2,
+    user_agent = $3,
+    expires_at = $4
+WHERE id = $5
+  AND current_token_hash = $6
+  AND revoked_at IS NULL
+`
+
+type RotateSessionParams struct {
+	NewTokenHash string    `json:"new_token_hash"`
+	IpAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ID           uuid.UUID `json:"id"`
+	TokenHash    string    `json:"token_hash"`
+}
+
+// replaces the session's refresh token, only if it is still the one presented, so two
+// refreshes racing with the same token cannot both rotate
+func (q *Queries) RotateSession(ctx context.Context, arg RotateSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateSession,
+		arg.NewTokenHash,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.ExpiresAt,
+		arg.ID,
+		arg.TokenHash,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -12,19 +12,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countFutureShowtimesByMovie = `-- name: CountFutureShowtimesByMovie :one
+SELECT COUNT(*) FROM showtimes
+WHERE movie_id = $1
+  AND start_time > now()
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) CountFutureShowtimesByMovie(ctx context.Context, movieID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countFutureShowtimesByMovie, movieID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createShowtime = `-- name: CreateShowtime :one
-INSERT INTO showtimes (movie_id, start_time, end_time, available_seats, price_per_seat, venue_id)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, movie_id, start_time, end_time, available_seats, price_per_seat, venue_id, created_at, updated_at, deleted_at
+INSERT INTO showtimes (
+  movie_id, start_time, end_time, available_seats, price_per_seat, auditorium_id,
+  format, audio_language, subtitle_language, is_dubbed, audio_described, relaxed_screening
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, movie_id, start_time, end_time, available_seats, price_per_seat, created_at, updated_at, deleted_at, format, audio_language, subtitle_language, is_dubbed, audio_described, relaxed_screening, auditorium_id
 `
 
 type CreateShowtimeParams struct {
-	MovieID        int64          `json:"movie_id"`
-	StartTime      time.Time      `json:"start_time"`
-	EndTime        time.Time      `json:"end_time"`
-	AvailableSeats int32          `json:"available_seats"`
-	PricePerSeat   pgtype.Numeric `json:"price_per_seat"`
-	VenueID        int32          `json:"venue_id"`
+	MovieID          int64          `json:"movie_id"`
+	StartTime        time.Time      `json:"start_time"`
+	EndTime          time.Time      `json:"end_time"`
+	AvailableSeats   int32          `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric `json:"price_per_seat"`
+	AuditoriumID     int32          `json:"auditorium_id"`
+	Format           string         `json:"format"`
+	AudioLanguage    *string        `json:"audio_language"`
+	SubtitleLanguage *string        `json:"subtitle_language"`
+	IsDubbed         bool           `json:"is_dubbed"`
+	AudioDescribed   bool           `json:"audio_described"`
+	RelaxedScreening bool           `json:"relaxed_screening"`
 }
 
 func (q *Queries) CreateShowtime(ctx context.Context, arg CreateShowtimeParams) (Showtime, error) {
@@ -34,7 +57,13 @@ func (q *Queries) CreateShowtime(ctx context.Context, arg CreateShowtimeParams)
 		arg.EndTime,
 		arg.AvailableSeats,
 		arg.PricePerSeat,
-		arg.VenueID,
+		arg.AuditoriumID,
+		arg.Format,
+		arg.AudioLanguage,
+		arg.SubtitleLanguage,
+		arg.IsDubbed,
+		arg.AudioDescribed,
+		arg.RelaxedScreening,
 	)
 	var i Showtime
 	err := row.Scan(
@@ -44,30 +73,252 @@ func (q *Queries) CreateShowtime(ctx context.Context, arg CreateShowtimeParams)
 		&i.EndTime,
 		&i.AvailableSeats,
 		&i.PricePerSeat,
-		&i.VenueID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Format,
+		&i.AudioLanguage,
+		&i.SubtitleLanguage,
+		&i.IsDubbed,
+		&i.AudioDescribed,
+		&i.RelaxedScreening,
+		&i.AuditoriumID,
 	)
 	return i, err
 }
 
-const deleteShowtime = `-- name: DeleteShowtime :exec
-UPDATE showtimes SET deleted_at = now() WHERE id = $1
+const deleteFutureShowtimesByMovie = `-- name: DeleteFutureShowtimesByMovie :many
+UPDATE showtimes SET deleted_at = now()
+WHERE movie_id = $1
+  AND start_time > now()
+  AND deleted_at IS NULL
+RETURNING id
 `
 
-func (q *Queries) DeleteShowtime(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteShowtime, id)
-	return err
+func (q *Queries) DeleteFutureShowtimesByMovie(ctx context.Context, movieID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, deleteFutureShowtimesByMovie, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteShowtime = `-- name: DeleteShowtime :execrows
+UPDATE showtimes SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) DeleteShowtime(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteShowtime, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDeletedShowtimeById = `-- name: GetDeletedShowtimeById :one
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name,
+  (m.deleted_at IS NOT NULL OR a.deleted_at IS NOT NULL OR v.deleted_at IS NOT NULL)::boolean AS parent_deleted
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.id = $1 AND s.deleted_at IS NOT NULL
+`
+
+type GetDeletedShowtimeByIdRow struct {
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	VenueName        string             `json:"venue_name"`
+	ParentDeleted    bool               `json:"parent_deleted"`
+}
+
+func (q *Queries) GetDeletedShowtimeById(ctx context.Context, id int64) (GetDeletedShowtimeByIdRow, error) {
+	row := q.db.QueryRow(ctx, getDeletedShowtimeById, id)
+	var i GetDeletedShowtimeByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.MovieID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.AvailableSeats,
+		&i.PricePerSeat,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Format,
+		&i.AudioLanguage,
+		&i.SubtitleLanguage,
+		&i.IsDubbed,
+		&i.AudioDescribed,
+		&i.RelaxedScreening,
+		&i.AuditoriumID,
+		&i.VenueID,
+		&i.AuditoriumName,
+		&i.MovieTitle,
+		&i.VenueName,
+		&i.ParentDeleted,
+	)
+	return i, err
+}
+
+const getDeletedShowtimes = `-- name: GetDeletedShowtimes :many
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name,
+  (m.deleted_at IS NOT NULL OR a.deleted_at IS NOT NULL OR v.deleted_at IS NOT NULL)::boolean AS parent_deleted
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.deleted_at IS NOT NULL
+ORDER BY s.deleted_at DESC, s.id
+LIMIT $1 OFFSET $2
+`
+
+type GetDeletedShowtimesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type GetDeletedShowtimesRow struct {
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	VenueName        string             `json:"venue_name"`
+	ParentDeleted    bool               `json:"parent_deleted"`
+}
+
+// trash: soft-deleted showtimes, most recently deleted first. parent_deleted is set when
+// the movie, auditorium or venue is deleted too and must be restored first.
+func (q *Queries) GetDeletedShowtimes(ctx context.Context, arg GetDeletedShowtimesParams) ([]GetDeletedShowtimesRow, error) {
+	rows, err := q.db.Query(ctx, getDeletedShowtimes, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDeletedShowtimesRow{}
+	for rows.Next() {
+		var i GetDeletedShowtimesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.AvailableSeats,
+			&i.PricePerSeat,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Format,
+			&i.AudioLanguage,
+			&i.SubtitleLanguage,
+			&i.IsDubbed,
+			&i.AudioDescribed,
+			&i.RelaxedScreening,
+			&i.AuditoriumID,
+			&i.VenueID,
+			&i.AuditoriumName,
+			&i.MovieTitle,
+			&i.VenueName,
+			&i.ParentDeleted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getShowtimeById = `-- name: GetShowtimeById :one
-SELECT id, movie_id, start_time, end_time, available_seats, price_per_seat, venue_id, created_at, updated_at, deleted_at FROM showtimes WHERE id = $1 AND deleted_at IS NULL
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  v.name as venue_name, v.city as venue_city
+FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN movies m ON m.id = s.movie_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.id = $1
+  AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
 `
 
-func (q *Queries) GetShowtimeById(ctx context.Context, id int64) (Showtime, error) {
+type GetShowtimeByIdRow struct {
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	MovieGenre       string             `json:"movie_genre"`
+	MovieAgeRating   string             `json:"movie_age_rating"`
+	VenueName        string             `json:"venue_name"`
+	VenueCity        string             `json:"venue_city"`
+}
+
+func (q *Queries) GetShowtimeById(ctx context.Context, id int64) (GetShowtimeByIdRow, error) {
 	row := q.db.QueryRow(ctx, getShowtimeById, id)
-	var i Showtime
+	var i GetShowtimeByIdRow
 	err := row.Scan(
 		&i.ID,
 		&i.MovieID,
@@ -75,19 +326,33 @@ func (q *Queries) GetShowtimeById(ctx context.Context, id int64) (Showtime, erro
 		&i.EndTime,
 		&i.AvailableSeats,
 		&i.PricePerSeat,
-		&i.VenueID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Format,
+		&i.AudioLanguage,
+		&i.SubtitleLanguage,
+		&i.IsDubbed,
+		&i.AudioDescribed,
+		&i.RelaxedScreening,
+		&i.AuditoriumID,
+		&i.VenueID,
+		&i.AuditoriumName,
+		&i.MovieTitle,
+		&i.MovieGenre,
+		&i.MovieAgeRating,
+		&i.VenueName,
+		&i.VenueCity,
 	)
 	return i, err
 }
 
 const getShowtimesAdmin = `-- name: GetShowtimesAdmin :many
-SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.venue_id, s.created_at, s.updated_at, s.deleted_at, m.title as movie_title, v.name as venue_name
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name
 FROM showtimes s
 JOIN movies m ON m.id = s.movie_id
-JOIN venues v ON v.id = s.venue_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
 WHERE s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT $1 OFFSET $2
@@ -99,18 +364,26 @@ type GetShowtimesAdminParams struct {
 }
 
 type GetShowtimesAdminRow struct {
-	ID             int64              `json:"id"`
-	MovieID        int64              `json:"movie_id"`
-	StartTime      time.Time          `json:"start_time"`
-	EndTime        time.Time          `json:"end_time"`
-	AvailableSeats int32              `json:"available_seats"`
-	PricePerSeat   pgtype.Numeric     `json:"price_per_seat"`
-	VenueID        int32              `json:"venue_id"`
-	CreatedAt      time.Time          `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
-	MovieTitle     string             `json:"movie_title"`
-	VenueName      string             `json:"venue_name"`
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	VenueName        string             `json:"venue_name"`
 }
 
 func (q *Queries) GetShowtimesAdmin(ctx context.Context, arg GetShowtimesAdminParams) ([]GetShowtimesAdminRow, error) {
@@ -129,10 +402,18 @@ func (q *Queries) GetShowtimesAdmin(ctx context.Context, arg GetShowtimesAdminPa
 			&i.EndTime,
 			&i.AvailableSeats,
 			&i.PricePerSeat,
-			&i.VenueID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Format,
+			&i.AudioLanguage,
+			&i.SubtitleLanguage,
+			&i.IsDubbed,
+			&i.AudioDescribed,
+			&i.RelaxedScreening,
+			&i.AuditoriumID,
+			&i.VenueID,
+			&i.AuditoriumName,
 			&i.MovieTitle,
 			&i.VenueName,
 		); err != nil {
@@ -147,28 +428,41 @@ func (q *Queries) GetShowtimesAdmin(ctx context.Context, arg GetShowtimesAdminPa
 }
 
 const getShowtimesByMovie = `-- name: GetShowtimesByMovie :many
-SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.venue_id, s.created_at, s.updated_at, s.deleted_at, v.name as venue_name, v.city as venue_city
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name, v.name as venue_name, v.city as venue_city
 FROM showtimes s
-JOIN venues v ON v.id = s.venue_id
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
 WHERE s.movie_id = $1
   AND s.start_time > now()
   AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
 ORDER BY s.start_time ASC
 `
 
 type GetShowtimesByMovieRow struct {
-	ID             int64              `json:"id"`
-	MovieID        int64              `json:"movie_id"`
-	StartTime      time.Time          `json:"start_time"`
-	EndTime        time.Time          `json:"end_time"`
-	AvailableSeats int32              `json:"available_seats"`
-	PricePerSeat   pgtype.Numeric     `json:"price_per_seat"`
-	VenueID        int32              `json:"venue_id"`
-	CreatedAt      time.Time          `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
-	VenueName      string             `json:"venue_name"`
-	VenueCity      string             `json:"venue_city"`
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	VenueName        string             `json:"venue_name"`
+	VenueCity        string             `json:"venue_city"`
 }
 
 func (q *Queries) GetShowtimesByMovie(ctx context.Context, movieID int64) ([]GetShowtimesByMovieRow, error) {
@@ -187,10 +481,245 @@ func (q *Queries) GetShowtimesByMovie(ctx context.Context, movieID int64) ([]Get
 			&i.EndTime,
 			&i.AvailableSeats,
 			&i.PricePerSeat,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Format,
+			&i.AudioLanguage,
+			&i.SubtitleLanguage,
+			&i.IsDubbed,
+			&i.AudioDescribed,
+			&i.RelaxedScreening,
+			&i.AuditoriumID,
 			&i.VenueID,
+			&i.AuditoriumName,
+			&i.VenueName,
+			&i.VenueCity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenueProgramme = `-- name: GetVenueProgramme :many
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  m.runtime as movie_runtime, m.poster_url as movie_poster_url
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = $1
+  AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND s.start_time >= $2
+  AND s.start_time < $3
+ORDER BY m.title ASC, s.movie_id ASC, s.start_time ASC
+`
+
+type GetVenueProgrammeParams struct {
+	VenueID     int32     `json:"venue_id"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+type GetVenueProgrammeRow struct {
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	MovieGenre       string             `json:"movie_genre"`
+	MovieAgeRating   string             `json:"movie_age_rating"`
+	MovieRuntime     int32              `json:"movie_runtime"`
+	MoviePosterUrl   *string            `json:"movie_poster_url"`
+}
+
+// a venue's programme for a time window, ordered so rows can be grouped by movie
+func (q *Queries) GetVenueProgramme(ctx context.Context, arg GetVenueProgrammeParams) ([]GetVenueProgrammeRow, error) {
+	rows, err := q.db.Query(ctx, getVenueProgramme, arg.VenueID, arg.WindowStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenueProgrammeRow{}
+	for rows.Next() {
+		var i GetVenueProgrammeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.AvailableSeats,
+			&i.PricePerSeat,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Format,
+			&i.AudioLanguage,
+			&i.SubtitleLanguage,
+			&i.IsDubbed,
+			&i.AudioDescribed,
+			&i.RelaxedScreening,
+			&i.AuditoriumID,
+			&i.VenueID,
+			&i.AuditoriumName,
+			&i.MovieTitle,
+			&i.MovieGenre,
+			&i.MovieAgeRating,
+			&i.MovieRuntime,
+			&i.MoviePosterUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShowtimes = `-- name: ListShowtimes :many
+SELECT s.id, s.movie_id, s.start_time, s.end_time, s.available_seats, s.price_per_seat, s.created_at, s.updated_at, s.deleted_at, s.format, s.audio_language, s.subtitle_language, s.is_dubbed, s.audio_described, s.relaxed_screening, s.auditorium_id, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  v.name as venue_name, v.city as venue_city
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
+  AND s.start_time > now()
+  AND ($1::int IS NULL OR a.venue_id = $1)
+  AND ($2::text IS NULL OR v.city = $2)
+  AND ($3::date IS NULL OR (s.start_time AT TIME ZONE v.timezone)::date >= $3)
+  AND ($4::date IS NULL OR (s.start_time AT TIME ZONE v.timezone)::date <= $4)
+  AND ($5::text IS NULL OR EXISTS (
+    SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND (g.slug = $5 OR g.name = $5)))
+  AND ($6::text IS NULL OR m.age_rating = $6)
+  AND ($7::int IS NULL OR s.available_seats >= $7)
+  AND ($8::text IS NULL OR s.format = $8)
+  AND ($9::text IS NULL OR s.audio_language = $9)
+  AND ($10::text IS NULL OR s.subtitle_language = $10)
+  AND ($11::boolean IS NULL OR s.audio_described = $11)
+  AND ($12::boolean IS NULL OR s.relaxed_screening = $12)
+ORDER BY s.start_time ASC
+LIMIT $14 OFFSET $13
+`
+
+type ListShowtimesParams struct {
+	VenueID          *int32      `json:"venue_id"`
+	City             *string     `json:"city"`
+	DateFrom         pgtype.Date `json:"date_from"`
+	DateTo           pgtype.Date `json:"date_to"`
+	Genre            *string     `json:"genre"`
+	AgeRating        *string     `json:"age_rating"`
+	MinSeats         *int32      `json:"min_seats"`
+	Format           *string     `json:"format"`
+	AudioLanguage    *string     `json:"audio_language"`
+	SubtitleLanguage *string     `json:"subtitle_language"`
+	AudioDescribed   *bool       `json:"audio_described"`
+	RelaxedScreening *bool       `json:"relaxed_screening"`
+	Offset           int32       `json:"offset"`
+	Limit            int32       `json:"limit"`
+}
+
+type ListShowtimesRow struct {
+	ID               int64              `json:"id"`
+	MovieID          int64              `json:"movie_id"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          time.Time          `json:"end_time"`
+	AvailableSeats   int32              `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	Format           string             `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         bool               `json:"is_dubbed"`
+	AudioDescribed   bool               `json:"audio_described"`
+	RelaxedScreening bool               `json:"relaxed_screening"`
+	AuditoriumID     int32              `json:"auditorium_id"`
+	VenueID          int32              `json:"venue_id"`
+	AuditoriumName   string             `json:"auditorium_name"`
+	MovieTitle       string             `json:"movie_title"`
+	MovieGenre       string             `json:"movie_genre"`
+	MovieAgeRating   string             `json:"movie_age_rating"`
+	VenueName        string             `json:"venue_name"`
+	VenueCity        string             `json:"venue_city"`
+}
+
+// public listing with optional filters, only future showtimes of live movies and venues
+func (q *Queries) ListShowtimes(ctx context.Context, arg ListShowtimesParams) ([]ListShowtimesRow, error) {
+	rows, err := q.db.Query(ctx, listShowtimes,
+		arg.VenueID,
+		arg.City,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.Genre,
+		arg.AgeRating,
+		arg.MinSeats,
+		arg.Format,
+		arg.AudioLanguage,
+		arg.SubtitleLanguage,
+		arg.AudioDescribed,
+		arg.RelaxedScreening,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListShowtimesRow{}
+	for rows.Next() {
+		var i ListShowtimesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.AvailableSeats,
+			&i.PricePerSeat,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Format,
+			&i.AudioLanguage,
+			&i.SubtitleLanguage,
+			&i.IsDubbed,
+			&i.AudioDescribed,
+			&i.RelaxedScreening,
+			&i.AuditoriumID,
+			&i.VenueID,
+			&i.AuditoriumName,
+			&i.MovieTitle,
+			&i.MovieGenre,
+			&i.MovieAgeRating,
 			&i.VenueName,
 			&i.VenueCity,
 		); err != nil {
@@ -204,25 +733,86 @@ func (q *Queries) GetShowtimesByMovie(ctx context.Context, movieID int64) ([]Get
 	return items, nil
 }
 
+const restoreFutureShowtimesByMovie = `-- name: RestoreFutureShowtimesByMovie :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE movie_id = $1
+  AND showtimes.deleted_at = $2
+  AND start_time > now()
+  AND auditorium_id IN (SELECT id FROM auditoriums WHERE deleted_at IS NULL)
+`
+
+type RestoreFutureShowtimesByMovieParams struct {
+	MovieID   int64              `json:"movie_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+// Cascaded deletes share their parent's deleted_at, as now() is fixed for the transaction,
+// so restoring the parent brings back exactly the showtimes it took down. Showtimes that
+// have since started stay deleted.
+func (q *Queries) RestoreFutureShowtimesByMovie(ctx context.Context, arg RestoreFutureShowtimesByMovieParams) error {
+	_, err := q.db.Exec(ctx, restoreFutureShowtimesByMovie, arg.MovieID, arg.DeletedAt)
+	return err
+}
+
+const restoreFutureShowtimesByVenue = `-- name: RestoreFutureShowtimesByVenue :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = $1 AND deleted_at IS NULL)
+  AND showtimes.deleted_at = $2
+  AND start_time > now()
+  AND movie_id IN (SELECT id FROM movies WHERE deleted_at IS NULL)
+`
+
+type RestoreFutureShowtimesByVenueParams struct {
+	VenueID   int32              `json:"venue_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreFutureShowtimesByVenue(ctx context.Context, arg RestoreFutureShowtimesByVenueParams) error {
+	_, err := q.db.Exec(ctx, restoreFutureShowtimesByVenue, arg.VenueID, arg.DeletedAt)
+	return err
+}
+
+const restoreShowtime = `-- name: RestoreShowtime :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreShowtime(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, restoreShowtime, id)
+	return err
+}
+
 const updateShowtime = `-- name: UpdateShowtime :one
 UPDATE showtimes SET
   start_time = COALESCE($1, start_time),
   end_time = COALESCE($2, end_time),
   available_seats = COALESCE($3, available_seats),
   price_per_seat = COALESCE($4, price_per_seat),
-  venue_id = COALESCE($5, venue_id),
+  auditorium_id = COALESCE($5, auditorium_id),
+  format = COALESCE($6, format),
+  audio_language = COALESCE($7, audio_language),
+  subtitle_language = COALESCE($8, subtitle_language),
+  is_dubbed = COALESCE($9, is_dubbed),
+  audio_described = COALESCE($10, audio_described),
+  relaxed_screening = COALESCE($11, relaxed_screening),
   updated_at = now()
-WHERE id = $6 AND deleted_at IS NULL
-RETURNING id, movie_id, start_time, end_time, available_seats, price_per_seat, venue_id, created_at, updated_at, deleted_at
+WHERE id = $12 AND deleted_at IS NULL
+RETURNING id, movie_id, start_time, end_time, available_seats, price_per_seat, created_at, updated_at, deleted_at, format, audio_language, subtitle_language, is_dubbed, audio_described, relaxed_screening, auditorium_id
 `
 
 type UpdateShowtimeParams struct {
-	StartTime      pgtype.Timestamptz `json:"start_time"`
-	EndTime        pgtype.Timestamptz `json:"end_time"`
-	AvailableSeats *int32             `json:"available_seats"`
-	PricePerSeat   pgtype.Numeric     `json:"price_per_seat"`
-	VenueID        *int32             `json:"venue_id"`
-	ID             int64              `json:"id"`
+	StartTime        pgtype.Timestamptz `json:"start_time"`
+	EndTime          pgtype.Timestamptz `json:"end_time"`
+	AvailableSeats   *int32             `json:"available_seats"`
+	PricePerSeat     pgtype.Numeric     `json:"price_per_seat"`
+	AuditoriumID     *int32             `json:"auditorium_id"`
+	Format           *string            `json:"format"`
+	AudioLanguage    *string            `json:"audio_language"`
+	SubtitleLanguage *string            `json:"subtitle_language"`
+	IsDubbed         *bool              `json:"is_dubbed"`
+	AudioDescribed   *bool              `json:"audio_described"`
+	RelaxedScreening *bool              `json:"relaxed_screening"`
+	ID               int64              `json:"id"`
 }
 
 func (q *Queries) UpdateShowtime(ctx context.Context, arg UpdateShowtimeParams) (Showtime, error) {
@@ -231,7 +821,13 @@ func (q *Queries) UpdateShowtime(ctx context.Context, arg UpdateShowtimeParams)
 		arg.EndTime,
 		arg.AvailableSeats,
 		arg.PricePerSeat,
-		arg.VenueID,
+		arg.AuditoriumID,
+		arg.Format,
+		arg.AudioLanguage,
+		arg.SubtitleLanguage,
+		arg.IsDubbed,
+		arg.AudioDescribed,
+		arg.RelaxedScreening,
 		arg.ID,
 	)
 	var i Showtime
@@ -242,10 +838,16 @@ func (q *Queries) UpdateShowtime(ctx context.Context, arg UpdateShowtimeParams)
 		&i.EndTime,
 		&i.AvailableSeats,
 		&i.PricePerSeat,
-		&i.VenueID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Format,
+		&i.AudioLanguage,
+		&i.SubtitleLanguage,
+		&i.IsDubbed,
+		&i.AudioDescribed,
+		&i.RelaxedScreening,
+		&i.AuditoriumID,
 	)
 	return i, err
 }
@@ -0,0 +1,91 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: trending.sql
+
+package dbgen
+
+import (
+	"context"
+)
+
+const getTrendingSignals = `-- name: GetTrendingSignals :many
+WITH sales AS (
+  SELECT s.movie_id,
+    SUM(r.number_of_seats)::bigint AS tickets_sold,
+    SUM(r.number_of_seats * exp(
+      -ln(2) * EXTRACT(EPOCH FROM now() - r.confirmed_at) / 3600 / $1::float8
+    ))::float8 AS velocity
+  FROM reservations r
+  JOIN showtimes s ON s.id = r.showtime_id
+  WHERE r.status = 'confirmed'
+    AND r.deleted_at IS NULL
+    AND r.confirmed_at > now() - $2::int * INTERVAL '1 day'
+  GROUP BY s.movie_id
+),
+occupancy AS (
+  SELECT s.movie_id,
+    SUM(GREATEST(a.capacity - s.available_seats, 0))::bigint AS seats_sold,
+    SUM(a.capacity)::bigint AS seats_total
+  FROM showtimes s
+  JOIN auditoriums a ON a.id = s.auditorium_id
+  WHERE s.start_time > now() AND s.deleted_at IS NULL
+  GROUP BY s.movie_id
+)
+SELECT m.id AS movie_id,
+  COALESCE(sa.tickets_sold, 0)::bigint AS tickets_sold,
+  COALESCE(sa.velocity, 0)::float8 AS velocity,
+  COALESCE(o.seats_sold, 0)::bigint AS seats_sold,
+  COALESCE(o.seats_total, 0)::bigint AS seats_total
+FROM movies m
+LEFT JOIN sales sa ON sa.movie_id = m.id
+LEFT JOIN occupancy o ON o.movie_id = m.id
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+`
+
+type GetTrendingSignalsParams struct {
+	HalfLifeHours float64 `json:"half_life_hours"`
+	WindowDays    int32   `json:"window_days"`
+}
+
+type GetTrendingSignalsRow struct {
+	MovieID     int64   `json:"movie_id"`
+	TicketsSold int64   `json:"tickets_sold"`
+	Velocity    float64 `json:"velocity"`
+	SeatsSold   int64   `json:"seats_sold"`
+	SeatsTotal  int64   `json:"seats_total"`
+}
+
+// sales velocity and occupancy of every movie now showing. Velocity sums the confirmed
+// seats sold over the window, each decayed by its age so recent sales count the most;
+// occupancy covers the movie's upcoming showtimes, as the share of each auditorium's
+// capacity no longer available.
+func (q *Queries) GetTrendingSignals(ctx context.Context, arg GetTrendingSignalsParams) ([]GetTrendingSignalsRow, error) {
+	rows, err := q.db.Query(ctx, getTrendingSignals, arg.HalfLifeHours, arg.WindowDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTrendingSignalsRow{}
+	for rows.Next() {
+		var i GetTrendingSignalsRow
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.TicketsSold,
+			&i.Velocity,
+			&i.SeatsSold,
+			&i.SeatsTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_tokens.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeUserToken = `-- name: ConsumeUserToken :one
+UPDATE user_tokens
+SET used_at = now()
+WHERE token_hash = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id
+`
+
+type ConsumeUserTokenParams struct {
+	TokenHash string `json:"token_hash"`
+	Purpose   string `json:"purpose"`
+}
+
+// marks an unused, unexpired token used, returning whose it was
+func (q *Queries) ConsumeUserToken(ctx context.Context, arg ConsumeUserTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, consumeUserToken, arg.TokenHash, arg.Purpose)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const createUserToken = `-- name: CreateUserToken :exec
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateUserTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Purpose   string    `json:"purpose"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateUserToken(ctx context.Context, arg CreateUserTokenParams) error {
+	_, err := q.db.Exec(ctx, createUserToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const getLatestUserTokenCreatedAt = `-- name: GetLatestUserTokenCreatedAt :one
+SELECT created_at FROM user_tokens
+WHERE user_id = $1 AND purpose = $2
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetLatestUserTokenCreatedAtParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Purpose string    `json:"purpose"`
+}
+
+func (q *Queries) GetLatestUserTokenCreatedAt(ctx context.Context, arg GetLatestUserTokenCreatedAtParams) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getLatestUserTokenCreatedAt, arg.UserID, arg.Purpose)
+	var created_at time.Time
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
+const invalidateUserTokens = `-- name: InvalidateUserTokens :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+`
+
+type InvalidateUserTokensParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Purpose string    `json:"purpose"`
+}
+
+// retires the user's outstanding tokens for a purpose, so only the newest one works
+func (q *Queries) InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error {
+	_, err := q.db.Exec(ctx, invalidateUserTokens, arg.UserID, arg.Purpose)
+	return err
+}
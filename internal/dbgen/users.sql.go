@@ -12,6 +12,29 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const clearUserPassword = `-- name: ClearUserPassword :exec
+UPDATE users
+SET password_hash = NULL,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) ClearUserPassword(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, clearUserPassword, id)
+	return err
+}
+
+const countUserIdentities = `-- name: CountUserIdentities :one
+SELECT count(*) FROM user_identities WHERE user_id = $1
+`
+
+func (q *Queries) CountUserIdentities(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUserIdentities, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createLocalUser = `-- name: CreateLocalUser :one
 INSERT INTO users(
     email, 
@@ -20,7 +43,7 @@ INSERT INTO users(
     full_name,
     verified_at
 ) VALUES($1, $2, $3, $4, NULL) 
-RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at
+RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth
 `
 
 type CreateLocalUserParams struct {
@@ -51,6 +74,7 @@ func (q *Queries) CreateLocalUser(ctx context.Context, arg CreateLocalUserParams
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
 	)
 	return i, err
 }
@@ -62,7 +86,7 @@ INSERT INTO users(
     profile_image_url,
     verified_at
 ) VALUES($1, $2, $3, $4) 
-RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at
+RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth
 `
 
 type CreateUserParams struct {
@@ -93,6 +117,56 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
+	)
+	return i, err
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :one
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2 AND provider_user_id = $3
+RETURNING id, user_id, provider, provider_user_id, created_at
+`
+
+type DeleteUserIdentityParams struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Provider       string    `json:"provider"`
+	ProviderUserID string    `json:"provider_user_id"`
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, deleteUserIdentity, arg.UserID, arg.Provider, arg.ProviderUserID)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.ProviderUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteUserIdentityByID = `-- name: DeleteUserIdentityByID :one
+DELETE FROM user_identities
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, provider, provider_user_id, created_at
+`
+
+type DeleteUserIdentityByIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserIdentityByID(ctx context.Context, arg DeleteUserIdentityByIDParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, deleteUserIdentityByID, arg.ID, arg.UserID)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.ProviderUserID,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -122,7 +196,7 @@ func (q *Queries) GetIdentityByProvider(ctx context.Context, arg GetIdentityByPr
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at FROM users 
+SELECT id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth FROM users 
 WHERE email = $1 AND deleted_at IS NULL
 `
 
@@ -142,12 +216,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at FROM users 
+SELECT id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth FROM users 
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -167,12 +242,13 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
 	)
 	return i, err
 }
 
 const getUserByProvider = `-- name: GetUserByProvider :one
-SELECT u.id, u.email, u.role, u.telephone_number, u.password_hash, u.full_name, u.profile_image_url, u.user_name, u.created_at, u.updated_at, u.verified_at, u.deleted_at 
+SELECT u.id, u.email, u.role, u.telephone_number, u.password_hash, u.full_name, u.profile_image_url, u.user_name, u.created_at, u.updated_at, u.verified_at, u.deleted_at, u.date_of_birth 
 FROM users u
 JOIN user_identities ui ON u.id = ui.user_id
 WHERE ui.provider = $1 
@@ -201,6 +277,7 @@ func (q *Queries) GetUserByProvider(ctx context.Context, arg GetUserByProviderPa
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
 	)
 	return i, err
 }
@@ -233,12 +310,84 @@ func (q *Queries) LinkIdentityToUser(ctx context.Context, arg LinkIdentityToUser
 	return i, err
 }
 
+const listUserIdentities = `-- name: ListUserIdentities :many
+SELECT id, user_id, provider, provider_user_id, created_at FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, listUserIdentities, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserIdentity{}
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.ProviderUserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :one
+SELECT id FROM users WHERE id = $1 FOR UPDATE
+`
+
+// serialises changes to a user's identities, so concurrent unlinks cannot remove the last one
+func (q *Queries) LockUser(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, lockUser, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const markUserVerified = `-- name: MarkUserVerified :one
+UPDATE users
+SET verified_at = COALESCE(verified_at, now()),
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth
+`
+
+func (q *Queries) MarkUserVerified(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, markUserVerified, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Role,
+		&i.TelephoneNumber,
+		&i.PasswordHash,
+		&i.FullName,
+		&i.ProfileImageUrl,
+		&i.UserName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VerifiedAt,
+		&i.DeletedAt,
+		&i.DateOfBirth,
+	)
+	return i, err
+}
+
 const updateUserPassword = `-- name: UpdateUserPassword :one
 UPDATE users 
 SET password_hash = $2, 
     updated_at = now() 
-WHERE id = $1 
-RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth
 `
 
 type UpdateUserPasswordParams struct {
@@ -262,6 +411,50 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 		&i.UpdatedAt,
 		&i.VerifiedAt,
 		&i.DeletedAt,
+		&i.DateOfBirth,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET full_name = COALESCE($2, full_name),
+    telephone_number = COALESCE($3, telephone_number),
+    date_of_birth = COALESCE($4, date_of_birth),
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, email, role, telephone_number, password_hash, full_name, profile_image_url, user_name, created_at, updated_at, verified_at, deleted_at, date_of_birth
+`
+
+type UpdateUserProfileParams struct {
+	ID              uuid.UUID   `json:"id"`
+	FullName        *string     `json:"full_name"`
+	TelephoneNumber *string     `json:"telephone_number"`
+	DateOfBirth     pgtype.Date `json:"date_of_birth"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.ID,
+		arg.FullName,
+		arg.TelephoneNumber,
+		arg.DateOfBirth,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Role,
+		&i.TelephoneNumber,
+		&i.PasswordHash,
+		&i.FullName,
+		&i.ProfileImageUrl,
+		&i.UserName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VerifiedAt,
+		&i.DeletedAt,
+		&i.DateOfBirth,
 	)
 	return i, err
 }
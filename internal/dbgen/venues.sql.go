@@ -7,19 +7,80 @@ package dbgen
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countFutureShowtimesByVenue = `-- name: CountFutureShowtimesByVenue :one
+SELECT COUNT(*) FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = $1
+  AND s.start_time > now()
+  AND s.deleted_at IS NULL
+`
+
+func (q *Queries) CountFutureShowtimesByVenue(ctx context.Context, venueID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countFutureShowtimesByVenue, venueID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOverlappingClosures = `-- name: CountOverlappingClosures :one
+SELECT COUNT(*) FROM venue_closures
+WHERE venue_id = $1
+  AND starts_at < $2
+  AND ends_at > $3
+`
+
+type CountOverlappingClosuresParams struct {
+	VenueID    int32     `json:"venue_id"`
+	RangeEnd   time.Time `json:"range_end"`
+	RangeStart time.Time `json:"range_start"`
+}
+
+func (q *Queries) CountOverlappingClosures(ctx context.Context, arg CountOverlappingClosuresParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverlappingClosures, arg.VenueID, arg.RangeEnd, arg.RangeStart)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countShowtimesInRangeByVenue = `-- name: CountShowtimesInRangeByVenue :one
+SELECT COUNT(*) FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = $1
+  AND s.deleted_at IS NULL
+  AND s.start_time < $2
+  AND s.end_time > $3
+`
+
+type CountShowtimesInRangeByVenueParams struct {
+	VenueID    int32     `json:"venue_id"`
+	RangeEnd   time.Time `json:"range_end"`
+	RangeStart time.Time `json:"range_start"`
+}
+
+func (q *Queries) CountShowtimesInRangeByVenue(ctx context.Context, arg CountShowtimesInRangeByVenueParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countShowtimesInRangeByVenue, arg.VenueID, arg.RangeEnd, arg.RangeStart)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createVenue = `-- name: CreateVenue :one
-INSERT INTO venues (name, address, city, total_seats)
-VALUES ($1, $2, $3, $4)
-RETURNING id, name, address, city, total_seats, created_at, updated_at, deleted_at
+INSERT INTO venues (name, address, city, timezone, amenities)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, address, city, created_at, updated_at, deleted_at, timezone, amenities
 `
 
 type CreateVenueParams struct {
-	Name       string `json:"name"`
-	Address    string `json:"address"`
-	City       string `json:"city"`
-	TotalSeats int32  `json:"total_seats"`
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Timezone  string   `json:"timezone"`
+	Amenities []string `json:"amenities"`
 }
 
 func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue, error) {
@@ -27,7 +88,8 @@ func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue
 		arg.Name,
 		arg.Address,
 		arg.City,
-		arg.TotalSeats,
+		arg.Timezone,
+		arg.Amenities,
 	)
 	var i Venue
 	err := row.Scan(
@@ -35,56 +97,481 @@ func (q *Queries) CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue
 		&i.Name,
 		&i.Address,
 		&i.City,
-		&i.TotalSeats,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Timezone,
+		&i.Amenities,
+	)
+	return i, err
+}
+
+const createVenueClosure = `-- name: CreateVenueClosure :one
+INSERT INTO venue_closures (venue_id, kind, reason, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, venue_id, reason, starts_at, ends_at, created_at, kind
+`
+
+type CreateVenueClosureParams struct {
+	VenueID  int32     `json:"venue_id"`
+	Kind     string    `json:"kind"`
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+func (q *Queries) CreateVenueClosure(ctx context.Context, arg CreateVenueClosureParams) (VenueClosure, error) {
+	row := q.db.QueryRow(ctx, createVenueClosure,
+		arg.VenueID,
+		arg.Kind,
+		arg.Reason,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i VenueClosure
+	err := row.Scan(
+		&i.ID,
+		&i.VenueID,
+		&i.Reason,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedAt,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const createVenueOpeningHours = `-- name: CreateVenueOpeningHours :exec
+INSERT INTO venue_opening_hours (venue_id, day_of_week, opens_at, closes_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateVenueOpeningHoursParams struct {
+	VenueID   int32       `json:"venue_id"`
+	DayOfWeek int16       `json:"day_of_week"`
+	OpensAt   pgtype.Time `json:"opens_at"`
+	ClosesAt  pgtype.Time `json:"closes_at"`
+}
+
+func (q *Queries) CreateVenueOpeningHours(ctx context.Context, arg CreateVenueOpeningHoursParams) error {
+	_, err := q.db.Exec(ctx, createVenueOpeningHours,
+		arg.VenueID,
+		arg.DayOfWeek,
+		arg.OpensAt,
+		arg.ClosesAt,
+	)
+	return err
+}
+
+const deleteFutureShowtimesByVenue = `-- name: DeleteFutureShowtimesByVenue :many
+UPDATE showtimes SET deleted_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = $1)
+  AND start_time > now()
+  AND deleted_at IS NULL
+RETURNING id
+`
+
+func (q *Queries) DeleteFutureShowtimesByVenue(ctx context.Context, venueID int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, deleteFutureShowtimesByVenue, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteShowtimesInRangeByVenue = `-- name: DeleteShowtimesInRangeByVenue :many
+UPDATE showtimes SET deleted_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = $1)
+  AND deleted_at IS NULL
+  AND start_time < $2
+  AND end_time > $3
+RETURNING id
+`
+
+type DeleteShowtimesInRangeByVenueParams struct {
+	VenueID    int32     `json:"venue_id"`
+	RangeEnd   time.Time `json:"range_end"`
+	RangeStart time.Time `json:"range_start"`
+}
+
+func (q *Queries) DeleteShowtimesInRangeByVenue(ctx context.Context, arg DeleteShowtimesInRangeByVenueParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, deleteShowtimesInRangeByVenue, arg.VenueID, arg.RangeEnd, arg.RangeStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteVenue = `-- name: DeleteVenue :execrows
+UPDATE venues SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) DeleteVenue(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteVenue, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteVenueClosure = `-- name: DeleteVenueClosure :execrows
+DELETE FROM venue_closures WHERE id = $1 AND venue_id = $2
+`
+
+type DeleteVenueClosureParams struct {
+	ID      int32 `json:"id"`
+	VenueID int32 `json:"venue_id"`
+}
+
+func (q *Queries) DeleteVenueClosure(ctx context.Context, arg DeleteVenueClosureParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteVenueClosure, arg.ID, arg.VenueID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteVenueOpeningHours = `-- name: DeleteVenueOpeningHours :exec
+DELETE FROM venue_opening_hours WHERE venue_id = $1
+`
+
+func (q *Queries) DeleteVenueOpeningHours(ctx context.Context, venueID int32) error {
+	_, err := q.db.Exec(ctx, deleteVenueOpeningHours, venueID)
+	return err
+}
+
+const getActiveClosuresByVenues = `-- name: GetActiveClosuresByVenues :many
+SELECT id, venue_id, reason, starts_at, ends_at, created_at, kind FROM venue_closures
+WHERE venue_id = ANY($1::int[])
+  AND starts_at <= now() AND ends_at > now()
+ORDER BY venue_id, starts_at
+`
+
+// closures in effect right now, for venue listings
+func (q *Queries) GetActiveClosuresByVenues(ctx context.Context, venueIds []int32) ([]VenueClosure, error) {
+	rows, err := q.db.Query(ctx, getActiveClosuresByVenues, venueIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VenueClosure{}
+	for rows.Next() {
+		var i VenueClosure
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			&i.Reason,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedVenueById = `-- name: GetDeletedVenueById :one
+SELECT id, name, address, city, created_at, updated_at, deleted_at, timezone, amenities FROM venues WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedVenueById(ctx context.Context, id int32) (Venue, error) {
+	row := q.db.QueryRow(ctx, getDeletedVenueById, id)
+	var i Venue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Timezone,
+		&i.Amenities,
 	)
 	return i, err
 }
 
+const getDeletedVenues = `-- name: GetDeletedVenues :many
+SELECT id, name, address, city, created_at, updated_at, deleted_at, timezone, amenities FROM venues
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT $1 OFFSET $2
+`
+
+type GetDeletedVenuesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// trash: soft-deleted venues, most recently deleted first
+func (q *Queries) GetDeletedVenues(ctx context.Context, arg GetDeletedVenuesParams) ([]Venue, error) {
+	rows, err := q.db.Query(ctx, getDeletedVenues, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Venue{}
+	for rows.Next() {
+		var i Venue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Address,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Timezone,
+			&i.Amenities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOpeningHoursByVenues = `-- name: GetOpeningHoursByVenues :many
+SELECT venue_id, day_of_week, opens_at, closes_at FROM venue_opening_hours
+WHERE venue_id = ANY($1::int[])
+ORDER BY venue_id, day_of_week
+`
+
+func (q *Queries) GetOpeningHoursByVenues(ctx context.Context, venueIds []int32) ([]VenueOpeningHour, error) {
+	rows, err := q.db.Query(ctx, getOpeningHoursByVenues, venueIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VenueOpeningHour{}
+	for rows.Next() {
+		var i VenueOpeningHour
+		if err := rows.Scan(
+			&i.VenueID,
+			&i.DayOfWeek,
+			&i.OpensAt,
+			&i.ClosesAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVenueById = `-- name: GetVenueById :one
-SELECT id, name, address, city, total_seats, created_at, updated_at, deleted_at FROM venues WHERE id = $1 AND deleted_at IS NULL
+SELECT v.id, v.name, v.address, v.city, v.created_at, v.updated_at, v.deleted_at, v.timezone, v.amenities,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.id = $1 AND v.deleted_at IS NULL
 `
 
-func (q *Queries) GetVenueById(ctx context.Context, id int32) (Venue, error) {
+type GetVenueByIdRow struct {
+	ID         int32              `json:"id"`
+	Name       string             `json:"name"`
+	Address    string             `json:"address"`
+	City       string             `json:"city"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	Timezone   string             `json:"timezone"`
+	Amenities  []string           `json:"amenities"`
+	TotalSeats int32              `json:"total_seats"`
+}
+
+func (q *Queries) GetVenueById(ctx context.Context, id int32) (GetVenueByIdRow, error) {
 	row := q.db.QueryRow(ctx, getVenueById, id)
-	var i Venue
+	var i GetVenueByIdRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Address,
 		&i.City,
-		&i.TotalSeats,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Timezone,
+		&i.Amenities,
+		&i.TotalSeats,
 	)
 	return i, err
 }
 
+const getVenueClosures = `-- name: GetVenueClosures :many
+SELECT id, venue_id, reason, starts_at, ends_at, created_at, kind FROM venue_closures
+WHERE venue_id = $1 AND ends_at > now()
+ORDER BY starts_at ASC
+`
+
+// closures that have not yet ended
+func (q *Queries) GetVenueClosures(ctx context.Context, venueID int32) ([]VenueClosure, error) {
+	rows, err := q.db.Query(ctx, getVenueClosures, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VenueClosure{}
+	for rows.Next() {
+		var i VenueClosure
+		if err := rows.Scan(
+			&i.ID,
+			&i.VenueID,
+			&i.Reason,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVenues = `-- name: GetVenues :many
-SELECT id, name, address, city, total_seats, created_at, updated_at, deleted_at FROM venues WHERE deleted_at IS NULL ORDER BY name
+SELECT v.id, v.name, v.address, v.city, v.created_at, v.updated_at, v.deleted_at, v.timezone, v.amenities,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM venue_closures c
+    WHERE c.venue_id = v.id AND c.kind = 'temporary'
+      AND c.starts_at <= now() AND c.ends_at > now()
+  )
+ORDER BY v.name
 `
 
-func (q *Queries) GetVenues(ctx context.Context) ([]Venue, error) {
+type GetVenuesRow struct {
+	ID         int32              `json:"id"`
+	Name       string             `json:"name"`
+	Address    string             `json:"address"`
+	City       string             `json:"city"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	Timezone   string             `json:"timezone"`
+	Amenities  []string           `json:"amenities"`
+	TotalSeats int32              `json:"total_seats"`
+}
+
+// public listing: total_seats is the combined capacity of the venue's auditoriums,
+// venues under an active temporary closure are hidden
+func (q *Queries) GetVenues(ctx context.Context) ([]GetVenuesRow, error) {
 	rows, err := q.db.Query(ctx, getVenues)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Venue{}
+	items := []GetVenuesRow{}
 	for rows.Next() {
-		var i Venue
+		var i GetVenuesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.Address,
 			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Timezone,
+			&i.Amenities,
 			&i.TotalSeats,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVenuesAdmin = `-- name: GetVenuesAdmin :many
+SELECT v.id, v.name, v.address, v.city, v.created_at, v.updated_at, v.deleted_at, v.timezone, v.amenities,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.deleted_at IS NULL
+ORDER BY v.name
+`
+
+type GetVenuesAdminRow struct {
+	ID         int32              `json:"id"`
+	Name       string             `json:"name"`
+	Address    string             `json:"address"`
+	City       string             `json:"city"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	Timezone   string             `json:"timezone"`
+	Amenities  []string           `json:"amenities"`
+	TotalSeats int32              `json:"total_seats"`
+}
+
+// admin listing: includes venues that are temporarily closed
+func (q *Queries) GetVenuesAdmin(ctx context.Context) ([]GetVenuesAdminRow, error) {
+	rows, err := q.db.Query(ctx, getVenuesAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetVenuesAdminRow{}
+	for rows.Next() {
+		var i GetVenuesAdminRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Address,
+			&i.City,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Timezone,
+			&i.Amenities,
+			&i.TotalSeats,
 		); err != nil {
 			return nil, err
 		}
@@ -95,3 +582,58 @@ func (q *Queries) GetVenues(ctx context.Context) ([]Venue, error) {
 	}
 	return items, nil
 }
+
+const restoreVenue = `-- name: RestoreVenue :exec
+UPDATE venues SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreVenue(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, restoreVenue, id)
+	return err
+}
+
+const updateVenue = `-- name: UpdateVenue :one
+UPDATE venues SET
+  name = COALESCE($1, name),
+  address = COALESCE($2, address),
+  city = COALESCE($3, city),
+  timezone = COALESCE($4, timezone),
+  amenities = COALESCE($5::varchar[], amenities),
+  updated_at = now()
+WHERE id = $6 AND deleted_at IS NULL
+RETURNING id, name, address, city, created_at, updated_at, deleted_at, timezone, amenities
+`
+
+type UpdateVenueParams struct {
+	Name      *string  `json:"name"`
+	Address   *string  `json:"address"`
+	City      *string  `json:"city"`
+	Timezone  *string  `json:"timezone"`
+	Amenities []string `json:"amenities"`
+	ID        int32    `json:"id"`
+}
+
+func (q *Queries) UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venue, error) {
+	row := q.db.QueryRow(ctx, updateVenue,
+		arg.Name,
+		arg.Address,
+		arg.City,
+		arg.Timezone,
+		arg.Amenities,
+		arg.ID,
+	)
+	var i Venue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Timezone,
+		&i.Amenities,
+	)
+	return i, err
+}
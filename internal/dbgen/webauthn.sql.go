@@ -0,0 +1,192 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webauthn.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createWebAuthnCeremony = `-- name: CreateWebAuthnCeremony :exec
+INSERT INTO webauthn_ceremonies (id, user_id, kind, session_data, credential_name, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateWebAuthnCeremonyParams struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         pgtype.UUID `json:"user_id"`
+	Kind           string      `json:"kind"`
+	SessionData    []byte      `json:"session_data"`
+	CredentialName string      `json:"credential_name"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+}
+
+func (q *Queries) CreateWebAuthnCeremony(ctx context.Context, arg CreateWebAuthnCeremonyParams) error {
+	_, err := q.db.Exec(ctx, createWebAuthnCeremony,
+		arg.ID,
+		arg.UserID,
+		arg.Kind,
+		arg.SessionData,
+		arg.CredentialName,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createWebAuthnCredential = `-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (id, user_id, name, credential)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, credential, created_at, last_used_at
+`
+
+type CreateWebAuthnCredentialParams struct {
+	ID         []byte    `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	Credential []byte    `json:"credential"`
+}
+
+func (q *Queries) CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, createWebAuthnCredential,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.Credential,
+	)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Credential,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredWebAuthnCeremonies = `-- name: DeleteExpiredWebAuthnCeremonies :exec
+DELETE FROM webauthn_ceremonies WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredWebAuthnCeremonies(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredWebAuthnCeremonies)
+	return err
+}
+
+const deleteWebAuthnCredential = `-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2
+`
+
+type DeleteWebAuthnCredentialParams struct {
+	ID     []byte    `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebAuthnCredential, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getWebAuthnCredential = `-- name: GetWebAuthnCredential :one
+SELECT id, user_id, name, credential, created_at, last_used_at FROM webauthn_credentials WHERE id = $1
+`
+
+func (q *Queries) GetWebAuthnCredential(ctx context.Context, id []byte) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, getWebAuthnCredential, id)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Credential,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const listWebAuthnCredentialsByUser = `-- name: ListWebAuthnCredentialsByUser :many
+SELECT id, user_id, name, credential, created_at, last_used_at FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListWebAuthnCredentialsByUser(ctx context.Context, userID uuid.UUID) ([]WebauthnCredential, error) {
+	rows, err := q.db.Query(ctx, listWebAuthnCredentialsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebauthnCredential{}
+	for rows.Next() {
+		var i WebauthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Credential,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const takeWebAuthnCeremony = `-- name: TakeWebAuthnCeremony :one
+DELETE FROM webauthn_ceremonies
+WHERE id = $1 AND kind = $2 AND expires_at > now()
+RETURNING id, user_id, kind, session_data, credential_name, expires_at
+`
+
+type TakeWebAuthnCeremonyParams struct {
+	ID   uuid.UUID `json:"id"`
+	Kind string    `json:"kind"`
+}
+
+// removes a ceremony as it is finished, so its challenge can only be answered once
+func (q *Queries) TakeWebAuthnCeremony(ctx context.Context, arg TakeWebAuthnCeremonyParams) (WebauthnCeremony, error) {
+	row := q.db.QueryRow(ctx, takeWebAuthnCeremony, arg.ID, arg.Kind)
+	var i WebauthnCeremony
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.SessionData,
+		&i.CredentialName,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const updateWebAuthnCredentialUse = `-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET credential = $2, last_used_at = now()
+WHERE id = $1
+`
+
+type UpdateWebAuthnCredentialUseParams struct {
+	ID         []byte `json:"id"`
+	Credential []byte `json:"credential"`
+}
+
+// stores the sign counter and flags an assertion moved the credential to
+func (q *Queries) UpdateWebAuthnCredentialUse(ctx context.Context, arg UpdateWebAuthnCredentialUseParams) error {
+	_, err := q.db.Exec(ctx, updateWebAuthnCredentialUse, arg.ID, arg.Credential)
+	return err
+}
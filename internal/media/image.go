@@ -0,0 +1,118 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+)
+
+// maxPixels bounds the size of the decoded image, so a small but highly compressed file
+// cannot exhaust memory.
+const maxPixels = 40_000_000
+
+const jpegQuality = 85
+
+// variantSpec describes a standard rendition of an asset, scaled to Width pixels wide.
+type variantSpec struct {
+	Name  string
+	Width int
+}
+
+// kindSpec holds the minimum upload width and the variants produced for an asset kind.
+type kindSpec struct {
+	MinWidth int
+	Variants []variantSpec
+	// PrimaryVariant is the variant used when the asset is referenced elsewhere,
+	// e.g. as a movie's poster URL.
+	PrimaryVariant string
+}
+
+var kinds = map[string]kindSpec{
+	KindPoster: {
+		MinWidth:       342,
+		Variants:       []variantSpec{{"small", 185}, {"medium", 342}, {"large", 780}},
+		PrimaryVariant: "large",
+	},
+	KindBackdrop: {
+		MinWidth:       780,
+		Variants:       []variantSpec{{"small", 300}, {"medium", 780}, {"large", 1280}},
+		PrimaryVariant: "large",
+	},
+	KindStill: {
+		MinWidth:       300,
+		Variants:       []variantSpec{{"small", 300}, {"medium", 780}, {"large", 1280}},
+		PrimaryVariant: "large",
+	},
+}
+
+// decodeImage validates and decodes an upload, returning the image and its format name.
+func decodeImage(data []byte) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	return img, format, nil
+}
+
+// flatten draws the image onto an opaque white canvas, since JPEG has no transparency.
+func flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// resize scales src to the given width, preserving the aspect ratio. Images are never
+// upscaled. Each destination pixel is the average of the source pixels it covers.
+func resize(src *image.RGBA, width int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if width >= sw {
+		return src
+	}
+	height := max(1, (sh*width+sw/2)/sw)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		srcW, srcH    int
+		width         int
+		wantW, wantH  int
+		wantUnchanged bool
+	}{
+		{name: "halves", srcW: 800, srcH: 1200, width: 400, wantW: 400, wantH: 600},
+		{name: "rounds height", srcW: 7, srcH: 5, width: 3, wantW: 3, wantH: 2},
+		{name: "keeps at least one row", srcW: 1000, srcH: 1, width: 10, wantW: 10, wantH: 1},
+		{name: "single column", srcW: 5, srcH: 9, width: 1, wantW: 1, wantH: 2},
+		{name: "same width", srcW: 300, srcH: 450, width: 300, wantW: 300, wantH: 450, wantUnchanged: true},
+		{name: "never upscales", srcW: 185, srcH: 278, width: 780, wantW: 185, wantH: 278, wantUnchanged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := solid(tt.srcW, tt.srcH, color.RGBA{10, 20, 30, 255})
+			got := resize(src, tt.width)
+			require.Equal(t, image.Rect(0, 0, tt.wantW, tt.wantH), got.Bounds())
+			if tt.wantUnchanged {
+				require.Same(t, src, got)
+			}
+			for y := 0; y < tt.wantH; y++ {
+				for x := 0; x < tt.wantW; x++ {
+					require.Equal(t, color.RGBA{10, 20, 30, 255}, got.RGBAAt(x, y))
+				}
+			}
+		})
+	}
+}
+
+func TestResizeAveragesSourcePixels(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		src.SetRGBA(0, y, color.RGBA{0, 0, 0, 255})
+		src.SetRGBA(1, y, color.RGBA{200, 100, 50, 255})
+		src.SetRGBA(2, y, color.RGBA{255, 255, 255, 255})
+		src.SetRGBA(3, y, color.RGBA{255, 255, 255, 255})
+	}
+
+	got := resize(src, 2)
+	require.Equal(t, image.Rect(0, 0, 2, 1), got.Bounds())
+	require.Equal(t, color.RGBA{100, 50, 25, 255}, got.RGBAAt(0, 0))
+	require.Equal(t, color.RGBA{255, 255, 255, 255}, got.RGBAAt(1, 0))
+}
+
+func TestDecodeImage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gif.Encode(&buf, solid(2, 3, color.RGBA{0, 0, 0, 255}), nil))
+	img, format, err := decodeImage(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "gif", format)
+	require.Equal(t, image.Rect(0, 0, 2, 3), img.Bounds())
+
+	// Claim a 65535x65535 logical screen; the pixel bound is checked before decoding.
+	huge := bytes.Clone(buf.Bytes())
+	binary.LittleEndian.PutUint16(huge[6:], 0xffff)
+	binary.LittleEndian.PutUint16(huge[8:], 0xffff)
+	_, _, err = decodeImage(huge)
+	require.ErrorIs(t, err, ErrImageTooLarge)
+
+	_, _, err = decodeImage([]byte("not an image"))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,139 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a media asset is not found.
+	ErrNotFound = errors.New("media asset not found")
+	// ErrVariantNotFound is returned when an asset has no variant with the requested name.
+	ErrVariantNotFound = errors.New("media variant not found")
+	// ErrObjectNotFound is returned by a Storage when no object is stored under a key.
+	ErrObjectNotFound = errors.New("stored object not found")
+	// ErrInvalidKind is returned when uploading an asset of an unknown kind.
+	ErrInvalidKind = errors.New("kind must be one of poster, backdrop or still")
+	// ErrUnsupportedFormat is returned when an upload is not a JPEG, PNG or GIF image.
+	ErrUnsupportedFormat = errors.New("image must be a JPEG, PNG or GIF")
+	// ErrImageTooLarge is returned when an image has more pixels than can safely be decoded.
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+	// ErrImageTooSmall is returned when an image is narrower than the minimum width for its kind.
+	ErrImageTooSmall = errors.New("image is too small for this kind of asset")
+)
+
+// Asset kinds.
+const (
+	KindPoster   = "poster"
+	KindBackdrop = "backdrop"
+	KindStill    = "still"
+)
+
+// MaxUploadSize is the largest upload accepted, in bytes.
+const MaxUploadSize = 10 << 20
+
+// Asset is an uploaded image belonging to a movie. The original upload is not kept;
+// it is stored as a set of resized JPEG variants.
+type Asset struct {
+	ID                  int64
+	MovieID             int64
+	Kind                string
+	OriginalFilename    string
+	OriginalContentType string
+	Width               int32
+	Height              int32
+	CreatedAt           time.Time
+
+	Variants []Variant
+}
+
+// Variant returns the variant with the given name.
+func (a *Asset) Variant(name string) (*Variant, bool) {
+	for i := range a.Variants {
+		if a.Variants[i].Name == name {
+			return &a.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// ToResponse converts an Asset to an AssetResponse.
+func (a *Asset) ToResponse() AssetResponse {
+	variants := make([]VariantResponse, 0, len(a.Variants))
+	for _, v := range a.Variants {
+		variants = append(variants, v.ToResponse())
+	}
+
+	return AssetResponse{
+		ID:               a.ID,
+		MovieID:          a.MovieID,
+		Kind:             a.Kind,
+		OriginalFilename: a.OriginalFilename,
+		Width:            a.Width,
+		Height:           a.Height,
+		CreatedAt:        a.CreatedAt,
+		Variants:         variants,
+	}
+}
+
+// AssetResponse represents the API response for a media asset.
+type AssetResponse struct {
+	ID               int64             `json:"id"`
+	MovieID          int64             `json:"movie_id"`
+	Kind             string            `json:"kind"`
+	OriginalFilename string            `json:"original_filename"`
+	Width            int32             `json:"width"`
+	Height           int32             `json:"height"`
+	CreatedAt        time.Time         `json:"created_at"`
+	Variants         []VariantResponse `json:"variants"`
+}
+
+// Variant is one resized rendition of an asset.
+type Variant struct {
+	Name       string
+	StorageKey string
+	Width      int32
+	Height     int32
+	SizeBytes  int64
+
+	// URL is where the variant is served from. It is populated by the service.
+	URL string
+}
+
+// ToResponse converts a Variant to a VariantResponse.
+func (v *Variant) ToResponse() VariantResponse {
+	return VariantResponse{
+		Name:      v.Name,
+		Width:     v.Width,
+		Height:    v.Height,
+		SizeBytes: v.SizeBytes,
+		URL:       v.URL,
+	}
+}
+
+// VariantResponse represents the API response for a media variant.
+type VariantResponse struct {
+	Name      string `json:"name"`
+	Width     int32  `json:"width"`
+	Height    int32  `json:"height"`
+	SizeBytes int64  `json:"size_bytes"`
+	URL       string `json:"url"`
+}
+
+// UploadRequest represents an uploaded image file.
+type UploadRequest struct {
+	MovieID  int64
+	Kind     string
+	Filename string
+	Data     []byte
+}
+
+// Storage stores the encoded variants. Keys are slash-separated paths.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns ErrObjectNotFound if nothing is stored under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
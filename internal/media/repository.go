@@ -0,0 +1,13 @@
+package media
+
+import "context"
+
+// Repository defines the data access contract for the media domain.
+type Repository interface {
+	// Create inserts the asset and its variants atomically.
+	Create(ctx context.Context, a Asset) (*Asset, error)
+	GetByID(ctx context.Context, id int64) (*Asset, error)
+	ListByMovie(ctx context.Context, movieId int64) ([]Asset, error)
+	GetVariant(ctx context.Context, assetId int64, name string) (*Variant, error)
+	Delete(ctx context.Context, id int64) error
+}
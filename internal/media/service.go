@@ -0,0 +1,218 @@
+package media
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Service defines the business operations for the media domain.
+type Service interface {
+	// Upload validates the image, stores its variants and records the asset. Uploading
+	// a poster also makes it the movie's poster.
+	Upload(ctx context.Context, req UploadRequest) (*Asset, error)
+	GetAsset(ctx context.Context, id int64) (*Asset, error)
+	ListAssets(ctx context.Context, movieId int64) ([]Asset, error)
+	DeleteAsset(ctx context.Context, id int64) error
+	// GetVariant returns the variant together with its encoded JPEG image.
+	GetVariant(ctx context.Context, assetId int64, name string) (*Variant, []byte, error)
+}
+
+type service struct {
+	repo    Repository
+	storage Storage
+	movies  movie.Service
+	baseURL string
+}
+
+// NewService creates a new media service. Variant URLs are built from baseURL.
+func NewService(repo Repository, storage Storage, movies movie.Service, baseURL string) Service {
+	return &service{repo: repo, storage: storage, movies: movies, baseURL: baseURL}
+}
+
+func (s *service) Upload(ctx context.Context, req UploadRequest) (*Asset, error) {
+	spec, ok := kinds[req.Kind]
+	if !ok {
+		return nil, ErrInvalidKind
+	}
+	if _, err := s.movies.GetMovie(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	img, format, err := decodeImage(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	if img.Bounds().Dx() < spec.MinWidth {
+		return nil, ErrImageTooSmall
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	asset := Asset{
+		MovieID:             req.MovieID,
+		Kind:                req.Kind,
+		OriginalFilename:    req.Filename,
+		OriginalContentType: "image/" + format,
+		Width:               int32(img.Bounds().Dx()),
+		Height:              int32(img.Bounds().Dy()),
+	}
+
+	flat := flatten(img)
+	for _, vs := range spec.Variants {
+		resized := resize(flat, vs.Width)
+		data, err := encodeJPEG(resized)
+		if err != nil {
+			s.removeObjects(ctx, asset.Variants)
+			return nil, err
+		}
+
+		key := fmt.Sprintf("movies/%d/%s/%s/%s.jpg", req.MovieID, req.Kind, token, vs.Name)
+		if err := s.storage.Put(ctx, key, data, "image/jpeg"); err != nil {
+			s.removeObjects(ctx, asset.Variants)
+			return nil, err
+		}
+		asset.Variants = append(asset.Variants, Variant{
+			Name:       vs.Name,
+			StorageKey: key,
+			Width:      int32(resized.Bounds().Dx()),
+			Height:     int32(resized.Bounds().Dy()),
+			SizeBytes:  int64(len(data)),
+		})
+	}
+
+	created, err := s.repo.Create(ctx, asset)
+	if err != nil {
+		s.removeObjects(ctx, asset.Variants)
+		return nil, err
+	}
+	s.setURLs(created)
+
+	if created.Kind == KindPoster {
+		primary, _ := created.Variant(spec.PrimaryVariant)
+		if _, err := s.movies.UpdateMovie(ctx, created.MovieID, movie.UpdateMovieRequest{PosterUrl: &primary.URL}); err != nil {
+			// The movie may have been deleted since it was checked; don't leave the asset behind.
+			if delErr := s.repo.Delete(ctx, created.ID); delErr != nil {
+				logger.WarnCtx(ctx, "failed to remove media asset after poster update failed",
+					zap.Int64("asset_id", created.ID),
+					zap.Error(delErr),
+				)
+			}
+			s.removeObjects(ctx, created.Variants)
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+func (s *service) GetAsset(ctx context.Context, id int64) (*Asset, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.setURLs(a)
+	return a, nil
+}
+
+func (s *service) ListAssets(ctx context.Context, movieId int64) ([]Asset, error) {
+	assets, err := s.repo.ListByMovie(ctx, movieId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		s.setURLs(&assets[i])
+	}
+	return assets, nil
+}
+
+// DeleteAsset removes the asset and its stored variants. If the asset was the movie's
+// poster, the most recent remaining poster takes its place.
+func (s *service) DeleteAsset(ctx context.Context, id int64) error {
+	a, err := s.GetAsset(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.removeObjects(ctx, a.Variants)
+
+	if a.Kind == KindPoster {
+		return s.replacePoster(ctx, a)
+	}
+	return nil
+}
+
+func (s *service) GetVariant(ctx context.Context, assetId int64, name string) (*Variant, []byte, error) {
+	v, err := s.repo.GetVariant(ctx, assetId, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.storage.Get(ctx, v.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, data, nil
+}
+
+// replacePoster points the movie at its newest remaining poster if it was using the deleted
+// one, or leaves it without a poster if none remain.
+func (s *service) replacePoster(ctx context.Context, deleted *Asset) error {
+	m, err := s.movies.GetMovie(ctx, deleted.MovieID)
+	if err != nil {
+		return err
+	}
+	primary, ok := deleted.Variant(kinds[KindPoster].PrimaryVariant)
+	if !ok || m.PosterUrl != primary.URL {
+		return nil
+	}
+
+	assets, err := s.ListAssets(ctx, deleted.MovieID)
+	if err != nil {
+		return err
+	}
+	for _, a := range assets {
+		if a.Kind != KindPoster {
+			continue
+		}
+		if v, ok := a.Variant(kinds[KindPoster].PrimaryVariant); ok {
+			_, err = s.movies.UpdateMovie(ctx, deleted.MovieID, movie.UpdateMovieRequest{PosterUrl: &v.URL})
+			return err
+		}
+	}
+	return s.movies.ClearPoster(ctx, deleted.MovieID)
+}
+
+func (s *service) setURLs(a *Asset) {
+	for i := range a.Variants {
+		a.Variants[i].URL = fmt.Sprintf("%s/api/v1/media/%d/%s", s.baseURL, a.ID, a.Variants[i].Name)
+	}
+}
+
+// removeObjects deletes stored variants on a best-effort basis; orphaned objects are harmless.
+func (s *service) removeObjects(ctx context.Context, variants []Variant) {
+	for _, v := range variants {
+		if err := s.storage.Delete(ctx, v.StorageKey); err != nil {
+			logger.WarnCtx(ctx, "failed to delete stored media variant",
+				zap.String("key", v.StorageKey),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
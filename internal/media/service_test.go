@@ -0,0 +1,144 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRepo struct {
+	assets []Asset
+	nextID int64
+}
+
+func (r *memoryRepo) Create(ctx context.Context, a Asset) (*Asset, error) {
+	r.nextID++
+	a.ID = r.nextID
+	// Newest first, like the database listing.
+	r.assets = append([]Asset{a}, r.assets...)
+	return &a, nil
+}
+
+func (r *memoryRepo) GetByID(ctx context.Context, id int64) (*Asset, error) {
+	for _, a := range r.assets {
+		if a.ID == id {
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRepo) ListByMovie(ctx context.Context, movieId int64) ([]Asset, error) {
+	var res []Asset
+	for _, a := range r.assets {
+		if a.MovieID == movieId {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryRepo) GetVariant(ctx context.Context, assetId int64, name string) (*Variant, error) {
+	return nil, ErrVariantNotFound
+}
+
+func (r *memoryRepo) Delete(ctx context.Context, id int64) error {
+	for i, a := range r.assets {
+		if a.ID == id {
+			r.assets = append(r.assets[:i], r.assets[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s[key] = data
+	return nil
+}
+
+func (s memoryStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (s memoryStorage) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+// fakeMovies holds a single movie's poster URL; a nil poster means the poster was cleared.
+type fakeMovies struct {
+	movie.Service
+	poster    *string
+	updateErr error
+}
+
+func (m *fakeMovies) GetMovie(ctx context.Context, id int64) (*movie.Movie, error) {
+	mv := &movie.Movie{ID: id}
+	if m.poster != nil {
+		mv.PosterUrl = *m.poster
+	}
+	return mv, nil
+}
+
+func (m *fakeMovies) UpdateMovie(ctx context.Context, id int64, req movie.UpdateMovieRequest) (*movie.Movie, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	m.poster = req.PosterUrl
+	return m.GetMovie(ctx, id)
+}
+
+func (m *fakeMovies) ClearPoster(ctx context.Context, id int64) error {
+	m.poster = nil
+	return nil
+}
+
+func posterPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, solid(400, 600, color.RGBA{200, 30, 30, 255})))
+	return buf.Bytes()
+}
+
+func TestUploadRollsBackWhenPosterUpdateFails(t *testing.T) {
+	ctx := context.Background()
+	repo, storage := &memoryRepo{}, memoryStorage{}
+	movies := &fakeMovies{updateErr: movie.ErrNotFound}
+	svc := NewService(repo, storage, movies, "http://localhost")
+
+	_, err := svc.Upload(ctx, UploadRequest{MovieID: 1, Kind: KindPoster, Filename: "poster.png", Data: posterPNG(t)})
+	require.ErrorIs(t, err, movie.ErrNotFound)
+	require.Empty(t, repo.assets)
+	require.Empty(t, storage)
+}
+
+func TestDeletingPostersFallsBackThenClears(t *testing.T) {
+	ctx := context.Background()
+	repo, storage := &memoryRepo{}, memoryStorage{}
+	movies := &fakeMovies{}
+	svc := NewService(repo, storage, movies, "http://localhost")
+
+	first, err := svc.Upload(ctx, UploadRequest{MovieID: 1, Kind: KindPoster, Filename: "a.png", Data: posterPNG(t)})
+	require.NoError(t, err)
+	second, err := svc.Upload(ctx, UploadRequest{MovieID: 1, Kind: KindPoster, Filename: "b.png", Data: posterPNG(t)})
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost/api/v1/media/2/large", *movies.poster)
+
+	require.NoError(t, svc.DeleteAsset(ctx, second.ID))
+	require.Equal(t, "http://localhost/api/v1/media/1/large", *movies.poster)
+
+	require.NoError(t, svc.DeleteAsset(ctx, first.ID))
+	require.Nil(t, movies.poster)
+	require.Empty(t, storage)
+}
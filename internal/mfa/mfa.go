@@ -0,0 +1,88 @@
+package mfa
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotEnrolled     = errors.New("two-factor authentication is not set up")
+	ErrAlreadyEnrolled = errors.New("two-factor authentication is already enabled")
+	ErrInvalidCode     = errors.New("the code is incorrect or has already been used")
+	ErrLocked          = errors.New("too many incorrect codes, please try again later")
+	ErrRequired        = errors.New("two-factor authentication is required for this account")
+	ErrUnavailable     = errors.New("authenticator apps are not available on this server")
+)
+
+const (
+	// PendingTokenDuration is how long a user has to enter a code after their password.
+	PendingTokenDuration = 5 * time.Minute
+	// MaxAttempts wrong codes in a row lock the authenticator for LockoutDuration.
+	MaxAttempts     = 5
+	LockoutDuration = 15 * time.Minute
+	// RecoveryCodeCount is how many recovery codes are issued at a time.
+	RecoveryCodeCount = 10
+)
+
+// TOTP is a user's authenticator app enrolment.
+type TOTP struct {
+	UserID           uuid.UUID
+	SecretCiphertext []byte
+	// ConfirmedAt is nil until the user enters a code from the app; until then the
+	// enrolment can be restarted and is not asked for at sign in.
+	ConfirmedAt *time.Time
+	// LastUsedStep is the time step of the last code accepted.
+	LastUsedStep int64
+	LockedUntil  *time.Time
+	CreatedAt    time.Time
+}
+
+// Enabled reports whether the enrolment is confirmed.
+func (t *TOTP) Enabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// Options configures the MFA service.
+type Options struct {
+	// Issuer names the service in authenticator apps.
+	Issuer string
+	// RequiredRoles are the roles that must enrol before using their privileges.
+	RequiredRoles []string
+}
+
+// Request/Response models
+
+// CodeRequest carries a code from an authenticator app, or a recovery code.
+type CodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// StatusResponse describes the current user's two-factor authentication.
+type StatusResponse struct {
+	// TOTPAvailable is false when the server has no key to encrypt authenticator secrets.
+	TOTPAvailable          bool       `json:"totp_available"`
+	TOTPEnabled            bool       `json:"totp_enabled"`
+	TOTPConfirmedAt        *time.Time `json:"totp_confirmed_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+	Required               bool       `json:"required"`
+}
+
+// EnrolmentResponse is what the user needs to add the account to an authenticator app.
+// ProvisioningURI is meant to be shown as a QR code; Secret is for typing in by hand.
+type EnrolmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse lists newly issued recovery codes. They are only ever shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ChallengeResponse tells a client that signing in needs a code as well as the password.
+type ChallengeResponse struct {
+	MFARequired bool      `json:"mfa_required"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,35 @@
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the data access contract for the mfa domain.
+type Repository interface {
+	// GetTOTP returns the user's enrolment, or ErrNotEnrolled if they have none.
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*TOTP, error)
+	// SavePendingTOTP starts or restarts an unconfirmed enrolment, returning
+	// ErrAlreadyEnrolled if the user has a confirmed one.
+	SavePendingTOTP(ctx context.Context, userID uuid.UUID, secretCiphertext []byte) error
+	// ConfirmTOTP confirms the enrolment with the code for step and issues the recovery
+	// codes. It returns ErrInvalidCode if step has been used and ErrAlreadyEnrolled if the
+	// enrolment was already confirmed.
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	// UseTOTPStep records that a code for step was accepted, returning false if a code
+	// for step or a later one already was.
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// RecordFailure counts a wrong code, locking the enrolment until lockUntil once
+	// maxAttempts wrong codes have been given in a row.
+	RecordFailure(ctx context.Context, userID uuid.UUID, maxAttempts int32, lockUntil time.Time) error
+	// DeleteTOTP removes the enrolment and the user's recovery codes.
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
+
+	// UseRecoveryCode marks an unused recovery code used, returning false if there is none.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	// ReplaceRecoveryCodes discards the user's recovery codes and stores new ones.
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,55 @@
+package mfa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// minimumEncryptionKeyLength matches the symmetric token key requirement.
+const minimumEncryptionKeyLength = 32
+
+// secretBox encrypts TOTP secrets at rest with AES-256-GCM, so a database leak alone does
+// not let anyone generate codes.
+type secretBox struct {
+	aead cipher.AEAD
+}
+
+func newSecretBox(key string) (*secretBox, error) {
+	if len(key) < minimumEncryptionKeyLength {
+		return nil, fmt.Errorf("invalid encryption key length, it must be at least %d characters", minimumEncryptionKeyLength)
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretBox{aead}, nil
+}
+
+func (b *secretBox) seal(secret string) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, []byte(secret), nil), nil
+}
+
+func (b *secretBox) open(ciphertext []byte) (string, error) {
+	size := b.aead.NonceSize()
+	if len(ciphertext) < size {
+		return "", errors.New("ciphertext too short")
+	}
+	plain, err := b.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt TOTP secret: %w", err)
+	}
+	return string(plain), nil
+}
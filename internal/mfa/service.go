@@ -0,0 +1,275 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Service defines the business operations for the mfa domain.
+type Service interface {
+	// Status describes the user's two-factor authentication.
+	Status(ctx context.Context, userID uuid.UUID, role string) (*StatusResponse, error)
+	// Enabled reports whether the user has to give a code after their password.
+	Enabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	// BeginTOTP generates a new secret for the user's authenticator app. The enrolment
+	// takes effect once confirmed with ConfirmTOTP.
+	BeginTOTP(ctx context.Context, userID uuid.UUID, accountName string) (*EnrolmentResponse, error)
+	// ConfirmTOTP enables the authenticator with a code from it and returns the user's
+	// recovery codes.
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// DisableTOTP removes the authenticator after checking a code. Users whose role
+	// requires two-factor authentication cannot disable it.
+	DisableTOTP(ctx context.Context, userID uuid.UUID, role, code string) error
+	// RegenerateRecoveryCodes replaces the user's recovery codes after checking a code.
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// Verify checks a code from the authenticator app, or an unused recovery code. Each
+	// code works once, and too many wrong codes return ErrLocked for a while.
+	Verify(ctx context.Context, userID uuid.UUID, code string) error
+	// CheckPolicy returns ErrRequired if the role requires two-factor authentication and
+	// the user has not enabled it.
+	CheckPolicy(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+type service struct {
+	repo Repository
+	// secrets is nil when no encryption key is configured.
+	secrets *secretBox
+	opts    Options
+	now     func() time.Time
+}
+
+// NewService creates a new mfa service. TOTP secrets are encrypted with encryptionKey.
+// Without a key, enrolling an authenticator app returns ErrUnavailable; users enrolled
+// earlier are still asked for a second factor, which only their recovery codes can satisfy.
+func NewService(repo Repository, encryptionKey string, opts Options) (Service, error) {
+	s := &service{repo: repo, opts: opts, now: time.Now}
+	if encryptionKey == "" {
+		return s, nil
+	}
+
+	secrets, err := newSecretBox(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	s.secrets = secrets
+	return s, nil
+}
+
+func (s *service) Status(ctx context.Context, userID uuid.UUID, role string) (*StatusResponse, error) {
+	res := &StatusResponse{TOTPAvailable: s.secrets != nil, Required: s.required(role)}
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, ErrNotEnrolled) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !t.Enabled() {
+		return res, nil
+	}
+
+	res.TOTPEnabled = true
+	res.TOTPConfirmedAt = t.ConfirmedAt
+	if res.RecoveryCodesRemaining, err = s.repo.CountRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, ErrNotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.Enabled(), nil
+}
+
+func (s *service) BeginTOTP(ctx context.Context, userID uuid.UUID, accountName string) (*EnrolmentResponse, error) {
+	if s.secrets == nil {
+		return nil, ErrUnavailable
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	ciphertext, err := s.secrets.seal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	if err := s.repo.SavePendingTOTP(ctx, userID, ciphertext); err != nil {
+		return nil, err
+	}
+
+	return &EnrolmentResponse{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI(s.opts.Issuer, accountName, secret),
+	}, nil
+}
+
+func (s *service) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled() {
+		return nil, ErrAlreadyEnrolled
+	}
+	if err := s.checkLock(t); err != nil {
+		return nil, err
+	}
+
+	step, ok, err := s.matchTOTP(t, strings.TrimSpace(code))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.fail(ctx, userID)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := s.repo.ConfirmTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "two-factor authentication enabled",
+		zap.String("user_id", userID.String()),
+	)
+	return codes, nil
+}
+
+func (s *service) DisableTOTP(ctx context.Context, userID uuid.UUID, role, code string) error {
+	if s.required(role) {
+		return ErrRequired
+	}
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTOTP(ctx, userID); err != nil {
+		return err
+	}
+
+	logger.InfoCtx(ctx, "two-factor authentication disabled",
+		zap.String("user_id", userID.String()),
+	)
+	return nil
+}
+
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *service) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	code = strings.TrimSpace(code)
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled() {
+		return ErrNotEnrolled
+	}
+	if err := s.checkLock(t); err != nil {
+		return err
+	}
+
+	if isTOTPCode(code) {
+		step, ok, err := s.matchTOTP(t, code)
+		if err != nil {
+			return err
+		}
+		if ok && step > t.LastUsedStep {
+			used, err := s.repo.UseTOTPStep(ctx, userID, step)
+			if err != nil {
+				return err
+			}
+			if used {
+				return nil
+			}
+		}
+	} else {
+		used, err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		if used {
+			logger.InfoCtx(ctx, "recovery code used",
+				zap.String("user_id", userID.String()),
+			)
+			return nil
+		}
+	}
+
+	return s.fail(ctx, userID)
+}
+
+func (s *service) CheckPolicy(ctx context.Context, userID uuid.UUID, role string) error {
+	if !s.required(role) {
+		return nil
+	}
+	enabled, err := s.Enabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrRequired
+	}
+	return nil
+}
+
+func (s *service) required(role string) bool {
+	return slices.Contains(s.opts.RequiredRoles, role)
+}
+
+// matchTOTP returns the time step code is valid for.
+func (s *service) matchTOTP(t *TOTP, code string) (int64, bool, error) {
+	if s.secrets == nil {
+		return 0, false, ErrUnavailable
+	}
+	secret, err := s.secrets.open(t.SecretCiphertext)
+	if err != nil {
+		return 0, false, err
+	}
+	step, ok := matchTOTP(secret, code, s.now())
+	return step, ok, nil
+}
+
+func (s *service) checkLock(t *TOTP) error {
+	if t.LockedUntil != nil && s.now().Before(*t.LockedUntil) {
+		return ErrLocked
+	}
+	return nil
+}
+
+// fail records a wrong code and returns ErrInvalidCode.
+func (s *service) fail(ctx context.Context, userID uuid.UUID) error {
+	logger.WarnCtx(ctx, "incorrect two-factor code",
+		zap.String("user_id", userID.String()),
+	)
+	if err := s.repo.RecordFailure(ctx, userID, MaxAttempts, s.now().Add(LockoutDuration)); err != nil {
+		return err
+	}
+	return ErrInvalidCode
+}
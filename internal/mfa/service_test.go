@@ -0,0 +1,215 @@
+package mfa
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo is a Repository over maps, enough to exercise enrolment and code checks.
+type memoryRepo struct {
+	totp     map[uuid.UUID]*TOTP
+	failures map[uuid.UUID]int32
+	codes    map[uuid.UUID]map[string]bool
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{
+		totp:     make(map[uuid.UUID]*TOTP),
+		failures: make(map[uuid.UUID]int32),
+		codes:    make(map[uuid.UUID]map[string]bool),
+	}
+}
+
+func (r *memoryRepo) GetTOTP(ctx context.Context, userID uuid.UUID) (*TOTP, error) {
+	t, ok := r.totp[userID]
+	if !ok {
+		return nil, ErrNotEnrolled
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (r *memoryRepo) SavePendingTOTP(ctx context.Context, userID uuid.UUID, secretCiphertext []byte) error {
+	if t, ok := r.totp[userID]; ok && t.Enabled() {
+		return ErrAlreadyEnrolled
+	}
+	r.totp[userID] = &TOTP{UserID: userID, SecretCiphertext: secretCiphertext}
+	return nil
+}
+
+func (r *memoryRepo) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	used, _ := r.UseTOTPStep(ctx, userID, step)
+	if !used {
+		return ErrInvalidCode
+	}
+	now := time.Now()
+	r.totp[userID].ConfirmedAt = &now
+	return r.ReplaceRecoveryCodes(ctx, userID, recoveryCodeHashes)
+}
+
+func (r *memoryRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	t := r.totp[userID]
+	if t == nil || t.LastUsedStep >= step {
+		return false, nil
+	}
+	t.LastUsedStep, t.LockedUntil, r.failures[userID] = step, nil, 0
+	return true, nil
+}
+
+func (r *memoryRepo) RecordFailure(ctx context.Context, userID uuid.UUID, maxAttempts int32, lockUntil time.Time) error {
+	r.failures[userID]++
+	if r.failures[userID] >= maxAttempts {
+		r.failures[userID] = 0
+		r.totp[userID].LockedUntil = &lockUntil
+	}
+	return nil
+}
+
+func (r *memoryRepo) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	delete(r.totp, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *memoryRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	if unused, ok := r.codes[userID][codeHash]; ok && unused {
+		r.codes[userID][codeHash] = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *memoryRepo) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	r.codes[userID] = make(map[string]bool)
+	for _, h := range codeHashes {
+		r.codes[userID][h] = true
+	}
+	return nil
+}
+
+func (r *memoryRepo) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var n int64
+	for _, unused := range r.codes[userID] {
+		if unused {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// enrol enables TOTP for a new user, returning their secret key and recovery codes.
+func enrol(t *testing.T, s *service) (uuid.UUID, []byte, []string) {
+	ctx := context.Background()
+	userID := uuid.New()
+	enrolment, err := s.BeginTOTP(ctx, userID, "jane@example.com")
+	require.NoError(t, err)
+	key, err := secretEncoding.DecodeString(enrolment.Secret)
+	require.NoError(t, err)
+
+	codes, err := s.ConfirmTOTP(ctx, userID, totpCode(key, totpStep(s.now())))
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+	return userID, key, codes
+}
+
+func newTestService(t *testing.T, opts Options) *service {
+	svc, err := NewService(newMemoryRepo(), "an-encryption-key-of-32-characters", opts)
+	require.NoError(t, err)
+	return svc.(*service)
+}
+
+func TestVerifyRejectsReplayedCode(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t, Options{Issuer: "Mobo"})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	userID, key, _ := enrol(t, s)
+
+	// The code used to confirm the enrolment cannot sign in
+	require.ErrorIs(t, s.Verify(ctx, userID, totpCode(key, totpStep(now))), ErrInvalidCode)
+
+	next := totpCode(key, totpStep(now)+1)
+	require.NoError(t, s.Verify(ctx, userID, next))
+	require.ErrorIs(t, s.Verify(ctx, userID, next), ErrInvalidCode)
+}
+
+func TestRecoveryCodesWorkOnce(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t, Options{Issuer: "Mobo"})
+	userID, _, codes := enrol(t, s)
+
+	require.NoError(t, s.Verify(ctx, userID, " "+codes[0]+" "))
+	require.ErrorIs(t, s.Verify(ctx, userID, codes[0]), ErrInvalidCode)
+
+	// Codes may be typed without the dash and in capitals
+	require.NoError(t, s.Verify(ctx, userID, strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))))
+
+	status, err := s.Status(ctx, userID, "user")
+	require.NoError(t, err)
+	require.True(t, status.TOTPEnabled)
+	require.Equal(t, int64(RecoveryCodeCount-2), status.RecoveryCodesRemaining)
+}
+
+func TestVerifyLocksAfterRepeatedFailures(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t, Options{Issuer: "Mobo"})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	userID, key, _ := enrol(t, s)
+
+	for range MaxAttempts {
+		require.ErrorIs(t, s.Verify(ctx, userID, "000000"), ErrInvalidCode)
+	}
+	// Even a correct code is refused while locked
+	require.ErrorIs(t, s.Verify(ctx, userID, totpCode(key, totpStep(now)+1)), ErrLocked)
+
+	s.now = func() time.Time { return now.Add(LockoutDuration + time.Minute) }
+	require.NoError(t, s.Verify(ctx, userID, totpCode(key, totpStep(s.now()))))
+}
+
+func TestPolicyRequiresEnrolment(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t, Options{Issuer: "Mobo", RequiredRoles: []string{"admin"}})
+
+	require.NoError(t, s.CheckPolicy(ctx, uuid.New(), "user"))
+	require.ErrorIs(t, s.CheckPolicy(ctx, uuid.New(), "admin"), ErrRequired)
+
+	userID, _, codes := enrol(t, s)
+	require.NoError(t, s.CheckPolicy(ctx, userID, "admin"))
+	require.ErrorIs(t, s.DisableTOTP(ctx, userID, "admin", codes[0]), ErrRequired)
+	require.NoError(t, s.DisableTOTP(ctx, userID, "user", codes[0]))
+
+	enabled, err := s.Enabled(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, enabled)
+}
+
+func TestWithoutEncryptionKey(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepo()
+	s := newTestService(t, Options{Issuer: "Mobo"})
+	s.repo = repo
+	userID, key, codes := enrol(t, s)
+
+	svc, err := NewService(repo, "", Options{Issuer: "Mobo"})
+	require.NoError(t, err)
+	s = svc.(*service)
+
+	status, err := s.Status(ctx, userID, "user")
+	require.NoError(t, err)
+	require.False(t, status.TOTPAvailable)
+	_, err = s.BeginTOTP(ctx, uuid.New(), "jane@example.com")
+	require.ErrorIs(t, err, ErrUnavailable)
+
+	// Users enrolled earlier still need a second factor, which a recovery code provides
+	enabled, err := s.Enabled(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, enabled)
+	require.ErrorIs(t, s.Verify(ctx, userID, totpCode(key, totpStep(time.Now())+1)), ErrUnavailable)
+	require.NoError(t, s.Verify(ctx, userID, codes[0]))
+}
@@ -0,0 +1,51 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	// SHA1 test vectors from RFC 6238 appendix B, truncated to six digits.
+	key := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		require.Equal(t, want, totpCode(key, totpStep(time.Unix(unix, 0))), "time %d", unix)
+	}
+}
+
+func TestMatchTOTPAllowsOneStepOfSkew(t *testing.T) {
+	secret, err := newSecret()
+	require.NoError(t, err)
+	key, err := secretEncoding.DecodeString(secret)
+	require.NoError(t, err)
+
+	now := time.Now()
+	step := totpStep(now)
+	for _, s := range []int64{step - 1, step, step + 1} {
+		matched, ok := matchTOTP(secret, totpCode(key, s), now)
+		require.True(t, ok)
+		require.Equal(t, s, matched)
+	}
+	_, ok := matchTOTP(secret, totpCode(key, step-2), now)
+	require.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(provisioningURI("Mobo", "jane@example.com", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/Mobo:jane@example.com", uri.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	require.Equal(t, "Mobo", uri.Query().Get("issuer"))
+}
@@ -0,0 +1,201 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrCatalogEntryNotFound is returned when the catalogue provider has no entry with the given ID.
+	ErrCatalogEntryNotFound = errors.New("catalogue entry not found")
+	// ErrCatalogUnavailable is returned when the catalogue provider cannot be reached.
+	ErrCatalogUnavailable = errors.New("catalogue provider is unavailable")
+	// ErrAlreadyImported is returned when importing a catalogue entry that is already linked to a movie.
+	ErrAlreadyImported = errors.New("this catalogue entry has already been imported")
+	// ErrNotLinked is returned when refreshing a movie that is not linked to a catalogue entry
+	// and no external ID was given.
+	ErrNotLinked = errors.New("movie is not linked to a catalogue entry; an external_id is required")
+)
+
+// maxImportedCast is how many billed cast members are imported from a catalogue entry.
+const maxImportedCast = 10
+
+// CatalogProvider looks up movie metadata in an external catalogue such as TMDB.
+type CatalogProvider interface {
+	// Search finds entries whose title matches query, best match first.
+	Search(ctx context.Context, query string) ([]CatalogMatch, error)
+	// Lookup returns the entry with the given ID, or ErrCatalogEntryNotFound.
+	Lookup(ctx context.Context, externalID string) (*CatalogEntry, error)
+}
+
+// CatalogMatch is a catalogue search result.
+type CatalogMatch struct {
+	ExternalID  string     `json:"external_id"`
+	Title       string     `json:"title"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+	PosterUrl   string     `json:"poster_url,omitempty"`
+}
+
+// CatalogEntry is a movie's metadata as held by a catalogue provider. Fields the
+// provider does not know are left empty.
+type CatalogEntry struct {
+	ExternalID  string
+	Title       string
+	Description string
+	// Runtime is in seconds, like Movie.Runtime.
+	Runtime     int32
+	Genres      []string
+	AgeRating   string
+	Directors   []string
+	Cast        []string
+	PosterUrl   string
+	ReleaseDate *time.Time
+}
+
+// ToAddMovieRequest pre-fills a request to add the movie. The first genre and director
+// are used, along with the top-billed cast.
+func (e *CatalogEntry) ToAddMovieRequest() AddMovieRequest {
+	req := AddMovieRequest{
+		Title:       e.Title,
+		Description: e.Description,
+		Runtime:     e.Runtime,
+		AgeRating:   e.AgeRating,
+		PosterUrl:   e.PosterUrl,
+		Cast:        e.billedCast(),
+		ExternalID:  &e.ExternalID,
+	}
+	if len(e.Genres) > 0 {
+		req.Genre = e.Genres[0]
+	}
+	if len(e.Directors) > 0 {
+		req.Director = e.Directors[0]
+	}
+	if e.ReleaseDate != nil {
+		req.ReleaseDate = e.ReleaseDate.Format("2006-01-02")
+	}
+	return req
+}
+
+func (e *CatalogEntry) billedCast() []string {
+	if len(e.Cast) > maxImportedCast {
+		return e.Cast[:maxImportedCast]
+	}
+	return e.Cast
+}
+
+// FieldChange is a difference between a movie and its catalogue entry.
+type FieldChange struct {
+	Field    string `json:"field"`
+	Current  any    `json:"current"`
+	Incoming any    `json:"incoming"`
+}
+
+// CatalogDiff lists how a movie differs from its catalogue entry. Update holds the
+// changes as an UpdateMovieRequest, ready to be applied.
+type CatalogDiff struct {
+	MovieID    int64              `json:"movie_id"`
+	ExternalID string             `json:"external_id"`
+	Changes    []FieldChange      `json:"changes"`
+	Update     UpdateMovieRequest `json:"update"`
+}
+
+// ApplyCatalogRequest represents the request to refresh a movie from its catalogue entry.
+type ApplyCatalogRequest struct {
+	// ExternalID links the movie to a catalogue entry if it is not linked yet.
+	ExternalID *string `json:"external_id" validate:"omitempty,min=1"`
+	// Fields limits the refresh to the given fields. All changes are applied if it is empty.
+	Fields []string `json:"fields" validate:"omitempty,dive,oneof=title description runtime genre age_rating director poster_url release_date cast"`
+}
+
+// diffCatalogEntry compares a movie with its catalogue entry. Fields the entry leaves
+// empty are never reported, so a sparse entry cannot clear existing data.
+func diffCatalogEntry(m *Movie, e *CatalogEntry) *CatalogDiff {
+	d := &CatalogDiff{
+		MovieID:    m.ID,
+		ExternalID: e.ExternalID,
+		Changes:    []FieldChange{},
+	}
+	incoming := e.ToAddMovieRequest()
+
+	if incoming.Title != "" && incoming.Title != m.Title {
+		d.add("title", m.Title, incoming.Title)
+		d.Update.Title = &incoming.Title
+	}
+	if incoming.Description != "" && incoming.Description != m.Description {
+		d.add("description", m.Description, incoming.Description)
+		d.Update.Description = &incoming.Description
+	}
+	if incoming.Runtime > 0 && incoming.Runtime != m.Runtime {
+		d.add("runtime", m.Runtime, incoming.Runtime)
+		d.Update.Runtime = &incoming.Runtime
+	}
+	if incoming.Genre != "" && incoming.Genre != m.Genre {
+		d.add("genre", m.Genre, incoming.Genre)
+		d.Update.Genre = &incoming.Genre
+	}
+	if incoming.AgeRating != "" && incoming.AgeRating != m.AgeRating {
+		d.add("age_rating", m.AgeRating, incoming.AgeRating)
+		d.Update.AgeRating = &incoming.AgeRating
+	}
+	if incoming.Director != "" && incoming.Director != m.Director {
+		d.add("director", m.Director, incoming.Director)
+		d.Update.Director = &incoming.Director
+	}
+	if incoming.PosterUrl != "" && incoming.PosterUrl != m.PosterUrl {
+		d.add("poster_url", m.PosterUrl, incoming.PosterUrl)
+		d.Update.PosterUrl = &incoming.PosterUrl
+	}
+	if current := m.ReleaseDate.Format("2006-01-02"); incoming.ReleaseDate != "" && incoming.ReleaseDate != current {
+		d.add("release_date", current, incoming.ReleaseDate)
+		d.Update.ReleaseDate = &incoming.ReleaseDate
+	}
+	if len(incoming.Cast) > 0 && !slices.Equal(incoming.Cast, m.Cast) {
+		d.add("cast", m.Cast, incoming.Cast)
+		d.Update.Cast = incoming.Cast
+	}
+	return d
+}
+
+func (d *CatalogDiff) add(field string, current, incoming any) {
+	d.Changes = append(d.Changes, FieldChange{Field: field, Current: current, Incoming: incoming})
+}
+
+// only returns the update restricted to the given fields, or the whole update if none are given.
+func (d *CatalogDiff) only(fields []string) UpdateMovieRequest {
+	if len(fields) == 0 {
+		return d.Update
+	}
+
+	u := d.Update
+	keep := func(field string) bool { return slices.Contains(fields, field) }
+	if !keep("title") {
+		u.Title = nil
+	}
+	if !keep("description") {
+		u.Description = nil
+	}
+	if !keep("runtime") {
+		u.Runtime = nil
+	}
+	if !keep("genre") {
+		u.Genre = nil
+	}
+	if !keep("age_rating") {
+		u.AgeRating = nil
+	}
+	if !keep("director") {
+		u.Director = nil
+	}
+	if !keep("poster_url") {
+		u.PosterUrl = nil
+	}
+	if !keep("release_date") {
+		u.ReleaseDate = nil
+	}
+	if !keep("cast") {
+		u.Cast = nil
+	}
+	return u
+}
@@ -0,0 +1,272 @@
+package movie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func catalogFixture() (*Movie, *CatalogEntry) {
+	released := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	m := &Movie{
+		ID:          1,
+		Title:       "Dune: Part Two",
+		Description: "Paul joins the Fremen.",
+		Runtime:     9960,
+		Genre:       "Science Fiction",
+		AgeRating:   "12A",
+		Director:    "Denis Villeneuve",
+		PosterUrl:   "https://img.example.com/dune.jpg",
+		ReleaseDate: released,
+		Cast:        []string{"Timothée Chalamet", "Zendaya"},
+	}
+	e := &CatalogEntry{
+		ExternalID:  "693134",
+		Title:       m.Title,
+		Description: m.Description,
+		Runtime:     m.Runtime,
+		Genres:      []string{m.Genre, "Adventure"},
+		AgeRating:   m.AgeRating,
+		Directors:   []string{m.Director},
+		Cast:        m.Cast,
+		PosterUrl:   m.PosterUrl,
+		ReleaseDate: &released,
+	}
+	return m, e
+}
+
+func TestDiffCatalogEntry(t *testing.T) {
+	later := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		change func(e *CatalogEntry)
+		want   []FieldChange
+		update UpdateMovieRequest
+	}{
+		{
+			name:   "identical",
+			change: func(*CatalogEntry) {},
+			want:   []FieldChange{},
+		},
+		{
+			name:   "title",
+			change: func(e *CatalogEntry) { e.Title = "Dune 2" },
+			want:   []FieldChange{{Field: "title", Current: "Dune: Part Two", Incoming: "Dune 2"}},
+			update: UpdateMovieRequest{Title: ptr("Dune 2")},
+		},
+		{
+			name:   "runtime",
+			change: func(e *CatalogEntry) { e.Runtime = 9900 },
+			want:   []FieldChange{{Field: "runtime", Current: int32(9960), Incoming: int32(9900)}},
+			update: UpdateMovieRequest{Runtime: ptr(int32(9900))},
+		},
+		{
+			name:   "primary genre only",
+			change: func(e *CatalogEntry) { e.Genres = []string{"Adventure", "Science Fiction"} },
+			want:   []FieldChange{{Field: "genre", Current: "Science Fiction", Incoming: "Adventure"}},
+			update: UpdateMovieRequest{Genre: ptr("Adventure")},
+		},
+		{
+			name:   "release date",
+			change: func(e *CatalogEntry) { e.ReleaseDate = &later },
+			want:   []FieldChange{{Field: "release_date", Current: "2024-03-01", Incoming: "2024-03-15"}},
+			update: UpdateMovieRequest{ReleaseDate: ptr("2024-03-15")},
+		},
+		{
+			name:   "cast order",
+			change: func(e *CatalogEntry) { e.Cast = []string{"Zendaya", "Timothée Chalamet"} },
+			want: []FieldChange{{
+				Field:    "cast",
+				Current:  []string{"Timothée Chalamet", "Zendaya"},
+				Incoming: []string{"Zendaya", "Timothée Chalamet"},
+			}},
+			update: UpdateMovieRequest{Cast: []string{"Zendaya", "Timothée Chalamet"}},
+		},
+		{
+			name: "empty fields never clear data",
+			change: func(e *CatalogEntry) {
+				*e = CatalogEntry{ExternalID: e.ExternalID}
+			},
+			want: []FieldChange{},
+		},
+		{
+			name: "several fields in order",
+			change: func(e *CatalogEntry) {
+				e.AgeRating = "15"
+				e.Description = "Paul unites with the Fremen."
+			},
+			want: []FieldChange{
+				{Field: "description", Current: "Paul joins the Fremen.", Incoming: "Paul unites with the Fremen."},
+				{Field: "age_rating", Current: "12A", Incoming: "15"},
+			},
+			update: UpdateMovieRequest{Description: ptr("Paul unites with the Fremen."), AgeRating: ptr("15")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, e := catalogFixture()
+			tt.change(e)
+
+			d := diffCatalogEntry(m, e)
+			require.Equal(t, m.ID, d.MovieID)
+			require.Equal(t, "693134", d.ExternalID)
+			require.Equal(t, tt.want, d.Changes)
+			require.Equal(t, tt.update, d.Update)
+		})
+	}
+}
+
+func TestCatalogDiffOnly(t *testing.T) {
+	d := &CatalogDiff{Update: UpdateMovieRequest{
+		Title:     ptr("Dune 2"),
+		Runtime:   ptr(int32(9900)),
+		AgeRating: ptr("15"),
+		Cast:      []string{"Zendaya"},
+	}}
+	tests := []struct {
+		name   string
+		fields []string
+		want   UpdateMovieRequest
+	}{
+		{"no selection keeps everything", nil, d.Update},
+		{"single field", []string{"title"}, UpdateMovieRequest{Title: ptr("Dune 2")}},
+		{
+			name:   "several fields",
+			fields: []string{"cast", "runtime"},
+			want:   UpdateMovieRequest{Runtime: ptr(int32(9900)), Cast: []string{"Zendaya"}},
+		},
+		{"unchanged field", []string{"director"}, UpdateMovieRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, d.only(tt.fields))
+		})
+	}
+}
+
+type catalogRepo struct {
+	Repository
+	movies  map[int64]*Movie
+	updates []UpdateMovieRequest
+}
+
+func (r *catalogRepo) GetByID(_ context.Context, id int64) (*Movie, error) {
+	m, ok := r.movies[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m, nil
+}
+
+func (r *catalogRepo) GetByExternalID(_ context.Context, externalID string) (*Movie, error) {
+	for _, m := range r.movies {
+		if m.ExternalID != nil && *m.ExternalID == externalID {
+			return m, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *catalogRepo) Update(_ context.Context, id int64, req UpdateMovieRequest) (*Movie, error) {
+	r.updates = append(r.updates, req)
+	return r.movies[id], nil
+}
+
+func (r *catalogRepo) ListGenresByMovies(context.Context, []int64) (map[int64][]Genre, error) {
+	return nil, nil
+}
+
+func (r *catalogRepo) ListCreditsByMovies(context.Context, []int64) (map[int64][]Credit, error) {
+	return nil, nil
+}
+
+type fixedCatalog struct {
+	entries map[string]*CatalogEntry
+}
+
+func (c *fixedCatalog) Search(context.Context, string) ([]CatalogMatch, error) { return nil, nil }
+
+func (c *fixedCatalog) Lookup(_ context.Context, externalID string) (*CatalogEntry, error) {
+	e, ok := c.entries[externalID]
+	if !ok {
+		return nil, ErrCatalogEntryNotFound
+	}
+	return e, nil
+}
+
+func TestApplyCatalog(t *testing.T) {
+	ctx := context.Background()
+	scheme, err := RatingSchemeFor("uk")
+	require.NoError(t, err)
+
+	newService := func() (Service, *catalogRepo) {
+		m, e := catalogFixture()
+		e.Title = "Dune 2"
+		e.AgeRating = "15"
+		e.Runtime = 9900
+		other := &Movie{ID: 2, Title: "Arrival", ExternalID: ptr("329865")}
+		repo := &catalogRepo{movies: map[int64]*Movie{1: m, 2: other}}
+		catalog := &fixedCatalog{entries: map[string]*CatalogEntry{
+			"693134": e,
+			"329865": {ExternalID: "329865", Title: "Arrival"},
+		}}
+		return NewService(repo, nil, catalog, scheme), repo
+	}
+
+	tests := []struct {
+		name    string
+		req     ApplyCatalogRequest
+		wantErr error
+		want    UpdateMovieRequest
+	}{
+		{
+			name:    "unlinked movie needs an external ID",
+			req:     ApplyCatalogRequest{},
+			wantErr: ErrNotLinked,
+		},
+		{
+			name:    "entry linked to another movie",
+			req:     ApplyCatalogRequest{ExternalID: ptr("329865")},
+			wantErr: ErrAlreadyImported,
+		},
+		{
+			name: "every change",
+			req:  ApplyCatalogRequest{ExternalID: ptr("693134")},
+			want: UpdateMovieRequest{
+				Title:      ptr("Dune 2"),
+				Runtime:    ptr(int32(9900)),
+				AgeRating:  ptr("15"),
+				ExternalID: ptr("693134"),
+			},
+		},
+		{
+			name: "selected fields",
+			req:  ApplyCatalogRequest{ExternalID: ptr("693134"), Fields: []string{"runtime", "director"}},
+			want: UpdateMovieRequest{Runtime: ptr(int32(9900)), ExternalID: ptr("693134")},
+		},
+		{
+			name: "no selected field changed still links the movie",
+			req:  ApplyCatalogRequest{ExternalID: ptr("693134"), Fields: []string{"cast"}},
+			want: UpdateMovieRequest{ExternalID: ptr("693134")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newService()
+			_, err := s.ApplyCatalog(ctx, 1, tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Empty(t, repo.updates)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []UpdateMovieRequest{tt.want}, repo.updates)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
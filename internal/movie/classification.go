@@ -0,0 +1,196 @@
+package movie
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownAgeRating is returned when an age rating is not part of the configured scheme.
+	ErrUnknownAgeRating = errors.New("unknown age rating for the configured rating region")
+	// ErrUnknownRatingRegion is returned when no rating scheme exists for a region.
+	ErrUnknownRatingRegion = errors.New("no age rating scheme for region")
+)
+
+// Admission policies, from least to most restrictive.
+const (
+	// PolicyNone admits anyone.
+	PolicyNone = "none"
+	// PolicyAdvisory admits anyone, but parents are cautioned about younger children.
+	PolicyAdvisory = "advisory"
+	// PolicyAccompanied admits anyone under MinAge only with an adult.
+	PolicyAccompanied = "accompanied"
+	// PolicyRestricted admits no one under MinAge.
+	PolicyRestricted = "restricted"
+)
+
+// Ticket types, used to check admission against a classification.
+const (
+	TicketAdult  = "adult"
+	TicketChild  = "child"
+	TicketSenior = "senior"
+)
+
+// ChildTicketMaxAge is the oldest age a child ticket is sold for.
+const ChildTicketMaxAge = 15
+
+// Classification is one age rating in a regional rating scheme.
+type Classification struct {
+	Code string
+	// MinAge is the age the policy applies below; zero for ratings without an age limit.
+	MinAge      int
+	Policy      string
+	Description string
+}
+
+// RequiresIDCheck reports whether staff should check the age of attendees at admission.
+func (c *Classification) RequiresIDCheck() bool {
+	return c.Policy == PolicyRestricted
+}
+
+// ToResponse converts a Classification to a ClassificationResponse.
+func (c *Classification) ToResponse() ClassificationResponse {
+	return ClassificationResponse{
+		Code:        c.Code,
+		MinAge:      c.MinAge,
+		Policy:      c.Policy,
+		Description: c.Description,
+		IDCheck:     c.RequiresIDCheck(),
+	}
+}
+
+// ClassificationResponse represents the API response for an age rating.
+type ClassificationResponse struct {
+	Code        string `json:"code"`
+	MinAge      int    `json:"min_age"`
+	Policy      string `json:"policy"`
+	Description string `json:"description"`
+	IDCheck     bool   `json:"id_check"`
+}
+
+// RatingScheme is the ordered set of age ratings used in a region.
+type RatingScheme struct {
+	Region  string
+	Ratings []Classification
+}
+
+// Lookup returns the classification for a rating code, ignoring case, or ErrUnknownAgeRating.
+func (s *RatingScheme) Lookup(code string) (*Classification, error) {
+	for i := range s.Ratings {
+		if strings.EqualFold(s.Ratings[i].Code, strings.TrimSpace(code)) {
+			return &s.Ratings[i], nil
+		}
+	}
+	return nil, ErrUnknownAgeRating
+}
+
+// Codes lists the scheme's rating codes, least restrictive first.
+func (s *RatingScheme) Codes() []string {
+	codes := make([]string, 0, len(s.Ratings))
+	for _, r := range s.Ratings {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+var ratingSchemes = map[string]RatingScheme{
+	"US": {Region: "US", Ratings: []Classification{
+		{Code: "G", Policy: PolicyNone, Description: "General audiences"},
+		{Code: "PG", Policy: PolicyAdvisory, Description: "Parental guidance suggested"},
+		{Code: "PG-13", MinAge: 13, Policy: PolicyAdvisory, Description: "Parents strongly cautioned, some material may be inappropriate for children under 13"},
+		{Code: "R", MinAge: 17, Policy: PolicyAccompanied, Description: "Under 17 requires accompanying parent or adult guardian"},
+		{Code: "NC-17", MinAge: 18, Policy: PolicyRestricted, Description: "No one 17 and under admitted"},
+	}},
+	"UK": {Region: "UK", Ratings: []Classification{
+		{Code: "U", Policy: PolicyNone, Description: "Universal, suitable for all"},
+		{Code: "PG", MinAge: 8, Policy: PolicyAdvisory, Description: "Parental guidance, some scenes may be unsuitable for young children"},
+		{Code: "12A", MinAge: 12, Policy: PolicyAccompanied, Description: "Under 12 must be accompanied by an adult"},
+		{Code: "15", MinAge: 15, Policy: PolicyRestricted, Description: "No one younger than 15 admitted"},
+		{Code: "18", MinAge: 18, Policy: PolicyRestricted, Description: "No one younger than 18 admitted"},
+		{Code: "R18", MinAge: 18, Policy: PolicyRestricted, Description: "Adults only, licensed cinemas"},
+	}},
+}
+
+// RatingSchemeFor returns the built-in rating scheme for a region such as US or UK.
+func RatingSchemeFor(region string) (*RatingScheme, error) {
+	scheme, ok := ratingSchemes[strings.ToUpper(region)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRatingRegion, region)
+	}
+	return &scheme, nil
+}
+
+// TicketCount is a number of tickets of one type.
+type TicketCount struct {
+	Type     string `json:"type" validate:"required,oneof=adult child senior"`
+	Quantity int32  `json:"quantity" validate:"required,min=1"`
+}
+
+// Admission is the outcome of checking a booking against a classification.
+type Admission struct {
+	Allowed bool
+	// Reasons explains why the booking was refused; empty when it is allowed.
+	Reasons  []string
+	Warnings []string
+}
+
+// AssessAdmission checks a booking against a classification. dateOfBirth is the booker's, if
+// known; child tickets are assumed to be for attendees aged ChildTicketMaxAge or younger.
+func (c *Classification) AssessAdmission(tickets []TicketCount, dateOfBirth *time.Time, now time.Time) Admission {
+	var adults, children int32
+	for _, t := range tickets {
+		if t.Type == TicketChild {
+			children += t.Quantity
+		} else {
+			adults += t.Quantity
+		}
+	}
+
+	admission := Admission{Allowed: true}
+	refuse := func(reason string) {
+		admission.Allowed = false
+		admission.Reasons = append(admission.Reasons, reason)
+	}
+
+	if dateOfBirth != nil && c.MinAge > 0 && AgeOn(*dateOfBirth, now) < c.MinAge {
+		switch c.Policy {
+		case PolicyRestricted:
+			refuse(fmt.Sprintf("%s films are restricted to ages %d and over", c.Code, c.MinAge))
+		case PolicyAccompanied:
+			admission.Warnings = append(admission.Warnings, fmt.Sprintf("you must be accompanied by an adult to see a %s film", c.Code))
+		}
+	}
+
+	if children > 0 {
+		switch c.Policy {
+		case PolicyRestricted:
+			if c.MinAge > ChildTicketMaxAge {
+				refuse(fmt.Sprintf("child tickets cannot be booked for %s films", c.Code))
+			} else {
+				admission.Warnings = append(admission.Warnings, fmt.Sprintf("children must be at least %d to see a %s film", c.MinAge, c.Code))
+			}
+		case PolicyAccompanied:
+			if adults == 0 {
+				refuse(fmt.Sprintf("children under %d must be accompanied by an adult to see a %s film", c.MinAge, c.Code))
+			}
+		case PolicyAdvisory:
+			admission.Warnings = append(admission.Warnings, fmt.Sprintf("%s: %s", c.Code, c.Description))
+		}
+	}
+
+	if admission.Allowed && c.RequiresIDCheck() {
+		admission.Warnings = append(admission.Warnings, fmt.Sprintf("photo ID may be required to prove attendees are %d or over", c.MinAge))
+	}
+	return admission
+}
+
+// AgeOn returns the age in whole years of someone born on dateOfBirth, as of day.
+func AgeOn(dateOfBirth, day time.Time) int {
+	age := day.Year() - dateOfBirth.Year()
+	if day.Month() < dateOfBirth.Month() || (day.Month() == dateOfBirth.Month() && day.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,145 @@
+package movie
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 19, 30, 0, 0, time.UTC)
+}
+
+func TestAgeOn(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateOfBirth time.Time
+		day         time.Time
+		want        int
+	}{
+		{"day before birthday", date(2000, time.June, 15), date(2018, time.June, 14), 17},
+		{"on birthday", date(2000, time.June, 15), date(2018, time.June, 15), 18},
+		{"earlier month", date(2000, time.June, 15), date(2018, time.May, 30), 17},
+		{"later month", date(2000, time.June, 15), date(2018, time.July, 1), 18},
+		{"leap day before 1 March", date(2004, time.February, 29), date(2022, time.February, 28), 17},
+		{"leap day from 1 March", date(2004, time.February, 29), date(2022, time.March, 1), 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, AgeOn(tt.dateOfBirth, tt.day))
+		})
+	}
+}
+
+func TestAssessAdmission(t *testing.T) {
+	scheme, err := RatingSchemeFor("uk")
+	require.NoError(t, err)
+	rating := func(code string) *Classification {
+		c, err := scheme.Lookup(code)
+		require.NoError(t, err)
+		return c
+	}
+	adult := []TicketCount{{Type: TicketAdult, Quantity: 1}}
+	child := []TicketCount{{Type: TicketChild, Quantity: 2}}
+	family := []TicketCount{{Type: TicketAdult, Quantity: 1}, {Type: TicketChild, Quantity: 2}}
+	screening := date(2026, time.March, 14)
+	fourteen := date(2011, time.March, 15)
+	fifteen := date(2011, time.March, 14)
+	eleven := date(2015, time.January, 1)
+
+	tests := []struct {
+		name        string
+		code        string
+		tickets     []TicketCount
+		dateOfBirth *time.Time
+		allowed     bool
+		reasons     []string
+		warnings    []string
+	}{
+		{
+			name:    "universal",
+			code:    "U",
+			tickets: child,
+			allowed: true,
+		},
+		{
+			name:     "advisory warns about children",
+			code:     "PG",
+			tickets:  family,
+			allowed:  true,
+			warnings: []string{"PG: Parental guidance, some scenes may be unsuitable for young children"},
+		},
+		{
+			name:    "accompanied children without an adult",
+			code:    "12A",
+			tickets: child,
+			allowed: false,
+			reasons: []string{"children under 12 must be accompanied by an adult to see a 12A film"},
+		},
+		{
+			name:    "accompanied children with an adult",
+			code:    "12A",
+			tickets: family,
+			allowed: true,
+		},
+		{
+			name:        "accompanied booker under age",
+			code:        "12A",
+			tickets:     adult,
+			dateOfBirth: &eleven,
+			allowed:     true,
+			warnings:    []string{"you must be accompanied by an adult to see a 12A film"},
+		},
+		{
+			name:        "restricted booker under age",
+			code:        "15",
+			tickets:     adult,
+			dateOfBirth: &fourteen,
+			allowed:     false,
+			reasons:     []string{"15 films are restricted to ages 15 and over"},
+		},
+		{
+			name:        "restricted booker comes of age on the day",
+			code:        "15",
+			tickets:     adult,
+			dateOfBirth: &fifteen,
+			allowed:     true,
+			warnings:    []string{"photo ID may be required to prove attendees are 15 or over"},
+		},
+		{
+			name:    "restricted child tickets within child ages",
+			code:    "15",
+			tickets: family,
+			allowed: true,
+			warnings: []string{
+				"children must be at least 15 to see a 15 film",
+				"photo ID may be required to prove attendees are 15 or over",
+			},
+		},
+		{
+			name:    "restricted child tickets above child ages",
+			code:    "18",
+			tickets: family,
+			allowed: false,
+			reasons: []string{"child tickets cannot be booked for 18 films"},
+		},
+		{
+			name:    "unknown date of birth",
+			code:    "18",
+			tickets: adult,
+			allowed: true,
+			warnings: []string{
+				"photo ID may be required to prove attendees are 18 or over",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rating(tt.code).AssessAdmission(tt.tickets, tt.dateOfBirth, screening)
+			require.Equal(t, tt.allowed, got.Allowed)
+			require.Equal(t, tt.reasons, got.Reasons)
+			require.Equal(t, tt.warnings, got.Warnings)
+		})
+	}
+}
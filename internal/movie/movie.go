@@ -1,6 +1,61 @@
 package movie
 
-import "time"
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a movie is not found.
+	ErrNotFound = errors.New("movie not found")
+	// ErrInvalidSort is returned when a listing is sorted by an unknown key.
+	ErrInvalidSort = errors.New("sort must be rating")
+	// ErrInvalidStatus is returned when a search filters on an unknown release status.
+	ErrInvalidStatus = errors.New("status must be one of announced, coming_soon, now_showing or archived")
+	// ErrNotUpcoming is returned when registering interest in a movie that is already on sale or archived.
+	ErrNotUpcoming = errors.New("interest can only be registered for announced or coming-soon movies")
+	// ErrInterestNotFound is returned when withdrawing interest that was never registered.
+	ErrInterestNotFound = errors.New("no interest registered for this movie")
+	// ErrGenreNotFound is returned when a genre is not found.
+	ErrGenreNotFound = errors.New("genre not found")
+	// ErrGenreExists is returned when a genre with the same slug already exists.
+	ErrGenreExists = errors.New("a genre with this name already exists")
+	// ErrDuplicateGenre is returned when a movie's genres list the same genre more than once.
+	ErrDuplicateGenre = errors.New("genre_ids lists the same genre more than once")
+	// ErrPersonNotFound is returned when a person is not found.
+	ErrPersonNotFound = errors.New("person not found")
+	// ErrShowtimesScheduled is returned when deleting a movie would strand scheduled showtimes and cascade was not requested.
+	ErrShowtimesScheduled = errors.New("movie has showtimes scheduled, retry with cascade=true to cancel them")
+)
+
+// Credit roles.
+const (
+	RoleDirector = "director"
+	RoleCast     = "cast"
+	RoleWriter   = "writer"
+)
+
+// Release statuses, derived from the release date and scheduled showtimes. A movie is
+// now showing while tickets are on sale for a future showtime, coming soon when it
+// releases within ComingSoonWindow, announced when it releases later, and archived otherwise.
+const (
+	StatusAnnounced  = "announced"
+	StatusComingSoon = "coming_soon"
+	StatusNowShowing = "now_showing"
+	StatusArchived   = "archived"
+)
+
+// SortRating orders listings by average rating, highest first.
+const SortRating = "rating"
+
+// ComingSoonWindow is how far ahead of release an announced movie becomes coming soon.
+// It must match the movie_status SQL function.
+const ComingSoonWindow = 56 * 24 * time.Hour
 
 // Movie represents a movie in the system.
 type Movie struct {
@@ -13,8 +68,30 @@ type Movie struct {
 	Director    string
 	PosterUrl   string
 	ReleaseDate time.Time
-	CreatedAt   time.Time
-	UpdatedAt   *time.Time
+	// Cast is the billed cast in order. Like Genre (the primary genre) and Director
+	// (director names joined with commas), it is denormalized from Genres and Credits.
+	Cast      []string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+
+	Genres  []Genre
+	Credits []Credit
+
+	// Status is one of the release statuses.
+	Status string
+	// ExternalID identifies the movie in the catalogue provider it was imported from.
+	ExternalID *string
+
+	// RatingAverage is the mean rating of visible reviews, or nil if there are none.
+	RatingAverage *float64
+	RatingCount   int32
+
+	// Classification is AgeRating in the configured rating scheme, or nil if the code is
+	// not part of it (such as ratings stored before the scheme was introduced).
+	Classification *Classification
+
+	// DeletedAt is set for movies in the trash.
+	DeletedAt *time.Time
 }
 
 // ToResponse converts a Movie to a MovieResponse.
@@ -24,6 +101,22 @@ func (m *Movie) ToResponse() MovieResponse {
 		updatedAt = *m.UpdatedAt
 	}
 
+	genres := make([]GenreResponse, 0, len(m.Genres))
+	for _, g := range m.Genres {
+		genres = append(genres, g.ToResponse())
+	}
+
+	credits := make([]CreditResponse, 0, len(m.Credits))
+	for _, c := range m.Credits {
+		credits = append(credits, c.ToResponse())
+	}
+
+	var classification *ClassificationResponse
+	if m.Classification != nil {
+		res := m.Classification.ToResponse()
+		classification = &res
+	}
+
 	return MovieResponse{
 		ID:          m.ID,
 		Title:       m.Title,
@@ -34,11 +127,37 @@ func (m *Movie) ToResponse() MovieResponse {
 		Director:    m.Director,
 		PosterUrl:   m.PosterUrl,
 		ReleaseDate: m.ReleaseDate,
+		Cast:        m.Cast,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   updatedAt,
+		Genres:      genres,
+		Credits:     credits,
+		Status:      m.Status,
+		ExternalID:  m.ExternalID,
+		Rating: RatingResponse{
+			Average: roundRating(m.RatingAverage),
+			Count:   m.RatingCount,
+		},
+		Classification: classification,
+		DeletedAt:      m.DeletedAt,
 	}
 }
 
+// RatingResponse represents a movie's aggregated review rating.
+type RatingResponse struct {
+	Average *float64 `json:"average"`
+	Count   int32    `json:"count"`
+}
+
+// roundRating rounds an average rating to two decimal places.
+func roundRating(avg *float64) *float64 {
+	if avg == nil {
+		return nil
+	}
+	rounded := math.Round(*avg*100) / 100
+	return &rounded
+}
+
 // MovieResponse represents the API response for a movie.
 type MovieResponse struct {
 	ID          int64     `json:"id"`
@@ -50,30 +169,269 @@ type MovieResponse struct {
 	Director    string    `json:"director"`
 	PosterUrl   string    `json:"poster_url"`
 	ReleaseDate time.Time `json:"release_date"`
+	Cast        []string  `json:"cast"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+
+	Genres     []GenreResponse  `json:"genres"`
+	Credits    []CreditResponse `json:"credits"`
+	Status     string           `json:"status,omitempty"`
+	ExternalID *string          `json:"external_id,omitempty"`
+	Rating     RatingResponse   `json:"rating"`
+
+	Classification *ClassificationResponse `json:"classification,omitempty"`
+	DeletedAt      *time.Time              `json:"deleted_at,omitempty"`
 }
 
 // AddMovieRequest represents the request to add a new movie.
+// Genre, Director and Cast are matched to existing genres and people by name, creating
+// them as needed; use the genre and credit endpoints for anything richer.
 type AddMovieRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Runtime     int32  `json:"runtime" validate:"required,min=1"`
-	Genre       string `json:"genre" validate:"required"`
-	AgeRating   string `json:"age_rating" validate:"required"`
-	Director    string `json:"director" validate:"required"`
-	PosterUrl   string `json:"poster_url" validate:"required,url"`
-	ReleaseDate string `json:"release_date" validate:"required,datetime=2006-01-02"`
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description" validate:"required"`
+	Runtime     int32    `json:"runtime" validate:"required,min=1"`
+	Genre       string   `json:"genre" validate:"required"`
+	AgeRating   string   `json:"age_rating" validate:"required"`
+	Director    string   `json:"director" validate:"required"`
+	PosterUrl   string   `json:"poster_url" validate:"omitempty,url"`
+	ReleaseDate string   `json:"release_date" validate:"required,datetime=2006-01-02"`
+	Cast        []string `json:"cast" validate:"omitempty,dive,required"`
+	ExternalID  *string  `json:"external_id" validate:"omitempty,min=1"`
 }
 
 // UpdateMovieRequest represents the request to update a movie.
+// Genre, Director and Cast replace the movie's genres, directors and cast respectively.
 type UpdateMovieRequest struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Runtime     *int32  `json:"runtime" validate:"omitempty,min=1"`
-	Genre       *string `json:"genre"`
-	AgeRating   *string `json:"age_rating"`
-	Director    *string `json:"director"`
-	PosterUrl   *string `json:"poster_url" validate:"omitempty,url"`
-	ReleaseDate *string `json:"release_date" validate:"omitempty,datetime=2006-01-02"`
+	Title       *string  `json:"title"`
+	Description *string  `json:"description"`
+	Runtime     *int32   `json:"runtime" validate:"omitempty,min=1"`
+	Genre       *string  `json:"genre" validate:"omitempty,min=1"`
+	AgeRating   *string  `json:"age_rating"`
+	Director    *string  `json:"director" validate:"omitempty,min=1"`
+	PosterUrl   *string  `json:"poster_url" validate:"omitempty,url"`
+	ReleaseDate *string  `json:"release_date" validate:"omitempty,datetime=2006-01-02"`
+	Cast        []string `json:"cast" validate:"omitempty,dive,required"`
+	ExternalID  *string  `json:"external_id" validate:"omitempty,min=1"`
+}
+
+// Interest is a customer's request to be told when tickets for a movie go on sale.
+type Interest struct {
+	UserID    uuid.UUID
+	MovieID   int64
+	Email     string
+	FullName  string
+	CreatedAt time.Time
+}
+
+// Notifier delivers on-sale notifications to customers who registered interest.
+type Notifier interface {
+	NotifyOnSale(ctx context.Context, m *Movie, recipients []Interest) error
+}
+
+// Genre is a movie genre. Movies may have several, the first being the primary genre.
+type Genre struct {
+	ID   int32
+	Name string
+	Slug string
+}
+
+// ToResponse converts a Genre to a GenreResponse.
+func (g *Genre) ToResponse() GenreResponse {
+	return GenreResponse{
+		ID:   g.ID,
+		Name: g.Name,
+		Slug: g.Slug,
+	}
+}
+
+// GenreResponse represents the API response for a genre.
+type GenreResponse struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Person is someone credited on a movie, e.g. as director, cast member or writer.
+type Person struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+
+	// Filmography is only populated when the person is fetched individually.
+	Filmography []FilmographyEntry
+}
+
+// ToResponse converts a Person to a PersonResponse.
+func (p *Person) ToResponse() PersonResponse {
+	var updatedAt time.Time
+	if p.UpdatedAt != nil {
+		updatedAt = *p.UpdatedAt
+	}
+
+	return PersonResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   updatedAt,
+		Filmography: p.Filmography,
+	}
+}
+
+// PersonResponse represents the API response for a person.
+type PersonResponse struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at,omitempty"`
+	Filmography []FilmographyEntry `json:"filmography,omitempty"`
+}
+
+// FilmographyEntry is one credit of a person, newest release first.
+type FilmographyEntry struct {
+	MovieID     int64     `json:"movie_id"`
+	Title       string    `json:"title"`
+	PosterUrl   string    `json:"poster_url"`
+	ReleaseDate time.Time `json:"release_date"`
+	Role        string    `json:"role"`
+	Character   *string   `json:"character,omitempty"`
+}
+
+// Credit links a person to a movie in a role. Position orders credits within a role.
+type Credit struct {
+	PersonID  int64
+	Name      string
+	Role      string
+	Character *string
+	Position  int32
+}
+
+// ToResponse converts a Credit to a CreditResponse.
+func (c *Credit) ToResponse() CreditResponse {
+	return CreditResponse{
+		PersonID:  c.PersonID,
+		Name:      c.Name,
+		Role:      c.Role,
+		Character: c.Character,
+		Position:  c.Position,
+	}
+}
+
+// CreditResponse represents the API response for a movie credit.
+type CreditResponse struct {
+	PersonID  int64   `json:"person_id"`
+	Name      string  `json:"name"`
+	Role      string  `json:"role"`
+	Character *string `json:"character,omitempty"`
+	Position  int32   `json:"position"`
+}
+
+// CreateGenreRequest represents the request to create a genre.
+type CreateGenreRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// SetGenresRequest replaces a movie's genres. The first genre becomes the primary genre.
+type SetGenresRequest struct {
+	GenreIDs []int32 `json:"genre_ids" validate:"required,min=1,dive,min=1"`
+}
+
+// CreatePersonRequest represents the request to create a person.
+type CreatePersonRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// UpdatePersonRequest represents the request to update a person.
+type UpdatePersonRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1"`
+}
+
+// SetCreditsRequest replaces a movie's credits. Credits are ordered within each role
+// in the order they are listed.
+type SetCreditsRequest struct {
+	Credits []CreditRequest `json:"credits" validate:"dive"`
+}
+
+// CreditRequest represents one credit in a SetCreditsRequest.
+type CreditRequest struct {
+	PersonID  int64   `json:"person_id" validate:"required,min=1"`
+	Role      string  `json:"role" validate:"required,oneof=director cast writer"`
+	Character *string `json:"character" validate:"omitempty,min=1"`
+}
+
+// Slugify derives the URL-friendly slug that identifies a genre from its name.
+func Slugify(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// SearchFilter narrows a catalogue search. Nil fields are not applied.
+type SearchFilter struct {
+	// Query is matched against the title, description, director and cast, tolerating typos in the title.
+	Query       *string
+	Genre       *string
+	AgeRating   *string
+	ReleaseYear *int32
+	Status      *string
+	// Sort is SortRating, or nil for relevance.
+	Sort   *string
+	Limit  int32
+	Offset int32
+}
+
+// IsEmpty reports whether no search criteria were given.
+func (f *SearchFilter) IsEmpty() bool {
+	return f.Query == nil && f.Genre == nil && f.AgeRating == nil && f.ReleaseYear == nil && f.Status == nil
+}
+
+// FacetCount is the number of matching movies sharing one facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Facets summarise the whole result set of a search, not just the current page.
+type Facets struct {
+	Genre       []FacetCount `json:"genre"`
+	AgeRating   []FacetCount `json:"age_rating"`
+	ReleaseYear []FacetCount `json:"release_year"`
+	Status      []FacetCount `json:"status"`
+}
+
+// SearchResult is a page of matching movies together with facets over all matches.
+type SearchResult struct {
+	Movies []Movie
+	Total  int64
+	Facets Facets
+}
+
+// ToResponse converts a SearchResult to a SearchResultResponse.
+func (r *SearchResult) ToResponse() SearchResultResponse {
+	results := make([]MovieResponse, 0, len(r.Movies))
+	for _, m := range r.Movies {
+		results = append(results, m.ToResponse())
+	}
+
+	return SearchResultResponse{
+		Results: results,
+		Total:   r.Total,
+		Facets:  r.Facets,
+	}
+}
+
+// SearchResultResponse represents the API response for a catalogue search.
+type SearchResultResponse struct {
+	Results []MovieResponse `json:"results"`
+	Total   int64           `json:"total"`
+	Facets  Facets          `json:"facets"`
 }
@@ -1,13 +1,57 @@
 package movie
 
-import "context"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 // Repository defines the data access contract for the movie domain.
 type Repository interface {
 	Add(ctx context.Context, req AddMovieRequest) (*Movie, error)
 	GetByID(ctx context.Context, id int64) (*Movie, error)
+	// ListByIDs returns the given movies that exist, in no particular order.
+	ListByIDs(ctx context.Context, ids []int64) ([]Movie, error)
+	// GetByExternalID returns the movie linked to a catalogue entry, or ErrNotFound.
+	GetByExternalID(ctx context.Context, externalID string) (*Movie, error)
 	ListAdmin(ctx context.Context, limit, offset int32) ([]Movie, error)
-	ListPublic(ctx context.Context, limit, offset int32) ([]Movie, error)
+	ListPublic(ctx context.Context, limit, offset int32, sort *string) ([]Movie, error)
+	ListComingSoon(ctx context.Context, limit, offset int32) ([]Movie, error)
+	Search(ctx context.Context, filter SearchFilter) (*SearchResult, error)
 	Update(ctx context.Context, id int64, req UpdateMovieRequest) (*Movie, error)
-	Delete(ctx context.Context, id int64) error
+	// ClearPoster removes the movie's poster URL, returning ErrNotFound if there is no such movie.
+	ClearPoster(ctx context.Context, id int64) error
+	// Delete soft-deletes the movie. It returns ErrShowtimesScheduled if future showtimes
+	// exist, unless cascade is set, in which case they are deleted and their reservations
+	// cancelled, with paid ones marked for refund.
+	Delete(ctx context.Context, id int64, cascade bool) error
+	// GetDeleted returns a movie in the trash, or ErrNotFound.
+	GetDeleted(ctx context.Context, id int64) (*Movie, error)
+	ListDeleted(ctx context.Context, limit, offset int32) ([]Movie, error)
+	// Restore undoes a soft delete, together with the future showtimes deleted with the movie.
+	Restore(ctx context.Context, id int64) (*Movie, error)
+
+	CreateGenre(ctx context.Context, name, slug string) (*Genre, error)
+	ListGenres(ctx context.Context) ([]Genre, error)
+	DeleteGenre(ctx context.Context, id int32) error
+	// SetGenres replaces the movie's genres, returning ErrGenreNotFound if any are unknown.
+	SetGenres(ctx context.Context, movieId int64, genreIds []int32) error
+	// ListGenresByMovies returns the genres of each of the given movies, keyed by movie ID.
+	ListGenresByMovies(ctx context.Context, movieIds []int64) (map[int64][]Genre, error)
+
+	CreatePerson(ctx context.Context, name string) (*Person, error)
+	GetPerson(ctx context.Context, id int64) (*Person, error)
+	SearchPeople(ctx context.Context, query *string, limit, offset int32) ([]Person, error)
+	UpdatePerson(ctx context.Context, id int64, req UpdatePersonRequest) (*Person, error)
+	ListFilmography(ctx context.Context, personId int64) ([]FilmographyEntry, error)
+	// SetCredits replaces the movie's credits, returning ErrPersonNotFound if anyone is unknown.
+	SetCredits(ctx context.Context, movieId int64, credits []CreditRequest) error
+	// ListCreditsByMovies returns the credits of each of the given movies, keyed by movie ID.
+	ListCreditsByMovies(ctx context.Context, movieIds []int64) (map[int64][]Credit, error)
+
+	CreateInterest(ctx context.Context, userId uuid.UUID, movieId int64) error
+	DeleteInterest(ctx context.Context, userId uuid.UUID, movieId int64) error
+	// ListPendingInterests returns interested customers who have not yet been notified.
+	ListPendingInterests(ctx context.Context, movieId int64) ([]Interest, error)
+	MarkInterestsNotified(ctx context.Context, movieId int64, userIds []uuid.UUID) error
 }
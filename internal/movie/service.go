@@ -1,46 +1,427 @@
 package movie
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
 
 // Service defines the business operations for the movie domain.
 type Service interface {
 	AddMovie(ctx context.Context, req AddMovieRequest) (*Movie, error)
 	GetMovie(ctx context.Context, id int64) (*Movie, error)
+	// GetMovies returns the given movies in the order of ids, skipping any that do not exist.
+	GetMovies(ctx context.Context, ids []int64) ([]Movie, error)
 	ListMoviesAdmin(ctx context.Context, limit, offset int32) ([]Movie, error)
-	ListMoviesPublic(ctx context.Context, limit, offset int32) ([]Movie, error)
+	// ListMoviesPublic lists movies now showing, soonest screening first, or by rating if
+	// sort is SortRating.
+	ListMoviesPublic(ctx context.Context, limit, offset int32, sort *string) ([]Movie, error)
+	ListMoviesComingSoon(ctx context.Context, limit, offset int32) ([]Movie, error)
+	SearchMovies(ctx context.Context, filter SearchFilter) (*SearchResult, error)
 	UpdateMovie(ctx context.Context, id int64, req UpdateMovieRequest) (*Movie, error)
-	DeleteMovie(ctx context.Context, id int64) error
+	// ClearPoster leaves the movie without a poster, e.g. once its last poster asset is deleted.
+	ClearPoster(ctx context.Context, id int64) error
+	DeleteMovie(ctx context.Context, id int64, cascade bool) error
+	ListDeletedMovies(ctx context.Context, limit, offset int32) ([]Movie, error)
+	RestoreMovie(ctx context.Context, id int64) (*Movie, error)
+
+	CreateGenre(ctx context.Context, req CreateGenreRequest) (*Genre, error)
+	ListGenres(ctx context.Context) ([]Genre, error)
+	DeleteGenre(ctx context.Context, id int32) error
+	SetMovieGenres(ctx context.Context, movieId int64, req SetGenresRequest) (*Movie, error)
+
+	CreatePerson(ctx context.Context, req CreatePersonRequest) (*Person, error)
+	// GetPerson returns the person together with their filmography.
+	GetPerson(ctx context.Context, id int64) (*Person, error)
+	SearchPeople(ctx context.Context, query *string, limit, offset int32) ([]Person, error)
+	UpdatePerson(ctx context.Context, id int64, req UpdatePersonRequest) (*Person, error)
+	SetMovieCredits(ctx context.Context, movieId int64, req SetCreditsRequest) (*Movie, error)
+
+	RegisterInterest(ctx context.Context, userId uuid.UUID, movieId int64) error
+	WithdrawInterest(ctx context.Context, userId uuid.UUID, movieId int64) error
+	// NotifyOnSale tells customers who registered interest that tickets are on sale. Each
+	// customer is notified at most once, so it is safe to call whenever a showtime is added.
+	NotifyOnSale(ctx context.Context, movieId int64) error
+
+	SearchCatalog(ctx context.Context, query string) ([]CatalogMatch, error)
+	// PrepareImport pre-fills a request to add the movie described by a catalogue entry.
+	PrepareImport(ctx context.Context, externalID string) (*AddMovieRequest, error)
+	// DiffCatalog compares a movie with its catalogue entry. externalID links the movie to
+	// an entry if it is not linked yet.
+	DiffCatalog(ctx context.Context, movieId int64, externalID *string) (*CatalogDiff, error)
+	// ApplyCatalog refreshes a movie with the differences reported by DiffCatalog.
+	ApplyCatalog(ctx context.Context, movieId int64, req ApplyCatalogRequest) (*Movie, error)
+
+	// Classify looks up an age rating in the configured rating scheme.
+	Classify(code string) (*Classification, error)
 }
 
 type service struct {
-	repo Repository
+	repo     Repository
+	notifier Notifier
+	catalog  CatalogProvider
+	ratings  *RatingScheme
 }
 
-// NewService creates a new movie service.
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// NewService creates a new movie service. Age ratings are validated against ratings.
+func NewService(repo Repository, notifier Notifier, catalog CatalogProvider, ratings *RatingScheme) Service {
+	return &service{repo: repo, notifier: notifier, catalog: catalog, ratings: ratings}
 }
 
 func (s *service) AddMovie(ctx context.Context, req AddMovieRequest) (*Movie, error) {
-	return s.repo.Add(ctx, req)
+	c, err := s.ratings.Lookup(req.AgeRating)
+	if err != nil {
+		return nil, err
+	}
+	req.AgeRating = c.Code
+
+	if req.ExternalID != nil {
+		if err := s.ensureNotImported(ctx, *req.ExternalID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := s.repo.Add(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCatalog(ctx, m)
 }
 
+// GetMovie returns the movie together with its genres and credits.
 func (s *service) GetMovie(ctx context.Context, id int64) (*Movie, error) {
-	return s.repo.GetByID(ctx, id)
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCatalog(ctx, m)
+}
+
+func (s *service) GetMovies(ctx context.Context, ids []int64) ([]Movie, error) {
+	found, err := s.repo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]Movie, len(found))
+	for _, m := range found {
+		byID[m.ID] = m
+	}
+	movies := make([]Movie, 0, len(found))
+	for _, id := range ids {
+		if m, ok := byID[id]; ok {
+			movies = append(movies, m)
+		}
+	}
+	return movies, s.attachCatalog(ctx, movies)
 }
 
 func (s *service) ListMoviesAdmin(ctx context.Context, limit, offset int32) ([]Movie, error) {
-	return s.repo.ListAdmin(ctx, limit, offset)
+	movies, err := s.repo.ListAdmin(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return movies, s.attachCatalog(ctx, movies)
+}
+
+func (s *service) ListMoviesPublic(ctx context.Context, limit, offset int32, sort *string) ([]Movie, error) {
+	if err := validateSort(sort); err != nil {
+		return nil, err
+	}
+
+	movies, err := s.repo.ListPublic(ctx, limit, offset, sort)
+	if err != nil {
+		return nil, err
+	}
+	return movies, s.attachCatalog(ctx, movies)
+}
+
+func (s *service) ListMoviesComingSoon(ctx context.Context, limit, offset int32) ([]Movie, error) {
+	movies, err := s.repo.ListComingSoon(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return movies, s.attachCatalog(ctx, movies)
+}
+
+func (s *service) SearchMovies(ctx context.Context, filter SearchFilter) (*SearchResult, error) {
+	if filter.Status != nil {
+		switch *filter.Status {
+		case StatusAnnounced, StatusComingSoon, StatusNowShowing, StatusArchived:
+		default:
+			return nil, ErrInvalidStatus
+		}
+	}
+	if err := validateSort(filter.Sort); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return result, s.attachCatalog(ctx, result.Movies)
 }
 
-func (s *service) ListMoviesPublic(ctx context.Context, limit, offset int32) ([]Movie, error) {
-	return s.repo.ListPublic(ctx, limit, offset)
+func (s *service) ClearPoster(ctx context.Context, id int64) error {
+	return s.repo.ClearPoster(ctx, id)
 }
 
 func (s *service) UpdateMovie(ctx context.Context, id int64, req UpdateMovieRequest) (*Movie, error) {
-	return s.repo.Update(ctx, id, req)
+	if req.AgeRating != nil {
+		c, err := s.ratings.Lookup(*req.AgeRating)
+		if err != nil {
+			return nil, err
+		}
+		req.AgeRating = &c.Code
+	}
+	if req.ExternalID != nil {
+		if err := s.ensureNotImported(ctx, *req.ExternalID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := s.repo.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCatalog(ctx, m)
+}
+
+func (s *service) DeleteMovie(ctx context.Context, id int64, cascade bool) error {
+	return s.repo.Delete(ctx, id, cascade)
+}
+
+func (s *service) ListDeletedMovies(ctx context.Context, limit, offset int32) ([]Movie, error) {
+	return s.repo.ListDeleted(ctx, limit, offset)
+}
+
+func (s *service) RestoreMovie(ctx context.Context, id int64) (*Movie, error) {
+	deleted, err := s.repo.GetDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The catalogue entry may have been imported again while the movie was in the trash.
+	if deleted.ExternalID != nil {
+		if err := s.ensureNotImported(ctx, *deleted.ExternalID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetMovie(ctx, id)
+}
+
+func (s *service) CreateGenre(ctx context.Context, req CreateGenreRequest) (*Genre, error) {
+	return s.repo.CreateGenre(ctx, req.Name, Slugify(req.Name))
+}
+
+func (s *service) ListGenres(ctx context.Context) ([]Genre, error) {
+	return s.repo.ListGenres(ctx)
+}
+
+func (s *service) DeleteGenre(ctx context.Context, id int32) error {
+	return s.repo.DeleteGenre(ctx, id)
+}
+
+func (s *service) SetMovieGenres(ctx context.Context, movieId int64, req SetGenresRequest) (*Movie, error) {
+	// The repository resolves the IDs in one query, so a repeated ID would read as unknown.
+	seen := make(map[int32]bool, len(req.GenreIDs))
+	for _, id := range req.GenreIDs {
+		if seen[id] {
+			return nil, ErrDuplicateGenre
+		}
+		seen[id] = true
+	}
+
+	if _, err := s.repo.GetByID(ctx, movieId); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetGenres(ctx, movieId, req.GenreIDs); err != nil {
+		return nil, err
+	}
+	return s.GetMovie(ctx, movieId)
+}
+
+func (s *service) CreatePerson(ctx context.Context, req CreatePersonRequest) (*Person, error) {
+	return s.repo.CreatePerson(ctx, req.Name)
+}
+
+func (s *service) GetPerson(ctx context.Context, id int64) (*Person, error) {
+	p, err := s.repo.GetPerson(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Filmography, err = s.repo.ListFilmography(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *service) SearchPeople(ctx context.Context, query *string, limit, offset int32) ([]Person, error) {
+	return s.repo.SearchPeople(ctx, query, limit, offset)
+}
+
+func (s *service) UpdatePerson(ctx context.Context, id int64, req UpdatePersonRequest) (*Person, error) {
+	return s.repo.UpdatePerson(ctx, id, req)
+}
+
+func (s *service) SetMovieCredits(ctx context.Context, movieId int64, req SetCreditsRequest) (*Movie, error) {
+	if _, err := s.repo.GetByID(ctx, movieId); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetCredits(ctx, movieId, req.Credits); err != nil {
+		return nil, err
+	}
+	return s.GetMovie(ctx, movieId)
+}
+
+func (s *service) RegisterInterest(ctx context.Context, userId uuid.UUID, movieId int64) error {
+	m, err := s.repo.GetByID(ctx, movieId)
+	if err != nil {
+		return err
+	}
+	if m.Status != StatusAnnounced && m.Status != StatusComingSoon {
+		return ErrNotUpcoming
+	}
+	return s.repo.CreateInterest(ctx, userId, movieId)
+}
+
+func (s *service) WithdrawInterest(ctx context.Context, userId uuid.UUID, movieId int64) error {
+	return s.repo.DeleteInterest(ctx, userId, movieId)
+}
+
+func (s *service) NotifyOnSale(ctx context.Context, movieId int64) error {
+	recipients, err := s.repo.ListPendingInterests(ctx, movieId)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+	m, err := s.repo.GetByID(ctx, movieId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.notifier.NotifyOnSale(ctx, m, recipients); err != nil {
+		return err
+	}
+
+	userIds := make([]uuid.UUID, 0, len(recipients))
+	for _, r := range recipients {
+		userIds = append(userIds, r.UserID)
+	}
+	return s.repo.MarkInterestsNotified(ctx, movieId, userIds)
+}
+
+// withCatalog attaches genres and credits to a single movie.
+func (s *service) withCatalog(ctx context.Context, m *Movie) (*Movie, error) {
+	movies := []Movie{*m}
+	if err := s.attachCatalog(ctx, movies); err != nil {
+		return nil, err
+	}
+	return &movies[0], nil
+}
+
+// attachCatalog loads genres and credits for a page of movies in one query each.
+func (s *service) attachCatalog(ctx context.Context, movies []Movie) error {
+	ids := make([]int64, 0, len(movies))
+	for _, m := range movies {
+		ids = append(ids, m.ID)
+	}
+
+	genres, err := s.repo.ListGenresByMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	credits, err := s.repo.ListCreditsByMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range movies {
+		movies[i].Genres = genres[movies[i].ID]
+		movies[i].Credits = credits[movies[i].ID]
+		movies[i].Classification, _ = s.ratings.Lookup(movies[i].AgeRating)
+	}
+	return nil
+}
+
+func (s *service) SearchCatalog(ctx context.Context, query string) ([]CatalogMatch, error) {
+	return s.catalog.Search(ctx, query)
+}
+
+func (s *service) PrepareImport(ctx context.Context, externalID string) (*AddMovieRequest, error) {
+	if err := s.ensureNotImported(ctx, externalID, 0); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.catalog.Lookup(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	req := entry.ToAddMovieRequest()
+	return &req, nil
+}
+
+func (s *service) DiffCatalog(ctx context.Context, movieId int64, externalID *string) (*CatalogDiff, error) {
+	m, err := s.repo.GetByID(ctx, movieId)
+	if err != nil {
+		return nil, err
+	}
+
+	if externalID == nil {
+		externalID = m.ExternalID
+	}
+	if externalID == nil {
+		return nil, ErrNotLinked
+	}
+	if err := s.ensureNotImported(ctx, *externalID, movieId); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.catalog.Lookup(ctx, *externalID)
+	if err != nil {
+		return nil, err
+	}
+	return diffCatalogEntry(m, entry), nil
+}
+
+func (s *service) ApplyCatalog(ctx context.Context, movieId int64, req ApplyCatalogRequest) (*Movie, error) {
+	diff, err := s.DiffCatalog(ctx, movieId, req.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := diff.only(req.Fields)
+	update.ExternalID = &diff.ExternalID
+	return s.UpdateMovie(ctx, movieId, update)
+}
+
+func (s *service) Classify(code string) (*Classification, error) {
+	return s.ratings.Lookup(code)
+}
+
+func validateSort(sort *string) error {
+	if sort != nil && *sort != SortRating {
+		return ErrInvalidSort
+	}
+	return nil
 }
 
-func (s *service) DeleteMovie(ctx context.Context, id int64) error {
-	return s.repo.Delete(ctx, id)
+// ensureNotImported returns ErrAlreadyImported if a movie other than movieId is linked to the entry.
+func (s *service) ensureNotImported(ctx context.Context, externalID string, movieId int64) error {
+	existing, err := s.repo.GetByExternalID(ctx, externalID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != movieId {
+		return ErrAlreadyImported
+	}
+	return nil
 }
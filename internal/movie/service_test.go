@@ -0,0 +1,51 @@
+package movie
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type genresRepo struct {
+	catalogRepo
+	genres map[int64][]int32
+}
+
+func (r *genresRepo) SetGenres(_ context.Context, movieId int64, genreIds []int32) error {
+	r.genres[movieId] = genreIds
+	return nil
+}
+
+func TestSetMovieGenres(t *testing.T) {
+	ctx := context.Background()
+	scheme, err := RatingSchemeFor("uk")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		genreIds []int32
+		wantErr  error
+	}{
+		{"distinct", []int32{3, 1, 2}, nil},
+		{"repeated", []int32{3, 1, 3}, ErrDuplicateGenre},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &genresRepo{
+				catalogRepo: catalogRepo{movies: map[int64]*Movie{1: {ID: 1}}},
+				genres:      map[int64][]int32{},
+			}
+			s := NewService(repo, nil, nil, scheme)
+
+			_, err := s.SetMovieGenres(ctx, 1, SetGenresRequest{GenreIDs: tt.genreIds})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Empty(t, repo.genres)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.genreIds, repo.genres[1])
+		})
+	}
+}
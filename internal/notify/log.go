@@ -0,0 +1,32 @@
+// Package notify contains adapters that deliver customer notifications.
+package notify
+
+import (
+	"context"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// LogNotifier records notifications in the application log instead of sending them.
+// It is used until a delivery channel is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// NotifyOnSale logs an on-sale notification for each recipient.
+func (n *LogNotifier) NotifyOnSale(ctx context.Context, m *movie.Movie, recipients []movie.Interest) error {
+	for _, r := range recipients {
+		logger.InfoCtx(ctx, "tickets on sale notification",
+			zap.Int64("movie_id", m.ID),
+			zap.String("title", m.Title),
+			zap.String("user_id", r.UserID.String()),
+			zap.String("email", r.Email),
+		)
+	}
+	return nil
+}
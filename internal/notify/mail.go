@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/user"
+)
+
+// MemoryMailer keeps sent emails in memory. It is intended for tests.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []user.Email
+}
+
+// NewMemoryMailer creates a new MemoryMailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send records the email.
+func (m *MemoryMailer) Send(ctx context.Context, email user.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+// Sent returns the emails sent so far, oldest first.
+func (m *MemoryMailer) Sent() []user.Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]user.Email(nil), m.sent...)
+}
+
+// FileMailer writes each email to a .eml file under a directory instead of sending it.
+// It is intended for development, where the files can be opened in a mail client.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a FileMailer writing to dir, creating the directory if needed.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send writes the email to a file named after the time and recipient.
+func (m *FileMailer) Send(ctx context.Context, email user.Email) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), sanitizeAddress(email.To))
+	return os.WriteFile(filepath.Join(m.dir, name), formatMessage(m.from, email, now), 0o644)
+}
+
+// SMTPConfig contains the settings for SMTPMailer.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends email through an SMTP server, using STARTTLS when the server offers it.
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer creates a new SMTPMailer.
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+// Send delivers the email.
+func (m *SMTPMailer) Send(ctx context.Context, email user.Email) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{email.To}, formatMessage(m.cfg.From, email, time.Now())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// formatMessage renders a plain-text RFC 5322 message.
+func formatMessage(from string, email user.Email, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", email.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", email.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func sanitizeAddress(addr string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		}
+		return '_'
+	}, addr)
+}
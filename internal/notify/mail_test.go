@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailerWritesMessage(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := NewFileMailer(dir, "Mobo <no-reply@example.com>")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), user.Email{
+		To:      "ada@example.com",
+		Subject: "Verify your email address",
+		Body:    "line one\nline two",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.True(t, strings.HasSuffix(files[0], "ada@example.com.eml"))
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	msg := string(data)
+	require.Contains(t, msg, "To: ada@example.com\r\n")
+	require.Contains(t, msg, "Subject: Verify your email address\r\n")
+	require.True(t, strings.HasSuffix(msg, "\r\n\r\nline one\r\nline two"))
+}
+
+func TestMemoryMailerRecordsMessages(t *testing.T) {
+	mailer := NewMemoryMailer()
+	require.Empty(t, mailer.Sent())
+
+	require.NoError(t, mailer.Send(context.Background(), user.Email{To: "a@example.com"}))
+	require.NoError(t, mailer.Send(context.Background(), user.Email{To: "b@example.com"}))
+
+	sent := mailer.Sent()
+	require.Len(t, sent, 2)
+	require.Equal(t, "a@example.com", sent[0].To)
+	require.Equal(t, "b@example.com", sent[1].To)
+}
@@ -0,0 +1,39 @@
+package passkey
+
+import (
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/mbeka02/ticketing-service/internal/user"
+)
+
+// account presents a user and their passkeys to the WebAuthn library.
+type account struct {
+	user        *user.User
+	credentials []*Credential
+}
+
+var _ webauthn.User = (*account)(nil)
+
+// WebAuthnID is the user handle stored on the authenticator. It is the user's ID, which
+// lets a discoverable login find the account without asking for an email.
+func (a *account) WebAuthnID() []byte {
+	return a.user.ID[:]
+}
+
+func (a *account) WebAuthnName() string {
+	return a.user.Email
+}
+
+func (a *account) WebAuthnDisplayName() string {
+	if a.user.FullName == "" {
+		return a.user.Email
+	}
+	return a.user.FullName
+}
+
+func (a *account) WebAuthnCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, len(a.credentials))
+	for i, c := range a.credentials {
+		creds[i] = c.Credential
+	}
+	return creds
+}
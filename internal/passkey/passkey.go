@@ -0,0 +1,103 @@
+package passkey
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound           = errors.New("passkey not found")
+	ErrAlreadyRegistered  = errors.New("this passkey is already registered")
+	ErrCeremonyExpired    = errors.New("the passkey request has expired, please try again")
+	ErrVerificationFailed = errors.New("the passkey could not be verified")
+	ErrCodeRequired       = errors.New("enter a code from your authenticator app to add a passkey")
+)
+
+const (
+	// ProviderWebAuthn is the user_identities provider passkeys are recorded under.
+	ProviderWebAuthn = "webauthn"
+	// CeremonyTTL is how long the browser has to answer a registration or login challenge.
+	CeremonyTTL = 5 * time.Minute
+
+	CeremonyRegistration = "registration"
+	CeremonyLogin        = "login"
+)
+
+// Credential is a passkey registered to a user.
+type Credential struct {
+	ID         []byte
+	UserID     uuid.UUID
+	Name       string
+	Credential webauthn.Credential
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// ToResponse converts a Credential to a CredentialResponse.
+func (c *Credential) ToResponse() CredentialResponse {
+	return CredentialResponse{
+		ID:             EncodeID(c.ID),
+		Name:           c.Name,
+		BackupEligible: c.Credential.Flags.BackupEligible,
+		CreatedAt:      c.CreatedAt,
+		LastUsedAt:     c.LastUsedAt,
+	}
+}
+
+// Ceremony is a registration or login waiting for the authenticator's response.
+type Ceremony struct {
+	ID uuid.UUID
+	// UserID is uuid.Nil for logins, where the passkey identifies the user.
+	UserID         uuid.UUID
+	Kind           string
+	Session        webauthn.SessionData
+	CredentialName string
+	ExpiresAt      time.Time
+}
+
+// Options configures the passkey service.
+type Options struct {
+	// RPID is the domain passkeys are scoped to, e.g. "mobo-ticketing.tech".
+	RPID          string
+	RPDisplayName string
+	// RPOrigins are the origins the React app is served from.
+	RPOrigins []string
+}
+
+// EncodeID encodes a credential ID the way the API and user_identities show it.
+func EncodeID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// DecodeID reverses EncodeID, returning ErrNotFound for IDs that cannot be a passkey's.
+func DecodeID(s string) ([]byte, error) {
+	id, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(id) == 0 {
+		return nil, ErrNotFound
+	}
+	return id, nil
+}
+
+// Request/Response models
+
+// BeginRegistrationRequest names the passkey being registered, e.g. "MacBook". Users with
+// two-factor authentication enabled must also give a code from their authenticator app or
+// a recovery code.
+type BeginRegistrationRequest struct {
+	Name string `json:"name" validate:"max=100"`
+	Code string `json:"code"`
+}
+
+// CredentialResponse represents a passkey in API responses.
+type CredentialResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// BackupEligible passkeys are synced by a password manager rather than bound to one device.
+	BackupEligible bool       `json:"backup_eligible"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package passkey
+
+import (
+	"context"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// Repository defines the data access contract for the passkey domain.
+type Repository interface {
+	// CreateCredential stores a passkey together with its user_identities row, returning
+	// ErrAlreadyRegistered if the credential ID is taken.
+	CreateCredential(ctx context.Context, cred *Credential) (*Credential, error)
+	// GetCredential returns a passkey by credential ID, or ErrNotFound.
+	GetCredential(ctx context.Context, id []byte) (*Credential, error)
+	ListCredentials(ctx context.Context, userID uuid.UUID) ([]*Credential, error)
+	// UpdateCredentialUse stores the sign counter and flags after a login.
+	UpdateCredentialUse(ctx context.Context, id []byte, cred webauthn.Credential) error
+	// DeleteCredential removes the user's passkey and its identity. It returns ErrNotFound
+	// if there is no such passkey and user.ErrLastIdentity if it is the user's only way to
+	// sign in.
+	DeleteCredential(ctx context.Context, userID uuid.UUID, id []byte) error
+
+	// SaveCeremony stores a ceremony, discarding expired ones.
+	SaveCeremony(ctx context.Context, c *Ceremony) error
+	// TakeCeremony removes and returns an unexpired ceremony of the given kind, or
+	// returns ErrCeremonyExpired.
+	TakeCeremony(ctx context.Context, id uuid.UUID, kind string) (*Ceremony, error)
+}
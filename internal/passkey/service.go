@@ -0,0 +1,287 @@
+package passkey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Users loads the account a passkey belongs to.
+type Users interface {
+	GetUser(ctx context.Context, id uuid.UUID) (*user.User, error)
+}
+
+// SecondFactor checks the user's other second factor before a passkey is added.
+type SecondFactor interface {
+	Enabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	Verify(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+// Service defines the business operations for the passkey domain.
+type Service interface {
+	// BeginRegistration starts registering a passkey for the user, returning the
+	// ceremony ID and the options for navigator.credentials.create. A passkey signs in
+	// without a code, so users with two-factor authentication enabled have to give one
+	// first: it returns ErrCodeRequired if they did not, or the SecondFactor's error if
+	// the code is wrong.
+	BeginRegistration(ctx context.Context, userID uuid.UUID, req BeginRegistrationRequest) (uuid.UUID, *protocol.CredentialCreation, error)
+	// FinishRegistration verifies the authenticator's response and stores the passkey.
+	FinishRegistration(ctx context.Context, userID, ceremonyID uuid.UUID, response *protocol.ParsedCredentialCreationData) (*CredentialResponse, error)
+	// BeginLogin starts a login with any passkey the browser offers, returning the
+	// ceremony ID and the options for navigator.credentials.get.
+	BeginLogin(ctx context.Context) (uuid.UUID, *protocol.CredentialAssertion, error)
+	// FinishLogin verifies the assertion and returns the passkey's user.
+	FinishLogin(ctx context.Context, ceremonyID uuid.UUID, response *protocol.ParsedCredentialAssertionData) (*user.User, error)
+	List(ctx context.Context, userID uuid.UUID) ([]CredentialResponse, error)
+	// Delete removes one of the user's passkeys by its encoded ID, refusing with
+	// user.ErrLastIdentity to remove their only way to sign in.
+	Delete(ctx context.Context, userID uuid.UUID, credentialID string) error
+}
+
+type service struct {
+	repo     Repository
+	users    Users
+	mfa      SecondFactor
+	webauthn *webauthn.WebAuthn
+	now      func() time.Time
+}
+
+// NewService creates a new passkey service for the relying party described by opts.
+func NewService(repo Repository, users Users, mfa SecondFactor, opts Options) (Service, error) {
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: CeremonyTTL, TimeoutUVD: CeremonyTTL}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          opts.RPID,
+		RPDisplayName: opts.RPDisplayName,
+		RPOrigins:     opts.RPOrigins,
+		Timeouts:      webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+	return &service{repo: repo, users: users, mfa: mfa, webauthn: w, now: time.Now}, nil
+}
+
+func (s *service) BeginRegistration(ctx context.Context, userID uuid.UUID, req BeginRegistrationRequest) (uuid.UUID, *protocol.CredentialCreation, error) {
+	if err := s.stepUp(ctx, userID, req.Code); err != nil {
+		return uuid.Nil, nil, err
+	}
+	acct, err := s.account(ctx, userID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, len(acct.credentials))
+	for i, c := range acct.credentials {
+		exclusions[i] = c.Credential.Descriptor()
+	}
+	// Passkeys must be discoverable so they can sign in without an email, and must
+	// verify the user so that signing in with one counts as two factors.
+	creation, session, err := s.webauthn.BeginRegistration(acct,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		}),
+	)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	id, err := s.saveCeremony(ctx, userID, CeremonyRegistration, session, req.Name)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return id, creation, nil
+}
+
+func (s *service) FinishRegistration(ctx context.Context, userID, ceremonyID uuid.UUID, response *protocol.ParsedCredentialCreationData) (*CredentialResponse, error) {
+	ceremony, err := s.repo.TakeCeremony(ctx, ceremonyID, CeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != userID {
+		return nil, ErrCeremonyExpired
+	}
+	acct, err := s.account(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.webauthn.CreateCredential(acct, ceremony.Session, response)
+	if err != nil {
+		logger.WarnCtx(ctx, "passkey registration rejected",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, ErrVerificationFailed
+	}
+
+	cred, err := s.repo.CreateCredential(ctx, &Credential{
+		ID:         credential.ID,
+		UserID:     userID,
+		Name:       ceremony.CredentialName,
+		Credential: *credential,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "passkey registered",
+		zap.String("user_id", userID.String()),
+		zap.String("credential_id", EncodeID(cred.ID)),
+	)
+	res := cred.ToResponse()
+	return &res, nil
+}
+
+func (s *service) BeginLogin(ctx context.Context) (uuid.UUID, *protocol.CredentialAssertion, error) {
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	id, err := s.saveCeremony(ctx, uuid.Nil, CeremonyLogin, session, "")
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return id, assertion, nil
+}
+
+func (s *service) FinishLogin(ctx context.Context, ceremonyID uuid.UUID, response *protocol.ParsedCredentialAssertionData) (*user.User, error) {
+	ceremony, err := s.repo.TakeCeremony(ctx, ceremonyID, CeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	// The library wraps whatever the handler returns, so keep lookup failures that are
+	// not the client's fault to report them as such.
+	var lookupErr error
+	var acct *account
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		cred, err := s.repo.GetCredential(ctx, rawID)
+		if err != nil {
+			lookupErr = err
+			return nil, err
+		}
+		if !bytes.Equal(userHandle, cred.UserID[:]) {
+			lookupErr = ErrNotFound
+			return nil, ErrNotFound
+		}
+		if acct, err = s.account(ctx, cred.UserID); err != nil {
+			lookupErr = err
+			return nil, err
+		}
+		return acct, nil
+	}
+
+	_, credential, err := s.webauthn.ValidatePasskeyLogin(handler, ceremony.Session, response)
+	if err != nil {
+		if lookupErr != nil && !errors.Is(lookupErr, ErrNotFound) && !errors.Is(lookupErr, user.ErrNotFound) {
+			return nil, lookupErr
+		}
+		logger.WarnCtx(ctx, "passkey login rejected", zap.Error(err))
+		return nil, ErrVerificationFailed
+	}
+	// A sign counter that went backwards means the credential may have been copied.
+	if credential.Authenticator.CloneWarning {
+		logger.WarnCtx(ctx, "passkey sign counter went backwards",
+			zap.String("user_id", acct.user.ID.String()),
+			zap.String("credential_id", EncodeID(credential.ID)),
+		)
+		return nil, ErrVerificationFailed
+	}
+
+	if err := s.repo.UpdateCredentialUse(ctx, credential.ID, *credential); err != nil {
+		return nil, fmt.Errorf("failed to record passkey use: %w", err)
+	}
+	return acct.user, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]CredentialResponse, error) {
+	creds, err := s.repo.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]CredentialResponse, len(creds))
+	for i, c := range creds {
+		res[i] = c.ToResponse()
+	}
+	return res, nil
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, credentialID string) error {
+	id, err := DecodeID(credentialID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCredential(ctx, userID, id); err != nil {
+		return err
+	}
+	logger.InfoCtx(ctx, "passkey removed",
+		zap.String("user_id", userID.String()),
+		zap.String("credential_id", credentialID),
+	)
+	return nil
+}
+
+// account loads the user and their passkeys for the WebAuthn library.
+func (s *service) account(ctx context.Context, userID uuid.UUID) (*account, error) {
+	u, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := s.repo.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &account{user: u, credentials: creds}, nil
+}
+
+func (s *service) saveCeremony(ctx context.Context, userID uuid.UUID, kind string, session *webauthn.SessionData, name string) (uuid.UUID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.repo.SaveCeremony(ctx, &Ceremony{
+		ID:             id,
+		UserID:         userID,
+		Kind:           kind,
+		Session:        *session,
+		CredentialName: name,
+		ExpiresAt:      s.now().Add(CeremonyTTL),
+	}); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save passkey ceremony: %w", err)
+	}
+	return id, nil
+}
+
+// stepUp checks code if the user has two-factor authentication enabled, so that a stolen
+// session alone cannot add a passkey that skips the second factor.
+func (s *service) stepUp(ctx context.Context, userID uuid.UUID, code string) error {
+	enabled, err := s.mfa.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		return err
+	}
+	if code == "" {
+		return ErrCodeRequired
+	}
+	if err := s.mfa.Verify(ctx, userID, code); err != nil {
+		logger.WarnCtx(ctx, "passkey registration with wrong two-factor code",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return err
+	}
+	return nil
+}
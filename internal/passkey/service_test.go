@@ -0,0 +1,219 @@
+package passkey
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo is a Repository over maps, enough to exercise the ceremony bookkeeping.
+type memoryRepo struct {
+	creds      []*Credential
+	ceremonies map[uuid.UUID]*Ceremony
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{ceremonies: make(map[uuid.UUID]*Ceremony)}
+}
+
+func (r *memoryRepo) CreateCredential(ctx context.Context, cred *Credential) (*Credential, error) {
+	if _, err := r.GetCredential(ctx, cred.ID); err == nil {
+		return nil, ErrAlreadyRegistered
+	}
+	cp := *cred
+	cp.CreatedAt = time.Now()
+	r.creds = append(r.creds, &cp)
+	return &cp, nil
+}
+
+func (r *memoryRepo) GetCredential(ctx context.Context, id []byte) (*Credential, error) {
+	for _, c := range r.creds {
+		if bytes.Equal(c.ID, id) {
+			return c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRepo) ListCredentials(ctx context.Context, userID uuid.UUID) ([]*Credential, error) {
+	var creds []*Credential
+	for _, c := range r.creds {
+		if c.UserID == userID {
+			creds = append(creds, c)
+		}
+	}
+	return creds, nil
+}
+
+func (r *memoryRepo) UpdateCredentialUse(ctx context.Context, id []byte, cred webauthn.Credential) error {
+	c, err := r.GetCredential(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	c.Credential, c.LastUsedAt = cred, &now
+	return nil
+}
+
+func (r *memoryRepo) DeleteCredential(ctx context.Context, userID uuid.UUID, id []byte) error {
+	for i, c := range r.creds {
+		if c.UserID == userID && bytes.Equal(c.ID, id) {
+			r.creds = append(r.creds[:i], r.creds[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryRepo) SaveCeremony(ctx context.Context, c *Ceremony) error {
+	r.ceremonies[c.ID] = c
+	return nil
+}
+
+func (r *memoryRepo) TakeCeremony(ctx context.Context, id uuid.UUID, kind string) (*Ceremony, error) {
+	c, ok := r.ceremonies[id]
+	if !ok || c.Kind != kind || !c.ExpiresAt.After(time.Now()) {
+		return nil, ErrCeremonyExpired
+	}
+	delete(r.ceremonies, id)
+	return c, nil
+}
+
+type memoryUsers map[uuid.UUID]*user.User
+
+func (u memoryUsers) GetUser(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if found, ok := u[id]; ok {
+		return found, nil
+	}
+	return nil, user.ErrNotFound
+}
+
+// fakeSecondFactor accepts code for the users in enabled.
+type fakeSecondFactor struct {
+	enabled map[uuid.UUID]bool
+	code    string
+}
+
+func (f *fakeSecondFactor) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return f.enabled[userID], nil
+}
+
+func (f *fakeSecondFactor) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	if !f.enabled[userID] || code != f.code {
+		return mfa.ErrInvalidCode
+	}
+	return nil
+}
+
+func newTestService(t *testing.T) (*service, *memoryRepo, *user.User) {
+	t.Helper()
+	u := &user.User{ID: uuid.New(), Email: "ada@example.com", FullName: "Ada Lovelace", Role: user.RoleUser}
+	repo := newMemoryRepo()
+	svc, err := NewService(repo, memoryUsers{u.ID: u}, &fakeSecondFactor{code: "123456"}, Options{
+		RPID:          "localhost",
+		RPDisplayName: "Mobo",
+		RPOrigins:     []string{"http://localhost:5173"},
+	})
+	require.NoError(t, err)
+	return svc.(*service), repo, u
+}
+
+func TestBeginRegistration(t *testing.T) {
+	svc, repo, u := newTestService(t)
+	ctx := context.Background()
+	existing, err := repo.CreateCredential(ctx, &Credential{ID: []byte("existing"), UserID: u.ID, Credential: webauthn.Credential{ID: []byte("existing")}})
+	require.NoError(t, err)
+
+	ceremonyID, creation, err := svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{Name: "Laptop"})
+	require.NoError(t, err)
+
+	options := creation.Response
+	require.Equal(t, protocol.URLEncodedBase64(u.ID[:]), options.User.ID)
+	require.Equal(t, u.Email, options.User.Name)
+	require.Equal(t, protocol.ResidentKeyRequirementRequired, options.AuthenticatorSelection.ResidentKey)
+	require.Equal(t, protocol.VerificationRequired, options.AuthenticatorSelection.UserVerification)
+	require.Len(t, options.CredentialExcludeList, 1)
+	require.Equal(t, protocol.URLEncodedBase64(existing.ID), options.CredentialExcludeList[0].CredentialID)
+
+	ceremony := repo.ceremonies[ceremonyID]
+	require.NotNil(t, ceremony)
+	require.Equal(t, u.ID, ceremony.UserID)
+	require.Equal(t, CeremonyRegistration, ceremony.Kind)
+	require.Equal(t, "Laptop", ceremony.CredentialName)
+}
+
+func TestBeginRegistrationStepUp(t *testing.T) {
+	svc, repo, u := newTestService(t)
+	ctx := context.Background()
+
+	// Without two-factor authentication there is no other factor to ask for.
+	_, _, err := svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{})
+	require.NoError(t, err)
+
+	svc.mfa.(*fakeSecondFactor).enabled = map[uuid.UUID]bool{u.ID: true}
+	repo.ceremonies = make(map[uuid.UUID]*Ceremony)
+
+	_, _, err = svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{})
+	require.ErrorIs(t, err, ErrCodeRequired)
+	_, _, err = svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{Code: "000000"})
+	require.ErrorIs(t, err, mfa.ErrInvalidCode)
+	require.Empty(t, repo.ceremonies)
+
+	ceremonyID, _, err := svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{Code: "123456"})
+	require.NoError(t, err)
+	require.Contains(t, repo.ceremonies, ceremonyID)
+}
+
+func TestFinishRegistrationRejectsAnotherUsersCeremony(t *testing.T) {
+	svc, repo, u := newTestService(t)
+	ctx := context.Background()
+
+	ceremonyID, _, err := svc.BeginRegistration(ctx, u.ID, BeginRegistrationRequest{})
+	require.NoError(t, err)
+
+	_, err = svc.FinishRegistration(ctx, uuid.New(), ceremonyID, &protocol.ParsedCredentialCreationData{})
+	require.ErrorIs(t, err, ErrCeremonyExpired)
+	require.Empty(t, repo.ceremonies, "a ceremony can only be answered once")
+}
+
+func TestCeremonyKindsAreNotInterchangeable(t *testing.T) {
+	svc, _, u := newTestService(t)
+	ctx := context.Background()
+
+	loginID, assertion, err := svc.BeginLogin(ctx)
+	require.NoError(t, err)
+	require.Equal(t, protocol.VerificationRequired, assertion.Response.UserVerification)
+	require.Empty(t, assertion.Response.AllowedCredentials, "logins are discoverable")
+
+	_, err = svc.FinishRegistration(ctx, u.ID, loginID, &protocol.ParsedCredentialCreationData{})
+	require.ErrorIs(t, err, ErrCeremonyExpired)
+
+	_, err = svc.FinishLogin(ctx, uuid.New(), &protocol.ParsedCredentialAssertionData{})
+	require.ErrorIs(t, err, ErrCeremonyExpired)
+}
+
+func TestDelete(t *testing.T) {
+	svc, repo, u := newTestService(t)
+	ctx := context.Background()
+	cred, err := repo.CreateCredential(ctx, &Credential{ID: []byte{0xfb, 0xff, 0x01}, UserID: u.ID})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.Delete(ctx, u.ID, "not base64!"), ErrNotFound)
+	require.ErrorIs(t, svc.Delete(ctx, uuid.New(), EncodeID(cred.ID)), ErrNotFound)
+
+	list, err := svc.List(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "-_8B", list[0].ID)
+
+	require.NoError(t, svc.Delete(ctx, u.ID, list[0].ID))
+	require.Empty(t, repo.creds)
+}
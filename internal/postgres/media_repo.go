@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/media"
+)
+
+type mediaRepo struct {
+	store *Store
+}
+
+// NewMediaRepository creates a new postgres media repository.
+func NewMediaRepository(store *Store) media.Repository {
+	return &mediaRepo{store}
+}
+
+func (r *mediaRepo) Create(ctx context.Context, a media.Asset) (*media.Asset, error) {
+	var created *media.Asset
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbAsset, err := q.CreateMediaAsset(ctx, dbgen.CreateMediaAssetParams{
+			MovieID:             a.MovieID,
+			Kind:                a.Kind,
+			OriginalFilename:    a.OriginalFilename,
+			OriginalContentType: a.OriginalContentType,
+			Width:               a.Width,
+			Height:              a.Height,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create media asset in transaction: %w", err)
+		}
+
+		created = fromDatabaseMediaAsset(&dbAsset)
+		for _, v := range a.Variants {
+			dbVariant, err := q.CreateMediaVariant(ctx, dbgen.CreateMediaVariantParams{
+				AssetID:    dbAsset.ID,
+				Name:       v.Name,
+				StorageKey: v.StorageKey,
+				Width:      v.Width,
+				Height:     v.Height,
+				SizeBytes:  v.SizeBytes,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create media variant in transaction: %w", err)
+			}
+			created.Variants = append(created.Variants, *fromDatabaseMediaVariant(&dbVariant))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *mediaRepo) GetByID(ctx context.Context, id int64) (*media.Asset, error) {
+	dbAsset, err := r.store.GetMediaAssetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, media.ErrNotFound
+		}
+		return nil, err
+	}
+
+	assets := []media.Asset{*fromDatabaseMediaAsset(&dbAsset)}
+	if err := r.attachVariants(ctx, assets); err != nil {
+		return nil, err
+	}
+	return &assets[0], nil
+}
+
+func (r *mediaRepo) ListByMovie(ctx context.Context, movieId int64) ([]media.Asset, error) {
+	dbAssets, err := r.store.GetMediaAssetsByMovie(ctx, movieId)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]media.Asset, 0, len(dbAssets))
+	for _, a := range dbAssets {
+		assets = append(assets, *fromDatabaseMediaAsset(&a))
+	}
+	if err := r.attachVariants(ctx, assets); err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+func (r *mediaRepo) GetVariant(ctx context.Context, assetId int64, name string) (*media.Variant, error) {
+	dbVariant, err := r.store.GetMediaVariant(ctx, dbgen.GetMediaVariantParams{
+		AssetID: assetId,
+		Name:    name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, media.ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseMediaVariant(&dbVariant), nil
+}
+
+func (r *mediaRepo) Delete(ctx context.Context, id int64) error {
+	rows, err := r.store.DeleteMediaAsset(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return media.ErrNotFound
+	}
+	return nil
+}
+
+// attachVariants loads the variants of a page of assets in a single query.
+func (r *mediaRepo) attachVariants(ctx context.Context, assets []media.Asset) error {
+	ids := make([]int64, 0, len(assets))
+	for _, a := range assets {
+		ids = append(ids, a.ID)
+	}
+
+	dbVariants, err := r.store.GetMediaVariantsByAssets(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byAsset := make(map[int64][]media.Variant, len(assets))
+	for _, v := range dbVariants {
+		byAsset[v.AssetID] = append(byAsset[v.AssetID], *fromDatabaseMediaVariant(&v))
+	}
+	for i := range assets {
+		assets[i].Variants = byAsset[assets[i].ID]
+	}
+	return nil
+}
+
+func fromDatabaseMediaAsset(a *dbgen.MediaAsset) *media.Asset {
+	return &media.Asset{
+		ID:                  a.ID,
+		MovieID:             a.MovieID,
+		Kind:                a.Kind,
+		OriginalFilename:    a.OriginalFilename,
+		OriginalContentType: a.OriginalContentType,
+		Width:               a.Width,
+		Height:              a.Height,
+		CreatedAt:           a.CreatedAt,
+	}
+}
+
+func fromDatabaseMediaVariant(v *dbgen.MediaVariant) *media.Variant {
+	return &media.Variant{
+		Name:       v.Name,
+		StorageKey: v.StorageKey,
+		Width:      v.Width,
+		Height:     v.Height,
+		SizeBytes:  v.SizeBytes,
+	}
+}
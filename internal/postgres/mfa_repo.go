@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/mfa"
+)
+
+type mfaRepo struct {
+	store *Store
+}
+
+// NewMFARepository creates a new postgres mfa repository.
+func NewMFARepository(store *Store) mfa.Repository {
+	return &mfaRepo{store}
+}
+
+func (r *mfaRepo) GetTOTP(ctx context.Context, userID uuid.UUID) (*mfa.TOTP, error) {
+	row, err := r.store.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, mfa.ErrNotEnrolled
+		}
+		return nil, err
+	}
+	return fromDatabaseTOTP(&row), nil
+}
+
+func (r *mfaRepo) SavePendingTOTP(ctx context.Context, userID uuid.UUID, secretCiphertext []byte) error {
+	n, err := r.store.UpsertPendingTOTP(ctx, dbgen.UpsertPendingTOTPParams{
+		UserID:           userID,
+		SecretCiphertext: secretCiphertext,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return mfa.ErrAlreadyEnrolled
+	}
+	return nil
+}
+
+func (r *mfaRepo) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		n, err := q.UseTOTPStep(ctx, dbgen.UseTOTPStepParams{UserID: userID, Step: step})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return mfa.ErrInvalidCode
+		}
+		if n, err = q.ConfirmUserTOTP(ctx, userID); err != nil {
+			return err
+		}
+		if n == 0 {
+			return mfa.ErrAlreadyEnrolled
+		}
+		return replaceRecoveryCodes(ctx, q, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *mfaRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	n, err := r.store.UseTOTPStep(ctx, dbgen.UseTOTPStepParams{UserID: userID, Step: step})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *mfaRepo) RecordFailure(ctx context.Context, userID uuid.UUID, maxAttempts int32, lockUntil time.Time) error {
+	return r.store.RecordTOTPFailure(ctx, dbgen.RecordTOTPFailureParams{
+		UserID:      userID,
+		MaxAttempts: maxAttempts,
+		LockUntil:   lockUntil,
+	})
+}
+
+func (r *mfaRepo) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		return q.DeleteUserTOTP(ctx, userID)
+	})
+}
+
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	n, err := r.store.UseRecoveryCode(ctx, dbgen.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		return replaceRecoveryCodes(ctx, q, userID, codeHashes)
+	})
+}
+
+func (r *mfaRepo) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.store.CountUnusedRecoveryCodes(ctx, userID)
+}
+
+func replaceRecoveryCodes(ctx context.Context, q *dbgen.Queries, userID uuid.UUID, codeHashes []string) error {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if err := q.InsertRecoveryCodes(ctx, dbgen.InsertRecoveryCodesParams{
+		UserID:     userID,
+		CodeHashes: codeHashes,
+	}); err != nil {
+		return fmt.Errorf("failed to insert recovery codes: %w", err)
+	}
+	return nil
+}
+
+// fromDatabaseTOTP converts a dbgen.UserTotp to an mfa.TOTP domain type.
+func fromDatabaseTOTP(row *dbgen.UserTotp) *mfa.TOTP {
+	var confirmedAt *time.Time
+	if row.ConfirmedAt.Valid {
+		confirmedAt = &row.ConfirmedAt.Time
+	}
+	var lockedUntil *time.Time
+	if row.LockedUntil.Valid {
+		lockedUntil = &row.LockedUntil.Time
+	}
+
+	return &mfa.TOTP{
+		UserID:           row.UserID,
+		SecretCiphertext: row.SecretCiphertext,
+		ConfirmedAt:      confirmedAt,
+		LastUsedStep:     row.LastUsedStep,
+		LockedUntil:      lockedUntil,
+		CreatedAt:        row.CreatedAt,
+	}
+}
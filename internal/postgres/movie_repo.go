@@ -2,8 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mbeka02/ticketing-service/internal/dbgen"
 	"github.com/mbeka02/ticketing-service/internal/movie"
@@ -18,36 +22,76 @@ func NewMovieRepository(store *Store) movie.Repository {
 	return &movieRepo{store}
 }
 
+// movieExternalIDIndex keeps a catalogue entry linked to at most one live movie.
+const movieExternalIDIndex = "idx_movies_external_id"
+
 func (r *movieRepo) Add(ctx context.Context, req movie.AddMovieRequest) (*movie.Movie, error) {
 	parsedDate, err := time.Parse("2006-01-02", req.ReleaseDate)
 	if err != nil {
 		return nil, err
 	}
 
-	dbMovie, err := r.store.AddMovie(ctx, dbgen.AddMovieParams{
-		Title:       req.Title,
-		Description: req.Description,
-		Runtime:     req.Runtime,
-		Genre:       req.Genre,
-		AgeRating:   req.AgeRating,
-		Director:    req.Director,
-		PosterUrl:   req.PosterUrl,
-		ReleaseDate: pgtype.Date{Time: parsedDate, Valid: true},
+	var movieId int64
+	err = r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbMovie, err := q.AddMovie(ctx, dbgen.AddMovieParams{
+			Title:       req.Title,
+			Description: req.Description,
+			Runtime:     req.Runtime,
+			Genre:       req.Genre,
+			AgeRating:   req.AgeRating,
+			Director:    req.Director,
+			PosterUrl:   optionalString(req.PosterUrl),
+			ReleaseDate: pgtype.Date{Time: parsedDate, Valid: true},
+			CastMembers: []string{},
+			ExternalID:  req.ExternalID,
+		})
+		if isUniqueViolation(err, movieExternalIDIndex) {
+			return movie.ErrAlreadyImported
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add movie in transaction: %w", err)
+		}
+		movieId = dbMovie.ID
+
+		if err := replaceGenreByName(ctx, q, movieId, req.Genre); err != nil {
+			return err
+		}
+		if err := replaceCreditsByName(ctx, q, movieId, movie.RoleDirector, []string{req.Director}); err != nil {
+			return err
+		}
+		if err := replaceCreditsByName(ctx, q, movieId, movie.RoleCast, req.Cast); err != nil {
+			return err
+		}
+		return q.SyncMovieCatalogFields(ctx, []int64{movieId})
 	})
 	if err != nil {
 		return nil, err
 	}
-	return fromDatabaseMovie(&dbMovie), nil
+	return r.GetByID(ctx, movieId)
 }
 
 func (r *movieRepo) GetByID(ctx context.Context, id int64) (*movie.Movie, error) {
 	row, err := r.store.GetMovieById(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, movie.ErrNotFound
+		}
 		return nil, err
 	}
 	return fromDatabaseGetMovieByIdRow(&row), nil
 }
 
+func (r *movieRepo) GetByExternalID(ctx context.Context, externalID string) (*movie.Movie, error) {
+	id, err := r.store.GetMovieIdByExternalId(ctx, &externalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, movie.ErrNotFound
+		}
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
 func (r *movieRepo) ListAdmin(ctx context.Context, limit, offset int32) ([]movie.Movie, error) {
 	movies, err := r.store.GetMoviesAdmin(ctx, dbgen.GetMoviesAdminParams{
 		Limit:  limit,
@@ -64,8 +108,9 @@ func (r *movieRepo) ListAdmin(ctx context.Context, limit, offset int32) ([]movie
 	return res, nil
 }
 
-func (r *movieRepo) ListPublic(ctx context.Context, limit, offset int32) ([]movie.Movie, error) {
+func (r *movieRepo) ListPublic(ctx context.Context, limit, offset int32, sort *string) ([]movie.Movie, error) {
 	movies, err := r.store.GetMoviesPublic(ctx, dbgen.GetMoviesPublicParams{
+		Sort:   sort,
 		Limit:  limit,
 		Offset: offset,
 	})
@@ -75,21 +120,105 @@ func (r *movieRepo) ListPublic(ctx context.Context, limit, offset int32) ([]movi
 
 	res := make([]movie.Movie, 0, len(movies))
 	for _, m := range movies {
-		res = append(res, *fromDatabaseMovie(&m))
+		publicMovie := fromDatabaseMovie(&m)
+		// Only movies with a future showtime are listed.
+		publicMovie.Status = movie.StatusNowShowing
+		res = append(res, *publicMovie)
+	}
+	return res, nil
+}
+
+func (r *movieRepo) ListComingSoon(ctx context.Context, limit, offset int32) ([]movie.Movie, error) {
+	movies, err := r.store.GetMoviesComingSoon(ctx, dbgen.GetMoviesComingSoonParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Movie, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, *fromDatabaseGetMoviesComingSoonRow(&m))
+	}
+	return res, nil
+}
+
+func (r *movieRepo) Search(ctx context.Context, filter movie.SearchFilter) (*movie.SearchResult, error) {
+	movies, err := r.store.SearchMovies(ctx, dbgen.SearchMoviesParams{
+		Query:       filter.Query,
+		Genre:       filter.Genre,
+		AgeRating:   filter.AgeRating,
+		ReleaseYear: filter.ReleaseYear,
+		Status:      filter.Status,
+		Sort:        filter.Sort,
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	facets, err := r.store.SearchMovieFacets(ctx, dbgen.SearchMovieFacetsParams{
+		Query:       filter.Query,
+		Genre:       filter.Genre,
+		AgeRating:   filter.AgeRating,
+		ReleaseYear: filter.ReleaseYear,
+		Status:      filter.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &movie.SearchResult{
+		Movies: make([]movie.Movie, 0, len(movies)),
+		Facets: movie.Facets{
+			Genre:       []movie.FacetCount{},
+			AgeRating:   []movie.FacetCount{},
+			ReleaseYear: []movie.FacetCount{},
+			Status:      []movie.FacetCount{},
+		},
+	}
+	for _, m := range movies {
+		res.Movies = append(res.Movies, *fromDatabaseSearchMoviesRow(&m))
+	}
+	for _, f := range facets {
+		count := movie.FacetCount{Value: f.Value, Count: f.Count}
+		switch f.Facet {
+		case "genre":
+			res.Facets.Genre = append(res.Facets.Genre, count)
+		case "age_rating":
+			res.Facets.AgeRating = append(res.Facets.AgeRating, count)
+		case "release_year":
+			res.Facets.ReleaseYear = append(res.Facets.ReleaseYear, count)
+		case "status":
+			// Every match has exactly one status, so these counts add up to the total.
+			res.Facets.Status = append(res.Facets.Status, count)
+			res.Total += f.Count
+		}
 	}
 	return res, nil
 }
 
+func (r *movieRepo) ClearPoster(ctx context.Context, id int64) error {
+	rows, err := r.store.ClearMoviePoster(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return movie.ErrNotFound
+	}
+	return nil
+}
+
 func (r *movieRepo) Update(ctx context.Context, id int64, req movie.UpdateMovieRequest) (*movie.Movie, error) {
 	params := dbgen.UpdateMovieParams{
 		ID:          id,
 		Title:       req.Title,
 		Description: req.Description,
 		Runtime:     req.Runtime,
-		Genre:       req.Genre,
 		AgeRating:   req.AgeRating,
-		Director:    req.Director,
 		PosterUrl:   req.PosterUrl,
+		ExternalID:  req.ExternalID,
 	}
 
 	if req.ReleaseDate != nil {
@@ -98,15 +227,471 @@ func (r *movieRepo) Update(ctx context.Context, id int64, req movie.UpdateMovieR
 		}
 	}
 
-	dbMovie, err := r.store.UpdateMovie(ctx, params)
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if _, err := q.UpdateMovie(ctx, params); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return movie.ErrNotFound
+			}
+			if isUniqueViolation(err, movieExternalIDIndex) {
+				return movie.ErrAlreadyImported
+			}
+			return err
+		}
+
+		if req.Genre != nil {
+			if err := replaceGenreByName(ctx, q, id, *req.Genre); err != nil {
+				return err
+			}
+		}
+		if req.Director != nil {
+			if err := replaceCreditsByName(ctx, q, id, movie.RoleDirector, []string{*req.Director}); err != nil {
+				return err
+			}
+		}
+		if req.Cast != nil {
+			if err := replaceCreditsByName(ctx, q, id, movie.RoleCast, req.Cast); err != nil {
+				return err
+			}
+		}
+		return q.SyncMovieCatalogFields(ctx, []int64{id})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *movieRepo) Delete(ctx context.Context, id int64, cascade bool) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		scheduled, err := q.CountFutureShowtimesByMovie(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to count scheduled showtimes: %w", err)
+		}
+		if scheduled > 0 {
+			if !cascade {
+				return movie.ErrShowtimesScheduled
+			}
+			deleted, err := q.DeleteFutureShowtimesByMovie(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to delete scheduled showtimes: %w", err)
+			}
+			if err := cancelReservations(ctx, q, deleted); err != nil {
+				return err
+			}
+		}
+
+		rows, err := q.DeleteMovie(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return movie.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *movieRepo) GetDeleted(ctx context.Context, id int64) (*movie.Movie, error) {
+	dbMovie, err := r.store.GetDeletedMovieById(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, movie.ErrNotFound
+		}
 		return nil, err
 	}
 	return fromDatabaseMovie(&dbMovie), nil
 }
 
-func (r *movieRepo) Delete(ctx context.Context, id int64) error {
-	return r.store.DeleteMovie(ctx, id)
+func (r *movieRepo) ListDeleted(ctx context.Context, limit, offset int32) ([]movie.Movie, error) {
+	movies, err := r.store.GetDeletedMovies(ctx, dbgen.GetDeletedMoviesParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Movie, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, *fromDatabaseMovie(&m))
+	}
+	return res, nil
+}
+
+func (r *movieRepo) Restore(ctx context.Context, id int64) (*movie.Movie, error) {
+	var restored *movie.Movie
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		deleted, err := q.GetDeletedMovieById(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return movie.ErrNotFound
+			}
+			return err
+		}
+
+		dbMovie, err := q.RestoreMovie(ctx, id)
+		if isUniqueViolation(err, movieExternalIDIndex) {
+			return movie.ErrAlreadyImported
+		}
+		if err != nil {
+			return err
+		}
+		if err := q.RestoreFutureShowtimesByMovie(ctx, dbgen.RestoreFutureShowtimesByMovieParams{
+			MovieID:   id,
+			DeletedAt: deleted.DeletedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to restore showtimes: %w", err)
+		}
+		restored = fromDatabaseMovie(&dbMovie)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (r *movieRepo) ListByIDs(ctx context.Context, ids []int64) ([]movie.Movie, error) {
+	rows, err := r.store.GetMoviesByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Movie, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *fromDatabaseGetMovieByIdRow((*dbgen.GetMovieByIdRow)(&row)))
+	}
+	return res, nil
+}
+
+func (r *movieRepo) CreateGenre(ctx context.Context, name, slug string) (*movie.Genre, error) {
+	dbGenre, err := r.store.CreateGenre(ctx, dbgen.CreateGenreParams{
+		Name: name,
+		Slug: slug,
+	})
+	if err != nil {
+		// CreateGenre does nothing on a slug conflict, so no row comes back.
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, movie.ErrGenreExists
+		}
+		return nil, err
+	}
+	return fromDatabaseGenre(&dbGenre), nil
+}
+
+func (r *movieRepo) ListGenres(ctx context.Context) ([]movie.Genre, error) {
+	genres, err := r.store.GetGenres(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Genre, 0, len(genres))
+	for _, g := range genres {
+		res = append(res, *fromDatabaseGenre(&g))
+	}
+	return res, nil
+}
+
+func (r *movieRepo) DeleteGenre(ctx context.Context, id int32) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		movieIds, err := q.GetMovieIdsByGenre(ctx, id)
+		if err != nil {
+			return err
+		}
+		rows, err := q.DeleteGenre(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return movie.ErrGenreNotFound
+		}
+		return q.SyncMovieCatalogFields(ctx, movieIds)
+	})
+}
+
+func (r *movieRepo) SetGenres(ctx context.Context, movieId int64, genreIds []int32) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		found, err := q.GetGenresByIds(ctx, genreIds)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(genreIds) {
+			return movie.ErrGenreNotFound
+		}
+
+		if err := q.DeleteMovieGenres(ctx, movieId); err != nil {
+			return fmt.Errorf("failed to clear movie genres: %w", err)
+		}
+		for i, genreId := range genreIds {
+			if err := q.AddMovieGenre(ctx, dbgen.AddMovieGenreParams{
+				MovieID:  movieId,
+				GenreID:  genreId,
+				Position: int32(i),
+			}); err != nil {
+				return fmt.Errorf("failed to add movie genre in transaction: %w", err)
+			}
+		}
+		return q.SyncMovieCatalogFields(ctx, []int64{movieId})
+	})
+}
+
+func (r *movieRepo) ListGenresByMovies(ctx context.Context, movieIds []int64) (map[int64][]movie.Genre, error) {
+	rows, err := r.store.GetGenresByMovies(ctx, movieIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64][]movie.Genre, len(movieIds))
+	for _, row := range rows {
+		res[row.MovieID] = append(res[row.MovieID], movie.Genre{
+			ID:   row.ID,
+			Name: row.Name,
+			Slug: row.Slug,
+		})
+	}
+	return res, nil
+}
+
+func (r *movieRepo) CreatePerson(ctx context.Context, name string) (*movie.Person, error) {
+	dbPerson, err := r.store.CreatePerson(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return fromDatabasePerson(&dbPerson), nil
+}
+
+func (r *movieRepo) GetPerson(ctx context.Context, id int64) (*movie.Person, error) {
+	dbPerson, err := r.store.GetPersonById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, movie.ErrPersonNotFound
+		}
+		return nil, err
+	}
+	return fromDatabasePerson(&dbPerson), nil
+}
+
+func (r *movieRepo) SearchPeople(ctx context.Context, query *string, limit, offset int32) ([]movie.Person, error) {
+	people, err := r.store.SearchPeople(ctx, dbgen.SearchPeopleParams{
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Person, 0, len(people))
+	for _, p := range people {
+		res = append(res, *fromDatabasePerson(&p))
+	}
+	return res, nil
+}
+
+func (r *movieRepo) UpdatePerson(ctx context.Context, id int64, req movie.UpdatePersonRequest) (*movie.Person, error) {
+	var person *movie.Person
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbPerson, err := q.UpdatePerson(ctx, dbgen.UpdatePersonParams{
+			ID:   id,
+			Name: req.Name,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return movie.ErrPersonNotFound
+			}
+			return err
+		}
+		person = fromDatabasePerson(&dbPerson)
+
+		// Renaming someone changes the denormalized director and cast of their movies.
+		movieIds, err := q.GetMovieIdsByPerson(ctx, id)
+		if err != nil {
+			return err
+		}
+		return q.SyncMovieCatalogFields(ctx, movieIds)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+func (r *movieRepo) ListFilmography(ctx context.Context, personId int64) ([]movie.FilmographyEntry, error) {
+	rows, err := r.store.GetFilmography(ctx, personId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.FilmographyEntry, 0, len(rows))
+	for _, row := range rows {
+		var releaseDate time.Time
+		if row.ReleaseDate.Valid {
+			releaseDate = row.ReleaseDate.Time
+		}
+		res = append(res, movie.FilmographyEntry{
+			MovieID:     row.ID,
+			Title:       row.Title,
+			PosterUrl:   stringOrEmpty(row.PosterUrl),
+			ReleaseDate: releaseDate,
+			Role:        row.Role,
+			Character:   row.CharacterName,
+		})
+	}
+	return res, nil
+}
+
+func (r *movieRepo) SetCredits(ctx context.Context, movieId int64, credits []movie.CreditRequest) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		personIds := make([]int64, 0, len(credits))
+		seen := make(map[int64]bool, len(credits))
+		for _, c := range credits {
+			if !seen[c.PersonID] {
+				seen[c.PersonID] = true
+				personIds = append(personIds, c.PersonID)
+			}
+		}
+		found, err := q.GetPeopleByIds(ctx, personIds)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(personIds) {
+			return movie.ErrPersonNotFound
+		}
+
+		if err := q.DeleteMovieCredits(ctx, movieId); err != nil {
+			return fmt.Errorf("failed to clear movie credits: %w", err)
+		}
+		positions := make(map[string]int32)
+		for _, c := range credits {
+			if err := q.AddMovieCredit(ctx, dbgen.AddMovieCreditParams{
+				MovieID:       movieId,
+				PersonID:      c.PersonID,
+				Role:          c.Role,
+				CharacterName: c.Character,
+				Position:      positions[c.Role],
+			}); err != nil {
+				return fmt.Errorf("failed to add movie credit in transaction: %w", err)
+			}
+			positions[c.Role]++
+		}
+		return q.SyncMovieCatalogFields(ctx, []int64{movieId})
+	})
+}
+
+func (r *movieRepo) ListCreditsByMovies(ctx context.Context, movieIds []int64) (map[int64][]movie.Credit, error) {
+	rows, err := r.store.GetCreditsByMovies(ctx, movieIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64][]movie.Credit, len(movieIds))
+	for _, row := range rows {
+		res[row.MovieID] = append(res[row.MovieID], movie.Credit{
+			PersonID:  row.PersonID,
+			Name:      row.Name,
+			Role:      row.Role,
+			Character: row.CharacterName,
+			Position:  row.Position,
+		})
+	}
+	return res, nil
+}
+
+func (r *movieRepo) CreateInterest(ctx context.Context, userId uuid.UUID, movieId int64) error {
+	return r.store.CreateMovieInterest(ctx, dbgen.CreateMovieInterestParams{
+		UserID:  userId,
+		MovieID: movieId,
+	})
+}
+
+func (r *movieRepo) DeleteInterest(ctx context.Context, userId uuid.UUID, movieId int64) error {
+	rows, err := r.store.DeleteMovieInterest(ctx, dbgen.DeleteMovieInterestParams{
+		UserID:  userId,
+		MovieID: movieId,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return movie.ErrInterestNotFound
+	}
+	return nil
+}
+
+func (r *movieRepo) ListPendingInterests(ctx context.Context, movieId int64) ([]movie.Interest, error) {
+	rows, err := r.store.GetPendingMovieInterests(ctx, movieId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]movie.Interest, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, movie.Interest{
+			UserID:    row.UserID,
+			MovieID:   row.MovieID,
+			Email:     row.Email,
+			FullName:  row.FullName,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (r *movieRepo) MarkInterestsNotified(ctx context.Context, movieId int64, userIds []uuid.UUID) error {
+	return r.store.MarkMovieInterestsNotified(ctx, dbgen.MarkMovieInterestsNotifiedParams{
+		MovieID: movieId,
+		UserIds: userIds,
+	})
+}
+
+// replaceGenreByName makes the named genre, created if needed, the movie's only genre.
+func replaceGenreByName(ctx context.Context, q *dbgen.Queries, movieId int64, name string) error {
+	genre, err := q.GetOrCreateGenre(ctx, dbgen.GetOrCreateGenreParams{
+		Name: name,
+		Slug: movie.Slugify(name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve genre %q: %w", name, err)
+	}
+
+	if err := q.DeleteMovieGenres(ctx, movieId); err != nil {
+		return fmt.Errorf("failed to clear movie genres: %w", err)
+	}
+	return q.AddMovieGenre(ctx, dbgen.AddMovieGenreParams{
+		MovieID: movieId,
+		GenreID: genre.ID,
+	})
+}
+
+// replaceCreditsByName replaces the movie's credits in one role with the named people,
+// matching existing people by exact name and creating the rest.
+func replaceCreditsByName(ctx context.Context, q *dbgen.Queries, movieId int64, role string, names []string) error {
+	if err := q.DeleteMovieCreditsByRole(ctx, dbgen.DeleteMovieCreditsByRoleParams{
+		MovieID: movieId,
+		Role:    role,
+	}); err != nil {
+		return fmt.Errorf("failed to clear %s credits: %w", role, err)
+	}
+
+	for i, name := range names {
+		person, err := q.GetPersonByName(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			person, err = q.CreatePerson(ctx, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve person %q: %w", name, err)
+		}
+
+		if err := q.AddMovieCredit(ctx, dbgen.AddMovieCreditParams{
+			MovieID:  movieId,
+			PersonID: person.ID,
+			Role:     role,
+			Position: int32(i),
+		}); err != nil {
+			return fmt.Errorf("failed to add %s credit: %w", role, err)
+		}
+	}
+	return nil
 }
 
 // Conversion helpers
@@ -122,17 +707,22 @@ func fromDatabaseMovie(dbMovie *dbgen.Movie) *movie.Movie {
 	}
 
 	return &movie.Movie{
-		ID:          dbMovie.ID,
-		Title:       dbMovie.Title,
-		Description: dbMovie.Description,
-		Runtime:     dbMovie.Runtime,
-		Genre:       dbMovie.Genre,
-		AgeRating:   dbMovie.AgeRating,
-		Director:    dbMovie.Director,
-		PosterUrl:   dbMovie.PosterUrl,
-		ReleaseDate: releaseDate,
-		CreatedAt:   dbMovie.CreatedAt,
-		UpdatedAt:   updatedAt,
+		ID:            dbMovie.ID,
+		Title:         dbMovie.Title,
+		Description:   dbMovie.Description,
+		Runtime:       dbMovie.Runtime,
+		Genre:         dbMovie.Genre,
+		AgeRating:     dbMovie.AgeRating,
+		Director:      dbMovie.Director,
+		PosterUrl:     stringOrEmpty(dbMovie.PosterUrl),
+		ReleaseDate:   releaseDate,
+		Cast:          dbMovie.CastMembers,
+		CreatedAt:     dbMovie.CreatedAt,
+		UpdatedAt:     updatedAt,
+		ExternalID:    dbMovie.ExternalID,
+		RatingAverage: dbMovie.RatingAverage,
+		RatingCount:   dbMovie.RatingCount,
+		DeletedAt:     optionalTime(dbMovie.DeletedAt.Time, dbMovie.DeletedAt.Valid),
 	}
 }
 
@@ -147,17 +737,22 @@ func fromDatabaseGetMoviesAdminRow(row *dbgen.GetMoviesAdminRow) *movie.Movie {
 	}
 
 	return &movie.Movie{
-		ID:          row.ID,
-		Title:       row.Title,
-		Description: row.Description,
-		Runtime:     row.Runtime,
-		Genre:       row.Genre,
-		AgeRating:   row.AgeRating,
-		Director:    row.Director,
-		PosterUrl:   row.PosterUrl,
-		ReleaseDate: releaseDate,
-		CreatedAt:   row.CreatedAt,
-		UpdatedAt:   updatedAt,
+		ID:            row.ID,
+		Title:         row.Title,
+		Description:   row.Description,
+		Runtime:       row.Runtime,
+		Genre:         row.Genre,
+		AgeRating:     row.AgeRating,
+		Director:      row.Director,
+		PosterUrl:     stringOrEmpty(row.PosterUrl),
+		ReleaseDate:   releaseDate,
+		Cast:          row.CastMembers,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     updatedAt,
+		Status:        row.Status,
+		ExternalID:    row.ExternalID,
+		RatingAverage: row.RatingAverage,
+		RatingCount:   row.RatingCount,
 	}
 }
 
@@ -172,16 +767,117 @@ func fromDatabaseGetMovieByIdRow(row *dbgen.GetMovieByIdRow) *movie.Movie {
 	}
 
 	return &movie.Movie{
-		ID:          row.ID,
-		Title:       row.Title,
-		Description: row.Description,
-		Runtime:     row.Runtime,
-		Genre:       row.Genre,
-		AgeRating:   row.AgeRating,
-		Director:    row.Director,
-		PosterUrl:   row.PosterUrl,
-		ReleaseDate: releaseDate,
-		CreatedAt:   row.CreatedAt,
-		UpdatedAt:   updatedAt,
+		ID:            row.ID,
+		Title:         row.Title,
+		Description:   row.Description,
+		Runtime:       row.Runtime,
+		Genre:         row.Genre,
+		AgeRating:     row.AgeRating,
+		Director:      row.Director,
+		PosterUrl:     stringOrEmpty(row.PosterUrl),
+		ReleaseDate:   releaseDate,
+		Cast:          row.CastMembers,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     updatedAt,
+		Status:        row.Status,
+		ExternalID:    row.ExternalID,
+		RatingAverage: row.RatingAverage,
+		RatingCount:   row.RatingCount,
+	}
+}
+
+func fromDatabaseSearchMoviesRow(row *dbgen.SearchMoviesRow) *movie.Movie {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	var releaseDate time.Time
+	if row.ReleaseDate.Valid {
+		releaseDate = row.ReleaseDate.Time
+	}
+
+	return &movie.Movie{
+		ID:            row.ID,
+		Title:         row.Title,
+		Description:   row.Description,
+		Runtime:       row.Runtime,
+		Genre:         row.Genre,
+		AgeRating:     row.AgeRating,
+		Director:      row.Director,
+		PosterUrl:     stringOrEmpty(row.PosterUrl),
+		ReleaseDate:   releaseDate,
+		Cast:          row.CastMembers,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     updatedAt,
+		Status:        row.Status,
+		RatingAverage: row.RatingAverage,
+		RatingCount:   row.RatingCount,
+	}
+}
+
+func fromDatabaseGetMoviesComingSoonRow(row *dbgen.GetMoviesComingSoonRow) *movie.Movie {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	var releaseDate time.Time
+	if row.ReleaseDate.Valid {
+		releaseDate = row.ReleaseDate.Time
+	}
+
+	return &movie.Movie{
+		ID:            row.ID,
+		Title:         row.Title,
+		Description:   row.Description,
+		Runtime:       row.Runtime,
+		Genre:         row.Genre,
+		AgeRating:     row.AgeRating,
+		Director:      row.Director,
+		PosterUrl:     stringOrEmpty(row.PosterUrl),
+		ReleaseDate:   releaseDate,
+		Cast:          row.CastMembers,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     updatedAt,
+		Status:        row.Status,
+		RatingAverage: row.RatingAverage,
+		RatingCount:   row.RatingCount,
+	}
+}
+
+func fromDatabaseGenre(dbGenre *dbgen.Genre) *movie.Genre {
+	return &movie.Genre{
+		ID:   dbGenre.ID,
+		Name: dbGenre.Name,
+		Slug: dbGenre.Slug,
+	}
+}
+
+func fromDatabasePerson(dbPerson *dbgen.Person) *movie.Person {
+	var updatedAt *time.Time
+	if dbPerson.UpdatedAt.Valid {
+		updatedAt = &dbPerson.UpdatedAt.Time
+	}
+
+	return &movie.Person{
+		ID:        dbPerson.ID,
+		Name:      dbPerson.Name,
+		CreatedAt: dbPerson.CreatedAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// optionalString stores an empty string as NULL.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// stringOrEmpty reads a NULL string as empty.
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
 	}
+	return *s
 }
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/passkey"
+	"github.com/mbeka02/ticketing-service/internal/user"
+)
+
+type passkeyRepo struct {
+	store *Store
+}
+
+// NewPasskeyRepository creates a new postgres passkey repository.
+func NewPasskeyRepository(store *Store) passkey.Repository {
+	return &passkeyRepo{store}
+}
+
+func (r *passkeyRepo) CreateCredential(ctx context.Context, cred *passkey.Credential) (*passkey.Credential, error) {
+	data, err := json.Marshal(cred.Credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential: %w", err)
+	}
+
+	var created *passkey.Credential
+	err = r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if _, err := q.GetWebAuthnCredential(ctx, cred.ID); err == nil {
+			return passkey.ErrAlreadyRegistered
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		row, err := q.CreateWebAuthnCredential(ctx, dbgen.CreateWebAuthnCredentialParams{
+			ID:         cred.ID,
+			UserID:     cred.UserID,
+			Name:       cred.Name,
+			Credential: data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create credential in transaction: %w", err)
+		}
+
+		_, err = q.LinkIdentityToUser(ctx, dbgen.LinkIdentityToUserParams{
+			UserID:         cred.UserID,
+			Provider:       passkey.ProviderWebAuthn,
+			ProviderUserID: passkey.EncodeID(cred.ID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link identity in transaction: %w", err)
+		}
+
+		created, err = fromDatabaseCredential(&row)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *passkeyRepo) GetCredential(ctx context.Context, id []byte) (*passkey.Credential, error) {
+	row, err := r.store.GetWebAuthnCredential(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, passkey.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseCredential(&row)
+}
+
+func (r *passkeyRepo) ListCredentials(ctx context.Context, userID uuid.UUID) ([]*passkey.Credential, error) {
+	rows, err := r.store.ListWebAuthnCredentialsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]*passkey.Credential, len(rows))
+	for i := range rows {
+		if creds[i], err = fromDatabaseCredential(&rows[i]); err != nil {
+			return nil, err
+		}
+	}
+	return creds, nil
+}
+
+func (r *passkeyRepo) UpdateCredentialUse(ctx context.Context, id []byte, cred webauthn.Credential) error {
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("failed to encode credential: %w", err)
+	}
+	return r.store.UpdateWebAuthnCredentialUse(ctx, dbgen.UpdateWebAuthnCredentialUseParams{
+		ID:         id,
+		Credential: data,
+	})
+}
+
+func (r *passkeyRepo) DeleteCredential(ctx context.Context, userID uuid.UUID, id []byte) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		_, err := unlinkIdentity(ctx, q, userID, func() (dbgen.UserIdentity, error) {
+			return q.DeleteUserIdentity(ctx, dbgen.DeleteUserIdentityParams{
+				UserID:         userID,
+				Provider:       passkey.ProviderWebAuthn,
+				ProviderUserID: passkey.EncodeID(id),
+			})
+		})
+		if errors.Is(err, user.ErrIdentityNotFound) {
+			return passkey.ErrNotFound
+		}
+		return err
+	})
+}
+
+func (r *passkeyRepo) SaveCeremony(ctx context.Context, c *passkey.Ceremony) error {
+	data, err := json.Marshal(c.Session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session data: %w", err)
+	}
+	if err := r.store.DeleteExpiredWebAuthnCeremonies(ctx); err != nil {
+		return fmt.Errorf("failed to delete expired ceremonies: %w", err)
+	}
+	return r.store.CreateWebAuthnCeremony(ctx, dbgen.CreateWebAuthnCeremonyParams{
+		ID:             c.ID,
+		UserID:         pgtype.UUID{Bytes: c.UserID, Valid: c.UserID != uuid.Nil},
+		Kind:           c.Kind,
+		SessionData:    data,
+		CredentialName: c.CredentialName,
+		ExpiresAt:      c.ExpiresAt,
+	})
+}
+
+func (r *passkeyRepo) TakeCeremony(ctx context.Context, id uuid.UUID, kind string) (*passkey.Ceremony, error) {
+	row, err := r.store.TakeWebAuthnCeremony(ctx, dbgen.TakeWebAuthnCeremonyParams{ID: id, Kind: kind})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, passkey.ErrCeremonyExpired
+		}
+		return nil, err
+	}
+
+	c := &passkey.Ceremony{
+		ID:             row.ID,
+		Kind:           row.Kind,
+		CredentialName: row.CredentialName,
+		ExpiresAt:      row.ExpiresAt,
+	}
+	if row.UserID.Valid {
+		c.UserID = row.UserID.Bytes
+	}
+	if err := json.Unmarshal(row.SessionData, &c.Session); err != nil {
+		return nil, fmt.Errorf("failed to decode session data: %w", err)
+	}
+	return c, nil
+}
+
+// fromDatabaseCredential converts a dbgen.WebauthnCredential to a passkey.Credential domain type.
+func fromDatabaseCredential(row *dbgen.WebauthnCredential) (*passkey.Credential, error) {
+	var lastUsedAt *time.Time
+	if row.LastUsedAt.Valid {
+		lastUsedAt = &row.LastUsedAt.Time
+	}
+
+	cred := &passkey.Credential{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: lastUsedAt,
+	}
+	if err := json.Unmarshal(row.Credential, &cred.Credential); err != nil {
+		return nil, fmt.Errorf("failed to decode credential: %w", err)
+	}
+	return cred, nil
+}
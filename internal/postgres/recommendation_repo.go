@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/recommendation"
+)
+
+// recommendationBatchSize caps how many scores are written per statement.
+const recommendationBatchSize = 5000
+
+type recommendationRepo struct {
+	store *Store
+}
+
+// NewRecommendationRepository creates a new postgres recommendation repository.
+func NewRecommendationRepository(store *Store) recommendation.Repository {
+	return &recommendationRepo{store}
+}
+
+func (r *recommendationRepo) ListInteractions(ctx context.Context) ([]recommendation.Interaction, error) {
+	bookings, err := r.store.GetBookingHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := r.store.GetReviewHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		userId  uuid.UUID
+		movieId int64
+	}
+	index := make(map[key]int, len(bookings))
+	res := make([]recommendation.Interaction, 0, len(bookings))
+	for _, b := range bookings {
+		index[key{b.UserID, b.MovieID}] = len(res)
+		res = append(res, recommendation.Interaction{
+			UserID:  b.UserID,
+			MovieID: b.MovieID,
+			Seats:   b.Seats,
+		})
+	}
+	for _, rv := range reviews {
+		rating := rv.Rating
+		if i, ok := index[key{rv.UserID, rv.MovieID}]; ok {
+			res[i].Rating = &rating
+			continue
+		}
+		res = append(res, recommendation.Interaction{
+			UserID:  rv.UserID,
+			MovieID: rv.MovieID,
+			Rating:  &rating,
+		})
+	}
+	return res, nil
+}
+
+func (r *recommendationRepo) ListCandidates(ctx context.Context) ([]recommendation.Candidate, error) {
+	rows, err := r.store.GetRecommendationCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]recommendation.Candidate, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, recommendation.Candidate{
+			MovieID:       row.ID,
+			RatingAverage: row.RatingAverage,
+			TicketsSold:   row.TicketsSold,
+		})
+	}
+	return res, nil
+}
+
+func (r *recommendationRepo) ListFeatures(ctx context.Context, movieIds []int64) ([]recommendation.Feature, error) {
+	rows, err := r.store.GetMovieFeatures(ctx, movieIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]recommendation.Feature, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, recommendation.Feature{
+			MovieID: row.MovieID,
+			Kind:    row.Kind,
+			ID:      row.FeatureID,
+		})
+	}
+	return res, nil
+}
+
+func (r *recommendationRepo) ReplaceScores(ctx context.Context, scores []recommendation.Score) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if err := q.DeleteAllRecommendations(ctx); err != nil {
+			return fmt.Errorf("failed to clear recommendations: %w", err)
+		}
+
+		for start := 0; start < len(scores); start += recommendationBatchSize {
+			batch := scores[start:min(start+recommendationBatchSize, len(scores))]
+			params := dbgen.InsertRecommendationsParams{
+				UserIds:  make([]uuid.UUID, 0, len(batch)),
+				MovieIds: make([]int64, 0, len(batch)),
+				Scores:   make([]float64, 0, len(batch)),
+			}
+			for _, s := range batch {
+				params.UserIds = append(params.UserIds, s.UserID)
+				params.MovieIds = append(params.MovieIds, s.MovieID)
+				params.Scores = append(params.Scores, s.Score)
+			}
+			if err := q.InsertRecommendations(ctx, params); err != nil {
+				return fmt.Errorf("failed to insert recommendations: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *recommendationRepo) LastComputedAt(ctx context.Context) (*time.Time, error) {
+	computedAt, err := r.store.GetRecommendationsComputedAt(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &computedAt, nil
+}
+
+func (r *recommendationRepo) ListForUser(ctx context.Context, userId uuid.UUID, limit int32) ([]recommendation.Score, error) {
+	rows, err := r.store.GetUserRecommendations(ctx, dbgen.GetUserRecommendationsParams{
+		UserID: userId,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]recommendation.Score, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, recommendation.Score{
+			UserID:  userId,
+			MovieID: row.MovieID,
+			Score:   row.Score,
+		})
+	}
+	return res, nil
+}
+
+func (r *recommendationRepo) ListPopular(ctx context.Context, userId uuid.UUID, limit int32) ([]recommendation.Candidate, error) {
+	rows, err := r.store.GetPopularMovies(ctx, dbgen.GetPopularMoviesParams{UserID: userId, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]recommendation.Candidate, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, recommendation.Candidate{
+			MovieID:       row.MovieID,
+			RatingAverage: row.RatingAverage,
+			TicketsSold:   row.TicketsSold,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/review"
+)
+
+type reviewRepo struct {
+	store *Store
+}
+
+// NewReviewRepository creates a new postgres review repository.
+func NewReviewRepository(store *Store) review.Repository {
+	return &reviewRepo{store}
+}
+
+// reviewMovieUserKey is the UNIQUE (movie_id, user_id) constraint, named by Postgres.
+const reviewMovieUserKey = "reviews_movie_id_user_id_key"
+
+func (r *reviewRepo) Create(ctx context.Context, movieId int64, userId uuid.UUID, req review.CreateReviewRequest) (*review.Review, error) {
+	var id int64
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbReview, err := q.CreateReview(ctx, dbgen.CreateReviewParams{
+			MovieID: movieId,
+			UserID:  userId,
+			Rating:  req.Rating,
+			Title:   req.Title,
+			Body:    req.Body,
+		})
+		if isUniqueViolation(err, reviewMovieUserKey) {
+			return review.ErrAlreadyReviewed
+		}
+		if err != nil {
+			return err
+		}
+		id = dbReview.ID
+		return q.SyncMovieRating(ctx, movieId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *reviewRepo) GetByID(ctx context.Context, id int64) (*review.Review, error) {
+	row, err := r.store.GetReviewById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, review.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseReviewRow(row), nil
+}
+
+func (r *reviewRepo) GetByMovieAndUser(ctx context.Context, movieId int64, userId uuid.UUID) (*review.Review, error) {
+	dbReview, err := r.store.GetReviewByMovieAndUser(ctx, dbgen.GetReviewByMovieAndUserParams{
+		MovieID: movieId,
+		UserID:  userId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, review.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseReview(&dbReview), nil
+}
+
+func (r *reviewRepo) ListByMovie(ctx context.Context, movieId int64, limit, offset int32) ([]review.Review, error) {
+	rows, err := r.store.GetReviewsByMovie(ctx, dbgen.GetReviewsByMovieParams{
+		MovieID: movieId,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]review.Review, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *fromDatabaseReviewRow(dbgen.GetReviewByIdRow(row)))
+	}
+	return res, nil
+}
+
+func (r *reviewRepo) ListAdmin(ctx context.Context, filter review.AdminFilter) ([]review.Review, error) {
+	rows, err := r.store.GetReviewsAdmin(ctx, dbgen.GetReviewsAdminParams{
+		MovieID: filter.MovieID,
+		Hidden:  filter.Hidden,
+		Flagged: filter.Flagged,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]review.Review, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *fromDatabaseReviewRow(dbgen.GetReviewByIdRow(row)))
+	}
+	return res, nil
+}
+
+func (r *reviewRepo) Update(ctx context.Context, id int64, req review.UpdateReviewRequest) (*review.Review, error) {
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbReview, err := q.UpdateReview(ctx, dbgen.UpdateReviewParams{
+			ID:     id,
+			Rating: req.Rating,
+			Title:  req.Title,
+			Body:   req.Body,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return review.ErrNotFound
+			}
+			return err
+		}
+		return q.SyncMovieRating(ctx, dbReview.MovieID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *reviewRepo) Delete(ctx context.Context, id int64) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		movieId, err := q.DeleteReview(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return review.ErrNotFound
+			}
+			return err
+		}
+		return q.SyncMovieRating(ctx, movieId)
+	})
+}
+
+func (r *reviewRepo) Moderate(ctx context.Context, id int64, action string, reason *string) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		var movieId int64
+		var err error
+		switch action {
+		case review.ActionHide:
+			movieId, err = q.HideReview(ctx, dbgen.HideReviewParams{ID: id, HiddenReason: reason})
+		case review.ActionUnhide:
+			movieId, err = q.UnhideReview(ctx, id)
+		case review.ActionFlag:
+			movieId, err = q.FlagReview(ctx, dbgen.FlagReviewParams{ID: id, FlagReason: reason})
+		case review.ActionUnflag:
+			movieId, err = q.UnflagReview(ctx, id)
+		}
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return review.ErrNotFound
+			}
+			return err
+		}
+		return q.SyncMovieRating(ctx, movieId)
+	})
+}
+
+func (r *reviewRepo) HasAttended(ctx context.Context, userId uuid.UUID, movieId int64) (bool, error) {
+	return r.store.HasAttendedMovie(ctx, dbgen.HasAttendedMovieParams{
+		UserID:  userId,
+		MovieID: movieId,
+	})
+}
+
+func fromDatabaseReview(dbReview *dbgen.Review) *review.Review {
+	return fromDatabaseReviewRow(dbgen.GetReviewByIdRow{
+		ID:           dbReview.ID,
+		MovieID:      dbReview.MovieID,
+		UserID:       dbReview.UserID,
+		Rating:       dbReview.Rating,
+		Title:        dbReview.Title,
+		Body:         dbReview.Body,
+		HiddenAt:     dbReview.HiddenAt,
+		HiddenReason: dbReview.HiddenReason,
+		FlaggedAt:    dbReview.FlaggedAt,
+		FlagReason:   dbReview.FlagReason,
+		CreatedAt:    dbReview.CreatedAt,
+		UpdatedAt:    dbReview.UpdatedAt,
+	})
+}
+
+// fromDatabaseReviewRow converts a review joined with its author. The listing queries
+// return identical row types, which convert directly to GetReviewByIdRow.
+func fromDatabaseReviewRow(row dbgen.GetReviewByIdRow) *review.Review {
+	return &review.Review{
+		ID:           row.ID,
+		MovieID:      row.MovieID,
+		UserID:       row.UserID,
+		AuthorName:   row.AuthorName,
+		Rating:       row.Rating,
+		Title:        row.Title,
+		Body:         row.Body,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    optionalTime(row.UpdatedAt.Time, row.UpdatedAt.Valid),
+		HiddenAt:     optionalTime(row.HiddenAt.Time, row.HiddenAt.Valid),
+		HiddenReason: row.HiddenReason,
+		FlaggedAt:    optionalTime(row.FlaggedAt.Time, row.FlaggedAt.Valid),
+		FlagReason:   row.FlagReason,
+	}
+}
+
+func optionalTime(t time.Time, valid bool) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
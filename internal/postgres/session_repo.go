@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/session"
+)
+
+type sessionRepo struct {
+	store *Store
+}
+
+// NewSessionRepository creates a new postgres session repository.
+func NewSessionRepository(store *Store) session.Repository {
+	return &sessionRepo{store}
+}
+
+func (r *sessionRepo) Create(ctx context.Context, params session.CreateParams) (*session.Session, error) {
+	row, err := r.store.CreateSession(ctx, dbgen.CreateSessionParams{
+		ID:               params.ID,
+		UserID:           params.UserID,
+		CurrentTokenHash: params.TokenHash,
+		Device:           params.Client.Device(),
+		IpAddress:        params.Client.IPAddress,
+		UserAgent:        params.Client.UserAgent,
+		ExpiresAt:        params.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromDatabaseSession(&row), nil
+}
+
+func (r *sessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	row, err := r.store.GetSessionById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, session.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseSession(&row), nil
+}
+
+func (r *sessionRepo) Rotate(ctx context.Context, params session.RotateParams) (bool, error) {
+	n, err := r.store.RotateSession(ctx, dbgen.RotateSessionParams{
+		ID:           params.ID,
+		TokenHash:    params.TokenHash,
+		NewTokenHash: params.NewTokenHash,
+		IpAddress:    params.Client.IPAddress,
+		UserAgent:    params.Client.UserAgent,
+		ExpiresAt:    params.ExpiresAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *sessionRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]session.Session, error) {
+	rows, err := r.store.GetActiveSessionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]session.Session, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *fromDatabaseSession(&row))
+	}
+	return res, nil
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, id uuid.UUID, reason string) error {
+	n, err := r.store.RevokeSession(ctx, dbgen.RevokeSessionParams{
+		ID:            id,
+		RevokedReason: &reason,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepo) RevokeForUser(ctx context.Context, userID, id uuid.UUID, reason string) error {
+	n, err := r.store.RevokeUserSession(ctx, dbgen.RevokeUserSessionParams{
+		ID:            id,
+		UserID:        userID,
+		RevokedReason: &reason,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepo) RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	return r.store.RevokeUserSessions(ctx, dbgen.RevokeUserSessionsParams{
+		UserID:        userID,
+		RevokedReason: &reason,
+	})
+}
+
+// fromDatabaseSession converts a dbgen.Session to a session.Session domain type.
+func fromDatabaseSession(row *dbgen.Session) *session.Session {
+	return &session.Session{
+		ID:                row.ID,
+		UserID:            row.UserID,
+		CurrentTokenHash:  row.CurrentTokenHash,
+		PreviousTokenHash: row.PreviousTokenHash,
+		RotatedAt:         optionalTime(row.RotatedAt.Time, row.RotatedAt.Valid),
+		Device:            row.Device,
+		IPAddress:         row.IpAddress,
+		UserAgent:         row.UserAgent,
+		CreatedAt:         row.CreatedAt,
+		LastSeenAt:        row.LastSeenAt,
+		ExpiresAt:         row.ExpiresAt,
+		RevokedAt:         optionalTime(row.RevokedAt.Time, row.RevokedAt.Valid),
+		RevokedReason:     row.RevokedReason,
+	}
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mbeka02/ticketing-service/internal/dbgen"
 	"github.com/mbeka02/ticketing-service/internal/showtime"
@@ -32,12 +35,18 @@ func (r *showtimeRepo) Create(ctx context.Context, req showtime.CreateShowtimeRe
 	price.Scan(req.PricePerSeat)
 
 	dbShowtime, err := r.store.CreateShowtime(ctx, dbgen.CreateShowtimeParams{
-		MovieID:        req.MovieID,
-		StartTime:      start,
-		EndTime:        end,
-		AvailableSeats: req.AvailableSeats,
-		PricePerSeat:   price,
-		VenueID:        req.VenueID,
+		MovieID:          req.MovieID,
+		StartTime:        start,
+		EndTime:          end,
+		AvailableSeats:   req.AvailableSeats,
+		PricePerSeat:     price,
+		AuditoriumID:     req.AuditoriumID,
+		Format:           req.Format,
+		AudioLanguage:    req.AudioLanguage,
+		SubtitleLanguage: req.SubtitleLanguage,
+		IsDubbed:         req.IsDubbed,
+		AudioDescribed:   req.AudioDescribed,
+		RelaxedScreening: req.RelaxedScreening,
 	})
 	if err != nil {
 		return nil, err
@@ -48,9 +57,12 @@ func (r *showtimeRepo) Create(ctx context.Context, req showtime.CreateShowtimeRe
 func (r *showtimeRepo) GetByID(ctx context.Context, id int64) (*showtime.Showtime, error) {
 	dbShowtime, err := r.store.GetShowtimeById(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, showtime.ErrNotFound
+		}
 		return nil, err
 	}
-	return fromDatabaseShowtime(&dbShowtime), nil
+	return fromDatabaseGetShowtimeByIdRow(&dbShowtime), nil
 }
 
 func (r *showtimeRepo) ListByMovie(ctx context.Context, movieId int64) ([]showtime.Showtime, error) {
@@ -82,11 +94,80 @@ func (r *showtimeRepo) ListAdmin(ctx context.Context, limit, offset int32) ([]sh
 	return res, nil
 }
 
+func (r *showtimeRepo) List(ctx context.Context, filter showtime.ListFilter) ([]showtime.Showtime, error) {
+	params := dbgen.ListShowtimesParams{
+		VenueID:          filter.VenueID,
+		City:             filter.City,
+		Genre:            filter.Genre,
+		AgeRating:        filter.AgeRating,
+		MinSeats:         filter.MinSeats,
+		Format:           filter.Format,
+		AudioLanguage:    filter.AudioLanguage,
+		SubtitleLanguage: filter.SubtitleLanguage,
+		AudioDescribed:   filter.AudioDescribed,
+		RelaxedScreening: filter.RelaxedScreening,
+		Limit:            filter.Limit,
+		Offset:           filter.Offset,
+	}
+	if filter.DateFrom != nil {
+		params.DateFrom = pgtype.Date{Time: *filter.DateFrom, Valid: true}
+	}
+	if filter.DateTo != nil {
+		params.DateTo = pgtype.Date{Time: *filter.DateTo, Valid: true}
+	}
+
+	rows, err := r.store.ListShowtimes(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]showtime.Showtime, 0, len(rows))
+	for _, s := range rows {
+		res = append(res, *fromDatabaseListShowtimesRow(&s))
+	}
+	return res, nil
+}
+
+func (r *showtimeRepo) GetVenueProgramme(ctx context.Context, venueId int32, windowStart, windowEnd time.Time) ([]showtime.MovieProgramme, error) {
+	rows, err := r.store.GetVenueProgramme(ctx, dbgen.GetVenueProgrammeParams{
+		VenueID:     venueId,
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are ordered by movie, so a new group starts whenever the movie changes.
+	res := make([]showtime.MovieProgramme, 0)
+	for _, row := range rows {
+		if len(res) == 0 || res[len(res)-1].MovieID != row.MovieID {
+			res = append(res, showtime.MovieProgramme{
+				MovieID:        row.MovieID,
+				MovieTitle:     row.MovieTitle,
+				MovieGenre:     row.MovieGenre,
+				MovieAgeRating: row.MovieAgeRating,
+				MovieRuntime:   row.MovieRuntime,
+				MoviePosterUrl: stringOrEmpty(row.MoviePosterUrl),
+			})
+		}
+		current := &res[len(res)-1]
+		current.Showtimes = append(current.Showtimes, *fromDatabaseGetVenueProgrammeRow(&row))
+	}
+	return res, nil
+}
+
 func (r *showtimeRepo) Update(ctx context.Context, id int64, req showtime.UpdateShowtimeRequest) (*showtime.Showtime, error) {
 	params := dbgen.UpdateShowtimeParams{
-		ID:             id,
-		AvailableSeats: req.AvailableSeats,
-		VenueID:        req.VenueID,
+		ID:               id,
+		AvailableSeats:   req.AvailableSeats,
+		AuditoriumID:     req.AuditoriumID,
+		Format:           req.Format,
+		AudioLanguage:    req.AudioLanguage,
+		SubtitleLanguage: req.SubtitleLanguage,
+		IsDubbed:         req.IsDubbed,
+		AudioDescribed:   req.AudioDescribed,
+		RelaxedScreening: req.RelaxedScreening,
 	}
 
 	if req.StartTime != nil {
@@ -113,7 +194,59 @@ func (r *showtimeRepo) Update(ctx context.Context, id int64, req showtime.Update
 }
 
 func (r *showtimeRepo) Delete(ctx context.Context, id int64) error {
-	return r.store.DeleteShowtime(ctx, id)
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		rows, err := q.DeleteShowtime(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return showtime.ErrNotFound
+		}
+		return cancelReservations(ctx, q, []int64{id})
+	})
+}
+
+func (r *showtimeRepo) GetDeleted(ctx context.Context, id int64) (*showtime.Showtime, error) {
+	row, err := r.store.GetDeletedShowtimeById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, showtime.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseGetDeletedShowtimesRow((*dbgen.GetDeletedShowtimesRow)(&row)), nil
+}
+
+func (r *showtimeRepo) ListDeleted(ctx context.Context, limit, offset int32) ([]showtime.Showtime, error) {
+	rows, err := r.store.GetDeletedShowtimes(ctx, dbgen.GetDeletedShowtimesParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]showtime.Showtime, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, *fromDatabaseGetDeletedShowtimesRow(&row))
+	}
+	return res, nil
+}
+
+func (r *showtimeRepo) Restore(ctx context.Context, id int64) error {
+	return r.store.RestoreShowtime(ctx, id)
+}
+
+// cancelReservations cancels the open reservations of showtimes that were just deleted,
+// releasing their seats and marking completed payments for refund.
+func cancelReservations(ctx context.Context, q *dbgen.Queries, showtimeIds []int64) error {
+	if len(showtimeIds) == 0 {
+		return nil
+	}
+	if err := q.CancelReservationsByShowtimes(ctx, showtimeIds); err != nil {
+		return fmt.Errorf("failed to cancel reservations: %w", err)
+	}
+	return nil
 }
 
 // Conversion helpers
@@ -126,15 +259,54 @@ func fromDatabaseShowtime(dbShowtime *dbgen.Showtime) *showtime.Showtime {
 	price, _ := dbShowtime.PricePerSeat.Float64Value()
 
 	return &showtime.Showtime{
-		ID:             dbShowtime.ID,
-		MovieID:        dbShowtime.MovieID,
-		StartTime:      dbShowtime.StartTime,
-		EndTime:        dbShowtime.EndTime,
-		AvailableSeats: dbShowtime.AvailableSeats,
-		PricePerSeat:   price.Float64,
-		VenueID:        dbShowtime.VenueID,
-		CreatedAt:      dbShowtime.CreatedAt,
-		UpdatedAt:      updatedAt,
+		ID:               dbShowtime.ID,
+		MovieID:          dbShowtime.MovieID,
+		StartTime:        dbShowtime.StartTime,
+		EndTime:          dbShowtime.EndTime,
+		AvailableSeats:   dbShowtime.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     dbShowtime.AuditoriumID,
+		CreatedAt:        dbShowtime.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           dbShowtime.Format,
+		AudioLanguage:    dbShowtime.AudioLanguage,
+		SubtitleLanguage: dbShowtime.SubtitleLanguage,
+		IsDubbed:         dbShowtime.IsDubbed,
+		AudioDescribed:   dbShowtime.AudioDescribed,
+		RelaxedScreening: dbShowtime.RelaxedScreening,
+	}
+}
+
+func fromDatabaseGetShowtimeByIdRow(row *dbgen.GetShowtimeByIdRow) *showtime.Showtime {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	price, _ := row.PricePerSeat.Float64Value()
+
+	return &showtime.Showtime{
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+		MovieTitle:       &row.MovieTitle,
+		MovieGenre:       &row.MovieGenre,
+		MovieAgeRating:   &row.MovieAgeRating,
+		VenueName:        &row.VenueName,
+		VenueCity:        &row.VenueCity,
 	}
 }
 
@@ -146,17 +318,25 @@ func fromDatabaseGetShowtimesByMovieRow(row *dbgen.GetShowtimesByMovieRow) *show
 	price, _ := row.PricePerSeat.Float64Value()
 
 	return &showtime.Showtime{
-		ID:             row.ID,
-		MovieID:        row.MovieID,
-		StartTime:      row.StartTime,
-		EndTime:        row.EndTime,
-		AvailableSeats: row.AvailableSeats,
-		PricePerSeat:   price.Float64,
-		VenueID:        row.VenueID,
-		CreatedAt:      row.CreatedAt,
-		UpdatedAt:      updatedAt,
-		VenueName:      &row.VenueName,
-		VenueCity:      &row.VenueCity,
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+		VenueName:        &row.VenueName,
+		VenueCity:        &row.VenueCity,
 	}
 }
 
@@ -168,16 +348,117 @@ func fromDatabaseGetShowtimesAdminRow(row *dbgen.GetShowtimesAdminRow) *showtime
 	price, _ := row.PricePerSeat.Float64Value()
 
 	return &showtime.Showtime{
-		ID:             row.ID,
-		MovieID:        row.MovieID,
-		StartTime:      row.StartTime,
-		EndTime:        row.EndTime,
-		AvailableSeats: row.AvailableSeats,
-		PricePerSeat:   price.Float64,
-		VenueID:        row.VenueID,
-		CreatedAt:      row.CreatedAt,
-		UpdatedAt:      updatedAt,
-		MovieTitle:     &row.MovieTitle,
-		VenueName:      &row.VenueName,
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+		MovieTitle:       &row.MovieTitle,
+		VenueName:        &row.VenueName,
+	}
+}
+
+func fromDatabaseListShowtimesRow(row *dbgen.ListShowtimesRow) *showtime.Showtime {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	price, _ := row.PricePerSeat.Float64Value()
+
+	return &showtime.Showtime{
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+		MovieTitle:       &row.MovieTitle,
+		MovieGenre:       &row.MovieGenre,
+		MovieAgeRating:   &row.MovieAgeRating,
+		VenueName:        &row.VenueName,
+		VenueCity:        &row.VenueCity,
+	}
+}
+
+func fromDatabaseGetVenueProgrammeRow(row *dbgen.GetVenueProgrammeRow) *showtime.Showtime {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	price, _ := row.PricePerSeat.Float64Value()
+
+	return &showtime.Showtime{
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+	}
+}
+
+func fromDatabaseGetDeletedShowtimesRow(row *dbgen.GetDeletedShowtimesRow) *showtime.Showtime {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+	price, _ := row.PricePerSeat.Float64Value()
+
+	return &showtime.Showtime{
+		ID:               row.ID,
+		MovieID:          row.MovieID,
+		StartTime:        row.StartTime,
+		EndTime:          row.EndTime,
+		AvailableSeats:   row.AvailableSeats,
+		PricePerSeat:     price.Float64,
+		AuditoriumID:     row.AuditoriumID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           row.Format,
+		AudioLanguage:    row.AudioLanguage,
+		SubtitleLanguage: row.SubtitleLanguage,
+		IsDubbed:         row.IsDubbed,
+		AudioDescribed:   row.AudioDescribed,
+		RelaxedScreening: row.RelaxedScreening,
+		VenueID:          &row.VenueID,
+		AuditoriumName:   &row.AuditoriumName,
+		MovieTitle:       &row.MovieTitle,
+		VenueName:        &row.VenueName,
+		DeletedAt:        optionalTime(row.DeletedAt.Time, row.DeletedAt.Valid),
+		ParentDeleted:    row.ParentDeleted,
 	}
 }
@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mbeka02/ticketing-service/config"
 	"github.com/mbeka02/ticketing-service/internal/dbgen"
@@ -82,6 +84,16 @@ func (s *Store) ExecTx(ctx context.Context, fn func(*dbgen.Queries) error) error
 	return tx.Commit(ctx)
 }
 
+// uniqueViolation is the SQLSTATE Postgres reports when a unique constraint is violated.
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err was caused by the named unique constraint or index.
+// Services check for duplicates up front; this catches the writes that race past the check.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
+
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
 func (s *Store) Health() map[string]string {
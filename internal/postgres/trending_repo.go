@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/trending"
+)
+
+type trendingRepo struct {
+	store *Store
+}
+
+// NewTrendingRepository creates a new postgres trending repository.
+func NewTrendingRepository(store *Store) trending.Repository {
+	return &trendingRepo{store}
+}
+
+func (r *trendingRepo) ListSignals(ctx context.Context, halfLife, window time.Duration) ([]trending.Signal, error) {
+	rows, err := r.store.GetTrendingSignals(ctx, dbgen.GetTrendingSignalsParams{
+		HalfLifeHours: halfLife.Hours(),
+		WindowDays:    int32(window / (24 * time.Hour)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]trending.Signal, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, trending.Signal{
+			MovieID:     row.MovieID,
+			TicketsSold: row.TicketsSold,
+			Velocity:    row.Velocity,
+			SeatsSold:   row.SeatsSold,
+			SeatsTotal:  row.SeatsTotal,
+		})
+	}
+	return res, nil
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mbeka02/ticketing-service/internal/dbgen"
+	"github.com/mbeka02/ticketing-service/internal/passkey"
+	"github.com/mbeka02/ticketing-service/internal/session"
 	"github.com/mbeka02/ticketing-service/internal/user"
 )
 
@@ -105,7 +107,7 @@ func (r *userRepo) CreateLocalWithIdentity(ctx context.Context, email, fullName,
 
 		_, err = q.LinkIdentityToUser(ctx, dbgen.LinkIdentityToUserParams{
 			UserID:         dbUser.ID,
-			Provider:       "local",
+			Provider:       user.ProviderLocal,
 			ProviderUserID: dbUser.ID.String(),
 		})
 		if err != nil {
@@ -131,6 +133,272 @@ func (r *userRepo) LinkIdentity(ctx context.Context, userID uuid.UUID, provider,
 	return err
 }
 
+func (r *userRepo) GetIdentity(ctx context.Context, provider, providerUserID string) (*user.Identity, error) {
+	row, err := r.store.GetIdentityByProvider(ctx, dbgen.GetIdentityByProviderParams{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseIdentity(&row), nil
+}
+
+func (r *userRepo) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*user.Identity, error) {
+	rows, err := r.store.ListUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identities := make([]*user.Identity, len(rows))
+	for i := range rows {
+		identities[i] = fromDatabaseIdentity(&rows[i])
+	}
+	return identities, nil
+}
+
+func (r *userRepo) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) (*user.Identity, error) {
+	var removed *user.Identity
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		row, err := unlinkIdentity(ctx, q, userID, func() (dbgen.UserIdentity, error) {
+			return q.DeleteUserIdentityByID(ctx, dbgen.DeleteUserIdentityByIDParams{
+				ID:     identityID,
+				UserID: userID,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		removed = fromDatabaseIdentity(&row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// unlinkIdentity deletes one of the user's identities with remove, along with the password
+// or passkey behind it, and fails with user.ErrLastIdentity if the user would be left
+// without a way to sign in. The user's row stays locked until the transaction ends, so
+// concurrent unlinks are checked one after the other.
+func unlinkIdentity(ctx context.Context, q *dbgen.Queries, userID uuid.UUID, remove func() (dbgen.UserIdentity, error)) (dbgen.UserIdentity, error) {
+	if _, err := q.LockUser(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbgen.UserIdentity{}, user.ErrNotFound
+		}
+		return dbgen.UserIdentity{}, err
+	}
+
+	identity, err := remove()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbgen.UserIdentity{}, user.ErrIdentityNotFound
+		}
+		return dbgen.UserIdentity{}, err
+	}
+
+	switch identity.Provider {
+	case user.ProviderLocal:
+		if err := q.ClearUserPassword(ctx, userID); err != nil {
+			return dbgen.UserIdentity{}, fmt.Errorf("failed to clear password: %w", err)
+		}
+	case passkey.ProviderWebAuthn:
+		credentialID, err := passkey.DecodeID(identity.ProviderUserID)
+		if err != nil {
+			return dbgen.UserIdentity{}, err
+		}
+		if _, err := q.DeleteWebAuthnCredential(ctx, dbgen.DeleteWebAuthnCredentialParams{
+			ID:     credentialID,
+			UserID: userID,
+		}); err != nil {
+			return dbgen.UserIdentity{}, fmt.Errorf("failed to delete passkey: %w", err)
+		}
+	}
+
+	remaining, err := q.CountUserIdentities(ctx, userID)
+	if err != nil {
+		return dbgen.UserIdentity{}, err
+	}
+	if remaining == 0 {
+		return dbgen.UserIdentity{}, user.ErrLastIdentity
+	}
+	return identity, nil
+}
+
+func (r *userRepo) UpdateProfile(ctx context.Context, id uuid.UUID, params user.UpdateProfileParams) (*user.User, error) {
+	args := dbgen.UpdateUserProfileParams{
+		ID:              id,
+		FullName:        params.FullName,
+		TelephoneNumber: params.TelephoneNumber,
+	}
+	if params.DateOfBirth != nil {
+		args.DateOfBirth = pgtype.Date{Time: *params.DateOfBirth, Valid: true}
+	}
+
+	dbUser, err := r.store.UpdateUserProfile(ctx, args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseUser(&dbUser), nil
+}
+
+func (r *userRepo) CreateToken(ctx context.Context, userID uuid.UUID, purpose, tokenHash string, expiresAt time.Time) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if err := q.InvalidateUserTokens(ctx, dbgen.InvalidateUserTokensParams{
+			UserID:  userID,
+			Purpose: purpose,
+		}); err != nil {
+			return fmt.Errorf("failed to invalidate tokens: %w", err)
+		}
+		return q.CreateUserToken(ctx, dbgen.CreateUserTokenParams{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: tokenHash,
+			ExpiresAt: expiresAt,
+		})
+	})
+}
+
+func (r *userRepo) LatestTokenAt(ctx context.Context, userID uuid.UUID, purpose string) (*time.Time, error) {
+	createdAt, err := r.store.GetLatestUserTokenCreatedAt(ctx, dbgen.GetLatestUserTokenCreatedAtParams{
+		UserID:  userID,
+		Purpose: purpose,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &createdAt, nil
+}
+
+func (r *userRepo) VerifyEmail(ctx context.Context, tokenHash string) (*user.User, error) {
+	var verified *user.User
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		userID, err := q.ConsumeUserToken(ctx, dbgen.ConsumeUserTokenParams{
+			TokenHash: tokenHash,
+			Purpose:   user.TokenPurposeEmailVerification,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return user.ErrInvalidToken
+			}
+			return err
+		}
+
+		dbUser, err := q.MarkUserVerified(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return user.ErrInvalidToken
+			}
+			return err
+		}
+		verified = fromDatabaseUser(&dbUser)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verified, nil
+}
+
+func (r *userRepo) SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) (*user.User, error) {
+	var updated *user.User
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		var err error
+		updated, err = setPassword(ctx, q, id, passwordHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *userRepo) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (*user.User, error) {
+	var updated *user.User
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		userID, err := q.ConsumeUserToken(ctx, dbgen.ConsumeUserTokenParams{
+			TokenHash: tokenHash,
+			Purpose:   user.TokenPurposePasswordReset,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return user.ErrInvalidToken
+			}
+			return err
+		}
+
+		updated, err = setPassword(ctx, q, userID, passwordHash)
+		if errors.Is(err, user.ErrNotFound) {
+			return user.ErrInvalidToken
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// setPassword updates the password, signs the user out everywhere and, for accounts that
+// only had social logins until now, links the local identity password logins are
+// recorded under.
+func setPassword(ctx context.Context, q *dbgen.Queries, id uuid.UUID, passwordHash string) (*user.User, error) {
+	dbUser, err := q.UpdateUserPassword(ctx, dbgen.UpdateUserPasswordParams{
+		ID:           id,
+		PasswordHash: &passwordHash,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+
+	reason := session.ReasonPasswordChanged
+	if _, err := q.RevokeUserSessions(ctx, dbgen.RevokeUserSessionsParams{
+		UserID:        id,
+		RevokedReason: &reason,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	_, err = q.GetIdentityByProvider(ctx, dbgen.GetIdentityByProviderParams{
+		Provider:       user.ProviderLocal,
+		ProviderUserID: id.String(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = q.LinkIdentityToUser(ctx, dbgen.LinkIdentityToUserParams{
+			UserID:         id,
+			Provider:       user.ProviderLocal,
+			ProviderUserID: id.String(),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to link local identity: %w", err)
+	}
+	return fromDatabaseUser(&dbUser), nil
+}
+
+// fromDatabaseIdentity converts a dbgen.UserIdentity to a user.Identity domain type.
+func fromDatabaseIdentity(row *dbgen.UserIdentity) *user.Identity {
+	return &user.Identity{
+		ID:             row.ID,
+		UserID:         row.UserID,
+		Provider:       row.Provider,
+		ProviderUserID: row.ProviderUserID,
+		CreatedAt:      row.CreatedAt,
+	}
+}
+
 // fromDatabaseUser converts a dbgen.User to a user.User domain type.
 func fromDatabaseUser(dbUser *dbgen.User) *user.User {
 	var updatedAt *time.Time
@@ -141,6 +409,10 @@ func fromDatabaseUser(dbUser *dbgen.User) *user.User {
 	if dbUser.VerifiedAt.Valid {
 		verifiedAt = &dbUser.VerifiedAt.Time
 	}
+	var dateOfBirth *time.Time
+	if dbUser.DateOfBirth.Valid {
+		dateOfBirth = &dbUser.DateOfBirth.Time
+	}
 
 	return &user.User{
 		ID:              dbUser.ID,
@@ -154,5 +426,6 @@ func fromDatabaseUser(dbUser *dbgen.User) *user.User {
 		CreatedAt:       dbUser.CreatedAt,
 		UpdatedAt:       updatedAt,
 		VerifiedAt:      verifiedAt,
+		DateOfBirth:     dateOfBirth,
 	}
 }
@@ -2,8 +2,13 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mbeka02/ticketing-service/internal/dbgen"
 	"github.com/mbeka02/ticketing-service/internal/venue"
 )
@@ -18,24 +23,46 @@ func NewVenueRepository(store *Store) venue.Repository {
 }
 
 func (r *venueRepo) Create(ctx context.Context, req venue.CreateVenueRequest) (*venue.Venue, error) {
-	dbVenue, err := r.store.CreateVenue(ctx, dbgen.CreateVenueParams{
-		Name:       req.Name,
-		Address:    req.Address,
-		City:       req.City,
-		TotalSeats: req.TotalSeats,
+	var createdVenue *venue.Venue
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbVenue, err := q.CreateVenue(ctx, dbgen.CreateVenueParams{
+			Name:      req.Name,
+			Address:   req.Address,
+			City:      req.City,
+			Timezone:  req.Timezone,
+			Amenities: req.Amenities,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create venue in transaction: %w", err)
+		}
+
+		createdVenue = fromDatabaseVenue(&dbVenue)
+		for _, a := range req.Auditoriums {
+			auditorium, err := createAuditorium(ctx, q, dbVenue.ID, a)
+			if err != nil {
+				return fmt.Errorf("failed to create auditorium in transaction: %w", err)
+			}
+			createdVenue.TotalSeats += auditorium.Capacity
+			createdVenue.Auditoriums = append(createdVenue.Auditoriums, *auditorium)
+		}
+		return nil
 	})
+
 	if err != nil {
 		return nil, err
 	}
-	return fromDatabaseVenue(&dbVenue), nil
+	return createdVenue, nil
 }
 
 func (r *venueRepo) GetByID(ctx context.Context, id int32) (*venue.Venue, error) {
-	dbVenue, err := r.store.GetVenueById(ctx, id)
+	row, err := r.store.GetVenueById(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, venue.ErrNotFound
+		}
 		return nil, err
 	}
-	return fromDatabaseVenue(&dbVenue), nil
+	return fromDatabaseGetVenueByIdRow(&row), nil
 }
 
 func (r *venueRepo) List(ctx context.Context) ([]venue.Venue, error) {
@@ -44,6 +71,97 @@ func (r *venueRepo) List(ctx context.Context) ([]venue.Venue, error) {
 		return nil, err
 	}
 
+	res := make([]venue.Venue, 0, len(venues))
+	for _, v := range venues {
+		res = append(res, *fromDatabaseGetVenuesRow(&v))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) ListAdmin(ctx context.Context) ([]venue.Venue, error) {
+	venues, err := r.store.GetVenuesAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]venue.Venue, 0, len(venues))
+	for _, v := range venues {
+		res = append(res, *fromDatabaseGetVenuesAdminRow(&v))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) Update(ctx context.Context, id int32, req venue.UpdateVenueRequest) (*venue.Venue, error) {
+	dbVenue, err := r.store.UpdateVenue(ctx, dbgen.UpdateVenueParams{
+		ID:        id,
+		Name:      req.Name,
+		Address:   req.Address,
+		City:      req.City,
+		Timezone:  req.Timezone,
+		Amenities: req.Amenities,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, venue.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseVenue(&dbVenue), nil
+}
+
+func (r *venueRepo) Delete(ctx context.Context, id int32, cascade bool) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		scheduled, err := q.CountFutureShowtimesByVenue(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to count scheduled showtimes: %w", err)
+		}
+		if scheduled > 0 {
+			if !cascade {
+				return venue.ErrShowtimesScheduled
+			}
+			deleted, err := q.DeleteFutureShowtimesByVenue(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to delete scheduled showtimes: %w", err)
+			}
+			if err := cancelReservations(ctx, q, deleted); err != nil {
+				return err
+			}
+		}
+
+		if err := q.DeleteAuditoriumsByVenue(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete auditoriums: %w", err)
+		}
+		rows, err := q.DeleteVenue(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return venue.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *venueRepo) GetDeleted(ctx context.Context, id int32) (*venue.Venue, error) {
+	dbVenue, err := r.store.GetDeletedVenueById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, venue.ErrNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseVenue(&dbVenue), nil
+}
+
+func (r *venueRepo) ListDeleted(ctx context.Context, limit, offset int32) ([]venue.Venue, error) {
+	venues, err := r.store.GetDeletedVenues(ctx, dbgen.GetDeletedVenuesParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	res := make([]venue.Venue, 0, len(venues))
 	for _, v := range venues {
 		res = append(res, *fromDatabaseVenue(&v))
@@ -51,6 +169,217 @@ func (r *venueRepo) List(ctx context.Context) ([]venue.Venue, error) {
 	return res, nil
 }
 
+func (r *venueRepo) Restore(ctx context.Context, id int32) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		dbVenue, err := q.GetDeletedVenueById(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return venue.ErrNotFound
+			}
+			return err
+		}
+
+		if err := q.RestoreVenue(ctx, id); err != nil {
+			return err
+		}
+		if err := q.RestoreAuditoriumsByVenue(ctx, dbgen.RestoreAuditoriumsByVenueParams{
+			VenueID:   id,
+			DeletedAt: dbVenue.DeletedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to restore auditoriums: %w", err)
+		}
+		if err := q.RestoreFutureShowtimesByVenue(ctx, dbgen.RestoreFutureShowtimesByVenueParams{
+			VenueID:   id,
+			DeletedAt: dbVenue.DeletedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to restore showtimes: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *venueRepo) CreateAuditorium(ctx context.Context, venueId int32, req venue.CreateAuditoriumRequest) (*venue.Auditorium, error) {
+	return createAuditorium(ctx, r.store.Queries, venueId, req)
+}
+
+func (r *venueRepo) GetAuditorium(ctx context.Context, id int32) (*venue.Auditorium, error) {
+	dbAuditorium, err := r.store.GetAuditoriumById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, venue.ErrAuditoriumNotFound
+		}
+		return nil, err
+	}
+	return fromDatabaseAuditorium(&dbAuditorium), nil
+}
+
+func (r *venueRepo) ListAuditoriums(ctx context.Context, venueId int32) ([]venue.Auditorium, error) {
+	auditoriums, err := r.store.GetAuditoriumsByVenue(ctx, venueId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]venue.Auditorium, 0, len(auditoriums))
+	for _, a := range auditoriums {
+		res = append(res, *fromDatabaseAuditorium(&a))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) CreateClosure(ctx context.Context, venueId int32, kind, reason string, startsAt, endsAt time.Time, cascade bool) (*venue.Closure, error) {
+	var closure *venue.Closure
+	err := r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		affected, err := q.CountShowtimesInRangeByVenue(ctx, dbgen.CountShowtimesInRangeByVenueParams{
+			VenueID:    venueId,
+			RangeStart: startsAt,
+			RangeEnd:   endsAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to count affected showtimes: %w", err)
+		}
+		if affected > 0 {
+			if !cascade {
+				return venue.ErrShowtimesScheduled
+			}
+			deleted, err := q.DeleteShowtimesInRangeByVenue(ctx, dbgen.DeleteShowtimesInRangeByVenueParams{
+				VenueID:    venueId,
+				RangeStart: startsAt,
+				RangeEnd:   endsAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete affected showtimes: %w", err)
+			}
+			if err := cancelReservations(ctx, q, deleted); err != nil {
+				return err
+			}
+		}
+
+		dbClosure, err := q.CreateVenueClosure(ctx, dbgen.CreateVenueClosureParams{
+			VenueID:  venueId,
+			Kind:     kind,
+			Reason:   reason,
+			StartsAt: startsAt,
+			EndsAt:   endsAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create closure in transaction: %w", err)
+		}
+		closure = fromDatabaseVenueClosure(&dbClosure)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return closure, nil
+}
+
+func (r *venueRepo) ListClosures(ctx context.Context, venueId int32) ([]venue.Closure, error) {
+	closures, err := r.store.GetVenueClosures(ctx, venueId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]venue.Closure, 0, len(closures))
+	for _, c := range closures {
+		res = append(res, *fromDatabaseVenueClosure(&c))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) ListActiveClosures(ctx context.Context, venueIds []int32) (map[int32][]venue.Closure, error) {
+	closures, err := r.store.GetActiveClosuresByVenues(ctx, venueIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int32][]venue.Closure, len(venueIds))
+	for _, c := range closures {
+		res[c.VenueID] = append(res[c.VenueID], *fromDatabaseVenueClosure(&c))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) DeleteClosure(ctx context.Context, venueId, closureId int32) error {
+	rows, err := r.store.DeleteVenueClosure(ctx, dbgen.DeleteVenueClosureParams{
+		ID:      closureId,
+		VenueID: venueId,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return venue.ErrClosureNotFound
+	}
+	return nil
+}
+
+func (r *venueRepo) CountOverlappingClosures(ctx context.Context, venueId int32, start, end time.Time) (int64, error) {
+	return r.store.CountOverlappingClosures(ctx, dbgen.CountOverlappingClosuresParams{
+		VenueID:    venueId,
+		RangeStart: start,
+		RangeEnd:   end,
+	})
+}
+
+func (r *venueRepo) ListOpeningHours(ctx context.Context, venueIds []int32) (map[int32][]venue.OpeningHours, error) {
+	rows, err := r.store.GetOpeningHoursByVenues(ctx, venueIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int32][]venue.OpeningHours, len(venueIds))
+	for _, row := range rows {
+		res[row.VenueID] = append(res[row.VenueID], fromDatabaseVenueOpeningHour(&row))
+	}
+	return res, nil
+}
+
+func (r *venueRepo) SetOpeningHours(ctx context.Context, venueId int32, hours []venue.OpeningHours) error {
+	return r.store.ExecTx(ctx, func(q *dbgen.Queries) error {
+		if err := q.DeleteVenueOpeningHours(ctx, venueId); err != nil {
+			return fmt.Errorf("failed to clear opening hours: %w", err)
+		}
+		for _, h := range hours {
+			if err := q.CreateVenueOpeningHours(ctx, dbgen.CreateVenueOpeningHoursParams{
+				VenueID:   venueId,
+				DayOfWeek: int16(h.Weekday),
+				OpensAt:   toDatabaseTime(h.OpensAt),
+				ClosesAt:  toDatabaseTime(h.ClosesAt),
+			}); err != nil {
+				return fmt.Errorf("failed to create opening hours in transaction: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// createAuditorium inserts an auditorium using q, which may be bound to a transaction.
+func createAuditorium(ctx context.Context, q *dbgen.Queries, venueId int32, req venue.CreateAuditoriumRequest) (*venue.Auditorium, error) {
+	layout := req.Layout
+	if layout == nil {
+		layout = []venue.LayoutRow{}
+	}
+	layoutJSON, err := json.Marshal(layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode auditorium layout: %w", err)
+	}
+
+	dbAuditorium, err := q.CreateAuditorium(ctx, dbgen.CreateAuditoriumParams{
+		VenueID:          venueId,
+		Name:             req.Name,
+		Capacity:         req.Capacity,
+		Layout:           layoutJSON,
+		SupportedFormats: req.SupportedFormats,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromDatabaseAuditorium(&dbAuditorium), nil
+}
+
+// Conversion helpers
+
 func fromDatabaseVenue(dbVenue *dbgen.Venue) *venue.Venue {
 	var updatedAt *time.Time
 	if dbVenue.UpdatedAt.Valid {
@@ -58,12 +387,116 @@ func fromDatabaseVenue(dbVenue *dbgen.Venue) *venue.Venue {
 	}
 
 	return &venue.Venue{
-		ID:         dbVenue.ID,
-		Name:       dbVenue.Name,
-		Address:    dbVenue.Address,
-		City:       dbVenue.City,
-		TotalSeats: dbVenue.TotalSeats,
-		CreatedAt:  dbVenue.CreatedAt,
+		ID:        dbVenue.ID,
+		Name:      dbVenue.Name,
+		Address:   dbVenue.Address,
+		City:      dbVenue.City,
+		Timezone:  dbVenue.Timezone,
+		Amenities: dbVenue.Amenities,
+		CreatedAt: dbVenue.CreatedAt,
+		UpdatedAt: updatedAt,
+		DeletedAt: optionalTime(dbVenue.DeletedAt.Time, dbVenue.DeletedAt.Valid),
+	}
+}
+
+func fromDatabaseGetVenueByIdRow(row *dbgen.GetVenueByIdRow) *venue.Venue {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+
+	return &venue.Venue{
+		ID:         row.ID,
+		Name:       row.Name,
+		Address:    row.Address,
+		City:       row.City,
+		Timezone:   row.Timezone,
+		Amenities:  row.Amenities,
+		TotalSeats: row.TotalSeats,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+func fromDatabaseGetVenuesRow(row *dbgen.GetVenuesRow) *venue.Venue {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+
+	return &venue.Venue{
+		ID:         row.ID,
+		Name:       row.Name,
+		Address:    row.Address,
+		City:       row.City,
+		Timezone:   row.Timezone,
+		Amenities:  row.Amenities,
+		TotalSeats: row.TotalSeats,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+func fromDatabaseGetVenuesAdminRow(row *dbgen.GetVenuesAdminRow) *venue.Venue {
+	var updatedAt *time.Time
+	if row.UpdatedAt.Valid {
+		updatedAt = &row.UpdatedAt.Time
+	}
+
+	return &venue.Venue{
+		ID:         row.ID,
+		Name:       row.Name,
+		Address:    row.Address,
+		City:       row.City,
+		Timezone:   row.Timezone,
+		Amenities:  row.Amenities,
+		TotalSeats: row.TotalSeats,
+		CreatedAt:  row.CreatedAt,
 		UpdatedAt:  updatedAt,
 	}
 }
+
+func fromDatabaseVenueClosure(dbClosure *dbgen.VenueClosure) *venue.Closure {
+	return &venue.Closure{
+		ID:        dbClosure.ID,
+		VenueID:   dbClosure.VenueID,
+		Kind:      dbClosure.Kind,
+		Reason:    dbClosure.Reason,
+		StartsAt:  dbClosure.StartsAt,
+		EndsAt:    dbClosure.EndsAt,
+		CreatedAt: dbClosure.CreatedAt,
+	}
+}
+
+func fromDatabaseVenueOpeningHour(row *dbgen.VenueOpeningHour) venue.OpeningHours {
+	return venue.OpeningHours{
+		Weekday:  time.Weekday(row.DayOfWeek),
+		OpensAt:  time.Duration(row.OpensAt.Microseconds) * time.Microsecond,
+		ClosesAt: time.Duration(row.ClosesAt.Microseconds) * time.Microsecond,
+	}
+}
+
+func toDatabaseTime(sinceMidnight time.Duration) pgtype.Time {
+	return pgtype.Time{Microseconds: sinceMidnight.Microseconds(), Valid: true}
+}
+
+func fromDatabaseAuditorium(dbAuditorium *dbgen.Auditorium) *venue.Auditorium {
+	var updatedAt *time.Time
+	if dbAuditorium.UpdatedAt.Valid {
+		updatedAt = &dbAuditorium.UpdatedAt.Time
+	}
+	// Layouts are always written by createAuditorium, so a decode failure leaves an empty layout.
+	layout := []venue.LayoutRow{}
+	_ = json.Unmarshal(dbAuditorium.Layout, &layout)
+
+	return &venue.Auditorium{
+		ID:               dbAuditorium.ID,
+		VenueID:          dbAuditorium.VenueID,
+		Name:             dbAuditorium.Name,
+		Capacity:         dbAuditorium.Capacity,
+		Layout:           layout,
+		SupportedFormats: dbAuditorium.SupportedFormats,
+		CreatedAt:        dbAuditorium.CreatedAt,
+		UpdatedAt:        updatedAt,
+	}
+}
@@ -0,0 +1,77 @@
+package recommendation
+
+import (
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+// Sources a recommendation can come from.
+const (
+	// SourcePersonalized marks movies scored for the customer by the nightly job.
+	SourcePersonalized = "personalized"
+	// SourcePopular marks the cold-start fallback: movies ranked by tickets sold.
+	SourcePopular = "popular"
+)
+
+// Kinds of movie metadata recommendations are scored on.
+const (
+	FeatureGenre    = "genre"
+	FeatureDirector = "director"
+)
+
+// MaxLimit is the most recommendations returned at once.
+const MaxLimit = 50
+
+// Recommendation is a currently-showing movie ranked for a customer.
+type Recommendation struct {
+	Movie  movie.Movie
+	Score  float64
+	Source string
+}
+
+// ToResponse converts a Recommendation to a RecommendationResponse.
+func (r *Recommendation) ToResponse() RecommendationResponse {
+	return RecommendationResponse{
+		Movie:  r.Movie.ToResponse(),
+		Score:  r.Score,
+		Source: r.Source,
+	}
+}
+
+// RecommendationResponse represents the API response for a recommendation.
+type RecommendationResponse struct {
+	Movie  movie.MovieResponse `json:"movie"`
+	Score  float64             `json:"score"`
+	Source string              `json:"source"`
+}
+
+// Interaction is a customer's history with a movie: confirmed seats booked and, if they
+// reviewed it, their rating.
+type Interaction struct {
+	UserID  uuid.UUID
+	MovieID int64
+	Seats   int32
+	Rating  *int16
+}
+
+// Candidate is a currently-showing movie that can be recommended.
+type Candidate struct {
+	MovieID       int64
+	RatingAverage *float64
+	// TicketsSold counts confirmed seats over the last 30 days.
+	TicketsSold int64
+}
+
+// Feature is a piece of movie metadata, such as one of its genres or directors.
+type Feature struct {
+	MovieID int64
+	Kind    string
+	ID      int64
+}
+
+// Score is a customer's computed affinity for a movie.
+type Score struct {
+	UserID  uuid.UUID
+	MovieID int64
+	Score   float64
+}
@@ -0,0 +1,28 @@
+package recommendation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the data access contract for the recommendation domain.
+type Repository interface {
+	// ListInteractions returns every customer's booking and review history, one entry per
+	// customer and movie.
+	ListInteractions(ctx context.Context) ([]Interaction, error)
+	// ListCandidates returns the movies now showing.
+	ListCandidates(ctx context.Context) ([]Candidate, error)
+	ListFeatures(ctx context.Context, movieIds []int64) ([]Feature, error)
+	// ReplaceScores atomically replaces all stored scores.
+	ReplaceScores(ctx context.Context, scores []Score) error
+	// LastComputedAt returns when scores were last stored, or nil if they never were.
+	LastComputedAt(ctx context.Context) (*time.Time, error)
+
+	// ListForUser returns the customer's stored scores for movies still showing, best first.
+	ListForUser(ctx context.Context, userId uuid.UUID, limit int32) ([]Score, error)
+	// ListPopular returns the movies now showing by tickets sold, leaving out those the
+	// customer already booked or reviewed.
+	ListPopular(ctx context.Context, userId uuid.UUID, limit int32) ([]Candidate, error)
+}
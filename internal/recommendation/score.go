@@ -0,0 +1,146 @@
+package recommendation
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Weights of each signal in a score. Content affinity dominates; popularity and rating
+// only break ties between movies the customer is equally likely to enjoy.
+const (
+	genreWeight      = 1.0
+	directorWeight   = 1.5
+	popularityWeight = 0.3
+	ratingWeight     = 0.2
+)
+
+// scoresPerUser caps how many scores are stored for each customer.
+const scoresPerUser = MaxLimit
+
+type featureKey struct {
+	kind string
+	id   int64
+}
+
+// ScoreAll ranks the candidates for every customer with history. A customer's profile is
+// the genres and directors of the movies they booked, weighted up or down by how they
+// reviewed them; candidates are scored by their overlap with the profile, plus a small
+// boost for popularity and rating. Movies the customer already booked or reviewed, and
+// movies with no overlap, are left out.
+func ScoreAll(interactions []Interaction, candidates []Candidate, features []Feature) []Score {
+	featuresByMovie := make(map[int64][]featureKey)
+	for _, f := range features {
+		featuresByMovie[f.MovieID] = append(featuresByMovie[f.MovieID], featureKey{f.Kind, f.ID})
+	}
+
+	var maxTickets int64
+	for _, c := range candidates {
+		maxTickets = max(maxTickets, c.TicketsSold)
+	}
+	base := make(map[int64]float64, len(candidates))
+	for _, c := range candidates {
+		base[c.MovieID] = baseScore(c, maxTickets)
+	}
+
+	byUser := make(map[uuid.UUID][]Interaction)
+	var users []uuid.UUID
+	for _, i := range interactions {
+		if _, ok := byUser[i.UserID]; !ok {
+			users = append(users, i.UserID)
+		}
+		byUser[i.UserID] = append(byUser[i.UserID], i)
+	}
+	sort.Slice(users, func(a, b int) bool { return users[a].String() < users[b].String() })
+
+	var scores []Score
+	for _, userId := range users {
+		scores = append(scores, scoreUser(userId, byUser[userId], candidates, featuresByMovie, base)...)
+	}
+	return scores
+}
+
+// baseScore is the popularity and rating boost every candidate gets, given the most tickets
+// sold by any candidate. It is at most popularityWeight+ratingWeight.
+func baseScore(c Candidate, maxTickets int64) float64 {
+	var b float64
+	if maxTickets > 0 {
+		b += popularityWeight * float64(c.TicketsSold) / float64(maxTickets)
+	}
+	if c.RatingAverage != nil {
+		b += ratingWeight * *c.RatingAverage / 5
+	}
+	return b
+}
+
+func scoreUser(userId uuid.UUID, history []Interaction, candidates []Candidate, featuresByMovie map[int64][]featureKey, base map[int64]float64) []Score {
+	profile := make(map[featureKey]float64)
+	seen := make(map[int64]bool, len(history))
+	var total float64
+	for _, i := range history {
+		seen[i.MovieID] = true
+		w := interactionWeight(i)
+		if w == 0 {
+			continue
+		}
+		total += abs(w)
+		for _, f := range featuresByMovie[i.MovieID] {
+			profile[f] += w
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var scores []Score
+	for _, c := range candidates {
+		if seen[c.MovieID] {
+			continue
+		}
+		var content float64
+		for _, f := range featuresByMovie[c.MovieID] {
+			content += featureWeight(f.kind) * profile[f] / total
+		}
+		if content <= 0 {
+			continue
+		}
+		scores = append(scores, Score{UserID: userId, MovieID: c.MovieID, Score: content + base[c.MovieID]})
+	}
+
+	sort.Slice(scores, func(a, b int) bool {
+		if scores[a].Score != scores[b].Score {
+			return scores[a].Score > scores[b].Score
+		}
+		return scores[a].MovieID < scores[b].MovieID
+	})
+	if len(scores) > scoresPerUser {
+		scores = scores[:scoresPerUser]
+	}
+	return scores
+}
+
+// interactionWeight counts a booking as 1, moved by up to 1 either way by a review.
+func interactionWeight(i Interaction) float64 {
+	var w float64
+	if i.Seats > 0 {
+		w = 1
+	}
+	if i.Rating != nil {
+		w += (float64(*i.Rating) - 3) / 2
+	}
+	return w
+}
+
+func featureWeight(kind string) float64 {
+	if kind == FeatureDirector {
+		return directorWeight
+	}
+	return genreWeight
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
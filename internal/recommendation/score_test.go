@@ -0,0 +1,77 @@
+package recommendation
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestScoreAll(t *testing.T) {
+	fan := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	critic := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	const (
+		horror = iota + 1
+		comedy
+		carpenter
+	)
+	features := []Feature{
+		{MovieID: 1, Kind: FeatureGenre, ID: horror},
+		{MovieID: 1, Kind: FeatureDirector, ID: carpenter},
+		{MovieID: 2, Kind: FeatureGenre, ID: comedy},
+		{MovieID: 10, Kind: FeatureGenre, ID: horror},
+		{MovieID: 11, Kind: FeatureGenre, ID: horror},
+		{MovieID: 11, Kind: FeatureDirector, ID: carpenter},
+		{MovieID: 12, Kind: FeatureGenre, ID: comedy},
+	}
+	candidates := []Candidate{
+		{MovieID: 1, TicketsSold: 100},
+		{MovieID: 10, TicketsSold: 100, RatingAverage: ptr(5.0)},
+		{MovieID: 11, TicketsSold: 50},
+		{MovieID: 12, TicketsSold: 0},
+	}
+	interactions := []Interaction{
+		{UserID: fan, MovieID: 1, Seats: 2},
+		{UserID: critic, MovieID: 1, Seats: 1, Rating: ptr[int16](1)},
+		{UserID: critic, MovieID: 2, Seats: 1, Rating: ptr[int16](5)},
+	}
+
+	scores := ScoreAll(interactions, candidates, features)
+
+	// The fan booked a Carpenter horror: the other Carpenter horror beats a plain horror,
+	// despite the latter's popularity and rating. The booked movie and the comedy are left out.
+	require.Equal(t, []int64{11, 10}, moviesFor(scores, fan))
+	byMovie := scoresFor(scores, fan)
+	require.InDelta(t, genreWeight+directorWeight+popularityWeight*0.5, byMovie[11], 1e-9)
+	require.InDelta(t, genreWeight+popularityWeight+ratingWeight, byMovie[10], 1e-9)
+
+	// The critic's poor review cancels out their horror booking, so only the comedy remains.
+	require.Equal(t, []int64{12}, moviesFor(scores, critic))
+	require.InDelta(t, genreWeight, scoresFor(scores, critic)[12], 1e-9)
+}
+
+func TestScoreAllWithoutHistory(t *testing.T) {
+	require.Empty(t, ScoreAll(nil, []Candidate{{MovieID: 1, TicketsSold: 10}}, nil))
+}
+
+func moviesFor(scores []Score, userId uuid.UUID) []int64 {
+	var ids []int64
+	for _, s := range scores {
+		if s.UserID == userId {
+			ids = append(ids, s.MovieID)
+		}
+	}
+	return ids
+}
+
+func scoresFor(scores []Score, userId uuid.UUID) map[int64]float64 {
+	res := make(map[int64]float64)
+	for _, s := range scores {
+		if s.UserID == userId {
+			res[s.MovieID] = s.Score
+		}
+	}
+	return res
+}
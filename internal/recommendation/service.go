@@ -0,0 +1,166 @@
+package recommendation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Service defines the business operations for the recommendation domain.
+type Service interface {
+	// Recommend returns up to limit movies now showing, ranked for the customer. Customers
+	// without enough history are topped up with the most popular movies they have not
+	// booked or reviewed, scored by the popularity and rating boost alone.
+	Recommend(ctx context.Context, userId uuid.UUID, limit int32) ([]Recommendation, error)
+	// Refresh recomputes every customer's scores, returning how many were stored.
+	Refresh(ctx context.Context) (int, error)
+	// RunNightly refreshes scores every day at hour (UTC) until ctx is cancelled, and once
+	// at start if they are more than a day old. Refreshing is idempotent, so running it on
+	// several instances only wastes work.
+	RunNightly(ctx context.Context, hour int)
+}
+
+type service struct {
+	repo   Repository
+	movies movie.Service
+}
+
+// NewService creates a new recommendation service.
+func NewService(repo Repository, movies movie.Service) Service {
+	return &service{repo: repo, movies: movies}
+}
+
+func (s *service) Recommend(ctx context.Context, userId uuid.UUID, limit int32) ([]Recommendation, error) {
+	personal, err := s.repo.ListForUser(ctx, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, limit)
+	sources := make(map[int64]string, limit)
+	scores := make(map[int64]float64, limit)
+	for _, sc := range personal {
+		ids = append(ids, sc.MovieID)
+		sources[sc.MovieID] = SourcePersonalized
+		scores[sc.MovieID] = sc.Score
+	}
+
+	if missing := limit - int32(len(ids)); missing > 0 {
+		// Ask for enough to skip the personalized movies already picked.
+		popular, err := s.repo.ListPopular(ctx, userId, missing+int32(len(ids)))
+		if err != nil {
+			return nil, err
+		}
+		// Popular movies are scored by the same boost personalized scores include, so the
+		// two can be ranked together. The first one sold the most tickets.
+		var maxTickets int64
+		if len(popular) > 0 {
+			maxTickets = popular[0].TicketsSold
+		}
+		for _, c := range popular {
+			if missing == 0 {
+				break
+			}
+			if _, ok := sources[c.MovieID]; ok {
+				continue
+			}
+			ids = append(ids, c.MovieID)
+			sources[c.MovieID] = SourcePopular
+			scores[c.MovieID] = baseScore(c, maxTickets)
+			missing--
+		}
+	}
+
+	movies, err := s.movies.GetMovies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Recommendation, 0, len(movies))
+	for _, m := range movies {
+		res = append(res, Recommendation{Movie: m, Score: scores[m.ID], Source: sources[m.ID]})
+	}
+	sort.SliceStable(res, func(a, b int) bool { return res[a].Score > res[b].Score })
+	return res, nil
+}
+
+func (s *service) Refresh(ctx context.Context) (int, error) {
+	interactions, err := s.repo.ListInteractions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	candidates, err := s.repo.ListCandidates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[int64]bool)
+	movieIds := make([]int64, 0, len(candidates))
+	for _, c := range candidates {
+		seen[c.MovieID] = true
+		movieIds = append(movieIds, c.MovieID)
+	}
+	for _, i := range interactions {
+		if !seen[i.MovieID] {
+			seen[i.MovieID] = true
+			movieIds = append(movieIds, i.MovieID)
+		}
+	}
+	features, err := s.repo.ListFeatures(ctx, movieIds)
+	if err != nil {
+		return 0, err
+	}
+
+	scores := ScoreAll(interactions, candidates, features)
+	if err := s.repo.ReplaceScores(ctx, scores); err != nil {
+		return 0, err
+	}
+	return len(scores), nil
+}
+
+func (s *service) RunNightly(ctx context.Context, hour int) {
+	last, err := s.repo.LastComputedAt(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to check recommendation freshness", zap.Error(err))
+	}
+	if err == nil && (last == nil || time.Since(*last) > 24*time.Hour) {
+		s.refreshAndLog(ctx)
+	}
+
+	for {
+		timer := time.NewTimer(time.Until(nextRun(time.Now().UTC(), hour)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.refreshAndLog(ctx)
+		}
+	}
+}
+
+func (s *service) refreshAndLog(ctx context.Context) {
+	start := time.Now()
+	n, err := s.Refresh(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "failed to refresh recommendations", zap.Error(err))
+		return
+	}
+	logger.InfoCtx(ctx, "refreshed recommendations",
+		zap.Int("scores", n),
+		zap.Duration("took", time.Since(start)),
+	)
+}
+
+// nextRun returns the next time after now at the given hour (UTC).
+func nextRun(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,117 @@
+package recommendation
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	at := func(day, hour, min int) time.Time { return time.Date(2026, time.March, day, hour, min, 0, 0, time.UTC) }
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"later today", at(14, 1, 30), at(14, 3, 0)},
+		{"exactly on the hour", at(14, 3, 0), at(15, 3, 0)},
+		{"already ran today", at(14, 17, 0), at(15, 3, 0)},
+		{"end of month", at(31, 23, 59), time.Date(2026, time.April, 1, 3, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, nextRun(tt.now, 3))
+		})
+	}
+}
+
+type fakeRepo struct {
+	Repository
+	personal []Score
+	popular  []Candidate
+	// seen holds the movies each customer booked or reviewed.
+	seen map[uuid.UUID][]int64
+}
+
+func (r *fakeRepo) ListForUser(context.Context, uuid.UUID, int32) ([]Score, error) {
+	return r.personal, nil
+}
+
+func (r *fakeRepo) ListPopular(_ context.Context, userId uuid.UUID, limit int32) ([]Candidate, error) {
+	res := make([]Candidate, 0, limit)
+	for _, c := range r.popular {
+		if len(res) < int(limit) && !slices.Contains(r.seen[userId], c.MovieID) {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+type fakeMovies struct {
+	movie.Service
+}
+
+func (fakeMovies) GetMovies(_ context.Context, ids []int64) ([]movie.Movie, error) {
+	movies := make([]movie.Movie, 0, len(ids))
+	for _, id := range ids {
+		movies = append(movies, movie.Movie{ID: id})
+	}
+	return movies, nil
+}
+
+func TestRecommendScoresPopularOnPersonalizedScale(t *testing.T) {
+	userId := uuid.New()
+	repo := &fakeRepo{
+		personal: []Score{{UserID: userId, MovieID: 1, Score: genreWeight + 0.1}},
+		popular: []Candidate{
+			{MovieID: 2, TicketsSold: 5000, RatingAverage: ptr(5.0)},
+			{MovieID: 1, TicketsSold: 4000},
+			{MovieID: 3, TicketsSold: 2500},
+		},
+	}
+
+	recs, err := NewService(repo, fakeMovies{}).Recommend(context.Background(), userId, 3)
+	require.NoError(t, err)
+
+	require.Len(t, recs, 3)
+	require.Equal(t, int64(1), recs[0].Movie.ID)
+	require.Equal(t, SourcePersonalized, recs[0].Source)
+	require.Equal(t, int64(2), recs[1].Movie.ID)
+	require.Equal(t, SourcePopular, recs[1].Source)
+	require.InDelta(t, popularityWeight+ratingWeight, recs[1].Score, 1e-9)
+	require.Equal(t, int64(3), recs[2].Movie.ID)
+	require.InDelta(t, popularityWeight*0.5, recs[2].Score, 1e-9)
+}
+
+func TestRecommendSkipsPopularMoviesAlreadySeen(t *testing.T) {
+	userId := uuid.New()
+	repo := &fakeRepo{
+		popular: []Candidate{
+			{MovieID: 1, TicketsSold: 5000},
+			{MovieID: 2, TicketsSold: 4000},
+			{MovieID: 3, TicketsSold: 2500},
+			{MovieID: 4, TicketsSold: 1000},
+		},
+		seen: map[uuid.UUID][]int64{userId: {1, 3}},
+	}
+
+	recs, err := NewService(repo, fakeMovies{}).Recommend(context.Background(), userId, 3)
+	require.NoError(t, err)
+
+	ids := make([]int64, 0, len(recs))
+	for _, r := range recs {
+		require.Equal(t, SourcePopular, r.Source)
+		ids = append(ids, r.Movie.ID)
+	}
+	require.Equal(t, []int64{2, 4}, ids)
+
+	recs, err = NewService(repo, fakeMovies{}).Recommend(context.Background(), uuid.New(), 3)
+	require.NoError(t, err)
+	require.Len(t, recs, 3)
+	require.Equal(t, int64(1), recs[0].Movie.ID)
+}
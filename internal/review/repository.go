@@ -0,0 +1,26 @@
+package review
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the data access contract for the review domain. Every write also
+// refreshes the movie's aggregated rating.
+type Repository interface {
+	Create(ctx context.Context, movieId int64, userId uuid.UUID, req CreateReviewRequest) (*Review, error)
+	GetByID(ctx context.Context, id int64) (*Review, error)
+	// GetByMovieAndUser returns the customer's review of the movie, or ErrNotFound.
+	GetByMovieAndUser(ctx context.Context, movieId int64, userId uuid.UUID) (*Review, error)
+	// ListByMovie returns visible reviews of the movie, newest first.
+	ListByMovie(ctx context.Context, movieId int64, limit, offset int32) ([]Review, error)
+	ListAdmin(ctx context.Context, filter AdminFilter) ([]Review, error)
+	Update(ctx context.Context, id int64, req UpdateReviewRequest) (*Review, error)
+	Delete(ctx context.Context, id int64) error
+	Moderate(ctx context.Context, id int64, action string, reason *string) error
+
+	// HasAttended reports whether the customer holds a confirmed reservation for a showtime
+	// of the movie that has already ended.
+	HasAttended(ctx context.Context, userId uuid.UUID, movieId int64) (bool, error)
+}
@@ -0,0 +1,130 @@
+package review
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a review is not found.
+	ErrNotFound = errors.New("review not found")
+	// ErrNotAttended is returned when a customer reviews a movie they have not seen at one of our venues.
+	ErrNotAttended = errors.New("only customers who attended a screening of this movie can review it")
+	// ErrAlreadyReviewed is returned when a customer reviews the same movie twice.
+	ErrAlreadyReviewed = errors.New("you have already reviewed this movie")
+	// ErrNotAuthor is returned when a customer changes someone else's review.
+	ErrNotAuthor = errors.New("only the author can change this review")
+)
+
+// Moderation actions.
+const (
+	ActionHide   = "hide"
+	ActionUnhide = "unhide"
+	ActionFlag   = "flag"
+	ActionUnflag = "unflag"
+)
+
+// Review is a customer's rating and review of a movie. Only customers with a confirmed
+// reservation for a past showtime of the movie may write one.
+type Review struct {
+	ID         int64
+	MovieID    int64
+	UserID     uuid.UUID
+	AuthorName string
+	Rating     int16
+	Title      *string
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+
+	// Hidden reviews are excluded from public listings and the movie's rating.
+	HiddenAt     *time.Time
+	HiddenReason *string
+	// Flagged reviews stay visible but are queued for a moderator's attention.
+	FlaggedAt  *time.Time
+	FlagReason *string
+}
+
+// ToResponse converts a Review to a ReviewResponse.
+func (r *Review) ToResponse() ReviewResponse {
+	var updatedAt time.Time
+	if r.UpdatedAt != nil {
+		updatedAt = *r.UpdatedAt
+	}
+
+	return ReviewResponse{
+		ID:         r.ID,
+		MovieID:    r.MovieID,
+		AuthorName: r.AuthorName,
+		Rating:     r.Rating,
+		Title:      r.Title,
+		Body:       r.Body,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+// ToAdminResponse converts a Review to a ReviewAdminResponse, including moderation details.
+func (r *Review) ToAdminResponse() ReviewAdminResponse {
+	return ReviewAdminResponse{
+		ReviewResponse: r.ToResponse(),
+		UserID:         r.UserID,
+		HiddenAt:       r.HiddenAt,
+		HiddenReason:   r.HiddenReason,
+		FlaggedAt:      r.FlaggedAt,
+		FlagReason:     r.FlagReason,
+	}
+}
+
+// ReviewResponse represents the API response for a review.
+type ReviewResponse struct {
+	ID         int64     `json:"id"`
+	MovieID    int64     `json:"movie_id"`
+	AuthorName string    `json:"author_name"`
+	Rating     int16     `json:"rating"`
+	Title      *string   `json:"title,omitempty"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// ReviewAdminResponse represents the API response for a review in the moderation queue.
+type ReviewAdminResponse struct {
+	ReviewResponse
+	UserID       uuid.UUID  `json:"user_id"`
+	HiddenAt     *time.Time `json:"hidden_at,omitempty"`
+	HiddenReason *string    `json:"hidden_reason,omitempty"`
+	FlaggedAt    *time.Time `json:"flagged_at,omitempty"`
+	FlagReason   *string    `json:"flag_reason,omitempty"`
+}
+
+// CreateReviewRequest represents the request to review a movie.
+type CreateReviewRequest struct {
+	Rating int16   `json:"rating" validate:"required,min=1,max=5"`
+	Title  *string `json:"title" validate:"omitempty,min=1,max=120"`
+	Body   string  `json:"body" validate:"max=5000"`
+}
+
+// UpdateReviewRequest represents the request to edit a review.
+type UpdateReviewRequest struct {
+	Rating *int16  `json:"rating" validate:"omitempty,min=1,max=5"`
+	Title  *string `json:"title" validate:"omitempty,min=1,max=120"`
+	Body   *string `json:"body" validate:"omitempty,max=5000"`
+}
+
+// ModerateReviewRequest represents a moderator's action on a review.
+type ModerateReviewRequest struct {
+	Action string  `json:"action" validate:"required,oneof=hide unhide flag unflag"`
+	Reason *string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// AdminFilter narrows the moderation queue. Nil fields are not applied.
+type AdminFilter struct {
+	MovieID *int64
+	Hidden  *bool
+	Flagged *bool
+	Limit   int32
+	Offset  int32
+}
@@ -0,0 +1,98 @@
+package review
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+// Service defines the business operations for the review domain.
+type Service interface {
+	// CreateReview reviews a movie on behalf of a customer who attended a screening of it.
+	CreateReview(ctx context.Context, userId uuid.UUID, movieId int64, req CreateReviewRequest) (*Review, error)
+	ListMovieReviews(ctx context.Context, movieId int64, limit, offset int32) ([]Review, error)
+	UpdateReview(ctx context.Context, userId uuid.UUID, id int64, req UpdateReviewRequest) (*Review, error)
+	DeleteReview(ctx context.Context, userId uuid.UUID, id int64) error
+
+	ListReviewsAdmin(ctx context.Context, filter AdminFilter) ([]Review, error)
+	ModerateReview(ctx context.Context, id int64, req ModerateReviewRequest) (*Review, error)
+	// DeleteReviewAdmin removes any review, e.g. one that breaks the content policy.
+	DeleteReviewAdmin(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo   Repository
+	movies movie.Service
+}
+
+// NewService creates a new review service.
+func NewService(repo Repository, movies movie.Service) Service {
+	return &service{repo: repo, movies: movies}
+}
+
+func (s *service) CreateReview(ctx context.Context, userId uuid.UUID, movieId int64, req CreateReviewRequest) (*Review, error) {
+	if _, err := s.movies.GetMovie(ctx, movieId); err != nil {
+		return nil, err
+	}
+
+	attended, err := s.repo.HasAttended(ctx, userId, movieId)
+	if err != nil {
+		return nil, err
+	}
+	if !attended {
+		return nil, ErrNotAttended
+	}
+
+	if _, err := s.repo.GetByMovieAndUser(ctx, movieId, userId); err == nil {
+		return nil, ErrAlreadyReviewed
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return s.repo.Create(ctx, movieId, userId, req)
+}
+
+func (s *service) ListMovieReviews(ctx context.Context, movieId int64, limit, offset int32) ([]Review, error) {
+	return s.repo.ListByMovie(ctx, movieId, limit, offset)
+}
+
+func (s *service) UpdateReview(ctx context.Context, userId uuid.UUID, id int64, req UpdateReviewRequest) (*Review, error) {
+	if err := s.ensureAuthor(ctx, userId, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, req)
+}
+
+func (s *service) DeleteReview(ctx context.Context, userId uuid.UUID, id int64) error {
+	if err := s.ensureAuthor(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) ListReviewsAdmin(ctx context.Context, filter AdminFilter) ([]Review, error) {
+	return s.repo.ListAdmin(ctx, filter)
+}
+
+func (s *service) ModerateReview(ctx context.Context, id int64, req ModerateReviewRequest) (*Review, error) {
+	if err := s.repo.Moderate(ctx, id, req.Action, req.Reason); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) DeleteReviewAdmin(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) ensureAuthor(ctx context.Context, userId uuid.UUID, id int64) error {
+	r, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if r.UserID != userId {
+		return ErrNotAuthor
+	}
+	return nil
+}
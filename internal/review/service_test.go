@@ -0,0 +1,216 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo keeps reviews in memory and, like the database, refreshes the movie's
+// aggregated rating from its visible reviews on every write.
+type memoryRepo struct {
+	Repository
+	reviews  map[int64]*Review
+	attended map[uuid.UUID]bool
+	ratings  map[int64]*movie.Movie
+	nextID   int64
+	created  int
+}
+
+func (r *memoryRepo) Create(_ context.Context, movieId int64, userId uuid.UUID, req CreateReviewRequest) (*Review, error) {
+	for _, existing := range r.reviews {
+		if existing.MovieID == movieId && existing.UserID == userId {
+			return nil, ErrAlreadyReviewed
+		}
+	}
+	r.nextID++
+	rev := &Review{ID: r.nextID, MovieID: movieId, UserID: userId, Rating: req.Rating, Body: req.Body}
+	r.reviews[rev.ID] = rev
+	r.created++
+	r.syncRating(movieId)
+	return rev, nil
+}
+
+func (r *memoryRepo) GetByID(_ context.Context, id int64) (*Review, error) {
+	rev, ok := r.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return rev, nil
+}
+
+func (r *memoryRepo) GetByMovieAndUser(_ context.Context, movieId int64, userId uuid.UUID) (*Review, error) {
+	for _, rev := range r.reviews {
+		if rev.MovieID == movieId && rev.UserID == userId {
+			return rev, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRepo) Update(_ context.Context, id int64, req UpdateReviewRequest) (*Review, error) {
+	rev := r.reviews[id]
+	if req.Rating != nil {
+		rev.Rating = *req.Rating
+	}
+	r.syncRating(rev.MovieID)
+	return rev, nil
+}
+
+func (r *memoryRepo) Delete(_ context.Context, id int64) error {
+	rev, ok := r.reviews[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(r.reviews, id)
+	r.syncRating(rev.MovieID)
+	return nil
+}
+
+func (r *memoryRepo) HasAttended(_ context.Context, userId uuid.UUID, _ int64) (bool, error) {
+	return r.attended[userId], nil
+}
+
+func (r *memoryRepo) syncRating(movieId int64) {
+	m := r.ratings[movieId]
+	var sum float64
+	m.RatingCount, m.RatingAverage = 0, nil
+	for _, rev := range r.reviews {
+		if rev.MovieID == movieId && rev.HiddenAt == nil {
+			sum += float64(rev.Rating)
+			m.RatingCount++
+		}
+	}
+	if m.RatingCount > 0 {
+		avg := sum / float64(m.RatingCount)
+		m.RatingAverage = &avg
+	}
+}
+
+type fakeMovies struct {
+	movie.Service
+	movies map[int64]*movie.Movie
+}
+
+func (f *fakeMovies) GetMovie(_ context.Context, id int64) (*movie.Movie, error) {
+	m, ok := f.movies[id]
+	if !ok {
+		return nil, movie.ErrNotFound
+	}
+	return m, nil
+}
+
+func newTestService(attendees ...uuid.UUID) (Service, *memoryRepo, *fakeMovies) {
+	movies := map[int64]*movie.Movie{1: {ID: 1, Title: "Dune: Part Two"}}
+	repo := &memoryRepo{
+		reviews:  map[int64]*Review{},
+		attended: map[uuid.UUID]bool{},
+		ratings:  movies,
+	}
+	for _, id := range attendees {
+		repo.attended[id] = true
+	}
+	fake := &fakeMovies{movies: movies}
+	return NewService(repo, fake), repo, fake
+}
+
+func TestCreateReview(t *testing.T) {
+	ctx := context.Background()
+	attendee, stranger := uuid.New(), uuid.New()
+	req := CreateReviewRequest{Rating: 4, Body: "Worth the IMAX ticket."}
+
+	tests := []struct {
+		name    string
+		userId  uuid.UUID
+		movieId int64
+		before  func(t *testing.T, s Service)
+		wantErr error
+	}{
+		{name: "attendee", userId: attendee, movieId: 1},
+		{name: "did not attend", userId: stranger, movieId: 1, wantErr: ErrNotAttended},
+		{name: "unknown movie", userId: attendee, movieId: 2, wantErr: movie.ErrNotFound},
+		{
+			name:    "second review of the same movie",
+			userId:  attendee,
+			movieId: 1,
+			before: func(t *testing.T, s Service) {
+				_, err := s.CreateReview(ctx, attendee, 1, req)
+				require.NoError(t, err)
+			},
+			wantErr: ErrAlreadyReviewed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, _ := newTestService(attendee)
+			if tt.before != nil {
+				tt.before(t, s)
+			}
+			created := repo.created
+
+			rev, err := s.CreateReview(ctx, tt.userId, tt.movieId, req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Equal(t, created, repo.created)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.userId, rev.UserID)
+			require.Equal(t, int16(4), rev.Rating)
+			require.Equal(t, created+1, repo.created)
+		})
+	}
+}
+
+func TestReviewsAggregateIntoMovieRating(t *testing.T) {
+	ctx := context.Background()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	s, _, movies := newTestService(alice, bob, carol)
+
+	rating := func() (*float64, int32) {
+		m, err := movies.GetMovie(ctx, 1)
+		require.NoError(t, err)
+		return m.RatingAverage, m.RatingCount
+	}
+
+	avg, count := rating()
+	require.Nil(t, avg)
+	require.Zero(t, count)
+
+	var ids []int64
+	for _, r := range []struct {
+		user   uuid.UUID
+		rating int16
+	}{{alice, 5}, {bob, 4}, {carol, 2}} {
+		rev, err := s.CreateReview(ctx, r.user, 1, CreateReviewRequest{Rating: r.rating})
+		require.NoError(t, err)
+		ids = append(ids, rev.ID)
+	}
+	avg, count = rating()
+	require.InDelta(t, 11.0/3, *avg, 1e-9)
+	require.Equal(t, int32(3), count)
+
+	_, err := s.UpdateReview(ctx, carol, ids[2], UpdateReviewRequest{Rating: ptr(int16(3))})
+	require.NoError(t, err)
+	avg, count = rating()
+	require.InDelta(t, 4.0, *avg, 1e-9)
+	require.Equal(t, int32(3), count)
+
+	// Only the author may change a review, so the rating is left alone.
+	_, err = s.UpdateReview(ctx, alice, ids[2], UpdateReviewRequest{Rating: ptr(int16(1))})
+	require.ErrorIs(t, err, ErrNotAuthor)
+	avg, _ = rating()
+	require.InDelta(t, 4.0, *avg, 1e-9)
+
+	require.NoError(t, s.DeleteReview(ctx, alice, ids[0]))
+	avg, count = rating()
+	require.InDelta(t, 3.5, *avg, 1e-9)
+	require.Equal(t, int32(2), count)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
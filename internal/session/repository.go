@@ -0,0 +1,43 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateParams contains the parameters for starting a session.
+type CreateParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	Client    Client
+	ExpiresAt time.Time
+}
+
+// RotateParams contains the parameters for replacing a session's refresh token.
+type RotateParams struct {
+	ID           uuid.UUID
+	TokenHash    string
+	NewTokenHash string
+	Client       Client
+	ExpiresAt    time.Time
+}
+
+// Repository defines the data access contract for the session domain.
+type Repository interface {
+	Create(ctx context.Context, params CreateParams) (*Session, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	// Rotate replaces the session's refresh token if it is still TokenHash, returning
+	// false if another refresh got there first or the session was revoked.
+	Rotate(ctx context.Context, params RotateParams) (bool, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]Session, error)
+	// Revoke revokes a session, returning ErrNotFound if it is not active.
+	Revoke(ctx context.Context, id uuid.UUID, reason string) error
+	// RevokeForUser revokes one of the user's sessions, returning ErrNotFound if they have
+	// no such active session.
+	RevokeForUser(ctx context.Context, userID, id uuid.UUID, reason string) error
+	// RevokeAll revokes every session of the user, returning how many were active.
+	RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+}
@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Service defines the business operations for the session domain.
+type Service interface {
+	// Start records a new session whose first refresh token is refreshToken.
+	Start(ctx context.Context, id, userID uuid.UUID, refreshToken string, client Client, expiresAt time.Time) (*Session, error)
+	// Refresh swaps the session's refresh token for newRefreshToken. It returns false
+	// without swapping if refreshToken was replaced moments ago by a concurrent refresh,
+	// in which case the caller already has the new token. Presenting any other old token
+	// revokes the whole session and returns ErrTokenReused.
+	Refresh(ctx context.Context, id uuid.UUID, refreshToken, newRefreshToken string, client Client, expiresAt time.Time) (bool, error)
+	// List returns the user's active sessions, most recently used first.
+	List(ctx context.Context, userID uuid.UUID) ([]Session, error)
+	// Revoke signs the user out of one of their sessions.
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	// RevokeAll signs the user out everywhere, returning how many sessions were active.
+	RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+	// Logout ends a session; ending one that is already over is not an error.
+	Logout(ctx context.Context, id uuid.UUID) error
+	// Check returns ErrRevoked if the session can no longer be refreshed.
+	Check(ctx context.Context, id uuid.UUID) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new session service.
+func NewService(repo Repository) Service {
+	return &service{repo}
+}
+
+func (s *service) Start(ctx context.Context, id, userID uuid.UUID, refreshToken string, client Client, expiresAt time.Time) (*Session, error) {
+	return s.repo.Create(ctx, CreateParams{
+		ID:        id,
+		UserID:    userID,
+		TokenHash: HashToken(refreshToken),
+		Client:    client,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *service) Refresh(ctx context.Context, id uuid.UUID, refreshToken, newRefreshToken string, client Client, expiresAt time.Time) (bool, error) {
+	sess, err := s.activeSession(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	hash := HashToken(refreshToken)
+	// An untracked session adopts the first token it is refreshed with.
+	if hash == sess.CurrentTokenHash || sess.CurrentTokenHash == "" {
+		rotated, err := s.repo.Rotate(ctx, RotateParams{
+			ID:           id,
+			TokenHash:    sess.CurrentTokenHash,
+			NewTokenHash: HashToken(newRefreshToken),
+			Client:       client,
+			ExpiresAt:    expiresAt,
+		})
+		if err != nil || rotated {
+			return rotated, err
+		}
+		// A concurrent refresh rotated the token first; judge the token against its result.
+		if sess, err = s.activeSession(ctx, id); err != nil {
+			return false, err
+		}
+	}
+
+	if sess.PreviousTokenHash != nil && (hash == *sess.PreviousTokenHash || *sess.PreviousTokenHash == "") &&
+		sess.RotatedAt != nil && time.Since(*sess.RotatedAt) < ReuseGrace {
+		return false, nil
+	}
+
+	logger.WarnCtx(ctx, "refresh token reuse detected, revoking session",
+		zap.String("session_id", id.String()),
+		zap.String("user_id", sess.UserID.String()),
+		zap.String("ip_address", client.IPAddress),
+	)
+	if err := s.repo.Revoke(ctx, id, ReasonTokenReuse); err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	return false, ErrTokenReused
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	return s.repo.ListActive(ctx, userID)
+}
+
+func (s *service) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.RevokeForUser(ctx, userID, id, ReasonUser)
+}
+
+func (s *service) RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	n, err := s.repo.RevokeAll(ctx, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.InfoCtx(ctx, "revoked all sessions",
+		zap.String("user_id", userID.String()),
+		zap.String("reason", reason),
+		zap.Int64("sessions", n),
+	)
+	return n, nil
+}
+
+func (s *service) Logout(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, id, ReasonLogout); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *service) Check(ctx context.Context, id uuid.UUID) error {
+	_, err := s.activeSession(ctx, id)
+	return err
+}
+
+// activeSession returns the session, or ErrRevoked if it cannot be refreshed.
+func (s *service) activeSession(ctx context.Context, id uuid.UUID) (*Session, error) {
+	sess, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrRevoked
+		}
+		return nil, err
+	}
+	if !sess.Active(time.Now()) {
+		return nil, ErrRevoked
+	}
+	return sess, nil
+}
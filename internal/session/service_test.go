@@ -0,0 +1,148 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo is a Repository over a map, enough to exercise token rotation.
+type memoryRepo struct {
+	sessions map[uuid.UUID]*Session
+}
+
+func (r *memoryRepo) Create(ctx context.Context, params CreateParams) (*Session, error) {
+	s := &Session{ID: params.ID, UserID: params.UserID, CurrentTokenHash: params.TokenHash, ExpiresAt: params.ExpiresAt}
+	r.sessions[s.ID] = s
+	return s, nil
+}
+
+func (r *memoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *memoryRepo) Rotate(ctx context.Context, params RotateParams) (bool, error) {
+	s := r.sessions[params.ID]
+	if s == nil || s.RevokedAt != nil || s.CurrentTokenHash != params.TokenHash {
+		return false, nil
+	}
+	now := time.Now()
+	prev := s.CurrentTokenHash
+	s.PreviousTokenHash, s.CurrentTokenHash, s.RotatedAt = &prev, params.NewTokenHash, &now
+	return true, nil
+}
+
+func (r *memoryRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) Revoke(ctx context.Context, id uuid.UUID, reason string) error {
+	s := r.sessions[id]
+	if s == nil || s.RevokedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now()
+	s.RevokedAt, s.RevokedReason = &now, &reason
+	return nil
+}
+
+func (r *memoryRepo) RevokeForUser(ctx context.Context, userID, id uuid.UUID, reason string) error {
+	return r.Revoke(ctx, id, reason)
+}
+
+func (r *memoryRepo) RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	return 0, nil
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryRepo{sessions: map[uuid.UUID]*Session{}}
+	svc := NewService(repo)
+	id := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := svc.Start(ctx, id, uuid.New(), "token-1", Client{}, expiresAt)
+	require.NoError(t, err)
+
+	rotated, err := svc.Refresh(ctx, id, "token-1", "token-2", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	// A concurrent request still carrying token-1 is let through without rotating.
+	rotated, err = svc.Refresh(ctx, id, "token-1", "token-x", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.False(t, rotated)
+
+	rotated, err = svc.Refresh(ctx, id, "token-2", "token-3", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	// token-1 is now two rotations old: presenting it again kills the whole family.
+	_, err = svc.Refresh(ctx, id, "token-1", "token-y", Client{}, expiresAt)
+	require.ErrorIs(t, err, ErrTokenReused)
+	require.Equal(t, ReasonTokenReuse, *repo.sessions[id].RevokedReason)
+
+	_, err = svc.Refresh(ctx, id, "token-3", "token-4", Client{}, expiresAt)
+	require.ErrorIs(t, err, ErrRevoked)
+}
+
+func TestRefreshRejectsOldTokenAfterGrace(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryRepo{sessions: map[uuid.UUID]*Session{}}
+	svc := NewService(repo)
+	id := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := svc.Start(ctx, id, uuid.New(), "token-1", Client{}, expiresAt)
+	require.NoError(t, err)
+	_, err = svc.Refresh(ctx, id, "token-1", "token-2", Client{}, expiresAt)
+	require.NoError(t, err)
+
+	rotatedAt := time.Now().Add(-ReuseGrace - time.Second)
+	repo.sessions[id].RotatedAt = &rotatedAt
+
+	_, err = svc.Refresh(ctx, id, "token-1", "token-x", Client{}, expiresAt)
+	require.ErrorIs(t, err, ErrTokenReused)
+}
+
+func TestClientDevice(t *testing.T) {
+	require.Equal(t, "Chrome on macOS", Client{UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"}.Device())
+	require.Equal(t, "Edge on Windows", Client{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0"}.Device())
+	require.Equal(t, "Safari on iOS", Client{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"}.Device())
+	require.Equal(t, "Unknown device", Client{UserAgent: "curl/8.0"}.Device())
+}
+
+func TestRefreshAdoptsTokenOfUntrackedSession(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	// A session started before refresh tokens were tracked, as migration 023 leaves it.
+	repo := &memoryRepo{sessions: map[uuid.UUID]*Session{
+		id: {ID: id, UserID: uuid.New(), ExpiresAt: expiresAt},
+	}}
+	svc := NewService(repo)
+
+	rotated, err := svc.Refresh(ctx, id, "token-1", "token-2", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	// A concurrent request with another pre-migration token is let through without rotating.
+	rotated, err = svc.Refresh(ctx, id, "token-0", "token-x", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.False(t, rotated)
+
+	// From now on the session is tracked like any other.
+	rotated, err = svc.Refresh(ctx, id, "token-2", "token-3", Client{}, expiresAt)
+	require.NoError(t, err)
+	require.True(t, rotated)
+	_, err = svc.Refresh(ctx, id, "token-1", "token-y", Client{}, expiresAt)
+	require.ErrorIs(t, err, ErrTokenReused)
+}
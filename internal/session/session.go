@@ -0,0 +1,146 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound    = errors.New("session not found")
+	ErrRevoked     = errors.New("session has been revoked or has expired")
+	ErrTokenReused = errors.New("refresh token was already used, the session has been revoked")
+)
+
+// Reasons a session was revoked.
+const (
+	ReasonLogout          = "logout"
+	ReasonUser            = "revoked_by_user"
+	ReasonAdmin           = "revoked_by_admin"
+	ReasonPasswordChanged = "password_changed"
+	ReasonTokenReuse      = "token_reuse"
+)
+
+// ReuseGrace is how long the refresh token a session was just rotated from is still
+// accepted. Browsers send several requests at once when the access token expires; all but
+// the first carry the old token and should not be mistaken for theft.
+const ReuseGrace = 30 * time.Second
+
+// Session is one login of a user on a device. Every refresh replaces its refresh token, so
+// the tokens issued for a session form a family.
+type Session struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// CurrentTokenHash is empty for sessions started before refresh tokens were tracked.
+	CurrentTokenHash  string
+	PreviousTokenHash *string
+	RotatedAt         *time.Time
+	Device            string
+	IPAddress         string
+	UserAgent         string
+	CreatedAt         time.Time
+	LastSeenAt        time.Time
+	ExpiresAt         time.Time
+	RevokedAt         *time.Time
+	RevokedReason     *string
+}
+
+// Active reports whether the session can still be refreshed.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ToResponse converts a Session to a SessionResponse. current marks the session the
+// request was made with.
+func (s *Session) ToResponse(current bool) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID.String(),
+		Device:     s.Device,
+		IPAddress:  s.IPAddress,
+		UserAgent:  s.UserAgent,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    current,
+	}
+}
+
+// SessionResponse represents the API response for a session.
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// Client describes where a request came from.
+type Client struct {
+	IPAddress string
+	UserAgent string
+}
+
+// userAgentRule names the browser or operating system of user agents containing any of
+// its markers.
+type userAgentRule struct {
+	name    string
+	markers []string
+}
+
+// Rules are checked in order, since user agents also name the browsers they imitate.
+var (
+	browserRules = []userAgentRule{
+		{"Edge", []string{"Edg/", "Edge/"}},
+		{"Opera", []string{"OPR/", "Opera"}},
+		{"Firefox", []string{"Firefox/", "FxiOS/"}},
+		{"Chrome", []string{"Chrome/", "CriOS/"}},
+		{"Safari", []string{"Safari/"}},
+	}
+	osRules = []userAgentRule{
+		{"iOS", []string{"iPhone", "iPad"}},
+		{"Android", []string{"Android"}},
+		{"Windows", []string{"Windows"}},
+		{"macOS", []string{"Macintosh", "Mac OS X"}},
+		{"Linux", []string{"Linux"}},
+	}
+)
+
+// Device returns a short description of the client's browser and operating system, such
+// as "Firefox on Windows", for listing sessions.
+func (c Client) Device() string {
+	browser := matchUserAgent(c.UserAgent, browserRules)
+	os := matchUserAgent(c.UserAgent, osRules)
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	}
+	return "Unknown device"
+}
+
+func matchUserAgent(userAgent string, rules []userAgentRule) string {
+	for _, rule := range rules {
+		for _, marker := range rule.markers {
+			if strings.Contains(userAgent, marker) {
+				return rule.name
+			}
+		}
+	}
+	return ""
+}
+
+// HashToken returns the hash a refresh token is stored under.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,9 @@
 package showtime
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository defines the data access contract for the showtime domain.
 type Repository interface {
@@ -8,6 +11,14 @@ type Repository interface {
 	GetByID(ctx context.Context, id int64) (*Showtime, error)
 	ListByMovie(ctx context.Context, movieId int64) ([]Showtime, error)
 	ListAdmin(ctx context.Context, limit, offset int32) ([]Showtime, error)
+	List(ctx context.Context, filter ListFilter) ([]Showtime, error)
+	GetVenueProgramme(ctx context.Context, venueId int32, windowStart, windowEnd time.Time) ([]MovieProgramme, error)
 	Update(ctx context.Context, id int64, req UpdateShowtimeRequest) (*Showtime, error)
+	// Delete soft-deletes the showtime and cancels its reservations, marking paid ones for
+	// refund, or returns ErrNotFound.
 	Delete(ctx context.Context, id int64) error
+	// GetDeleted returns a showtime in the trash, or ErrNotFound.
+	GetDeleted(ctx context.Context, id int64) (*Showtime, error)
+	ListDeleted(ctx context.Context, limit, offset int32) ([]Showtime, error)
+	Restore(ctx context.Context, id int64) error
 }
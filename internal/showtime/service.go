@@ -3,11 +3,26 @@ package showtime
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/internal/user"
+	"github.com/mbeka02/ticketing-service/internal/venue"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
 )
 
 var (
-	ErrInvalidTimeRange = errors.New("start time must be before end time")
+	ErrNotFound            = errors.New("showtime not found")
+	ErrInvalidTimeRange    = errors.New("start time must be before end time")
+	ErrInvalidFilterRange  = errors.New("date_from must not be after date_to")
+	ErrInvalidDate         = errors.New("invalid date, expected YYYY-MM-DD")
+	ErrUnsupportedFormat   = errors.New("the auditorium does not support this screening format")
+	ErrSeatsExceedCapacity = errors.New("available seats exceed the auditorium's capacity")
+	ErrParentDeleted       = errors.New("the showtime's movie or venue is deleted, restore it first")
 )
 
 // Service defines the business operations for the showtime domain.
@@ -16,17 +31,32 @@ type Service interface {
 	GetShowtime(ctx context.Context, id int64) (*Showtime, error)
 	ListShowtimesByMovie(ctx context.Context, movieId int64) ([]Showtime, error)
 	ListShowtimesAdmin(ctx context.Context, limit, offset int32) ([]Showtime, error)
+	ListShowtimes(ctx context.Context, filter ListFilter) ([]Showtime, error)
+	GetVenueProgramme(ctx context.Context, venueId int32, date *string) ([]MovieProgramme, error)
 	UpdateShowtime(ctx context.Context, id int64, req UpdateShowtimeRequest) (*Showtime, error)
+	// DeleteShowtime soft-deletes the showtime and cancels its reservations.
 	DeleteShowtime(ctx context.Context, id int64) error
+	ListDeletedShowtimes(ctx context.Context, limit, offset int32) ([]Showtime, error)
+	RestoreShowtime(ctx context.Context, id int64) (*Showtime, error)
+
+	// CheckAdmission checks a booking for the showtime against the movie's age rating and
+	// the booker's date of birth, if they have given one. It returns user.ErrEmailNotVerified
+	// if bookings require a verified email address and the booker has none.
+	CheckAdmission(ctx context.Context, id int64, userId uuid.UUID, req AdmissionRequest) (*AdmissionCheck, error)
+	// GetCheckIn returns what staff need to admit customers to the showtime.
+	GetCheckIn(ctx context.Context, id int64) (*CheckIn, error)
 }
 
 type service struct {
-	repo Repository
+	repo   Repository
+	venues venue.Service
+	movies movie.Service
+	users  user.Service
 }
 
 // NewService creates a new showtime service.
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, venues venue.Service, movies movie.Service, users user.Service) Service {
+	return &service{repo: repo, venues: venues, movies: movies, users: users}
 }
 
 func (s *service) CreateShowtime(ctx context.Context, req CreateShowtimeRequest) (*Showtime, error) {
@@ -43,7 +73,44 @@ func (s *service) CreateShowtime(ctx context.Context, req CreateShowtimeRequest)
 		return nil, ErrInvalidTimeRange
 	}
 
-	return s.repo.Create(ctx, req)
+	// Showtimes of deleted movies would be hidden from every listing yet still go on sale.
+	if _, err := s.movies.GetMovie(ctx, req.MovieID); err != nil {
+		return nil, err
+	}
+
+	if req.Format == "" {
+		req.Format = Format2D
+	}
+	auditorium, err := s.checkAuditorium(ctx, req.AuditoriumID, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if req.AvailableSeats == 0 {
+		req.AvailableSeats = auditorium.Capacity
+	}
+	if req.AvailableSeats > auditorium.Capacity {
+		return nil, ErrSeatsExceedCapacity
+	}
+	if err := s.venues.EnsureOpen(ctx, auditorium.VenueID, start, end); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	created.VenueID = &auditorium.VenueID
+	created.AuditoriumName = &auditorium.Name
+
+	// The first showtime puts a movie on sale. Failing to notify interested customers
+	// must not fail the scheduling; those not notified are retried on the next showtime.
+	if err := s.movies.NotifyOnSale(ctx, req.MovieID); err != nil {
+		logger.WarnCtx(ctx, "failed to send on-sale notifications",
+			zap.Int64("movie_id", req.MovieID),
+			zap.Error(err),
+		)
+	}
+	return created, nil
 }
 
 func (s *service) GetShowtime(ctx context.Context, id int64) (*Showtime, error) {
@@ -58,10 +125,180 @@ func (s *service) ListShowtimesAdmin(ctx context.Context, limit, offset int32) (
 	return s.repo.ListAdmin(ctx, limit, offset)
 }
 
+func (s *service) ListShowtimes(ctx context.Context, filter ListFilter) ([]Showtime, error) {
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return nil, ErrInvalidFilterRange
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// GetVenueProgramme returns every showtime at the venue on the given calendar day (YYYY-MM-DD),
+// grouped by movie. The day is interpreted in the venue's time zone; a nil date means today.
+func (s *service) GetVenueProgramme(ctx context.Context, venueId int32, date *string) ([]MovieProgramme, error) {
+	v, err := s.venues.GetVenue(ctx, venueId)
+	if err != nil {
+		return nil, err
+	}
+	loc := v.Location()
+
+	now := time.Now().In(loc)
+	windowStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if date != nil {
+		if windowStart, err = time.ParseInLocation(time.DateOnly, *date, loc); err != nil {
+			return nil, ErrInvalidDate
+		}
+	}
+	windowEnd := windowStart.AddDate(0, 0, 1)
+	return s.repo.GetVenueProgramme(ctx, venueId, windowStart, windowEnd)
+}
+
 func (s *service) UpdateShowtime(ctx context.Context, id int64, req UpdateShowtimeRequest) (*Showtime, error) {
+	rescheduled := req.StartTime != nil || req.EndTime != nil || req.AuditoriumID != nil
+	if !rescheduled && req.Format == nil && req.AvailableSeats == nil {
+		return s.repo.Update(ctx, id, req)
+	}
+
+	// Changes must still leave the showtime in a format and size its auditorium supports,
+	// at a time when the venue is open.
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	auditoriumId, format := existing.AuditoriumID, existing.Format
+	if req.AuditoriumID != nil {
+		auditoriumId = *req.AuditoriumID
+	}
+	if req.Format != nil {
+		format = *req.Format
+	}
+	auditorium, err := s.checkAuditorium(ctx, auditoriumId, format)
+	if err != nil {
+		return nil, err
+	}
+	// Moving to a smaller auditorium must not leave more seats on sale than it holds.
+	seats := existing.AvailableSeats
+	if req.AvailableSeats != nil {
+		seats = *req.AvailableSeats
+	}
+	if seats > auditorium.Capacity {
+		return nil, ErrSeatsExceedCapacity
+	}
+
+	if rescheduled {
+		start, end := existing.StartTime, existing.EndTime
+		if req.StartTime != nil {
+			if start, err = time.Parse(time.RFC3339, *req.StartTime); err != nil {
+				return nil, err
+			}
+		}
+		if req.EndTime != nil {
+			if end, err = time.Parse(time.RFC3339, *req.EndTime); err != nil {
+				return nil, err
+			}
+		}
+		if !end.After(start) {
+			return nil, ErrInvalidTimeRange
+		}
+		if err := s.venues.EnsureOpen(ctx, auditorium.VenueID, start, end); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.repo.Update(ctx, id, req)
 }
 
 func (s *service) DeleteShowtime(ctx context.Context, id int64) error {
 	return s.repo.Delete(ctx, id)
 }
+
+func (s *service) ListDeletedShowtimes(ctx context.Context, limit, offset int32) ([]Showtime, error) {
+	return s.repo.ListDeleted(ctx, limit, offset)
+}
+
+func (s *service) RestoreShowtime(ctx context.Context, id int64) (*Showtime, error) {
+	deleted, err := s.repo.GetDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deleted.ParentDeleted {
+		return nil, ErrParentDeleted
+	}
+	// A showtime that is still to come goes back on sale, so the venue must still be open then.
+	if deleted.StartTime.After(time.Now()) {
+		if err := s.venues.EnsureOpen(ctx, *deleted.VenueID, deleted.StartTime, deleted.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) CheckAdmission(ctx context.Context, id int64, userId uuid.UUID, req AdmissionRequest) (*AdmissionCheck, error) {
+	st, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	booker, err := s.users.GetUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.CanBook(booker); err != nil {
+		return nil, err
+	}
+
+	check := &AdmissionCheck{Showtime: st}
+	check.Classification, err = s.classify(st)
+	if errors.Is(err, movie.ErrUnknownAgeRating) {
+		// Movies rated before the rating scheme was configured cannot be assessed; staff
+		// fall back to the rating shown at check-in.
+		check.Admission = movie.Admission{
+			Allowed:  true,
+			Warnings: []string{"this movie's age rating is not part of the rating scheme, check the rating before booking children"},
+		}
+		return check, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Ages are taken on the day of the screening, not the day of booking.
+	check.Admission = check.Classification.AssessAdmission(req.Tickets, booker.DateOfBirth, st.StartTime)
+	return check, nil
+}
+
+func (s *service) GetCheckIn(ctx context.Context, id int64) (*CheckIn, error) {
+	st, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	checkIn := &CheckIn{Showtime: st}
+	checkIn.Classification, err = s.classify(st)
+	if err != nil && !errors.Is(err, movie.ErrUnknownAgeRating) {
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+// classify looks up the showtime's movie rating in the configured rating scheme.
+func (s *service) classify(st *Showtime) (*movie.Classification, error) {
+	if st.MovieAgeRating == nil {
+		return nil, movie.ErrUnknownAgeRating
+	}
+	return s.movies.Classify(*st.MovieAgeRating)
+}
+
+// checkAuditorium ensures the auditorium exists and can present the given format.
+func (s *service) checkAuditorium(ctx context.Context, auditoriumId int32, format string) (*venue.Auditorium, error) {
+	auditorium, err := s.venues.GetAuditorium(ctx, auditoriumId)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(auditorium.SupportedFormats, format) {
+		return nil, fmt.Errorf("%w: %s in auditorium %d", ErrUnsupportedFormat, format, auditoriumId)
+	}
+	return auditorium, nil
+}
@@ -0,0 +1,163 @@
+package showtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/internal/venue"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	Repository
+	showtimes map[int64]*Showtime
+	created   []CreateShowtimeRequest
+	windows   [][2]time.Time
+}
+
+func (r *fakeRepo) GetByID(ctx context.Context, id int64) (*Showtime, error) {
+	s, ok := r.showtimes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, id int64, req UpdateShowtimeRequest) (*Showtime, error) {
+	return r.showtimes[id], nil
+}
+
+func (r *fakeRepo) Create(ctx context.Context, req CreateShowtimeRequest) (*Showtime, error) {
+	r.created = append(r.created, req)
+	return &Showtime{MovieID: req.MovieID, AuditoriumID: req.AuditoriumID}, nil
+}
+
+func (r *fakeRepo) GetVenueProgramme(ctx context.Context, venueId int32, windowStart, windowEnd time.Time) ([]MovieProgramme, error) {
+	r.windows = append(r.windows, [2]time.Time{windowStart, windowEnd})
+	return nil, nil
+}
+
+type fakeVenues struct {
+	venue.Service
+	auditoriums map[int32]*venue.Auditorium
+	venues      map[int32]*venue.Venue
+}
+
+func (v *fakeVenues) GetVenue(ctx context.Context, id int32) (*venue.Venue, error) {
+	found, ok := v.venues[id]
+	if !ok {
+		return nil, venue.ErrNotFound
+	}
+	return found, nil
+}
+
+func (v *fakeVenues) GetAuditorium(ctx context.Context, id int32) (*venue.Auditorium, error) {
+	a, ok := v.auditoriums[id]
+	if !ok {
+		return nil, venue.ErrAuditoriumNotFound
+	}
+	return a, nil
+}
+
+func (v *fakeVenues) EnsureOpen(ctx context.Context, venueId int32, start, end time.Time) error {
+	return nil
+}
+
+type fakeMovies struct {
+	movie.Service
+	movies   map[int64]bool
+	notified []int64
+}
+
+func (m *fakeMovies) GetMovie(ctx context.Context, id int64) (*movie.Movie, error) {
+	if !m.movies[id] {
+		return nil, movie.ErrNotFound
+	}
+	return &movie.Movie{ID: id}, nil
+}
+
+func (m *fakeMovies) NotifyOnSale(ctx context.Context, movieId int64) error {
+	m.notified = append(m.notified, movieId)
+	return nil
+}
+
+func newTestService() (*service, *fakeRepo, *fakeMovies) {
+	start := time.Now().Add(24 * time.Hour)
+	repo := &fakeRepo{showtimes: map[int64]*Showtime{
+		1: {ID: 1, MovieID: 1, AuditoriumID: 1, Format: Format2D, AvailableSeats: 150,
+			StartTime: start, EndTime: start.Add(2 * time.Hour)},
+	}}
+	venues := &fakeVenues{auditoriums: map[int32]*venue.Auditorium{
+		1: {ID: 1, VenueID: 1, Capacity: 200, SupportedFormats: []string{Format2D}},
+		2: {ID: 2, VenueID: 1, Capacity: 80, SupportedFormats: []string{Format2D}},
+	}, venues: map[int32]*venue.Venue{
+		1: {ID: 1, Timezone: "Africa/Nairobi"},
+	}}
+	movies := &fakeMovies{movies: map[int64]bool{1: true}}
+	return &service{repo: repo, venues: venues, movies: movies}, repo, movies
+}
+
+func TestUpdateShowtimeChecksSeatsAgainstNewAuditorium(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService()
+	small, large := int32(2), int32(1)
+
+	// The showtime's 150 seats do not fit in the 80-seat auditorium.
+	_, err := s.UpdateShowtime(ctx, 1, UpdateShowtimeRequest{AuditoriumID: &small})
+	require.ErrorIs(t, err, ErrSeatsExceedCapacity)
+
+	seats := int32(80)
+	_, err = s.UpdateShowtime(ctx, 1, UpdateShowtimeRequest{AuditoriumID: &small, AvailableSeats: &seats})
+	require.NoError(t, err)
+
+	_, err = s.UpdateShowtime(ctx, 1, UpdateShowtimeRequest{AuditoriumID: &large})
+	require.NoError(t, err)
+}
+
+func TestCreateShowtimeRequiresMovie(t *testing.T) {
+	ctx := context.Background()
+	s, repo, movies := newTestService()
+	start := time.Now().Add(24 * time.Hour)
+	req := CreateShowtimeRequest{
+		MovieID:      2,
+		StartTime:    start.Format(time.RFC3339),
+		EndTime:      start.Add(2 * time.Hour).Format(time.RFC3339),
+		AuditoriumID: 1,
+	}
+
+	_, err := s.CreateShowtime(ctx, req)
+	require.ErrorIs(t, err, movie.ErrNotFound)
+	require.Empty(t, repo.created)
+	require.Empty(t, movies.notified)
+
+	req.MovieID = 1
+	_, err = s.CreateShowtime(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, int32(200), repo.created[0].AvailableSeats)
+	require.Equal(t, []int64{1}, movies.notified)
+}
+
+func TestGetVenueProgrammeUsesVenueTimeZone(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _ := newTestService()
+	nairobi, err := time.LoadLocation("Africa/Nairobi")
+	require.NoError(t, err)
+
+	date := "2026-03-14"
+	_, err = s.GetVenueProgramme(ctx, 1, &date)
+	require.NoError(t, err)
+	// Midnight in Nairobi (UTC+3) is 21:00 UTC the day before.
+	require.Equal(t, time.Date(2026, time.March, 13, 21, 0, 0, 0, time.UTC), repo.windows[0][0].UTC())
+	require.Equal(t, time.Date(2026, time.March, 15, 0, 0, 0, 0, nairobi), repo.windows[0][1])
+
+	_, err = s.GetVenueProgramme(ctx, 1, nil)
+	require.NoError(t, err)
+	now := time.Now().In(nairobi)
+	require.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, nairobi), repo.windows[1][0])
+
+	bad := "14/03/2026"
+	_, err = s.GetVenueProgramme(ctx, 1, &bad)
+	require.ErrorIs(t, err, ErrInvalidDate)
+}
@@ -1,6 +1,19 @@
 package showtime
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+// Screening formats a showtime can be presented in.
+const (
+	Format2D         = "2d"
+	Format3D         = "3d"
+	FormatIMAX       = "imax"
+	FormatDolbyAtmos = "dolby_atmos"
+)
 
 // Showtime represents a showtime in the system.
 type Showtime struct {
@@ -10,14 +23,31 @@ type Showtime struct {
 	EndTime        time.Time
 	AvailableSeats int32
 	PricePerSeat   float64
-	VenueID        int32
+	AuditoriumID   int32
 	CreatedAt      time.Time
 	UpdatedAt      *time.Time
 
+	// Screening attributes
+	Format           string
+	AudioLanguage    *string
+	SubtitleLanguage *string
+	IsDubbed         bool
+	AudioDescribed   bool
+	RelaxedScreening bool
+
 	// Enriched fields (populated by joins)
-	MovieTitle *string
-	VenueName  *string
-	VenueCity  *string
+	VenueID        *int32
+	AuditoriumName *string
+	MovieTitle     *string
+	MovieGenre     *string
+	MovieAgeRating *string
+	VenueName      *string
+	VenueCity      *string
+
+	// DeletedAt is set for showtimes in the trash. ParentDeleted reports that the movie or
+	// venue is in the trash too, so the showtime cannot be restored on its own.
+	DeletedAt     *time.Time
+	ParentDeleted bool
 }
 
 // ToResponse converts a Showtime to a ShowtimeResponse.
@@ -28,45 +58,79 @@ func (s *Showtime) ToResponse() ShowtimeResponse {
 	}
 
 	return ShowtimeResponse{
-		ID:             s.ID,
-		MovieID:        s.MovieID,
-		StartTime:      s.StartTime,
-		EndTime:        s.EndTime,
-		AvailableSeats: s.AvailableSeats,
-		PricePerSeat:   s.PricePerSeat,
-		VenueID:        s.VenueID,
-		CreatedAt:      s.CreatedAt,
-		UpdatedAt:      updatedAt,
-		MovieTitle:     s.MovieTitle,
-		VenueName:      s.VenueName,
-		VenueCity:      s.VenueCity,
+		ID:               s.ID,
+		MovieID:          s.MovieID,
+		StartTime:        s.StartTime,
+		EndTime:          s.EndTime,
+		AvailableSeats:   s.AvailableSeats,
+		PricePerSeat:     s.PricePerSeat,
+		AuditoriumID:     s.AuditoriumID,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        updatedAt,
+		Format:           s.Format,
+		AudioLanguage:    s.AudioLanguage,
+		SubtitleLanguage: s.SubtitleLanguage,
+		IsDubbed:         s.IsDubbed,
+		AudioDescribed:   s.AudioDescribed,
+		RelaxedScreening: s.RelaxedScreening,
+		VenueID:          s.VenueID,
+		AuditoriumName:   s.AuditoriumName,
+		MovieTitle:       s.MovieTitle,
+		MovieGenre:       s.MovieGenre,
+		MovieAgeRating:   s.MovieAgeRating,
+		VenueName:        s.VenueName,
+		VenueCity:        s.VenueCity,
+		DeletedAt:        s.DeletedAt,
+		ParentDeleted:    s.ParentDeleted,
 	}
 }
 
 // ShowtimeResponse represents the API response for a showtime.
 type ShowtimeResponse struct {
-	ID             int64     `json:"id"`
-	MovieID        int64     `json:"movie_id"`
-	StartTime      time.Time `json:"start_time"`
-	EndTime        time.Time `json:"end_time"`
-	AvailableSeats int32     `json:"available_seats"`
-	PricePerSeat   float64   `json:"price_per_seat"`
-	VenueID        int32     `json:"venue_id"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at,omitempty"`
-	MovieTitle     *string   `json:"movie_title,omitempty"`
-	VenueName      *string   `json:"venue_name,omitempty"`
-	VenueCity      *string   `json:"venue_city,omitempty"`
+	ID               int64     `json:"id"`
+	MovieID          int64     `json:"movie_id"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	AvailableSeats   int32     `json:"available_seats"`
+	PricePerSeat     float64   `json:"price_per_seat"`
+	AuditoriumID     int32     `json:"auditorium_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+	Format           string    `json:"format"`
+	AudioLanguage    *string   `json:"audio_language,omitempty"`
+	SubtitleLanguage *string   `json:"subtitle_language,omitempty"`
+	IsDubbed         bool      `json:"is_dubbed"`
+	AudioDescribed   bool      `json:"audio_described"`
+	RelaxedScreening bool      `json:"relaxed_screening"`
+	VenueID          *int32    `json:"venue_id,omitempty"`
+	AuditoriumName   *string   `json:"auditorium_name,omitempty"`
+	MovieTitle       *string   `json:"movie_title,omitempty"`
+	MovieGenre       *string   `json:"movie_genre,omitempty"`
+	MovieAgeRating   *string   `json:"movie_age_rating,omitempty"`
+	VenueName        *string   `json:"venue_name,omitempty"`
+	VenueCity        *string   `json:"venue_city,omitempty"`
+
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	ParentDeleted bool       `json:"parent_deleted,omitempty"`
 }
 
 // CreateShowtimeRequest represents the request to create a showtime.
 type CreateShowtimeRequest struct {
-	MovieID        int64   `json:"movie_id" validate:"required"`
-	StartTime      string  `json:"start_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
-	EndTime        string  `json:"end_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
-	AvailableSeats int32   `json:"available_seats" validate:"required,min=1"`
-	PricePerSeat   float64 `json:"price_per_seat" validate:"required,min=0"`
-	VenueID        int32   `json:"venue_id" validate:"required"`
+	MovieID      int64   `json:"movie_id" validate:"required"`
+	StartTime    string  `json:"start_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndTime      string  `json:"end_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	PricePerSeat float64 `json:"price_per_seat" validate:"required,min=0"`
+	AuditoriumID int32   `json:"auditorium_id" validate:"required"`
+	// AvailableSeats defaults to the auditorium's capacity when omitted.
+	AvailableSeats int32 `json:"available_seats" validate:"omitempty,min=1"`
+
+	// Format defaults to 2d when omitted.
+	Format           string  `json:"format" validate:"omitempty,oneof=2d 3d imax dolby_atmos"`
+	AudioLanguage    *string `json:"audio_language" validate:"omitempty,bcp47_language_tag"`
+	SubtitleLanguage *string `json:"subtitle_language" validate:"omitempty,bcp47_language_tag"`
+	IsDubbed         bool    `json:"is_dubbed"`
+	AudioDescribed   bool    `json:"audio_described"`
+	RelaxedScreening bool    `json:"relaxed_screening"`
 }
 
 // UpdateShowtimeRequest represents the request to update a showtime.
@@ -75,5 +139,167 @@ type UpdateShowtimeRequest struct {
 	EndTime        *string  `json:"end_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	AvailableSeats *int32   `json:"available_seats" validate:"omitempty,min=0"`
 	PricePerSeat   *float64 `json:"price_per_seat" validate:"omitempty,min=0"`
-	VenueID        *int32   `json:"venue_id"`
+	AuditoriumID   *int32   `json:"auditorium_id"`
+
+	Format           *string `json:"format" validate:"omitempty,oneof=2d 3d imax dolby_atmos"`
+	AudioLanguage    *string `json:"audio_language" validate:"omitempty,bcp47_language_tag"`
+	SubtitleLanguage *string `json:"subtitle_language" validate:"omitempty,bcp47_language_tag"`
+	IsDubbed         *bool   `json:"is_dubbed"`
+	AudioDescribed   *bool   `json:"audio_described"`
+	RelaxedScreening *bool   `json:"relaxed_screening"`
+}
+
+// AdmissionRequest describes the tickets a customer intends to book.
+type AdmissionRequest struct {
+	Tickets []movie.TicketCount `json:"tickets" validate:"required,min=1,dive"`
+}
+
+// AdmissionCheck is the outcome of checking a booking against the movie's age rating.
+type AdmissionCheck struct {
+	Showtime *Showtime
+	// Classification is nil if the movie's rating is not part of the rating scheme.
+	Classification *movie.Classification
+	movie.Admission
+}
+
+// ToResponse converts an AdmissionCheck to an AdmissionResponse.
+func (a *AdmissionCheck) ToResponse() AdmissionResponse {
+	reasons := a.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	warnings := a.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return AdmissionResponse{
+		ShowtimeID:     a.Showtime.ID,
+		Allowed:        a.Allowed,
+		Reasons:        reasons,
+		Warnings:       warnings,
+		Classification: classificationResponse(a.Classification),
+	}
+}
+
+// AdmissionResponse represents the API response for an admission check.
+type AdmissionResponse struct {
+	ShowtimeID     int64                         `json:"showtime_id"`
+	Allowed        bool                          `json:"allowed"`
+	Reasons        []string                      `json:"reasons"`
+	Warnings       []string                      `json:"warnings"`
+	Classification *movie.ClassificationResponse `json:"classification,omitempty"`
+}
+
+// CheckIn is what staff see when admitting customers to a showtime.
+type CheckIn struct {
+	Showtime *Showtime
+	// Classification is nil if the movie's rating is not part of the rating scheme.
+	Classification *movie.Classification
+}
+
+// Reminder returns the age check staff should make at admission, or an empty string.
+func (c *CheckIn) Reminder() string {
+	if c.Classification == nil {
+		if c.Showtime.MovieAgeRating != nil && *c.Showtime.MovieAgeRating != "" {
+			return fmt.Sprintf("Rated %s, check the rating's age limit before admitting children", *c.Showtime.MovieAgeRating)
+		}
+		return ""
+	}
+
+	switch c.Classification.Policy {
+	case movie.PolicyRestricted:
+		return fmt.Sprintf("Rated %s: check photo ID, no one under %d admitted", c.Classification.Code, c.Classification.MinAge)
+	case movie.PolicyAccompanied:
+		return fmt.Sprintf("Rated %s: under %d must be accompanied by an adult", c.Classification.Code, c.Classification.MinAge)
+	}
+	return ""
+}
+
+// ToResponse converts a CheckIn to a CheckInResponse.
+func (c *CheckIn) ToResponse() CheckInResponse {
+	return CheckInResponse{
+		Showtime:       c.Showtime.ToResponse(),
+		Classification: classificationResponse(c.Classification),
+		IDCheck:        c.Classification != nil && c.Classification.RequiresIDCheck(),
+		Reminder:       c.Reminder(),
+	}
+}
+
+// CheckInResponse represents the API response for a showtime's check-in details.
+type CheckInResponse struct {
+	Showtime       ShowtimeResponse              `json:"showtime"`
+	Classification *movie.ClassificationResponse `json:"classification,omitempty"`
+	IDCheck        bool                          `json:"id_check"`
+	Reminder       string                        `json:"reminder,omitempty"`
+}
+
+func classificationResponse(c *movie.Classification) *movie.ClassificationResponse {
+	if c == nil {
+		return nil
+	}
+	res := c.ToResponse()
+	return &res
+}
+
+// ListFilter narrows the public showtime listing. Nil fields are not applied.
+type ListFilter struct {
+	VenueID *int32
+	City    *string
+	// DateFrom and DateTo are inclusive calendar dates, compared with each showtime's
+	// start in its venue's time zone.
+	DateFrom  *time.Time
+	DateTo    *time.Time
+	Genre     *string
+	AgeRating *string
+	MinSeats  *int32
+
+	Format           *string
+	AudioLanguage    *string
+	SubtitleLanguage *string
+	AudioDescribed   *bool
+	RelaxedScreening *bool
+
+	Limit  int32
+	Offset int32
+}
+
+// MovieProgramme groups a venue's showtimes for a single movie.
+type MovieProgramme struct {
+	MovieID        int64
+	MovieTitle     string
+	MovieGenre     string
+	MovieAgeRating string
+	MovieRuntime   int32
+	MoviePosterUrl string
+	Showtimes      []Showtime
+}
+
+// ToResponse converts a MovieProgramme to a MovieProgrammeResponse.
+func (p *MovieProgramme) ToResponse() MovieProgrammeResponse {
+	showtimes := make([]ShowtimeResponse, 0, len(p.Showtimes))
+	for _, s := range p.Showtimes {
+		showtimes = append(showtimes, s.ToResponse())
+	}
+
+	return MovieProgrammeResponse{
+		MovieID:        p.MovieID,
+		MovieTitle:     p.MovieTitle,
+		MovieGenre:     p.MovieGenre,
+		MovieAgeRating: p.MovieAgeRating,
+		MovieRuntime:   p.MovieRuntime,
+		MoviePosterUrl: p.MoviePosterUrl,
+		Showtimes:      showtimes,
+	}
+}
+
+// MovieProgrammeResponse represents the API response for one movie in a venue's programme.
+type MovieProgrammeResponse struct {
+	MovieID        int64              `json:"movie_id"`
+	MovieTitle     string             `json:"movie_title"`
+	MovieGenre     string             `json:"movie_genre"`
+	MovieAgeRating string             `json:"movie_age_rating"`
+	MovieRuntime   int32              `json:"movie_runtime"`
+	MoviePosterUrl string             `json:"movie_poster_url"`
+	Showtimes      []ShowtimeResponse `json:"showtimes"`
 }
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mbeka02/ticketing-service/internal/media"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "movies/1/poster.jpg", []byte("jpeg"), "image/jpeg"))
+	data, err := s.Get(ctx, "movies/1/poster.jpg")
+	require.NoError(t, err)
+	require.Equal(t, []byte("jpeg"), data)
+
+	require.NoError(t, s.Delete(ctx, "movies/1/poster.jpg"))
+	_, err = s.Get(ctx, "movies/1/poster.jpg")
+	require.ErrorIs(t, err, media.ErrObjectNotFound)
+	require.NoError(t, s.Delete(ctx, "movies/1/poster.jpg"))
+}
+
+func TestLocalStorageRejectsKeysOutsideRoot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "media")
+	s, err := NewLocalStorage(root)
+	require.NoError(t, err)
+
+	outside := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o600))
+
+	for _, key := range []string{"../secret", "movies/../../secret", "..", "", "."} {
+		t.Run(key, func(t *testing.T) {
+			_, err := s.Get(ctx, key)
+			require.Error(t, err)
+			require.NotErrorIs(t, err, media.ErrObjectNotFound)
+			require.Error(t, s.Put(ctx, key, []byte("x"), "image/jpeg"))
+			require.Error(t, s.Delete(ctx, key))
+		})
+	}
+
+	data, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), data)
+
+	// Dot segments that stay inside the root are cleaned rather than rejected.
+	require.NoError(t, s.Put(ctx, "movies/../poster.jpg", []byte("jpeg"), "image/jpeg"))
+	_, err = os.Stat(filepath.Join(root, "poster.jpg"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestCanonicalRequest(t *testing.T) {
+	path := "/" + uriEncode("media bucket", false) + "/" + uriEncode("movies/1/poster (1)é.jpg", true)
+	require.Equal(t, "/media%20bucket/movies/1/poster%20%281%29%C3%A9.jpg", path)
+
+	got := canonicalRequest(http.MethodGet, path, "s3.eu-west-1.amazonaws.com", emptyPayloadHash, "20130524T000000Z")
+	want := strings.Join([]string{
+		"GET",
+		"/media%20bucket/movies/1/poster%20%281%29%C3%A9.jpg",
+		"",
+		"host:s3.eu-west-1.amazonaws.com",
+		"x-amz-content-sha256:" + emptyPayloadHash,
+		"x-amz-date:20130524T000000Z",
+		"",
+		"host;x-amz-content-sha256;x-amz-date",
+		emptyPayloadHash,
+	}, "\n")
+	require.Equal(t, want, got)
+}
+
+func TestSigningKey(t *testing.T) {
+	// Example from the AWS documentation on deriving a Signature Version 4 signing key.
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	require.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestSign(t *testing.T) {
+	s := NewS3Storage(S3Config{
+		Endpoint:        "https://s3.eu-west-1.amazonaws.com/",
+		Region:          "eu-west-1",
+		Bucket:          "media",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	})
+	req, err := http.NewRequest(http.MethodGet, s.cfg.Endpoint+"/media/poster.jpg", nil)
+	require.NoError(t, err)
+
+	s.sign(req, "/media/poster.jpg", nil, time.Date(2013, time.May, 24, 0, 0, 0, 0, time.UTC))
+
+	require.Equal(t, "20130524T000000Z", req.Header.Get("X-Amz-Date"))
+	require.Equal(t, emptyPayloadHash, req.Header.Get("X-Amz-Content-Sha256"))
+	auth := req.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(auth,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/eu-west-1/s3/aws4_request, "+
+			"SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="), auth)
+	require.Len(t, strings.TrimPrefix(auth[strings.Index(auth, "Signature="):], "Signature="), 64)
+}
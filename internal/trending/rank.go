@@ -0,0 +1,37 @@
+package trending
+
+import "sort"
+
+// Rank scores and orders movies by sales velocity, relative to the fastest-selling movie,
+// and occupancy. Ties go to the movie with more tickets sold, then the lower id.
+func Rank(signals []Signal) []Ranking {
+	var maxVelocity float64
+	for _, s := range signals {
+		maxVelocity = max(maxVelocity, s.Velocity)
+	}
+
+	rankings := make([]Ranking, 0, len(signals))
+	for _, s := range signals {
+		var score float64
+		if maxVelocity > 0 {
+			score += velocityWeight * s.Velocity / maxVelocity
+		}
+		score += occupancyWeight * s.Occupancy()
+		rankings = append(rankings, Ranking{Signal: s, Score: score})
+	}
+
+	sort.Slice(rankings, func(a, b int) bool {
+		ra, rb := rankings[a], rankings[b]
+		if ra.Score != rb.Score {
+			return ra.Score > rb.Score
+		}
+		if ra.TicketsSold != rb.TicketsSold {
+			return ra.TicketsSold > rb.TicketsSold
+		}
+		return ra.MovieID < rb.MovieID
+	})
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+	return rankings
+}
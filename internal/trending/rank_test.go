@@ -0,0 +1,35 @@
+package trending
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankOccupancyMovesScore(t *testing.T) {
+	// Same sales velocity; the second movie's upcoming showtimes are fuller.
+	rankings := Rank([]Signal{
+		{MovieID: 1, TicketsSold: 10, Velocity: 5, SeatsSold: 20, SeatsTotal: 200},
+		{MovieID: 2, TicketsSold: 10, Velocity: 5, SeatsSold: 150, SeatsTotal: 200},
+		{MovieID: 3},
+	})
+
+	require.Equal(t, int64(2), rankings[0].MovieID)
+	require.InDelta(t, velocityWeight+occupancyWeight*0.75, rankings[0].Score, 1e-9)
+	require.Equal(t, int64(1), rankings[1].MovieID)
+	require.InDelta(t, velocityWeight+occupancyWeight*0.1, rankings[1].Score, 1e-9)
+	require.Equal(t, int64(3), rankings[2].MovieID)
+	require.Zero(t, rankings[2].Score)
+	require.Equal(t, 3, rankings[2].Rank)
+}
+
+func TestRankOccupancyWithoutSales(t *testing.T) {
+	rankings := Rank([]Signal{
+		{MovieID: 1, SeatsSold: 0, SeatsTotal: 100},
+		{MovieID: 2, SeatsSold: 50, SeatsTotal: 100},
+	})
+
+	require.Equal(t, int64(2), rankings[0].MovieID)
+	require.InDelta(t, occupancyWeight*0.5, rankings[0].Score, 1e-9)
+	require.Zero(t, rankings[1].Score)
+}
@@ -0,0 +1,13 @@
+package trending
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the data access contract for the trending domain.
+type Repository interface {
+	// ListSignals returns the sales signals of every movie now showing, counting sales
+	// over window and halving their weight every halfLife.
+	ListSignals(ctx context.Context, halfLife, window time.Duration) ([]Signal, error)
+}
@@ -0,0 +1,134 @@
+package trending
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+	"github.com/mbeka02/ticketing-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Service defines the business operations for the trending domain.
+type Service interface {
+	// Trending returns up to limit movies now showing, best ranked first, and when the
+	// ranking was computed. Rankings are served from memory and recomputed on the first
+	// request after they go stale.
+	Trending(ctx context.Context, limit int32) ([]TrendingMovie, time.Time, error)
+	// Refresh recomputes the rankings.
+	Refresh(ctx context.Context) error
+	// RunRefresher recomputes the rankings every interval until ctx is cancelled.
+	RunRefresher(ctx context.Context)
+}
+
+type service struct {
+	repo     Repository
+	movies   movie.Service
+	interval time.Duration
+
+	mu         sync.RWMutex
+	rankings   []Ranking
+	computedAt time.Time
+	// refreshMu stops concurrent requests recomputing a stale ranking at the same time.
+	refreshMu sync.Mutex
+}
+
+// NewService creates a new trending service whose rankings are refreshed every interval.
+func NewService(repo Repository, movies movie.Service, interval time.Duration) Service {
+	return &service{repo: repo, movies: movies, interval: interval}
+}
+
+func (s *service) Trending(ctx context.Context, limit int32) ([]TrendingMovie, time.Time, error) {
+	rankings, computedAt := s.cached()
+	// Serve a slightly old ranking rather than block on the refresher, but recompute once
+	// it has missed a run.
+	if computedAt.IsZero() || time.Since(computedAt) > 2*s.interval {
+		if err := s.refreshIfStale(ctx, computedAt); err != nil {
+			return nil, time.Time{}, err
+		}
+		rankings, computedAt = s.cached()
+	}
+
+	if int(limit) < len(rankings) {
+		rankings = rankings[:limit]
+	}
+	ids := make([]int64, 0, len(rankings))
+	for _, r := range rankings {
+		ids = append(ids, r.MovieID)
+	}
+	movies, err := s.movies.GetMovies(ctx, ids)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	byId := make(map[int64]movie.Movie, len(movies))
+	for _, m := range movies {
+		byId[m.ID] = m
+	}
+	res := make([]TrendingMovie, 0, len(rankings))
+	for _, r := range rankings {
+		m, ok := byId[r.MovieID]
+		if !ok {
+			// Deleted since the ranking was computed.
+			continue
+		}
+		res = append(res, TrendingMovie{Ranking: r, Movie: m})
+	}
+	return res, computedAt, nil
+}
+
+func (s *service) Refresh(ctx context.Context) error {
+	signals, err := s.repo.ListSignals(ctx, HalfLife, Window)
+	if err != nil {
+		return err
+	}
+	rankings := Rank(signals)
+
+	s.mu.Lock()
+	s.rankings = rankings
+	s.computedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *service) RunRefresher(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.refreshAndLog(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshAndLog(ctx)
+		}
+	}
+}
+
+func (s *service) refreshAndLog(ctx context.Context) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if err := s.Refresh(ctx); err != nil {
+		logger.ErrorCtx(ctx, "failed to refresh trending movies", zap.Error(err))
+	}
+}
+
+// refreshIfStale recomputes the rankings unless another caller already has since seen.
+func (s *service) refreshIfStale(ctx context.Context, seen time.Time) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if _, computedAt := s.cached(); computedAt.After(seen) {
+		return nil
+	}
+	return s.Refresh(ctx)
+}
+
+func (s *service) cached() ([]Ranking, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rankings, s.computedAt
+}
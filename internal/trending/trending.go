@@ -0,0 +1,77 @@
+package trending
+
+import (
+	"time"
+
+	"github.com/mbeka02/ticketing-service/internal/movie"
+)
+
+// Signal window and decay. A sale loses half its weight every HalfLife, and sales older
+// than Window are ignored.
+const (
+	HalfLife = 48 * time.Hour
+	Window   = 14 * 24 * time.Hour
+)
+
+// Weights of each signal in a trending score, both scaled to 0..1 first.
+const (
+	velocityWeight  = 0.7
+	occupancyWeight = 0.3
+)
+
+// MaxLimit is the most trending movies returned at once.
+const MaxLimit = 50
+
+// Signal is the raw sales data a movie now showing is ranked on.
+type Signal struct {
+	MovieID int64
+	// TicketsSold counts confirmed seats over the window.
+	TicketsSold int64
+	// Velocity is TicketsSold with each sale decayed by its age.
+	Velocity float64
+	// SeatsSold and SeatsTotal cover the movie's upcoming showtimes: SeatsTotal is the
+	// capacity of their auditoriums and SeatsSold the seats no longer available.
+	SeatsSold  int64
+	SeatsTotal int64
+}
+
+// Occupancy returns the share of seats sold across the movie's upcoming showtimes.
+func (s *Signal) Occupancy() float64 {
+	if s.SeatsTotal == 0 {
+		return 0
+	}
+	return float64(s.SeatsSold) / float64(s.SeatsTotal)
+}
+
+// Ranking is a movie's place in the trending list.
+type Ranking struct {
+	Signal
+	Rank  int
+	Score float64
+}
+
+// TrendingMovie is a ranked movie with its details.
+type TrendingMovie struct {
+	Ranking
+	Movie movie.Movie
+}
+
+// ToResponse converts a TrendingMovie to a TrendingMovieResponse.
+func (t *TrendingMovie) ToResponse() TrendingMovieResponse {
+	return TrendingMovieResponse{
+		Rank:        t.Rank,
+		Score:       t.Score,
+		TicketsSold: t.TicketsSold,
+		Occupancy:   t.Occupancy(),
+		Movie:       t.Movie.ToResponse(),
+	}
+}
+
+// TrendingMovieResponse represents the API response for a trending movie.
+type TrendingMovieResponse struct {
+	Rank        int                 `json:"rank"`
+	Score       float64             `json:"score"`
+	TicketsSold int64               `json:"tickets_sold"`
+	Occupancy   float64             `json:"occupancy"`
+	Movie       movie.MovieResponse `json:"movie"`
+}
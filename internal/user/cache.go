@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cacheSweepSize is how many entries the cache holds before expired ones are swept out.
+const cacheSweepSize = 10000
+
+// Cache looks users up by ID, remembering each result for a short TTL. Authentication
+// reads the current role and deletion state through it rather than trusting token claims,
+// without a database query on every request.
+type Cache struct {
+	repo Repository
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cacheEntry
+}
+
+type cacheEntry struct {
+	user      *User
+	err       error
+	expiresAt time.Time
+}
+
+// NewCache creates a Cache over repo whose entries live for ttl.
+func NewCache(repo Repository, ttl time.Duration) *Cache {
+	return &Cache{repo: repo, ttl: ttl, entries: make(map[uuid.UUID]cacheEntry)}
+}
+
+// Get returns the user, or ErrNotFound if they do not exist or were deleted. Both outcomes
+// are cached; other errors are not.
+func (c *Cache) Get(ctx context.Context, id uuid.UUID) (*User, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.user, entry.err
+	}
+
+	u, err := c.repo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= cacheSweepSize {
+		for key, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	c.entries[id] = cacheEntry{user: u, err: err, expiresAt: now.Add(c.ttl)}
+	return u, err
+}
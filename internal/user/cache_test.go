@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRepo serves GetByID from a map and counts the calls; other methods are unused.
+type countingRepo struct {
+	Repository
+	users map[uuid.UUID]*User
+	calls int
+}
+
+func (r *countingRepo) GetByID(ctx context.Context, id uuid.UUID) (*User, error) {
+	r.calls++
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func TestCachePicksUpChangesAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	repo := &countingRepo{users: map[uuid.UUID]*User{id: {ID: id, Role: RoleAdmin}}}
+	cache := NewCache(repo, 50*time.Millisecond)
+
+	u, err := cache.Get(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, u.Role)
+
+	// Demoted: the cached role is served until the entry expires.
+	repo.users[id].Role = RoleUser
+	u, err = cache.Get(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, u.Role)
+	require.Equal(t, 1, repo.calls)
+
+	time.Sleep(60 * time.Millisecond)
+	u, err = cache.Get(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, RoleUser, u.Role)
+	require.Equal(t, 2, repo.calls)
+}
+
+func TestCacheRemembersMissingUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingRepo{users: map[uuid.UUID]*User{}}
+	cache := NewCache(repo, time.Minute)
+	id := uuid.New()
+
+	_, err := cache.Get(ctx, id)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = cache.Get(ctx, id)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, 1, repo.calls)
+}
@@ -8,8 +8,15 @@ import (
 	"github.com/google/uuid"
 )
 
-// ErrNotFound is returned when a user is not found.
-var ErrNotFound = errors.New("user not found")
+var (
+	// ErrNotFound is returned when a user is not found.
+	ErrNotFound = errors.New("user not found")
+	// ErrIdentityNotFound is returned when a user has no such identity.
+	ErrIdentityNotFound = errors.New("sign in method not found")
+	// ErrLastIdentity is returned when removing an identity would leave a user unable to
+	// sign in.
+	ErrLastIdentity = errors.New("you cannot remove your only way to sign in")
+)
 
 // CreateUserParams contains the parameters for creating an OAuth user.
 type CreateUserParams struct {
@@ -19,6 +26,13 @@ type CreateUserParams struct {
 	VerifiedAt      time.Time
 }
 
+// UpdateProfileParams contains the profile fields to change; nil fields are left as they are.
+type UpdateProfileParams struct {
+	FullName        *string
+	TelephoneNumber *string
+	DateOfBirth     *time.Time
+}
+
 // Repository defines the data access contract for the user domain.
 type Repository interface {
 	GetByProvider(ctx context.Context, provider, providerUserID string) (*User, error)
@@ -27,4 +41,25 @@ type Repository interface {
 	CreateWithIdentity(ctx context.Context, params CreateUserParams, provider, providerUserID string) (*User, error)
 	CreateLocalWithIdentity(ctx context.Context, email, fullName, passwordHash, telephone string) (*User, error)
 	LinkIdentity(ctx context.Context, userID uuid.UUID, provider, providerUserID string) error
+	// GetIdentity returns the identity for a provider's user ID, or ErrIdentityNotFound.
+	GetIdentity(ctx context.Context, provider, providerUserID string) (*Identity, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]*Identity, error)
+	// UnlinkIdentity removes one of the user's identities, clearing their password if it is
+	// the local one. It returns ErrIdentityNotFound if the user has no such identity and
+	// ErrLastIdentity if it is the only one they have left.
+	UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) (*Identity, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, params UpdateProfileParams) (*User, error)
+
+	// CreateToken stores a token hash for purpose, retiring the user's earlier tokens for it.
+	CreateToken(ctx context.Context, userID uuid.UUID, purpose, tokenHash string, expiresAt time.Time) error
+	// LatestTokenAt returns when the user was last issued a token for purpose, or nil.
+	LatestTokenAt(ctx context.Context, userID uuid.UUID, purpose string) (*time.Time, error)
+	// VerifyEmail consumes an email verification token and marks its user verified. It
+	// returns ErrInvalidToken if the token is unknown, used or expired.
+	VerifyEmail(ctx context.Context, tokenHash string) (*User, error)
+	// SetPassword replaces the user's password and revokes all their sessions.
+	SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) (*User, error)
+	// ResetPassword consumes a password reset token and sets its user's password as
+	// SetPassword does. It returns ErrInvalidToken if the token is unknown, used or expired.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (*User, error)
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/mbeka02/ticketing-service/internal/auth"
 	"github.com/mbeka02/ticketing-service/pkg/logger"
 	"go.uber.org/zap"
@@ -14,6 +17,9 @@ var (
 	ErrEmailAlreadyExists = errors.New("a user with this email already exists")
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrOAuthOnlyAccount   = errors.New("this account uses a social login provider, please log in with your provider or set a password in account settings")
+	ErrInvalidDateOfBirth = errors.New("date of birth must be in the past")
+	ErrAccountExists      = errors.New("an account with this email already exists, log in to it and link this provider from your account settings")
+	ErrIdentityInUse      = errors.New("this sign in is already linked to another account")
 )
 
 // Service defines the business operations for the user domain.
@@ -21,15 +27,46 @@ type Service interface {
 	CreateOrLoginOAuthUser(ctx context.Context, data OAuthUserData) (*User, error)
 	RegisterLocalUser(ctx context.Context, email, fullName, password, telephone string) (*User, error)
 	LoginLocalUser(ctx context.Context, email, password string) (*User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, req UpdateProfileRequest) (*User, error)
+
+	// ListIdentities lists the ways the user can sign in.
+	ListIdentities(ctx context.Context, id uuid.UUID) ([]IdentityResponse, error)
+	// LinkOAuthIdentity lets the signed in user sign in with a provider account as well,
+	// whatever its email. It returns ErrIdentityInUse if another user already has it.
+	LinkOAuthIdentity(ctx context.Context, id uuid.UUID, data OAuthUserData) error
+	// UnlinkIdentity removes one of the user's ways to sign in, refusing with
+	// ErrLastIdentity to remove the only one.
+	UnlinkIdentity(ctx context.Context, id, identityID uuid.UUID) error
+
+	// VerifyEmail marks the user a verification token was sent to as verified.
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+	// ResendVerification emails the user a new verification link, at most once per
+	// ResendInterval. Earlier links stop working.
+	ResendVerification(ctx context.Context, id uuid.UUID) error
+	// CanBook returns ErrEmailNotVerified if verification is required and u is unverified.
+	CanBook(u *User) error
+
+	// RequestPasswordReset emails a password reset link to the account with email, if there
+	// is one. It does not reveal whether the account exists.
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword sets a new password with a reset token and signs the user out everywhere.
+	ResetPassword(ctx context.Context, token, newPassword string) (*User, error)
+	// ChangePassword sets a new password after checking the current one, and signs the user
+	// out everywhere. Accounts without a password, such as social logins, can set one.
+	ChangePassword(ctx context.Context, id uuid.UUID, req ChangePasswordRequest) (*User, error)
 }
 
 type service struct {
-	repo Repository
+	repo          Repository
+	mailer        Mailer
+	verification  VerificationOptions
+	passwordReset PasswordResetOptions
 }
 
 // NewService creates a new user service.
-func NewService(repo Repository) Service {
-	return &service{repo}
+func NewService(repo Repository, mailer Mailer, verification VerificationOptions, passwordReset PasswordResetOptions) Service {
+	return &service{repo: repo, mailer: mailer, verification: verification, passwordReset: passwordReset}
 }
 
 func (s *service) CreateOrLoginOAuthUser(ctx context.Context, data OAuthUserData) (*User, error) {
@@ -50,8 +87,20 @@ func (s *service) CreateOrLoginOAuthUser(ctx context.Context, data OAuthUserData
 	// 2. Identity not found, check if user with same email exists
 	userByEmail, err := s.repo.GetByEmail(ctx, data.Email)
 	if err == nil {
-		// 3. User exists, link new identity to this user
-		logger.DebugCtx(ctx, "user with same email found, linking new identity",
+		// 3. User exists. Matching emails only prove it is the same person when the
+		// provider vouches for the address and the account owner has confirmed it too;
+		// otherwise whoever registered the email first could take over the other
+		// account. They can still link the provider after logging in.
+		if !data.EmailVerified || userByEmail.VerifiedAt == nil {
+			logger.WarnCtx(ctx, "refusing to link identity to account with unverified email",
+				zap.String("user_id", userByEmail.ID.String()),
+				zap.String("provider", data.Provider),
+				zap.Bool("provider_verified", data.EmailVerified),
+			)
+			return nil, ErrAccountExists
+		}
+
+		logger.DebugCtx(ctx, "user with same verified email found, linking new identity",
 			zap.String("user_id", userByEmail.ID.String()),
 			zap.String("provider", data.Provider),
 			zap.String("email", data.Email),
@@ -76,11 +125,16 @@ func (s *service) CreateOrLoginOAuthUser(ctx context.Context, data OAuthUserData
 	)
 
 	fullName := fmt.Sprintf("%s %s", data.FirstName, data.LastName)
-	newUser, err := s.repo.CreateWithIdentity(ctx, CreateUserParams{
+	params := CreateUserParams{
 		Email:           data.Email,
 		FullName:        fullName,
 		ProfileImageUrl: data.AvatarURL,
-	}, data.Provider, data.ProviderUserID)
+	}
+	// Trust the provider's verification instead of sending our own email.
+	if data.EmailVerified {
+		params.VerifiedAt = time.Now()
+	}
+	newUser, err := s.repo.CreateWithIdentity(ctx, params, data.Provider, data.ProviderUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user with identity: %w", err)
 	}
@@ -88,6 +142,58 @@ func (s *service) CreateOrLoginOAuthUser(ctx context.Context, data OAuthUserData
 	return newUser, nil
 }
 
+func (s *service) ListIdentities(ctx context.Context, id uuid.UUID) ([]IdentityResponse, error) {
+	identities, err := s.repo.ListIdentities(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]IdentityResponse, len(identities))
+	for i, identity := range identities {
+		res[i] = identity.ToResponse()
+	}
+	return res, nil
+}
+
+func (s *service) LinkOAuthIdentity(ctx context.Context, id uuid.UUID, data OAuthUserData) error {
+	existing, err := s.repo.GetIdentity(ctx, data.Provider, data.ProviderUserID)
+	if err == nil {
+		if existing.UserID != id {
+			logger.WarnCtx(ctx, "attempt to link identity owned by another user",
+				zap.String("user_id", id.String()),
+				zap.String("provider", data.Provider),
+			)
+			return ErrIdentityInUse
+		}
+		return nil
+	}
+	if !errors.Is(err, ErrIdentityNotFound) {
+		return fmt.Errorf("error checking existing identity: %w", err)
+	}
+
+	if err := s.repo.LinkIdentity(ctx, id, data.Provider, data.ProviderUserID); err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	logger.InfoCtx(ctx, "identity linked",
+		zap.String("user_id", id.String()),
+		zap.String("provider", data.Provider),
+	)
+	return nil
+}
+
+func (s *service) UnlinkIdentity(ctx context.Context, id, identityID uuid.UUID) error {
+	identity, err := s.repo.UnlinkIdentity(ctx, id, identityID)
+	if err != nil {
+		return err
+	}
+
+	logger.InfoCtx(ctx, "identity unlinked",
+		zap.String("user_id", id.String()),
+		zap.String("provider", identity.Provider),
+	)
+	return nil
+}
+
 func (s *service) RegisterLocalUser(ctx context.Context, email, fullName, password, telephone string) (*User, error) {
 	// Check if email is already taken
 	_, err := s.repo.GetByEmail(ctx, email)
@@ -123,6 +229,15 @@ func (s *service) RegisterLocalUser(ctx context.Context, email, fullName, passwo
 		zap.String("email", email),
 	)
 
+	// The account is usable without verification, so a failed email only means the
+	// user has to ask for another one.
+	if err := s.sendVerification(ctx, user); err != nil {
+		logger.ErrorCtx(ctx, "failed to send verification email",
+			zap.Error(err),
+			zap.String("user_id", user.ID.String()),
+		)
+	}
+
 	return user, nil
 }
 
@@ -162,3 +277,172 @@ func (s *service) LoginLocalUser(ctx context.Context, email, password string) (*
 
 	return user, nil
 }
+
+func (s *service) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) UpdateProfile(ctx context.Context, id uuid.UUID, req UpdateProfileRequest) (*User, error) {
+	params := UpdateProfileParams{
+		FullName:        req.Fullname,
+		TelephoneNumber: req.TelephoneNumber,
+	}
+	if req.DateOfBirth != nil {
+		dob, err := time.Parse(time.DateOnly, *req.DateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		if !dob.Before(time.Now()) {
+			return nil, ErrInvalidDateOfBirth
+		}
+		params.DateOfBirth = &dob
+	}
+	return s.repo.UpdateProfile(ctx, id, params)
+}
+
+func (s *service) VerifyEmail(ctx context.Context, token string) (*User, error) {
+	u, err := s.repo.VerifyEmail(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "email address verified",
+		zap.String("user_id", u.ID.String()),
+	)
+	return u, nil
+}
+
+func (s *service) ResendVerification(ctx context.Context, id uuid.UUID) error {
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u.VerifiedAt != nil {
+		return ErrAlreadyVerified
+	}
+
+	last, err := s.repo.LatestTokenAt(ctx, id, TokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < s.verification.ResendInterval {
+		return ErrVerificationThrottled
+	}
+
+	return s.sendVerification(ctx, u)
+}
+
+func (s *service) CanBook(u *User) error {
+	if s.verification.Required && u.VerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// sendVerification issues a verification token for u and emails them a link to it.
+func (s *service) sendVerification(ctx context.Context, u *User) error {
+	token, hash, err := newToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	expiresAt := time.Now().Add(s.verification.TTL)
+	if err := s.repo.CreateToken(ctx, u.ID, TokenPurposeEmailVerification, hash, expiresAt); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+
+	link := s.verification.LinkURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, Email{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+			u.FullName, s.verification.TTL, link),
+	})
+}
+
+func (s *service) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			logger.WarnCtx(ctx, "password reset requested for non-existent email",
+				zap.String("email", email),
+			)
+			return nil
+		}
+		return err
+	}
+
+	last, err := s.repo.LatestTokenAt(ctx, u.ID, TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < s.passwordReset.ResendInterval {
+		logger.WarnCtx(ctx, "password reset throttled",
+			zap.String("user_id", u.ID.String()),
+		)
+		return nil
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	expiresAt := time.Now().Add(s.passwordReset.TTL)
+	if err := s.repo.CreateToken(ctx, u.ID, TokenPurposePasswordReset, hash, expiresAt); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+
+	link := s.passwordReset.LinkURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, Email{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one. It expires in %s.\n\n%s\n\nIf you did not ask to reset your password, you can ignore this email.\n",
+			u.FullName, s.passwordReset.TTL, link),
+	})
+}
+
+func (s *service) ResetPassword(ctx context.Context, token, newPassword string) (*User, error) {
+	hashedPassword, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	u, err := s.repo.ResetPassword(ctx, hashToken(token), hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "password reset",
+		zap.String("user_id", u.ID.String()),
+	)
+	return u, nil
+}
+
+func (s *service) ChangePassword(ctx context.Context, id uuid.UUID, req ChangePasswordRequest) (*User, error) {
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.PasswordHash != nil && *u.PasswordHash != "" {
+		if err := auth.ComparePassword(req.CurrentPassword, *u.PasswordHash); err != nil {
+			logger.WarnCtx(ctx, "password change with wrong current password",
+				zap.String("user_id", id.String()),
+			)
+			return nil, ErrIncorrectPassword
+		}
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	u, err = s.repo.SetPassword(ctx, id, hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoCtx(ctx, "password changed",
+		zap.String("user_id", id.String()),
+	)
+	return u, nil
+}
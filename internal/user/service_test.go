@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// identityRepo keeps users and their identities in memory; other methods are unused.
+type identityRepo struct {
+	Repository
+	users      map[uuid.UUID]*User
+	identities []*Identity
+}
+
+func newIdentityRepo() *identityRepo {
+	return &identityRepo{users: make(map[uuid.UUID]*User)}
+}
+
+func (r *identityRepo) add(email string, verified bool) *User {
+	u := &User{ID: uuid.New(), Email: email}
+	if verified {
+		now := time.Now()
+		u.VerifiedAt = &now
+	}
+	r.users[u.ID] = u
+	return u
+}
+
+func (r *identityRepo) GetByProvider(ctx context.Context, provider, providerUserID string) (*User, error) {
+	identity, err := r.GetIdentity(ctx, provider, providerUserID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return r.users[identity.UserID], nil
+}
+
+func (r *identityRepo) GetByEmail(ctx context.Context, email string) (*User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *identityRepo) CreateWithIdentity(ctx context.Context, params CreateUserParams, provider, providerUserID string) (*User, error) {
+	u := &User{ID: uuid.New(), Email: params.Email, FullName: params.FullName}
+	if !params.VerifiedAt.IsZero() {
+		u.VerifiedAt = &params.VerifiedAt
+	}
+	r.users[u.ID] = u
+	return u, r.LinkIdentity(ctx, u.ID, provider, providerUserID)
+}
+
+func (r *identityRepo) LinkIdentity(ctx context.Context, userID uuid.UUID, provider, providerUserID string) error {
+	r.identities = append(r.identities, &Identity{ID: uuid.New(), UserID: userID, Provider: provider, ProviderUserID: providerUserID})
+	return nil
+}
+
+func (r *identityRepo) GetIdentity(ctx context.Context, provider, providerUserID string) (*Identity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.ProviderUserID == providerUserID {
+			return identity, nil
+		}
+	}
+	return nil, ErrIdentityNotFound
+}
+
+func (r *identityRepo) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*Identity, error) {
+	var res []*Identity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			res = append(res, identity)
+		}
+	}
+	return res, nil
+}
+
+func TestCreateOrLoginOAuthUserLinksOnlyVerifiedEmails(t *testing.T) {
+	ctx := context.Background()
+	repo := newIdentityRepo()
+	svc := NewService(repo, nil, VerificationOptions{}, PasswordResetOptions{})
+
+	unverified := repo.add("unverified@example.com", false)
+	verified := repo.add("verified@example.com", true)
+
+	// The account owner never confirmed their address.
+	_, err := svc.CreateOrLoginOAuthUser(ctx, OAuthUserData{Email: unverified.Email, EmailVerified: true, Provider: "google", ProviderUserID: "1"})
+	require.ErrorIs(t, err, ErrAccountExists)
+
+	// The provider does not vouch for the address.
+	_, err = svc.CreateOrLoginOAuthUser(ctx, OAuthUserData{Email: verified.Email, Provider: "microsoft", ProviderUserID: "2"})
+	require.ErrorIs(t, err, ErrAccountExists)
+	require.Empty(t, repo.identities)
+
+	u, err := svc.CreateOrLoginOAuthUser(ctx, OAuthUserData{Email: verified.Email, EmailVerified: true, Provider: "google", ProviderUserID: "3"})
+	require.NoError(t, err)
+	require.Equal(t, verified.ID, u.ID)
+
+	// A new account is only verified if the provider says the address is.
+	u, err = svc.CreateOrLoginOAuthUser(ctx, OAuthUserData{Email: "new@example.com", Provider: "microsoft", ProviderUserID: "4"})
+	require.NoError(t, err)
+	require.Nil(t, u.VerifiedAt)
+}
+
+func TestLinkOAuthIdentity(t *testing.T) {
+	ctx := context.Background()
+	repo := newIdentityRepo()
+	svc := NewService(repo, nil, VerificationOptions{}, PasswordResetOptions{})
+
+	alice := repo.add("alice@example.com", false)
+	bob := repo.add("bob@example.com", true)
+	data := OAuthUserData{Email: "someone-else@example.com", Provider: "github", ProviderUserID: "42"}
+
+	// Linking from a signed in session works whatever the provider's email.
+	require.NoError(t, svc.LinkOAuthIdentity(ctx, alice.ID, data))
+	require.NoError(t, svc.LinkOAuthIdentity(ctx, alice.ID, data))
+	require.ErrorIs(t, svc.LinkOAuthIdentity(ctx, bob.ID, data), ErrIdentityInUse)
+
+	identities, err := svc.ListIdentities(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	require.Equal(t, "github", identities[0].Provider)
+
+	u, err := svc.CreateOrLoginOAuthUser(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, alice.ID, u.ID)
+}
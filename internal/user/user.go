@@ -25,6 +25,7 @@ type User struct {
 	CreatedAt       time.Time
 	UpdatedAt       *time.Time
 	VerifiedAt      *time.Time
+	DateOfBirth     *time.Time
 }
 
 // ToResponse converts a User to a UserResponse.
@@ -39,6 +40,12 @@ func (u *User) ToResponse() UserResponse {
 		updatedAt = *u.UpdatedAt
 	}
 
+	var dateOfBirth *string
+	if u.DateOfBirth != nil {
+		dob := u.DateOfBirth.Format(time.DateOnly)
+		dateOfBirth = &dob
+	}
+
 	return UserResponse{
 		UserId:          u.ID.String(),
 		Fullname:        u.FullName,
@@ -48,6 +55,28 @@ func (u *User) ToResponse() UserResponse {
 		CreatedAt:       u.CreatedAt,
 		UpdatedAt:       updatedAt,
 		VerifiedAt:      verifiedAt,
+		DateOfBirth:     dateOfBirth,
+	}
+}
+
+// ProviderLocal is the identity provider password logins are recorded under.
+const ProviderLocal = "local"
+
+// Identity is one way a user can sign in: their password, a social login or a passkey.
+type Identity struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Provider       string
+	ProviderUserID string
+	CreatedAt      time.Time
+}
+
+// ToResponse converts an Identity to an IdentityResponse.
+func (i *Identity) ToResponse() IdentityResponse {
+	return IdentityResponse{
+		ID:        i.ID.String(),
+		Provider:  i.Provider,
+		CreatedAt: i.CreatedAt,
 	}
 }
 
@@ -71,6 +100,33 @@ type UserResponse struct {
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 	VerifiedAt      time.Time `json:"verified_at"`
+	DateOfBirth     *string   `json:"date_of_birth,omitempty"`
+}
+
+// UpdateProfileRequest represents the request to update the current user's profile.
+// The date of birth is used to check age ratings when booking.
+type UpdateProfileRequest struct {
+	Fullname        *string `json:"full_name" validate:"omitempty,min=2"`
+	TelephoneNumber *string `json:"telephone_number" validate:"omitempty,max=15"`
+	DateOfBirth     *string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ForgotPasswordRequest represents the request to email a password reset link.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the request to set a new password with a reset token.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangePasswordRequest represents the request to change the current user's password.
+// CurrentPassword may be omitted by accounts that do not have a password yet.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
 }
 
 // LoginRequest represents the request to log in.
@@ -87,4 +143,13 @@ type OAuthUserData struct {
 	Provider       string
 	ProviderUserID string
 	AvatarURL      string
+	// EmailVerified is whether the provider vouches that the user owns Email.
+	EmailVerified bool
+}
+
+// IdentityResponse represents a sign in method in API responses.
+type IdentityResponse struct {
+	ID        string    `json:"id"`
+	Provider  string    `json:"provider"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidToken          = errors.New("token is invalid or has expired")
+	ErrAlreadyVerified       = errors.New("email address is already verified")
+	ErrVerificationThrottled = errors.New("a verification email was sent recently, please wait before requesting another")
+	ErrEmailNotVerified      = errors.New("please verify your email address first")
+	ErrIncorrectPassword     = errors.New("current password is incorrect")
+)
+
+// Token purposes; a token only works for the purpose it was issued for.
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// Email is a message sent to a user.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. Adapters live in the notify package.
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// VerificationOptions configures email verification for local accounts.
+type VerificationOptions struct {
+	// LinkURL is the page verification links point to; the token is appended as ?token=.
+	LinkURL string
+	// TTL is how long a verification link works for.
+	TTL time.Duration
+	// ResendInterval is the least time between verification emails to the same user.
+	ResendInterval time.Duration
+	// Required refuses bookings from users who have not verified their email address.
+	Required bool
+}
+
+// PasswordResetOptions configures password reset emails.
+type PasswordResetOptions struct {
+	// LinkURL is the page reset links point to; the token is appended as ?token=.
+	LinkURL string
+	// TTL is how long a reset link works for.
+	TTL time.Duration
+	// ResendInterval is the least time between reset emails to the same user.
+	ResendInterval time.Duration
+}
+
+// newToken returns a random URL-safe token and the hash it is stored under.
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,10 +1,44 @@
 package venue
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository defines the data access contract for the venue domain.
 type Repository interface {
+	// Create inserts the venue and its auditoriums atomically.
 	Create(ctx context.Context, req CreateVenueRequest) (*Venue, error)
 	GetByID(ctx context.Context, id int32) (*Venue, error)
+	// List returns venues visible to customers, hiding those under an active closure.
 	List(ctx context.Context) ([]Venue, error)
+	ListAdmin(ctx context.Context) ([]Venue, error)
+	Update(ctx context.Context, id int32, req UpdateVenueRequest) (*Venue, error)
+	// Delete soft-deletes the venue and its auditoriums. It returns ErrShowtimesScheduled
+	// if future showtimes exist, unless cascade is set, in which case they are deleted too.
+	Delete(ctx context.Context, id int32, cascade bool) error
+	// GetDeleted returns a venue in the trash, or ErrNotFound.
+	GetDeleted(ctx context.Context, id int32) (*Venue, error)
+	ListDeleted(ctx context.Context, limit, offset int32) ([]Venue, error)
+	// Restore undoes a soft delete, together with the auditoriums and future showtimes
+	// deleted with the venue.
+	Restore(ctx context.Context, id int32) error
+
+	CreateAuditorium(ctx context.Context, venueId int32, req CreateAuditoriumRequest) (*Auditorium, error)
+	GetAuditorium(ctx context.Context, id int32) (*Auditorium, error)
+	ListAuditoriums(ctx context.Context, venueId int32) ([]Auditorium, error)
+
+	// CreateClosure records a closure. Like Delete, it refuses to strand showtimes in the
+	// closed period unless cascade is set.
+	CreateClosure(ctx context.Context, venueId int32, kind, reason string, startsAt, endsAt time.Time, cascade bool) (*Closure, error)
+	ListClosures(ctx context.Context, venueId int32) ([]Closure, error)
+	// ListActiveClosures returns the closures in effect now for each of the given venues, keyed by venue ID.
+	ListActiveClosures(ctx context.Context, venueIds []int32) (map[int32][]Closure, error)
+	DeleteClosure(ctx context.Context, venueId, closureId int32) error
+	CountOverlappingClosures(ctx context.Context, venueId int32, start, end time.Time) (int64, error)
+
+	// ListOpeningHours returns the opening hours of each of the given venues, keyed by venue ID.
+	ListOpeningHours(ctx context.Context, venueIds []int32) (map[int32][]OpeningHours, error)
+	// SetOpeningHours atomically replaces the venue's weekly opening hours.
+	SetOpeningHours(ctx context.Context, venueId int32, hours []OpeningHours) error
 }
@@ -1,12 +1,32 @@
 package venue
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Service defines the business operations for the venue domain.
 type Service interface {
 	CreateVenue(ctx context.Context, req CreateVenueRequest) (*Venue, error)
 	GetVenue(ctx context.Context, id int32) (*Venue, error)
 	ListVenues(ctx context.Context) ([]Venue, error)
+	ListVenuesAdmin(ctx context.Context) ([]Venue, error)
+	UpdateVenue(ctx context.Context, id int32, req UpdateVenueRequest) (*Venue, error)
+	DeleteVenue(ctx context.Context, id int32, cascade bool) error
+	ListDeletedVenues(ctx context.Context, limit, offset int32) ([]Venue, error)
+	RestoreVenue(ctx context.Context, id int32) (*Venue, error)
+
+	CreateAuditorium(ctx context.Context, venueId int32, req CreateAuditoriumRequest) (*Auditorium, error)
+	GetAuditorium(ctx context.Context, id int32) (*Auditorium, error)
+	ListAuditoriums(ctx context.Context, venueId int32) ([]Auditorium, error)
+
+	CloseVenue(ctx context.Context, venueId int32, req CreateClosureRequest, cascade bool) (*Closure, error)
+	ListClosures(ctx context.Context, venueId int32) ([]Closure, error)
+	ReopenVenue(ctx context.Context, venueId, closureId int32) error
+	SetOpeningHours(ctx context.Context, venueId int32, req SetOpeningHoursRequest) (*Venue, error)
+	// EnsureOpen returns ErrOutsideOpeningHours if a screening from start to end would not fit
+	// within the venue's opening hours, or ErrClosed if it would fall within a closure.
+	EnsureOpen(ctx context.Context, venueId int32, start, end time.Time) error
 }
 
 type service struct {
@@ -19,13 +39,224 @@ func NewService(repo Repository) Service {
 }
 
 func (s *service) CreateVenue(ctx context.Context, req CreateVenueRequest) (*Venue, error) {
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if len(req.Auditoriums) == 0 {
+		req.Auditoriums = []CreateAuditoriumRequest{{
+			Name:     defaultAuditoriumName,
+			Capacity: req.TotalSeats,
+		}}
+	}
+	for i := range req.Auditoriums {
+		if err := normalizeAuditorium(&req.Auditoriums[i]); err != nil {
+			return nil, err
+		}
+	}
 	return s.repo.Create(ctx, req)
 }
 
+// GetVenue returns the venue together with its opening hours, auditoriums and upcoming closures.
 func (s *service) GetVenue(ctx context.Context, id int32) (*Venue, error) {
-	return s.repo.GetByID(ctx, id)
+	v, err := s.getVenueWithHours(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Auditoriums, err = s.repo.ListAuditoriums(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	v.Closures, err = s.repo.ListClosures(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
 func (s *service) ListVenues(ctx context.Context) ([]Venue, error) {
-	return s.repo.List(ctx)
+	venues, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return venues, s.attachSchedules(ctx, venues)
+}
+
+func (s *service) ListVenuesAdmin(ctx context.Context) ([]Venue, error) {
+	venues, err := s.repo.ListAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return venues, s.attachSchedules(ctx, venues)
+}
+
+func (s *service) UpdateVenue(ctx context.Context, id int32, req UpdateVenueRequest) (*Venue, error) {
+	if _, err := s.repo.Update(ctx, id, req); err != nil {
+		return nil, err
+	}
+	return s.GetVenue(ctx, id)
+}
+
+func (s *service) DeleteVenue(ctx context.Context, id int32, cascade bool) error {
+	return s.repo.Delete(ctx, id, cascade)
+}
+
+func (s *service) ListDeletedVenues(ctx context.Context, limit, offset int32) ([]Venue, error) {
+	return s.repo.ListDeleted(ctx, limit, offset)
+}
+
+func (s *service) RestoreVenue(ctx context.Context, id int32) (*Venue, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetVenue(ctx, id)
+}
+
+func (s *service) CreateAuditorium(ctx context.Context, venueId int32, req CreateAuditoriumRequest) (*Auditorium, error) {
+	if _, err := s.repo.GetByID(ctx, venueId); err != nil {
+		return nil, err
+	}
+	if err := normalizeAuditorium(&req); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateAuditorium(ctx, venueId, req)
+}
+
+func (s *service) GetAuditorium(ctx context.Context, id int32) (*Auditorium, error) {
+	return s.repo.GetAuditorium(ctx, id)
+}
+
+func (s *service) ListAuditoriums(ctx context.Context, venueId int32) ([]Auditorium, error) {
+	return s.repo.ListAuditoriums(ctx, venueId)
+}
+
+func (s *service) CloseVenue(ctx context.Context, venueId int32, req CreateClosureRequest, cascade bool) (*Closure, error) {
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	if !endsAt.After(startsAt) {
+		return nil, ErrInvalidClosureRange
+	}
+
+	if req.Kind == "" {
+		req.Kind = ClosureTemporary
+	}
+
+	if _, err := s.repo.GetByID(ctx, venueId); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateClosure(ctx, venueId, req.Kind, req.Reason, startsAt, endsAt, cascade)
+}
+
+func (s *service) ListClosures(ctx context.Context, venueId int32) ([]Closure, error) {
+	return s.repo.ListClosures(ctx, venueId)
+}
+
+// ReopenVenue removes a closure, ending it early or cancelling it before it starts.
+func (s *service) ReopenVenue(ctx context.Context, venueId, closureId int32) error {
+	return s.repo.DeleteClosure(ctx, venueId, closureId)
+}
+
+func (s *service) SetOpeningHours(ctx context.Context, venueId int32, req SetOpeningHoursRequest) (*Venue, error) {
+	hours := make([]OpeningHours, 0, len(req.Hours))
+	seen := make(map[time.Weekday]bool, len(req.Hours))
+	for _, h := range req.Hours {
+		parsed, err := h.toOpeningHours()
+		if err != nil {
+			return nil, err
+		}
+		if seen[parsed.Weekday] {
+			return nil, ErrDuplicateOpeningDay
+		}
+		seen[parsed.Weekday] = true
+		hours = append(hours, parsed)
+	}
+
+	if _, err := s.repo.GetByID(ctx, venueId); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetOpeningHours(ctx, venueId, hours); err != nil {
+		return nil, err
+	}
+	return s.GetVenue(ctx, venueId)
+}
+
+func (s *service) EnsureOpen(ctx context.Context, venueId int32, start, end time.Time) error {
+	v, err := s.getVenueWithHours(ctx, venueId)
+	if err != nil {
+		return err
+	}
+	if !v.IsOpenBetween(start, end) {
+		return ErrOutsideOpeningHours
+	}
+
+	overlapping, err := s.repo.CountOverlappingClosures(ctx, venueId, start, end)
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (s *service) getVenueWithHours(ctx context.Context, id int32) (*Venue, error) {
+	v, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.repo.ListOpeningHours(ctx, []int32{id})
+	if err != nil {
+		return nil, err
+	}
+	v.OpeningHours = hours[id]
+	return v, nil
+}
+
+// attachSchedules loads opening hours and active closures for a page of venues,
+// one query each, so that listings report whether each venue is open today.
+func (s *service) attachSchedules(ctx context.Context, venues []Venue) error {
+	ids := make([]int32, 0, len(venues))
+	for _, v := range venues {
+		ids = append(ids, v.ID)
+	}
+
+	hours, err := s.repo.ListOpeningHours(ctx, ids)
+	if err != nil {
+		return err
+	}
+	closures, err := s.repo.ListActiveClosures(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range venues {
+		venues[i].OpeningHours = hours[venues[i].ID]
+		venues[i].Closures = closures[venues[i].ID]
+	}
+	return nil
+}
+
+// normalizeAuditorium applies defaults and checks that any layout adds up to the capacity.
+func normalizeAuditorium(req *CreateAuditoriumRequest) error {
+	if len(req.SupportedFormats) == 0 {
+		req.SupportedFormats = []string{defaultFormat}
+	}
+	if len(req.Layout) == 0 {
+		return nil
+	}
+
+	var seats int32
+	for _, row := range req.Layout {
+		seats += row.Seats
+	}
+	if seats != req.Capacity {
+		return ErrLayoutCapacityMismatch
+	}
+	return nil
 }
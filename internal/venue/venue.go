@@ -1,16 +1,75 @@
 package venue
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
-// Venue represents a venue in the system.
+var (
+	// ErrNotFound is returned when a venue is not found.
+	ErrNotFound = errors.New("venue not found")
+	// ErrAuditoriumNotFound is returned when an auditorium is not found.
+	ErrAuditoriumNotFound = errors.New("auditorium not found")
+	// ErrLayoutCapacityMismatch is returned when a seating layout does not add up to the stated capacity.
+	ErrLayoutCapacityMismatch = errors.New("seating layout does not match auditorium capacity")
+	// ErrShowtimesScheduled is returned when an operation would strand scheduled showtimes and cascade was not requested.
+	ErrShowtimesScheduled = errors.New("venue has showtimes scheduled in this period, retry with cascade=true to cancel them")
+	// ErrClosed is returned when a screening would take place while the venue is closed.
+	ErrClosed = errors.New("venue is closed during this period")
+	// ErrClosureNotFound is returned when a closure is not found.
+	ErrClosureNotFound = errors.New("venue closure not found")
+	// ErrInvalidClosureRange is returned when a closure does not end after it starts.
+	ErrInvalidClosureRange = errors.New("closure must end after it starts")
+	// ErrOutsideOpeningHours is returned when a screening would not fit within the venue's opening hours.
+	ErrOutsideOpeningHours = errors.New("screening falls outside the venue's opening hours")
+	// ErrDuplicateOpeningDay is returned when opening hours list the same day more than once.
+	ErrDuplicateOpeningDay = errors.New("opening hours list the same day more than once")
+)
+
+// Closure kinds. Temporary closures hide the venue from the public listing;
+// exceptional ones only block screenings.
+const (
+	ClosureTemporary    = "temporary"
+	ClosureHoliday      = "holiday"
+	ClosurePrivateEvent = "private_event"
+)
+
+// clockLayout is the format of opening and closing times in requests and responses.
+const clockLayout = "15:04"
+
+const (
+	// defaultFormat is assigned to auditoriums created without any supported formats.
+	defaultFormat = "2d"
+	// defaultAuditoriumName is used for the single screen created alongside a venue from total_seats.
+	defaultAuditoriumName = "Screen 1"
+)
+
+// Venue represents a cinema site. Screenings take place in its auditoriums.
 type Venue struct {
-	ID         int32
-	Name       string
-	Address    string
-	City       string
+	ID        int32
+	Name      string
+	Address   string
+	City      string
+	Timezone  string
+	Amenities []string
+	// TotalSeats is the combined capacity of the venue's auditoriums.
 	TotalSeats int32
 	CreatedAt  time.Time
 	UpdatedAt  *time.Time
+
+	// OpeningHours holds at most one entry per weekday. An empty slice means no
+	// hours have been recorded and the venue is treated as always open.
+	OpeningHours []OpeningHours
+
+	// Auditoriums are only populated when the venue is fetched individually. Closures
+	// holds all upcoming closures for a single venue, but only those in effect now for listings.
+	Auditoriums []Auditorium
+	Closures    []Closure
+
+	// DeletedAt is set for venues in the trash.
+	DeletedAt *time.Time
 }
 
 // ToResponse converts a Venue to a VenueResponse.
@@ -20,32 +79,361 @@ func (v *Venue) ToResponse() VenueResponse {
 		updatedAt = *v.UpdatedAt
 	}
 
+	var auditoriums []AuditoriumResponse
+	for _, a := range v.Auditoriums {
+		auditoriums = append(auditoriums, a.ToResponse())
+	}
+
+	var closures []ClosureResponse
+	for _, c := range v.Closures {
+		closures = append(closures, c.ToResponse())
+	}
+
+	var openingHours []OpeningHoursResponse
+	for _, h := range v.OpeningHours {
+		openingHours = append(openingHours, h.ToResponse())
+	}
+
 	return VenueResponse{
-		ID:         v.ID,
-		Name:       v.Name,
-		Address:    v.Address,
-		City:       v.City,
-		TotalSeats: v.TotalSeats,
-		CreatedAt:  v.CreatedAt,
-		UpdatedAt:  updatedAt,
+		ID:          v.ID,
+		Name:        v.Name,
+		Address:     v.Address,
+		City:        v.City,
+		Timezone:    v.Timezone,
+		Amenities:   v.Amenities,
+		TotalSeats:  v.TotalSeats,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   updatedAt,
+		Auditoriums: auditoriums,
+		Closures:    closures,
+
+		OpeningHours: openingHours,
+		Today:        v.today(time.Now()),
+		DeletedAt:    v.DeletedAt,
+	}
+}
+
+// today summarises the venue's hours for the local calendar day containing now.
+// While the previous day's hours are still running past midnight, those are reported instead.
+func (v *Venue) today(now time.Time) *TodayResponse {
+	if len(v.OpeningHours) == 0 {
+		return nil
+	}
+
+	local := now.In(v.Location())
+	res := &TodayResponse{Date: local.Format(time.DateOnly)}
+	if v.ActiveClosure(now) != nil {
+		return res
+	}
+
+	h := v.HoursOn(local.Weekday())
+	yesterday := local.AddDate(0, 0, -1)
+	if prev := v.HoursOn(yesterday.Weekday()); prev != nil {
+		if _, closes := prev.window(yesterday); now.Before(closes) {
+			h = prev
+		}
+	}
+	if h != nil {
+		res.Open = true
+		res.OpensAt = formatClock(h.OpensAt)
+		res.ClosesAt = formatClock(h.ClosesAt)
+	}
+	return res
+}
+
+// HoursOn returns the opening hours for the given weekday, or nil if the venue is closed that day.
+func (v *Venue) HoursOn(day time.Weekday) *OpeningHours {
+	for i := range v.OpeningHours {
+		if v.OpeningHours[i].Weekday == day {
+			return &v.OpeningHours[i]
+		}
+	}
+	return nil
+}
+
+// IsOpenBetween reports whether the venue is open for the whole of [start, end].
+// Hours that run past midnight are attributed to the day on which they open.
+func (v *Venue) IsOpenBetween(start, end time.Time) bool {
+	if len(v.OpeningHours) == 0 {
+		return true
+	}
+
+	local := start.In(v.Location())
+	for _, offset := range []int{-1, 0} {
+		day := local.AddDate(0, 0, offset)
+		h := v.HoursOn(day.Weekday())
+		if h == nil {
+			continue
+		}
+		opens, closes := h.window(day)
+		if !start.Before(opens) && !end.After(closes) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveClosure returns the closure in effect at t, if any.
+func (v *Venue) ActiveClosure(t time.Time) *Closure {
+	for i := range v.Closures {
+		if v.Closures[i].Covers(t) {
+			return &v.Closures[i]
+		}
+	}
+	return nil
+}
+
+// Location returns the venue's time zone, falling back to UTC if it cannot be loaded.
+func (v *Venue) Location() *time.Location {
+	loc, err := time.LoadLocation(v.Timezone)
+	if err != nil {
+		return time.UTC
 	}
+	return loc
 }
 
 // VenueResponse represents the API response for a venue.
 type VenueResponse struct {
-	ID         int32     `json:"id"`
-	Name       string    `json:"name"`
-	Address    string    `json:"address"`
-	City       string    `json:"city"`
-	TotalSeats int32     `json:"total_seats"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+	ID          int32                `json:"id"`
+	Name        string               `json:"name"`
+	Address     string               `json:"address"`
+	City        string               `json:"city"`
+	Timezone    string               `json:"timezone"`
+	Amenities   []string             `json:"amenities"`
+	TotalSeats  int32                `json:"total_seats"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at,omitempty"`
+	Auditoriums []AuditoriumResponse `json:"auditoriums,omitempty"`
+	Closures    []ClosureResponse    `json:"closures,omitempty"`
+
+	OpeningHours []OpeningHoursResponse `json:"opening_hours,omitempty"`
+	Today        *TodayResponse         `json:"today,omitempty"`
+	DeletedAt    *time.Time             `json:"deleted_at,omitempty"`
+}
+
+// TodayResponse describes a venue's hours for the current local day.
+type TodayResponse struct {
+	Date     string `json:"date"`
+	Open     bool   `json:"open"`
+	OpensAt  string `json:"opens_at,omitempty"`
+	ClosesAt string `json:"closes_at,omitempty"`
+}
+
+// LayoutRow describes one row of seats in an auditorium.
+type LayoutRow struct {
+	Row   string `json:"row" validate:"required,max=1"`
+	Seats int32  `json:"seats" validate:"required,min=1"`
+}
+
+// Auditorium represents a single screen within a venue.
+type Auditorium struct {
+	ID               int32
+	VenueID          int32
+	Name             string
+	Capacity         int32
+	Layout           []LayoutRow
+	SupportedFormats []string
+	CreatedAt        time.Time
+	UpdatedAt        *time.Time
+}
+
+// ToResponse converts an Auditorium to an AuditoriumResponse.
+func (a *Auditorium) ToResponse() AuditoriumResponse {
+	var updatedAt time.Time
+	if a.UpdatedAt != nil {
+		updatedAt = *a.UpdatedAt
+	}
+
+	return AuditoriumResponse{
+		ID:               a.ID,
+		VenueID:          a.VenueID,
+		Name:             a.Name,
+		Capacity:         a.Capacity,
+		Layout:           a.Layout,
+		SupportedFormats: a.SupportedFormats,
+		CreatedAt:        a.CreatedAt,
+		UpdatedAt:        updatedAt,
+	}
+}
+
+// AuditoriumResponse represents the API response for an auditorium.
+type AuditoriumResponse struct {
+	ID               int32       `json:"id"`
+	VenueID          int32       `json:"venue_id"`
+	Name             string      `json:"name"`
+	Capacity         int32       `json:"capacity"`
+	Layout           []LayoutRow `json:"layout"`
+	SupportedFormats []string    `json:"supported_formats"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at,omitempty"`
 }
 
 // CreateVenueRequest represents the request to create a venue.
+// Auditoriums may be created in the same request; otherwise a single
+// auditorium sized by TotalSeats is created for compatibility with single-screen clients.
 type CreateVenueRequest struct {
-	Name       string `json:"name" validate:"required"`
-	Address    string `json:"address" validate:"required"`
-	City       string `json:"city" validate:"required"`
-	TotalSeats int32  `json:"total_seats" validate:"required,min=1"`
+	Name        string                    `json:"name" validate:"required"`
+	Address     string                    `json:"address" validate:"required"`
+	City        string                    `json:"city" validate:"required"`
+	Timezone    string                    `json:"timezone" validate:"omitempty,timezone"`
+	Amenities   []string                  `json:"amenities" validate:"omitempty,dive,required"`
+	TotalSeats  int32                     `json:"total_seats" validate:"required_without=Auditoriums,gte=0"`
+	Auditoriums []CreateAuditoriumRequest `json:"auditoriums" validate:"omitempty,dive"`
+}
+
+// CreateAuditoriumRequest represents the request to add an auditorium to a venue.
+type CreateAuditoriumRequest struct {
+	Name     string      `json:"name" validate:"required"`
+	Capacity int32       `json:"capacity" validate:"required,min=1"`
+	Layout   []LayoutRow `json:"layout" validate:"omitempty,dive"`
+	// SupportedFormats defaults to 2d only when omitted.
+	SupportedFormats []string `json:"supported_formats" validate:"omitempty,dive,oneof=2d 3d imax dolby_atmos"`
+}
+
+// UpdateVenueRequest represents the request to update a venue.
+type UpdateVenueRequest struct {
+	Name      *string  `json:"name" validate:"omitempty,min=1"`
+	Address   *string  `json:"address" validate:"omitempty,min=1"`
+	City      *string  `json:"city" validate:"omitempty,min=1"`
+	Timezone  *string  `json:"timezone" validate:"omitempty,timezone"`
+	Amenities []string `json:"amenities" validate:"omitempty,dive,required"`
+}
+
+// OpeningHours are a venue's regular hours on one day of the week. Times are offsets from
+// local midnight; a ClosesAt at or before OpensAt means the venue closes after midnight.
+type OpeningHours struct {
+	Weekday  time.Weekday
+	OpensAt  time.Duration
+	ClosesAt time.Duration
+}
+
+// window returns the opening and closing instants for these hours on the given local day.
+func (h *OpeningHours) window(day time.Time) (time.Time, time.Time) {
+	at := func(d time.Duration, dayOffset int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day()+dayOffset,
+			int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, day.Location())
+	}
+
+	closesNextDay := 0
+	if h.ClosesAt <= h.OpensAt {
+		closesNextDay = 1
+	}
+	return at(h.OpensAt, 0), at(h.ClosesAt, closesNextDay)
+}
+
+// ToResponse converts OpeningHours to an OpeningHoursResponse.
+func (h *OpeningHours) ToResponse() OpeningHoursResponse {
+	return OpeningHoursResponse{
+		Day:      strings.ToLower(h.Weekday.String()),
+		OpensAt:  formatClock(h.OpensAt),
+		ClosesAt: formatClock(h.ClosesAt),
+	}
+}
+
+// OpeningHoursResponse represents the API response for a day's opening hours.
+type OpeningHoursResponse struct {
+	Day      string `json:"day"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
+
+// SetOpeningHoursRequest replaces a venue's weekly opening hours. Days that are
+// not listed are treated as closed; an empty list removes all restrictions.
+type SetOpeningHoursRequest struct {
+	Hours []OpeningHoursRequest `json:"hours" validate:"dive"`
+}
+
+// OpeningHoursRequest represents the hours for one day of the week.
+type OpeningHoursRequest struct {
+	Day      string `json:"day" validate:"required,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	OpensAt  string `json:"opens_at" validate:"required,datetime=15:04"`
+	ClosesAt string `json:"closes_at" validate:"required,datetime=15:04"`
+}
+
+// toOpeningHours parses the request into OpeningHours.
+func (r *OpeningHoursRequest) toOpeningHours() (OpeningHours, error) {
+	weekday, err := parseWeekday(r.Day)
+	if err != nil {
+		return OpeningHours{}, err
+	}
+	opensAt, err := parseClock(r.OpensAt)
+	if err != nil {
+		return OpeningHours{}, err
+	}
+	closesAt, err := parseClock(r.ClosesAt)
+	if err != nil {
+		return OpeningHours{}, err
+	}
+	return OpeningHours{Weekday: weekday, OpensAt: opensAt, ClosesAt: closesAt}, nil
+}
+
+func parseWeekday(day string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day of week %q", day)
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse(clockLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// Closure is a period during which a venue is shut. Temporary closures (e.g. renovation)
+// hide the venue; exceptional ones (holidays, private events) only block screenings.
+type Closure struct {
+	ID        int32
+	VenueID   int32
+	Kind      string
+	Reason    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedAt time.Time
+}
+
+// Covers reports whether the closure is in effect at t.
+func (c *Closure) Covers(t time.Time) bool {
+	return !t.Before(c.StartsAt) && t.Before(c.EndsAt)
+}
+
+// ToResponse converts a Closure to a ClosureResponse.
+func (c *Closure) ToResponse() ClosureResponse {
+	return ClosureResponse{
+		ID:        c.ID,
+		VenueID:   c.VenueID,
+		Kind:      c.Kind,
+		Reason:    c.Reason,
+		StartsAt:  c.StartsAt,
+		EndsAt:    c.EndsAt,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// ClosureResponse represents the API response for a venue closure.
+type ClosureResponse struct {
+	ID        int32     `json:"id"`
+	VenueID   int32     `json:"venue_id"`
+	Kind      string    `json:"kind"`
+	Reason    string    `json:"reason"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateClosureRequest represents the request to close a venue for a period.
+type CreateClosureRequest struct {
+	// Kind defaults to temporary when omitted.
+	Kind     string `json:"kind" validate:"omitempty,oneof=temporary holiday private_event"`
+	Reason   string `json:"reason" validate:"required"`
+	StartsAt string `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt   string `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
 }
@@ -0,0 +1,80 @@
+package venue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToday(t *testing.T) {
+	v := &Venue{
+		Timezone: "UTC",
+		OpeningHours: []OpeningHours{
+			{Weekday: time.Friday, OpensAt: 18 * time.Hour, ClosesAt: 2 * time.Hour},
+			{Weekday: time.Saturday, OpensAt: 12 * time.Hour, ClosesAt: 23 * time.Hour},
+		},
+	}
+	// 2026-03-14 is a Saturday.
+	at := func(hour int) time.Time { return time.Date(2026, time.March, 14, hour, 0, 0, 0, time.UTC) }
+
+	t.Run("previous day's overnight hours", func(t *testing.T) {
+		got := v.today(at(1))
+		require.Equal(t, &TodayResponse{Date: "2026-03-14", Open: true, OpensAt: "18:00", ClosesAt: "02:00"}, got)
+	})
+
+	t.Run("own hours once overnight hours end", func(t *testing.T) {
+		got := v.today(at(3))
+		require.Equal(t, &TodayResponse{Date: "2026-03-14", Open: true, OpensAt: "12:00", ClosesAt: "23:00"}, got)
+	})
+
+	t.Run("closed on a day without hours", func(t *testing.T) {
+		got := v.today(at(3).AddDate(0, 0, 1))
+		require.Equal(t, &TodayResponse{Date: "2026-03-15"}, got)
+	})
+
+	t.Run("active closure", func(t *testing.T) {
+		closed := *v
+		closed.Closures = []Closure{{Kind: ClosureHoliday, StartsAt: at(0), EndsAt: at(23)}}
+		require.Equal(t, &TodayResponse{Date: "2026-03-14"}, closed.today(at(13)))
+	})
+}
+
+type closuresRepo struct {
+	Repository
+	venues   []Venue
+	closures map[int32][]Closure
+}
+
+func (r *closuresRepo) List(context.Context) ([]Venue, error) { return r.venues, nil }
+
+func (r *closuresRepo) ListOpeningHours(_ context.Context, ids []int32) (map[int32][]OpeningHours, error) {
+	hours := make(map[int32][]OpeningHours, len(ids))
+	for _, id := range ids {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			hours[id] = append(hours[id], OpeningHours{Weekday: d, OpensAt: 0, ClosesAt: 0})
+		}
+	}
+	return hours, nil
+}
+
+func (r *closuresRepo) ListActiveClosures(context.Context, []int32) (map[int32][]Closure, error) {
+	return r.closures, nil
+}
+
+func TestListVenuesReportsActiveClosures(t *testing.T) {
+	now := time.Now()
+	repo := &closuresRepo{
+		venues: []Venue{{ID: 1, Timezone: "UTC"}, {ID: 2, Timezone: "UTC"}},
+		closures: map[int32][]Closure{
+			2: {{VenueID: 2, Kind: ClosureHoliday, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}},
+		},
+	}
+
+	venues, err := NewService(repo).ListVenues(context.Background())
+	require.NoError(t, err)
+	require.Len(t, venues, 2)
+	require.True(t, venues[0].ToResponse().Today.Open)
+	require.False(t, venues[1].ToResponse().Today.Open)
+}
@@ -0,0 +1,24 @@
+-- name: CreateAuditorium :one
+INSERT INTO auditoriums (venue_id, name, capacity, layout, supported_formats)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAuditoriumById :one
+SELECT a.* FROM auditoriums a
+JOIN venues v ON v.id = a.venue_id
+WHERE a.id = $1
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL;
+
+-- name: GetAuditoriumsByVenue :many
+SELECT * FROM auditoriums
+WHERE venue_id = $1 AND deleted_at IS NULL
+ORDER BY name;
+
+-- name: DeleteAuditoriumsByVenue :exec
+UPDATE auditoriums SET deleted_at = now() WHERE venue_id = $1 AND deleted_at IS NULL;
+
+-- restores the auditoriums deleted together with the venue
+-- name: RestoreAuditoriumsByVenue :exec
+UPDATE auditoriums SET deleted_at = NULL
+WHERE venue_id = sqlc.arg('venue_id') AND deleted_at = sqlc.arg('deleted_at');
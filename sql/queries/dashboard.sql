@@ -2,11 +2,12 @@
 SELECT
   COALESCE(SUM(p.amount), 0)::text as total_revenue,
   COALESCE(SUM(r.number_of_seats), 0)::bigint as tickets_sold,
-  COUNT(DISTINCT s.venue_id)::int as active_venues,
+  COUNT(DISTINCT a.venue_id)::int as active_venues,
   (SELECT COUNT(*) FROM movies WHERE deleted_at IS NULL)::int as active_movies
 FROM payments p
 JOIN reservations r ON r.id = p.reservation_id
 JOIN showtimes s ON s.id = r.showtime_id
+JOIN auditoriums a ON a.id = s.auditorium_id
 WHERE p.payment_status = 'completed'
   AND r.deleted_at IS NULL;
 
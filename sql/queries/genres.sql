@@ -0,0 +1,37 @@
+-- name: CreateGenre :one
+INSERT INTO genres (name, slug)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING
+RETURNING *;
+
+-- returns the genre with the given slug, creating it if needed
+-- name: GetOrCreateGenre :one
+INSERT INTO genres (name, slug)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+RETURNING *;
+
+-- name: GetGenres :many
+SELECT * FROM genres ORDER BY name;
+
+-- name: GetGenresByIds :many
+SELECT * FROM genres WHERE id = ANY(sqlc.arg('ids')::int[]);
+
+-- name: DeleteGenre :execrows
+DELETE FROM genres WHERE id = $1;
+
+-- name: DeleteMovieGenres :exec
+DELETE FROM movie_genres WHERE movie_id = $1;
+
+-- name: AddMovieGenre :exec
+INSERT INTO movie_genres (movie_id, genre_id, position)
+VALUES ($1, $2, $3);
+
+-- name: GetGenresByMovies :many
+SELECT mg.movie_id, g.id, g.name, g.slug FROM movie_genres mg
+JOIN genres g ON g.id = mg.genre_id
+WHERE mg.movie_id = ANY(sqlc.arg('movie_ids')::bigint[])
+ORDER BY mg.movie_id, mg.position;
+
+-- name: GetMovieIdsByGenre :many
+SELECT movie_id FROM movie_genres WHERE genre_id = $1;
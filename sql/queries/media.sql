@@ -0,0 +1,28 @@
+-- name: CreateMediaAsset :one
+INSERT INTO media_assets (movie_id, kind, original_filename, original_content_type, width, height)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: CreateMediaVariant :one
+INSERT INTO media_variants (asset_id, name, storage_key, width, height, size_bytes)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetMediaAssetById :one
+SELECT * FROM media_assets WHERE id = $1;
+
+-- name: GetMediaAssetsByMovie :many
+SELECT * FROM media_assets
+WHERE movie_id = $1
+ORDER BY kind, created_at DESC;
+
+-- name: GetMediaVariantsByAssets :many
+SELECT * FROM media_variants
+WHERE asset_id = ANY(sqlc.arg('asset_ids')::bigint[])
+ORDER BY asset_id, width;
+
+-- name: GetMediaVariant :one
+SELECT * FROM media_variants WHERE asset_id = $1 AND name = $2;
+
+-- name: DeleteMediaAsset :execrows
+DELETE FROM media_assets WHERE id = $1;
@@ -0,0 +1,60 @@
+-- name: GetUserTOTP :one
+SELECT * FROM user_totp WHERE user_id = $1;
+
+-- starts or restarts an enrolment, leaving a confirmed authenticator alone
+-- name: UpsertPendingTOTP :execrows
+INSERT INTO user_totp (user_id, secret_ciphertext)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret_ciphertext = EXCLUDED.secret_ciphertext,
+    last_used_step = 0,
+    failed_attempts = 0,
+    locked_until = NULL,
+    created_at = now()
+WHERE user_totp.confirmed_at IS NULL;
+
+-- name: ConfirmUserTOTP :execrows
+UPDATE user_totp
+SET confirmed_at = now()
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- accepts a code's time step only if it is later than the last one accepted, so two
+-- requests racing with the same code cannot both succeed
+-- name: UseTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = sqlc.arg('step'),
+    failed_attempts = 0,
+    locked_until = NULL
+WHERE user_id = sqlc.arg('user_id') AND last_used_step < sqlc.arg('step');
+
+-- counts a wrong code, locking the authenticator once max_attempts are reached in a row
+-- name: RecordTOTPFailure :exec
+UPDATE user_totp
+SET failed_attempts = CASE
+        WHEN failed_attempts + 1 >= sqlc.arg('max_attempts')::int THEN 0
+        ELSE failed_attempts + 1
+    END,
+    locked_until = CASE
+        WHEN failed_attempts + 1 >= sqlc.arg('max_attempts')::int THEN sqlc.arg('lock_until')::timestamptz
+        ELSE locked_until
+    END
+WHERE user_id = sqlc.arg('user_id');
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM user_totp WHERE user_id = $1;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes WHERE user_id = $1;
+
+-- name: InsertRecoveryCodes :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+SELECT sqlc.arg('user_id')::uuid, unnest(sqlc.arg('code_hashes')::varchar[]);
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT count(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
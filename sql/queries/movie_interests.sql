@@ -0,0 +1,22 @@
+-- name: CreateMovieInterest :exec
+INSERT INTO movie_interests (user_id, movie_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, movie_id) DO NOTHING;
+
+-- name: DeleteMovieInterest :execrows
+DELETE FROM movie_interests WHERE user_id = $1 AND movie_id = $2;
+
+-- interested customers who have not yet been told that tickets are on sale
+-- name: GetPendingMovieInterests :many
+SELECT i.user_id, i.movie_id, u.email, u.full_name, i.created_at
+FROM movie_interests i
+JOIN users u ON u.id = i.user_id
+WHERE i.movie_id = $1
+  AND i.notified_at IS NULL
+  AND u.deleted_at IS NULL
+ORDER BY i.created_at;
+
+-- name: MarkMovieInterestsNotified :exec
+UPDATE movie_interests SET notified_at = now()
+WHERE movie_id = sqlc.arg('movie_id')
+  AND user_id = ANY(sqlc.arg('user_ids')::uuid[]);
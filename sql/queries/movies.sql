@@ -1,47 +1,160 @@
 -- name: AddMovie :one
 INSERT INTO movies (
-title,description,runtime,genre,age_rating,director,poster_url,release_date 
-) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+title,description,runtime,genre,age_rating,director,poster_url,release_date,cast_members,external_id
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
 RETURNING *;
 
 -- admin listing , gets all the movies even if they aren't showing.
 -- name: GetMoviesAdmin :many
 SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
-,created_at,updated_at
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
 FROM movies 
 WHERE  deleted_at IS NULL
 ORDER BY release_date DESC 
 LIMIT $1 OFFSET $2;
 
--- name: DeleteMovie :exec
-UPDATE movies SET deleted_at=now() WHERE id=$1;
+-- name: DeleteMovie :execrows
+UPDATE movies SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL;
 
--- public listing: only movies with at least one future showtime
+-- trash: soft-deleted movies, most recently deleted first
+-- name: GetDeletedMovies :many
+SELECT * FROM movies
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT $1 OFFSET $2;
+
+-- name: GetDeletedMovieById :one
+SELECT * FROM movies WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestoreMovie :one
+UPDATE movies SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- public listing: only movies with at least one future showtime, soonest screening first
+-- unless sorted by rating
 -- name: GetMoviesPublic :many
-SELECT DISTINCT m.* FROM movies m
-JOIN showtimes s ON s.movie_id = m.id
+SELECT m.* FROM movies m
+JOIN (
+  SELECT movie_id, MIN(start_time) AS next_start FROM showtimes
+  WHERE start_time > now() AND deleted_at IS NULL
+  GROUP BY movie_id
+) s ON s.movie_id = m.id
 WHERE m.deleted_at IS NULL
-  AND s.start_time > now()
-  AND s.deleted_at IS NULL
-ORDER BY s.start_time ASC
+ORDER BY
+  CASE WHEN sqlc.narg('sort')::text = 'rating' THEN m.rating_average END DESC NULLS LAST,
+  CASE WHEN sqlc.narg('sort')::text = 'rating' THEN m.rating_count END DESC,
+  s.next_start ASC, m.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- announced and coming-soon movies, earliest release first
+-- name: GetMoviesComingSoon :many
+SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+FROM movies
+WHERE deleted_at IS NULL
+  AND movie_status(id, release_date) IN ('announced', 'coming_soon')
+ORDER BY release_date ASC, id
 LIMIT $1 OFFSET $2;
 
 -- name: GetMovieById :one 
 SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
-,created_at,updated_at
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
 FROM movies 
 WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: GetMovieIdByExternalId :one
+SELECT id FROM movies WHERE external_id = $1 AND deleted_at IS NULL;
+
+-- genre, director and cast_members are maintained by SyncMovieCatalogFields
 -- name: UpdateMovie :one
 UPDATE movies SET
   title = COALESCE(sqlc.narg('title'), title),
   description = COALESCE(sqlc.narg('description'), description),
   runtime = COALESCE(sqlc.narg('runtime'), runtime),
-  genre = COALESCE(sqlc.narg('genre'), genre),
   age_rating = COALESCE(sqlc.narg('age_rating'), age_rating),
-  director = COALESCE(sqlc.narg('director'), director),
   poster_url = COALESCE(sqlc.narg('poster_url'), poster_url),
   release_date = COALESCE(sqlc.narg('release_date'), release_date),
+  external_id = COALESCE(sqlc.narg('external_id'), external_id),
   updated_at = now()
 WHERE id = sqlc.arg('id') AND deleted_at IS NULL
 RETURNING *;
+
+-- name: ClearMoviePoster :execrows
+UPDATE movies SET poster_url = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- catalogue search: full-text match on the search document, or a fuzzy trigram match on
+-- the title to tolerate typos. Without a query, results are ordered by release date.
+-- name: SearchMovies :many
+SELECT m.id, m.title, m.description, m.runtime, m.genre, m.age_rating, m.director,
+  m.poster_url, m.release_date, m.cast_members, m.created_at, m.updated_at,
+  movie_status(m.id, m.release_date)::text AS status, m.rating_average, m.rating_count,
+  (CASE WHEN sqlc.narg('query')::text IS NULL THEN 0
+    ELSE ts_rank(movie_search_document(m.title, m.description, m.director, m.cast_members),
+        websearch_to_tsquery('english', sqlc.narg('query')::text))
+      + similarity(m.title, sqlc.narg('query')::text)
+  END)::real AS rank
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND (sqlc.narg('query')::text IS NULL
+    OR movie_search_document(m.title, m.description, m.director, m.cast_members)
+      @@ websearch_to_tsquery('english', sqlc.narg('query')::text)
+    OR m.title % sqlc.narg('query')::text)
+  AND (sqlc.narg('genre')::text IS NULL OR EXISTS (
+    SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND (g.slug = sqlc.narg('genre') OR g.name = sqlc.narg('genre'))))
+  AND (sqlc.narg('age_rating')::text IS NULL OR m.age_rating = sqlc.narg('age_rating'))
+  AND (sqlc.narg('release_year')::int IS NULL
+    OR EXTRACT(YEAR FROM m.release_date)::int = sqlc.narg('release_year'))
+  AND (sqlc.narg('status')::text IS NULL OR movie_status(m.id, m.release_date) = sqlc.narg('status'))
+ORDER BY
+  CASE WHEN sqlc.narg('sort')::text = 'rating' THEN m.rating_average END DESC NULLS LAST,
+  CASE WHEN sqlc.narg('sort')::text = 'rating' THEN m.rating_count END DESC,
+  rank DESC, m.release_date DESC, m.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- facet counts over the whole result set of a catalogue search, ignoring pagination
+-- name: SearchMovieFacets :many
+WITH matches AS (
+  SELECT m.id, m.age_rating,
+    EXTRACT(YEAR FROM m.release_date)::int AS release_year,
+    movie_status(m.id, m.release_date)::text AS status
+  FROM movies m
+  WHERE m.deleted_at IS NULL
+    AND (sqlc.narg('query')::text IS NULL
+      OR movie_search_document(m.title, m.description, m.director, m.cast_members)
+        @@ websearch_to_tsquery('english', sqlc.narg('query')::text)
+      OR m.title % sqlc.narg('query')::text)
+    AND (sqlc.narg('genre')::text IS NULL OR EXISTS (
+      SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+      WHERE mg.movie_id = m.id AND (g.slug = sqlc.narg('genre') OR g.name = sqlc.narg('genre'))))
+    AND (sqlc.narg('age_rating')::text IS NULL OR m.age_rating = sqlc.narg('age_rating'))
+    AND (sqlc.narg('release_year')::int IS NULL
+      OR EXTRACT(YEAR FROM m.release_date)::int = sqlc.narg('release_year'))
+    AND (sqlc.narg('status')::text IS NULL OR movie_status(m.id, m.release_date) = sqlc.narg('status'))
+)
+SELECT 'genre'::text AS facet, g.name::text AS value, COUNT(*) AS count FROM matches
+JOIN movie_genres mg ON mg.movie_id = matches.id
+JOIN genres g ON g.id = mg.genre_id
+GROUP BY g.name
+UNION ALL
+SELECT 'age_rating', age_rating::text, COUNT(*) FROM matches GROUP BY age_rating
+UNION ALL
+SELECT 'release_year', release_year::text, COUNT(*) FROM matches GROUP BY release_year
+UNION ALL
+SELECT 'status', status, COUNT(*) FROM matches GROUP BY status
+ORDER BY facet, count DESC, value;
+
+-- name: GetMoviesByIds :many
+SELECT id,title,description,runtime,genre,age_rating,director,poster_url,release_date
+,cast_members,created_at,updated_at,movie_status(id, release_date)::text AS status
+,rating_average,rating_count
+,external_id
+FROM movies
+WHERE id = ANY(sqlc.arg('ids')::bigint[]) AND deleted_at IS NULL;
@@ -0,0 +1,72 @@
+-- name: CreatePerson :one
+INSERT INTO people (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: GetPersonById :one
+SELECT * FROM people WHERE id = $1;
+
+-- used when free-text names are given for a movie's director or cast
+-- name: GetPersonByName :one
+SELECT * FROM people WHERE name = $1 ORDER BY id LIMIT 1;
+
+-- name: GetPeopleByIds :many
+SELECT * FROM people WHERE id = ANY(sqlc.arg('ids')::bigint[]);
+
+-- name: SearchPeople :many
+SELECT * FROM people
+WHERE sqlc.narg('query')::text IS NULL OR name ILIKE '%' || sqlc.narg('query')::text || '%'
+ORDER BY name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdatePerson :one
+UPDATE people SET
+  name = COALESCE(sqlc.narg('name'), name),
+  updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteMovieCredits :exec
+DELETE FROM movie_credits WHERE movie_id = $1;
+
+-- name: DeleteMovieCreditsByRole :exec
+DELETE FROM movie_credits WHERE movie_id = $1 AND role = $2;
+
+-- name: AddMovieCredit :exec
+INSERT INTO movie_credits (movie_id, person_id, role, character_name, position)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (movie_id, person_id, role) DO NOTHING;
+
+-- name: GetCreditsByMovies :many
+SELECT c.movie_id, c.person_id, p.name, c.role, c.character_name, c.position
+FROM movie_credits c
+JOIN people p ON p.id = c.person_id
+WHERE c.movie_id = ANY(sqlc.arg('movie_ids')::bigint[])
+ORDER BY c.movie_id, c.role, c.position;
+
+-- name: GetMovieIdsByPerson :many
+SELECT DISTINCT movie_id FROM movie_credits WHERE person_id = $1;
+
+-- name: GetFilmography :many
+SELECT m.id, m.title, m.poster_url, m.release_date, c.role, c.character_name
+FROM movie_credits c
+JOIN movies m ON m.id = c.movie_id
+WHERE c.person_id = $1 AND m.deleted_at IS NULL
+ORDER BY m.release_date DESC, c.role;
+
+-- refreshes the denormalized genre, director and cast_members columns from genres and credits
+-- name: SyncMovieCatalogFields :exec
+UPDATE movies m SET
+  genre = COALESCE((
+    SELECT g.name FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id ORDER BY mg.position LIMIT 1), ''),
+  director = COALESCE((
+    SELECT string_agg(p.name, ', ' ORDER BY c.position) FROM movie_credits c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.movie_id = m.id AND c.role = 'director'), ''),
+  cast_members = COALESCE((
+    SELECT array_agg(p.name ORDER BY c.position) FROM movie_credits c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.movie_id = m.id AND c.role = 'cast'), '{}'),
+  updated_at = now()
+WHERE m.id = ANY(sqlc.arg('movie_ids')::bigint[]);
@@ -0,0 +1,96 @@
+-- confirmed bookings per customer and movie
+-- name: GetBookingHistory :many
+SELECT r.user_id, s.movie_id, SUM(r.number_of_seats)::int AS seats
+FROM reservations r
+JOIN showtimes s ON s.id = r.showtime_id
+WHERE r.status = 'confirmed'
+  AND r.deleted_at IS NULL
+GROUP BY r.user_id, s.movie_id;
+
+-- name: GetReviewHistory :many
+SELECT user_id, movie_id, rating FROM reviews WHERE hidden_at IS NULL;
+
+-- movies now showing, with tickets sold for them over the last 30 days
+-- name: GetRecommendationCandidates :many
+SELECT m.id, m.rating_average,
+  COALESCE((
+    SELECT SUM(r.number_of_seats) FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+      AND r.confirmed_at > now() - INTERVAL '30 days'
+  ), 0)::bigint AS tickets_sold
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  );
+
+-- genres and directors of the given movies, the features recommendations are scored on
+-- name: GetMovieFeatures :many
+SELECT movie_id, 'genre'::text AS kind, genre_id::bigint AS feature_id
+FROM movie_genres
+WHERE movie_id = ANY(sqlc.arg('movie_ids')::bigint[])
+UNION ALL
+SELECT movie_id, 'director'::text AS kind, person_id AS feature_id
+FROM movie_credits
+WHERE role = 'director' AND movie_id = ANY(sqlc.arg('movie_ids')::bigint[]);
+
+-- name: DeleteAllRecommendations :exec
+DELETE FROM user_recommendations;
+
+-- name: InsertRecommendations :exec
+INSERT INTO user_recommendations (user_id, movie_id, score)
+SELECT unnest(sqlc.arg('user_ids')::uuid[]), unnest(sqlc.arg('movie_ids')::bigint[]), unnest(sqlc.arg('scores')::float8[]);
+
+-- name: GetRecommendationsComputedAt :one
+SELECT computed_at FROM user_recommendations ORDER BY computed_at DESC LIMIT 1;
+
+-- a customer's scored movies that are still showing, best first
+-- name: GetUserRecommendations :many
+SELECT ur.movie_id, ur.score
+FROM user_recommendations ur
+JOIN movies m ON m.id = ur.movie_id
+WHERE ur.user_id = $1
+  AND m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+ORDER BY ur.score DESC, ur.movie_id
+LIMIT $2;
+
+-- cold-start fallback: movies now showing by tickets sold over the last 30 days, leaving
+-- out those the customer already booked or reviewed, as scoring does
+-- name: GetPopularMovies :many
+SELECT m.id AS movie_id, m.rating_average,
+  COALESCE((
+    SELECT SUM(r.number_of_seats) FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+      AND r.confirmed_at > now() - INTERVAL '30 days'
+  ), 0)::bigint AS tickets_sold
+FROM movies m
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM reservations r
+    JOIN showtimes s ON s.id = r.showtime_id
+    WHERE s.movie_id = m.id
+      AND r.user_id = sqlc.arg('user_id')
+      AND r.status = 'confirmed'
+      AND r.deleted_at IS NULL
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM reviews rv
+    WHERE rv.movie_id = m.id AND rv.user_id = sqlc.arg('user_id') AND rv.hidden_at IS NULL
+  )
+ORDER BY tickets_sold DESC, m.rating_average DESC NULLS LAST, m.id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,30 @@
+-- Cancels the open reservations of the given showtimes and releases their seats, so a
+-- showtime that is later restored can be sold again. Completed payments for the cancelled
+-- reservations are marked refund_due for the payments team to refund.
+-- name: CancelReservationsByShowtimes :exec
+WITH cancelled AS (
+  UPDATE reservations SET status = 'cancelled'
+  WHERE showtime_id = ANY(sqlc.arg('showtime_ids')::bigint[])
+    AND status IN ('pending', 'confirmed')
+    AND deleted_at IS NULL
+  RETURNING id, showtime_id, number_of_seats
+),
+released AS (
+  UPDATE seats
+  SET reservation_id = NULL, reserved_at = NULL, expires_at = NULL, confirmed_at = NULL
+  WHERE reservation_id IN (SELECT id FROM cancelled)
+),
+refunds AS (
+  UPDATE payments
+  SET payment_status = 'refund_due', updated_at = now()
+  WHERE reservation_id IN (SELECT id FROM cancelled)
+    AND payment_status = 'completed'
+    AND deleted_at IS NULL
+)
+UPDATE showtimes s SET available_seats = s.available_seats + c.seats
+FROM (
+  SELECT showtime_id, SUM(number_of_seats)::int AS seats
+  FROM cancelled
+  GROUP BY showtime_id
+) c
+WHERE s.id = c.showtime_id;
@@ -0,0 +1,82 @@
+-- name: CreateReview :one
+INSERT INTO reviews (movie_id, user_id, rating, title, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetReviewById :one
+SELECT r.*, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE r.id = $1;
+
+-- name: GetReviewByMovieAndUser :one
+SELECT * FROM reviews WHERE movie_id = $1 AND user_id = $2;
+
+-- visible reviews of a movie, newest first
+-- name: GetReviewsByMovie :many
+SELECT r.*, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE r.movie_id = $1 AND r.hidden_at IS NULL
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $2 OFFSET $3;
+
+-- moderation queue: all reviews, optionally narrowed to a movie, hidden or flagged reviews
+-- name: GetReviewsAdmin :many
+SELECT r.*, u.full_name AS author_name
+FROM reviews r
+JOIN users u ON u.id = r.user_id
+WHERE (sqlc.narg('movie_id')::bigint IS NULL OR r.movie_id = sqlc.narg('movie_id'))
+  AND (sqlc.narg('hidden')::boolean IS NULL OR (r.hidden_at IS NOT NULL) = sqlc.narg('hidden'))
+  AND (sqlc.narg('flagged')::boolean IS NULL OR (r.flagged_at IS NOT NULL) = sqlc.narg('flagged'))
+ORDER BY r.flagged_at DESC NULLS LAST, r.created_at DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateReview :one
+UPDATE reviews SET
+  rating = COALESCE(sqlc.narg('rating'), rating),
+  title = COALESCE(sqlc.narg('title'), title),
+  body = COALESCE(sqlc.narg('body'), body),
+  updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteReview :one
+DELETE FROM reviews WHERE id = $1
+RETURNING movie_id;
+
+-- name: HideReview :one
+UPDATE reviews SET hidden_at = now(), hidden_reason = $2 WHERE id = $1
+RETURNING movie_id;
+
+-- name: UnhideReview :one
+UPDATE reviews SET hidden_at = NULL, hidden_reason = NULL WHERE id = $1
+RETURNING movie_id;
+
+-- name: FlagReview :one
+UPDATE reviews SET flagged_at = now(), flag_reason = $2 WHERE id = $1
+RETURNING movie_id;
+
+-- name: UnflagReview :one
+UPDATE reviews SET flagged_at = NULL, flag_reason = NULL WHERE id = $1
+RETURNING movie_id;
+
+-- a customer attended a movie if they hold a confirmed reservation for a showtime that has ended
+-- name: HasAttendedMovie :one
+SELECT EXISTS (
+  SELECT 1 FROM reservations r
+  JOIN showtimes s ON s.id = r.showtime_id
+  WHERE r.user_id = $1
+    AND s.movie_id = $2
+    AND r.status = 'confirmed'
+    AND r.deleted_at IS NULL
+    AND s.end_time < now()
+);
+
+-- name: SyncMovieRating :exec
+UPDATE movies m SET
+  rating_average = (SELECT AVG(r.rating)::float8 FROM reviews r
+    WHERE r.movie_id = m.id AND r.hidden_at IS NULL),
+  rating_count = (SELECT COUNT(*) FROM reviews r
+    WHERE r.movie_id = m.id AND r.hidden_at IS NULL)
+WHERE m.id = $1;
@@ -0,0 +1,42 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, current_token_hash, device, ip_address, user_agent, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetSessionById :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- replaces the session's refresh token, only if it is still the one presented, so two
+-- refreshes racing with the same token cannot both rotate
+-- name: RotateSession :execrows
+UPDATE sessions
+SET previous_token_hash = current_token_hash,
+    current_token_hash = sqlc.arg('new_token_hash'),
+    rotated_at = now(),
+    last_seen_at = now(),
+    ip_address = sqlc.arg('ip_address'),
+    user_agent = sqlc.arg('user_agent'),
+    expires_at = sqlc.arg('expires_at')
+WHERE id = sqlc.arg('id')
+  AND current_token_hash = sqlc.arg('token_hash')
+  AND revoked_at IS NULL;
+
+-- name: GetActiveSessionsByUser :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_seen_at DESC;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $2
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :execrows
+UPDATE sessions
+SET revoked_at = now(), revoked_reason = $2
+WHERE user_id = $1 AND revoked_at IS NULL;
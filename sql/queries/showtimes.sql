@@ -1,25 +1,45 @@
 -- name: CreateShowtime :one
-INSERT INTO showtimes (movie_id, start_time, end_time, available_seats, price_per_seat, venue_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO showtimes (
+  movie_id, start_time, end_time, available_seats, price_per_seat, auditorium_id,
+  format, audio_language, subtitle_language, is_dubbed, audio_described, relaxed_screening
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING *;
 
 -- name: GetShowtimeById :one
-SELECT * FROM showtimes WHERE id = $1 AND deleted_at IS NULL;
+SELECT s.*, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  v.name as venue_name, v.city as venue_city
+FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN movies m ON m.id = s.movie_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.id = $1
+  AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL;
 
 -- name: GetShowtimesByMovie :many
-SELECT s.*, v.name as venue_name, v.city as venue_city
+SELECT s.*, a.venue_id, a.name as auditorium_name, v.name as venue_name, v.city as venue_city
 FROM showtimes s
-JOIN venues v ON v.id = s.venue_id
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
 WHERE s.movie_id = $1
   AND s.start_time > now()
   AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
 ORDER BY s.start_time ASC;
 
 -- name: GetShowtimesAdmin :many
-SELECT s.*, m.title as movie_title, v.name as venue_name
+SELECT s.*, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name
 FROM showtimes s
 JOIN movies m ON m.id = s.movie_id
-JOIN venues v ON v.id = s.venue_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
 WHERE s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT $1 OFFSET $2;
@@ -30,10 +50,119 @@ UPDATE showtimes SET
   end_time = COALESCE(sqlc.narg('end_time'), end_time),
   available_seats = COALESCE(sqlc.narg('available_seats'), available_seats),
   price_per_seat = COALESCE(sqlc.narg('price_per_seat'), price_per_seat),
-  venue_id = COALESCE(sqlc.narg('venue_id'), venue_id),
+  auditorium_id = COALESCE(sqlc.narg('auditorium_id'), auditorium_id),
+  format = COALESCE(sqlc.narg('format'), format),
+  audio_language = COALESCE(sqlc.narg('audio_language'), audio_language),
+  subtitle_language = COALESCE(sqlc.narg('subtitle_language'), subtitle_language),
+  is_dubbed = COALESCE(sqlc.narg('is_dubbed'), is_dubbed),
+  audio_described = COALESCE(sqlc.narg('audio_described'), audio_described),
+  relaxed_screening = COALESCE(sqlc.narg('relaxed_screening'), relaxed_screening),
   updated_at = now()
 WHERE id = sqlc.arg('id') AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteShowtime :exec
-UPDATE showtimes SET deleted_at = now() WHERE id = $1;
+-- name: DeleteShowtime :execrows
+UPDATE showtimes SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: CountFutureShowtimesByMovie :one
+SELECT COUNT(*) FROM showtimes
+WHERE movie_id = $1
+  AND start_time > now()
+  AND deleted_at IS NULL;
+
+-- name: DeleteFutureShowtimesByMovie :many
+UPDATE showtimes SET deleted_at = now()
+WHERE movie_id = $1
+  AND start_time > now()
+  AND deleted_at IS NULL
+RETURNING id;
+
+-- Cascaded deletes share their parent's deleted_at, as now() is fixed for the transaction,
+-- so restoring the parent brings back exactly the showtimes it took down. Showtimes that
+-- have since started stay deleted.
+-- name: RestoreFutureShowtimesByMovie :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE movie_id = sqlc.arg('movie_id')
+  AND showtimes.deleted_at = sqlc.arg('deleted_at')
+  AND start_time > now()
+  AND auditorium_id IN (SELECT id FROM auditoriums WHERE deleted_at IS NULL);
+
+-- name: RestoreFutureShowtimesByVenue :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = sqlc.arg('venue_id') AND deleted_at IS NULL)
+  AND showtimes.deleted_at = sqlc.arg('deleted_at')
+  AND start_time > now()
+  AND movie_id IN (SELECT id FROM movies WHERE deleted_at IS NULL);
+
+-- trash: soft-deleted showtimes, most recently deleted first. parent_deleted is set when
+-- the movie, auditorium or venue is deleted too and must be restored first.
+-- name: GetDeletedShowtimes :many
+SELECT s.*, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name,
+  (m.deleted_at IS NOT NULL OR a.deleted_at IS NOT NULL OR v.deleted_at IS NOT NULL)::boolean AS parent_deleted
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.deleted_at IS NOT NULL
+ORDER BY s.deleted_at DESC, s.id
+LIMIT $1 OFFSET $2;
+
+-- name: GetDeletedShowtimeById :one
+SELECT s.*, a.venue_id, a.name as auditorium_name, m.title as movie_title, v.name as venue_name,
+  (m.deleted_at IS NOT NULL OR a.deleted_at IS NOT NULL OR v.deleted_at IS NOT NULL)::boolean AS parent_deleted
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.id = $1 AND s.deleted_at IS NOT NULL;
+
+-- name: RestoreShowtime :exec
+UPDATE showtimes SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- public listing with optional filters, only future showtimes of live movies and venues
+-- name: ListShowtimes :many
+SELECT s.*, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  v.name as venue_name, v.city as venue_city
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+JOIN venues v ON v.id = a.venue_id
+WHERE s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND v.deleted_at IS NULL
+  AND s.start_time > now()
+  AND (sqlc.narg('venue_id')::int IS NULL OR a.venue_id = sqlc.narg('venue_id'))
+  AND (sqlc.narg('city')::text IS NULL OR v.city = sqlc.narg('city'))
+  AND (sqlc.narg('date_from')::date IS NULL OR (s.start_time AT TIME ZONE v.timezone)::date >= sqlc.narg('date_from'))
+  AND (sqlc.narg('date_to')::date IS NULL OR (s.start_time AT TIME ZONE v.timezone)::date <= sqlc.narg('date_to'))
+  AND (sqlc.narg('genre')::text IS NULL OR EXISTS (
+    SELECT 1 FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND (g.slug = sqlc.narg('genre') OR g.name = sqlc.narg('genre'))))
+  AND (sqlc.narg('age_rating')::text IS NULL OR m.age_rating = sqlc.narg('age_rating'))
+  AND (sqlc.narg('min_seats')::int IS NULL OR s.available_seats >= sqlc.narg('min_seats'))
+  AND (sqlc.narg('format')::text IS NULL OR s.format = sqlc.narg('format'))
+  AND (sqlc.narg('audio_language')::text IS NULL OR s.audio_language = sqlc.narg('audio_language'))
+  AND (sqlc.narg('subtitle_language')::text IS NULL OR s.subtitle_language = sqlc.narg('subtitle_language'))
+  AND (sqlc.narg('audio_described')::boolean IS NULL OR s.audio_described = sqlc.narg('audio_described'))
+  AND (sqlc.narg('relaxed_screening')::boolean IS NULL OR s.relaxed_screening = sqlc.narg('relaxed_screening'))
+ORDER BY s.start_time ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- a venue's programme for a time window, ordered so rows can be grouped by movie
+-- name: GetVenueProgramme :many
+SELECT s.*, a.venue_id, a.name as auditorium_name,
+  m.title as movie_title, m.genre as movie_genre, m.age_rating as movie_age_rating,
+  m.runtime as movie_runtime, m.poster_url as movie_poster_url
+FROM showtimes s
+JOIN movies m ON m.id = s.movie_id
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = sqlc.arg('venue_id')
+  AND s.deleted_at IS NULL
+  AND m.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+  AND s.start_time >= sqlc.arg('window_start')
+  AND s.start_time < sqlc.arg('window_end')
+ORDER BY m.title ASC, s.movie_id ASC, s.start_time ASC;
@@ -0,0 +1,40 @@
+-- sales velocity and occupancy of every movie now showing. Velocity sums the confirmed
+-- seats sold over the window, each decayed by its age so recent sales count the most;
+-- occupancy covers the movie's upcoming showtimes, as the share of each auditorium's
+-- capacity no longer available.
+-- name: GetTrendingSignals :many
+WITH sales AS (
+  SELECT s.movie_id,
+    SUM(r.number_of_seats)::bigint AS tickets_sold,
+    SUM(r.number_of_seats * exp(
+      -ln(2) * EXTRACT(EPOCH FROM now() - r.confirmed_at) / 3600 / sqlc.arg('half_life_hours')::float8
+    ))::float8 AS velocity
+  FROM reservations r
+  JOIN showtimes s ON s.id = r.showtime_id
+  WHERE r.status = 'confirmed'
+    AND r.deleted_at IS NULL
+    AND r.confirmed_at > now() - sqlc.arg('window_days')::int * INTERVAL '1 day'
+  GROUP BY s.movie_id
+),
+occupancy AS (
+  SELECT s.movie_id,
+    SUM(GREATEST(a.capacity - s.available_seats, 0))::bigint AS seats_sold,
+    SUM(a.capacity)::bigint AS seats_total
+  FROM showtimes s
+  JOIN auditoriums a ON a.id = s.auditorium_id
+  WHERE s.start_time > now() AND s.deleted_at IS NULL
+  GROUP BY s.movie_id
+)
+SELECT m.id AS movie_id,
+  COALESCE(sa.tickets_sold, 0)::bigint AS tickets_sold,
+  COALESCE(sa.velocity, 0)::float8 AS velocity,
+  COALESCE(o.seats_sold, 0)::bigint AS seats_sold,
+  COALESCE(o.seats_total, 0)::bigint AS seats_total
+FROM movies m
+LEFT JOIN sales sa ON sa.movie_id = m.id
+LEFT JOIN occupancy o ON o.movie_id = m.id
+WHERE m.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM showtimes s
+    WHERE s.movie_id = m.id AND s.start_time > now() AND s.deleted_at IS NULL
+  );
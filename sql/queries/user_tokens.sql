@@ -0,0 +1,25 @@
+-- name: CreateUserToken :exec
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- retires the user's outstanding tokens for a purpose, so only the newest one works
+-- name: InvalidateUserTokens :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
+
+-- name: GetLatestUserTokenCreatedAt :one
+SELECT created_at FROM user_tokens
+WHERE user_id = $1 AND purpose = $2
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- marks an unused, unexpired token used, returning whose it was
+-- name: ConsumeUserToken :one
+UPDATE user_tokens
+SET used_at = now()
+WHERE token_hash = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id;
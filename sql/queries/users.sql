@@ -11,7 +11,7 @@ RETURNING *;
 UPDATE users 
 SET password_hash = $2, 
     updated_at = now() 
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: GetUserByEmail :one 
@@ -52,3 +52,47 @@ JOIN user_identities ui ON u.id = ui.user_id
 WHERE ui.provider = $1 
   AND ui.provider_user_id = $2 
   AND u.deleted_at IS NULL;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET full_name = COALESCE(sqlc.narg('full_name'), full_name),
+    telephone_number = COALESCE(sqlc.narg('telephone_number'), telephone_number),
+    date_of_birth = COALESCE(sqlc.narg('date_of_birth'), date_of_birth),
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: MarkUserVerified :one
+UPDATE users
+SET verified_at = COALESCE(verified_at, now()),
+    updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserIdentity :one
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2 AND provider_user_id = $3
+RETURNING *;
+
+-- name: ListUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: DeleteUserIdentityByID :one
+DELETE FROM user_identities
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: CountUserIdentities :one
+SELECT count(*) FROM user_identities WHERE user_id = $1;
+
+-- serialises changes to a user's identities, so concurrent unlinks cannot remove the last one
+-- name: LockUser :one
+SELECT id FROM users WHERE id = $1 FOR UPDATE;
+
+-- name: ClearUserPassword :exec
+UPDATE users
+SET password_hash = NULL,
+    updated_at = now()
+WHERE id = $1;
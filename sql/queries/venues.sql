@@ -1,10 +1,132 @@
 -- name: CreateVenue :one
-INSERT INTO venues (name, address, city, total_seats)
-VALUES ($1, $2, $3, $4)
+INSERT INTO venues (name, address, city, timezone, amenities)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- public listing: total_seats is the combined capacity of the venue's auditoriums,
+-- venues under an active temporary closure are hidden
 -- name: GetVenues :many
-SELECT * FROM venues WHERE deleted_at IS NULL ORDER BY name;
+SELECT v.*,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM venue_closures c
+    WHERE c.venue_id = v.id AND c.kind = 'temporary'
+      AND c.starts_at <= now() AND c.ends_at > now()
+  )
+ORDER BY v.name;
+
+-- admin listing: includes venues that are temporarily closed
+-- name: GetVenuesAdmin :many
+SELECT v.*,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.deleted_at IS NULL
+ORDER BY v.name;
 
 -- name: GetVenueById :one
-SELECT * FROM venues WHERE id = $1 AND deleted_at IS NULL;
+SELECT v.*,
+  COALESCE((SELECT SUM(a.capacity) FROM auditoriums a
+    WHERE a.venue_id = v.id AND a.deleted_at IS NULL), 0)::int AS total_seats
+FROM venues v
+WHERE v.id = $1 AND v.deleted_at IS NULL;
+
+-- name: UpdateVenue :one
+UPDATE venues SET
+  name = COALESCE(sqlc.narg('name'), name),
+  address = COALESCE(sqlc.narg('address'), address),
+  city = COALESCE(sqlc.narg('city'), city),
+  timezone = COALESCE(sqlc.narg('timezone'), timezone),
+  amenities = COALESCE(sqlc.narg('amenities')::varchar[], amenities),
+  updated_at = now()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteVenue :execrows
+UPDATE venues SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;
+
+-- trash: soft-deleted venues, most recently deleted first
+-- name: GetDeletedVenues :many
+SELECT * FROM venues
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT $1 OFFSET $2;
+
+-- name: GetDeletedVenueById :one
+SELECT * FROM venues WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestoreVenue :exec
+UPDATE venues SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: CountFutureShowtimesByVenue :one
+SELECT COUNT(*) FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = $1
+  AND s.start_time > now()
+  AND s.deleted_at IS NULL;
+
+-- name: DeleteFutureShowtimesByVenue :many
+UPDATE showtimes SET deleted_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = $1)
+  AND start_time > now()
+  AND deleted_at IS NULL
+RETURNING id;
+
+-- name: CreateVenueClosure :one
+INSERT INTO venue_closures (venue_id, kind, reason, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- closures that have not yet ended
+-- name: GetVenueClosures :many
+SELECT * FROM venue_closures
+WHERE venue_id = $1 AND ends_at > now()
+ORDER BY starts_at ASC;
+
+-- closures in effect right now, for venue listings
+-- name: GetActiveClosuresByVenues :many
+SELECT * FROM venue_closures
+WHERE venue_id = ANY(sqlc.arg('venue_ids')::int[])
+  AND starts_at <= now() AND ends_at > now()
+ORDER BY venue_id, starts_at;
+
+-- name: DeleteVenueClosure :execrows
+DELETE FROM venue_closures WHERE id = $1 AND venue_id = $2;
+
+-- name: CountOverlappingClosures :one
+SELECT COUNT(*) FROM venue_closures
+WHERE venue_id = sqlc.arg('venue_id')
+  AND starts_at < sqlc.arg('range_end')
+  AND ends_at > sqlc.arg('range_start');
+
+-- name: CountShowtimesInRangeByVenue :one
+SELECT COUNT(*) FROM showtimes s
+JOIN auditoriums a ON a.id = s.auditorium_id
+WHERE a.venue_id = sqlc.arg('venue_id')
+  AND s.deleted_at IS NULL
+  AND s.start_time < sqlc.arg('range_end')
+  AND s.end_time > sqlc.arg('range_start');
+
+-- name: DeleteShowtimesInRangeByVenue :many
+UPDATE showtimes SET deleted_at = now()
+WHERE auditorium_id IN (SELECT id FROM auditoriums WHERE venue_id = sqlc.arg('venue_id'))
+  AND deleted_at IS NULL
+  AND start_time < sqlc.arg('range_end')
+  AND end_time > sqlc.arg('range_start')
+RETURNING id;
+
+-- name: GetOpeningHoursByVenues :many
+SELECT * FROM venue_opening_hours
+WHERE venue_id = ANY(sqlc.arg('venue_ids')::int[])
+ORDER BY venue_id, day_of_week;
+
+-- name: DeleteVenueOpeningHours :exec
+DELETE FROM venue_opening_hours WHERE venue_id = $1;
+
+-- name: CreateVenueOpeningHours :exec
+INSERT INTO venue_opening_hours (venue_id, day_of_week, opens_at, closes_at)
+VALUES ($1, $2, $3, $4);
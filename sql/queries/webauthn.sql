@@ -0,0 +1,34 @@
+-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (id, user_id, name, credential)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetWebAuthnCredential :one
+SELECT * FROM webauthn_credentials WHERE id = $1;
+
+-- name: ListWebAuthnCredentialsByUser :many
+SELECT * FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- stores the sign counter and flags an assertion moved the credential to
+-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET credential = $2, last_used_at = now()
+WHERE id = $1;
+
+-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2;
+
+-- name: CreateWebAuthnCeremony :exec
+INSERT INTO webauthn_ceremonies (id, user_id, kind, session_data, credential_name, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: DeleteExpiredWebAuthnCeremonies :exec
+DELETE FROM webauthn_ceremonies WHERE expires_at <= now();
+
+-- removes a ceremony as it is finished, so its challenge can only be answered once
+-- name: TakeWebAuthnCeremony :one
+DELETE FROM webauthn_ceremonies
+WHERE id = $1 AND kind = $2 AND expires_at > now()
+RETURNING *;
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE showtimes
+    ADD COLUMN format VARCHAR NOT NULL DEFAULT '2d', -- 2d, 3d, imax, dolby_atmos
+    ADD COLUMN audio_language VARCHAR(35), -- BCP 47 tag, NULL means the original language
+    ADD COLUMN subtitle_language VARCHAR(35),
+    ADD COLUMN is_dubbed BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN audio_described BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN relaxed_screening BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE showtimes ADD CONSTRAINT chk_showtime_format
+    CHECK (format IN ('2d', '3d', 'imax', 'dolby_atmos'));
+
+CREATE INDEX idx_showtimes_format ON showtimes(format);
+
+ALTER TABLE venues
+    ADD COLUMN supported_formats VARCHAR[] NOT NULL DEFAULT '{2d}';
+
+ALTER TABLE venues ADD CONSTRAINT chk_venue_supported_formats
+    CHECK (supported_formats <@ ARRAY['2d', '3d', 'imax', 'dolby_atmos']::VARCHAR[]);
+
+-- +goose Down
+ALTER TABLE venues DROP CONSTRAINT chk_venue_supported_formats;
+ALTER TABLE venues DROP COLUMN supported_formats;
+DROP INDEX idx_showtimes_format;
+ALTER TABLE showtimes DROP CONSTRAINT chk_showtime_format;
+ALTER TABLE showtimes
+    DROP COLUMN format,
+    DROP COLUMN audio_language,
+    DROP COLUMN subtitle_language,
+    DROP COLUMN is_dubbed,
+    DROP COLUMN audio_described,
+    DROP COLUMN relaxed_screening;
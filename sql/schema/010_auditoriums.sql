@@ -0,0 +1,74 @@
+-- +goose Up
+-- A venue is now a cinema site; each screen within it is an auditorium.
+CREATE TABLE IF NOT EXISTS auditoriums(
+    id SERIAL PRIMARY KEY,
+    venue_id INT NOT NULL REFERENCES venues(id),
+    name VARCHAR NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    layout JSONB NOT NULL DEFAULT '[]', -- [{"row": "A", "seats": 12}, ...]
+    supported_formats VARCHAR[] NOT NULL DEFAULT '{2d}',
+    created_at timestamptz NOT NULL DEFAULT (now()),
+    updated_at timestamptz DEFAULT (now()),
+    deleted_at TIMESTAMPTZ,
+    UNIQUE(venue_id, name)
+);
+CREATE INDEX idx_auditoriums_venue_id ON auditoriums(venue_id);
+ALTER TABLE auditoriums ADD CONSTRAINT chk_auditorium_supported_formats
+    CHECK (supported_formats <@ ARRAY['2d', '3d', 'imax', 'dolby_atmos']::VARCHAR[]);
+
+ALTER TABLE venues
+    ADD COLUMN timezone VARCHAR NOT NULL DEFAULT 'UTC',
+    ADD COLUMN amenities VARCHAR[] NOT NULL DEFAULT '{}';
+
+-- Every existing venue becomes a site with a single auditorium.
+INSERT INTO auditoriums (venue_id, name, capacity, supported_formats, created_at)
+SELECT id, 'Screen 1', total_seats, supported_formats, created_at FROM venues;
+
+ALTER TABLE showtimes ADD COLUMN auditorium_id INT REFERENCES auditoriums(id);
+UPDATE showtimes s SET auditorium_id = a.id
+FROM auditoriums a
+WHERE a.venue_id = s.venue_id;
+ALTER TABLE showtimes ALTER COLUMN auditorium_id SET NOT NULL;
+CREATE INDEX idx_showtimes_auditorium_id ON showtimes(auditorium_id);
+
+DROP INDEX idx_showtimes_venue_id;
+ALTER TABLE showtimes DROP COLUMN venue_id;
+ALTER TABLE venues DROP CONSTRAINT chk_venue_supported_formats;
+ALTER TABLE venues
+    DROP COLUMN total_seats,
+    DROP COLUMN supported_formats;
+
+-- +goose Down
+ALTER TABLE venues
+    ADD COLUMN total_seats INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN supported_formats VARCHAR[] NOT NULL DEFAULT '{2d}';
+UPDATE venues v SET
+    total_seats = agg.capacity,
+    supported_formats = agg.formats
+FROM (
+    SELECT a.venue_id,
+        SUM(a.capacity)::int AS capacity,
+        ARRAY(
+            SELECT DISTINCT f FROM auditoriums a2, unnest(a2.supported_formats) f
+            WHERE a2.venue_id = a.venue_id
+        )::VARCHAR[] AS formats
+    FROM auditoriums a
+    GROUP BY a.venue_id
+) agg
+WHERE agg.venue_id = v.id;
+ALTER TABLE venues ADD CONSTRAINT chk_venue_supported_formats
+    CHECK (supported_formats <@ ARRAY['2d', '3d', 'imax', 'dolby_atmos']::VARCHAR[]);
+
+ALTER TABLE showtimes ADD COLUMN venue_id INT REFERENCES venues(id);
+UPDATE showtimes s SET venue_id = a.venue_id
+FROM auditoriums a
+WHERE a.id = s.auditorium_id;
+ALTER TABLE showtimes ALTER COLUMN venue_id SET NOT NULL;
+CREATE INDEX idx_showtimes_venue_id ON showtimes(venue_id);
+DROP INDEX idx_showtimes_auditorium_id;
+ALTER TABLE showtimes DROP COLUMN auditorium_id;
+
+ALTER TABLE venues
+    DROP COLUMN timezone,
+    DROP COLUMN amenities;
+DROP TABLE auditoriums;
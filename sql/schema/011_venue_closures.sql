@@ -0,0 +1,16 @@
+-- +goose Up
+-- Temporary closures (e.g. renovation) hide a venue and block new showtimes while active.
+CREATE TABLE IF NOT EXISTS venue_closures(
+    id SERIAL PRIMARY KEY,
+    venue_id INT NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+    reason VARCHAR NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+CREATE INDEX idx_venue_closures_venue_id ON venue_closures(venue_id);
+ALTER TABLE venue_closures ADD CONSTRAINT chk_venue_closure_times
+    CHECK (ends_at > starts_at);
+
+-- +goose Down
+DROP TABLE venue_closures;
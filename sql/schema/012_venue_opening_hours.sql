@@ -0,0 +1,22 @@
+-- +goose Up
+-- Regular weekly opening hours. A closing time at or before the opening time means the
+-- venue closes after midnight. Venues without any rows are treated as always open.
+CREATE TABLE IF NOT EXISTS venue_opening_hours(
+    venue_id INT NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL,
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (venue_id, day_of_week)
+);
+ALTER TABLE venue_opening_hours ADD CONSTRAINT chk_venue_opening_hours_day
+    CHECK (day_of_week BETWEEN 0 AND 6);
+
+-- Exceptional closures (holidays, private events) block screenings without hiding the venue.
+ALTER TABLE venue_closures ADD COLUMN kind VARCHAR NOT NULL DEFAULT 'temporary';
+ALTER TABLE venue_closures ADD CONSTRAINT chk_venue_closure_kind
+    CHECK (kind IN ('temporary', 'holiday', 'private_event'));
+
+-- +goose Down
+ALTER TABLE venue_closures DROP CONSTRAINT chk_venue_closure_kind;
+ALTER TABLE venue_closures DROP COLUMN kind;
+DROP TABLE venue_opening_hours;
@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE movies ADD COLUMN cast_members VARCHAR[] NOT NULL DEFAULT '{}';
+
+-- The weighted search document over title, cast, director and description. Declared
+-- IMMUTABLE so it can back an expression index; it only depends on its arguments.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION movie_search_document(
+    title VARCHAR, description VARCHAR, director VARCHAR, cast_members VARCHAR[]
+) RETURNS tsvector
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT setweight(to_tsvector('english', title), 'A') ||
+           setweight(to_tsvector('english', array_to_string(cast_members, ' ')), 'B') ||
+           setweight(to_tsvector('english', director), 'B') ||
+           setweight(to_tsvector('english', description), 'C')
+$$;
+-- +goose StatementEnd
+
+-- now_showing: at least one future showtime; coming_soon: not yet released.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION movie_status(movie_id BIGINT, release_date DATE) RETURNS VARCHAR
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN EXISTS (
+            SELECT 1 FROM showtimes s
+            WHERE s.movie_id = movie_status.movie_id
+              AND s.start_time > now()
+              AND s.deleted_at IS NULL
+        ) THEN 'now_showing'
+        WHEN release_date > CURRENT_DATE THEN 'coming_soon'
+        ELSE 'not_showing'
+    END
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX idx_movies_search ON movies
+    USING GIN (movie_search_document(title, description, director, cast_members));
+CREATE INDEX idx_movies_title_trgm ON movies USING GIN (title gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_movies_title_trgm;
+DROP INDEX idx_movies_search;
+DROP FUNCTION movie_status(BIGINT, DATE);
+DROP FUNCTION movie_search_document(VARCHAR, VARCHAR, VARCHAR, VARCHAR[]);
+ALTER TABLE movies DROP COLUMN cast_members;
@@ -0,0 +1,75 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS genres(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    slug VARCHAR NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS movie_genres(
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (movie_id, genre_id)
+);
+CREATE INDEX idx_movie_genres_genre_id ON movie_genres(genre_id);
+
+CREATE TABLE IF NOT EXISTS people(
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMPTZ DEFAULT (now())
+);
+CREATE INDEX idx_people_name_trgm ON people USING GIN (name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS movie_credits(
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    person_id BIGINT NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    role VARCHAR NOT NULL,
+    character_name VARCHAR,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (movie_id, person_id, role)
+);
+CREATE INDEX idx_movie_credits_person_id ON movie_credits(person_id);
+ALTER TABLE movie_credits ADD CONSTRAINT chk_movie_credit_role
+    CHECK (role IN ('director', 'cast', 'writer'));
+
+-- Backfill from the free-text columns. movies.genre, director and cast_members are kept as
+-- denormalized copies (primary genre, joined director names, billed cast) for search and display.
+INSERT INTO genres (name, slug)
+SELECT DISTINCT ON (slug) name, slug FROM (
+    SELECT trim(genre) AS name,
+        trim(BOTH '-' FROM regexp_replace(lower(trim(genre)), '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM movies
+) g
+WHERE slug <> ''
+ORDER BY slug, name;
+
+INSERT INTO movie_genres (movie_id, genre_id, position)
+SELECT m.id, g.id, 0 FROM movies m
+JOIN genres g ON g.slug = trim(BOTH '-' FROM regexp_replace(lower(trim(m.genre)), '[^a-z0-9]+', '-', 'g'));
+
+INSERT INTO people (name)
+SELECT DISTINCT name FROM (
+    SELECT trim(director) AS name FROM movies
+    UNION
+    SELECT trim(unnest(cast_members)) FROM movies
+) p
+WHERE name <> '';
+
+INSERT INTO movie_credits (movie_id, person_id, role, position)
+SELECT m.id, p.id, 'director', 0 FROM movies m
+JOIN people p ON p.name = trim(m.director);
+
+INSERT INTO movie_credits (movie_id, person_id, role, position)
+SELECT m.id, p.id, 'cast', (c.ord - 1)::int
+FROM movies m
+CROSS JOIN LATERAL unnest(m.cast_members) WITH ORDINALITY AS c(name, ord)
+JOIN people p ON p.name = trim(c.name)
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE movie_credits;
+DROP TABLE people;
+DROP TABLE movie_genres;
+DROP TABLE genres;
@@ -0,0 +1,50 @@
+-- +goose Up
+-- now_showing: tickets are on sale (at least one future showtime).
+-- coming_soon: releases within the next eight weeks; announced: further out.
+-- archived: released and no longer scheduled.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION movie_status(movie_id BIGINT, release_date DATE) RETURNS VARCHAR
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN EXISTS (
+            SELECT 1 FROM showtimes s
+            WHERE s.movie_id = movie_status.movie_id
+              AND s.start_time > now()
+              AND s.deleted_at IS NULL
+        ) THEN 'now_showing'
+        WHEN release_date > CURRENT_DATE + 56 THEN 'announced'
+        WHEN release_date > CURRENT_DATE THEN 'coming_soon'
+        ELSE 'archived'
+    END
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX idx_movies_release_date ON movies(release_date);
+
+CREATE TABLE IF NOT EXISTS movie_interests(
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    notified_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, movie_id)
+);
+CREATE INDEX idx_movie_interests_pending ON movie_interests(movie_id) WHERE notified_at IS NULL;
+
+-- +goose Down
+DROP TABLE movie_interests;
+DROP INDEX idx_movies_release_date;
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION movie_status(movie_id BIGINT, release_date DATE) RETURNS VARCHAR
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN EXISTS (
+            SELECT 1 FROM showtimes s
+            WHERE s.movie_id = movie_status.movie_id
+              AND s.start_time > now()
+              AND s.deleted_at IS NULL
+        ) THEN 'now_showing'
+        WHEN release_date > CURRENT_DATE THEN 'coming_soon'
+        ELSE 'not_showing'
+    END
+$$;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Uploaded posters, backdrops and stills. The original upload is not kept: each asset is
+-- stored as a set of resized JPEG variants in the configured storage backend.
+CREATE TABLE IF NOT EXISTS media_assets(
+    id BIGSERIAL PRIMARY KEY,
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    kind VARCHAR NOT NULL,
+    original_filename VARCHAR NOT NULL,
+    original_content_type VARCHAR NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+CREATE INDEX idx_media_assets_movie_id ON media_assets(movie_id);
+ALTER TABLE media_assets ADD CONSTRAINT chk_media_asset_kind
+    CHECK (kind IN ('poster', 'backdrop', 'still'));
+
+CREATE TABLE IF NOT EXISTS media_variants(
+    asset_id BIGINT NOT NULL REFERENCES media_assets(id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    storage_key VARCHAR NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    PRIMARY KEY (asset_id, name)
+);
+
+-- +goose Down
+DROP TABLE media_variants;
+DROP TABLE media_assets;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Links a movie to its entry in the configured external catalogue provider (e.g. a TMDB ID),
+-- so its metadata can be refreshed later.
+ALTER TABLE movies ADD COLUMN external_id VARCHAR;
+CREATE UNIQUE INDEX idx_movies_external_id ON movies(external_id)
+    WHERE external_id IS NOT NULL AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_movies_external_id;
+ALTER TABLE movies DROP COLUMN external_id;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Reviews may only be written by customers with a confirmed reservation for a past
+-- showtime of the movie; that check happens in the application. Hidden reviews are
+-- excluded from public listings and from the movie's rating.
+CREATE TABLE IF NOT EXISTS reviews(
+    id BIGSERIAL PRIMARY KEY,
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL,
+    title VARCHAR,
+    body TEXT NOT NULL DEFAULT '',
+    hidden_at TIMESTAMPTZ,
+    hidden_reason VARCHAR,
+    flagged_at TIMESTAMPTZ,
+    flag_reason VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMPTZ DEFAULT (now()),
+    UNIQUE (movie_id, user_id)
+);
+ALTER TABLE reviews ADD CONSTRAINT chk_review_rating CHECK (rating BETWEEN 1 AND 5);
+CREATE INDEX idx_reviews_movie_id ON reviews(movie_id, created_at DESC) WHERE hidden_at IS NULL;
+CREATE INDEX idx_reviews_flagged ON reviews(flagged_at) WHERE flagged_at IS NOT NULL;
+
+-- Denormalized from visible reviews by SyncMovieRating, so listings can sort by rating.
+ALTER TABLE movies ADD COLUMN rating_average DOUBLE PRECISION;
+ALTER TABLE movies ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+CREATE INDEX idx_movies_rating ON movies(rating_average DESC NULLS LAST) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_movies_rating;
+ALTER TABLE movies DROP COLUMN rating_count;
+ALTER TABLE movies DROP COLUMN rating_average;
+DROP TABLE reviews;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Scores are recomputed in bulk by the recommendations job; a row exists only for users
+-- with booking or review history.
+CREATE TABLE IF NOT EXISTS user_recommendations(
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    movie_id BIGINT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    PRIMARY KEY (user_id, movie_id)
+);
+CREATE INDEX idx_user_recommendations_score ON user_recommendations(user_id, score DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recommendations;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Single-use tokens emailed to users, such as email verification links. Only a hash of
+-- the token is stored; the token itself exists only in the email.
+CREATE TABLE IF NOT EXISTS user_tokens(
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR NOT NULL,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens(user_id, purpose, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_tokens;
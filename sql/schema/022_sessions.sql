@@ -0,0 +1,15 @@
+-- +goose Up
+-- One row per login. Refresh tokens carry its ID and stop working once it is revoked, so a
+-- user can be signed out everywhere.
+CREATE TABLE IF NOT EXISTS sessions(
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    revoked_reason VARCHAR
+);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id) WHERE revoked_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS sessions;
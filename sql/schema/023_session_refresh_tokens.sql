@@ -0,0 +1,31 @@
+-- +goose Up
+-- Each refresh replaces current_token_hash, so a session is a family of refresh tokens;
+-- presenting a token the family has moved past revokes the session.
+ALTER TABLE sessions
+    ADD COLUMN current_token_hash VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN previous_token_hash VARCHAR,
+    ADD COLUMN rotated_at TIMESTAMPTZ,
+    ADD COLUMN device VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN ip_address VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN user_agent VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT (now());
+ALTER TABLE sessions ALTER COLUMN current_token_hash DROP DEFAULT;
+
+-- Sessions started before tokens were tracked keep an empty current_token_hash; their next
+-- refresh adopts the token presented, so nobody is signed out by this migration.
+UPDATE sessions SET last_seen_at = created_at;
+
+DROP INDEX IF EXISTS idx_sessions_user_id;
+CREATE INDEX idx_sessions_user_id ON sessions(user_id, last_seen_at DESC) WHERE revoked_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_sessions_user_id;
+CREATE INDEX idx_sessions_user_id ON sessions(user_id) WHERE revoked_at IS NULL;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS current_token_hash,
+    DROP COLUMN IF EXISTS previous_token_hash,
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS device,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS last_seen_at;
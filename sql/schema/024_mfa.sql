@@ -0,0 +1,27 @@
+-- +goose Up
+-- TOTP authenticators. The shared secret is encrypted, since it has to be read back to
+-- check codes. confirmed_at stays null until the user proves their app produces codes.
+CREATE TABLE IF NOT EXISTS user_totp(
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_ciphertext BYTEA NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    -- the last time step a code was accepted for, so a code cannot be replayed
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+-- Single-use codes for signing in without the authenticator. Only hashes are stored.
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes(
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    UNIQUE (user_id, code_hash)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Passkeys. Each one is also a user_identities row with provider 'webauthn', alongside the
+-- user's password and social logins. The credential column holds the public key, sign
+-- counter and flags as the WebAuthn library serialises them.
+CREATE TABLE IF NOT EXISTS webauthn_credentials(
+    id BYTEA PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL DEFAULT '',
+    credential JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    last_used_at TIMESTAMPTZ
+);
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+-- Registration and login ceremonies waiting for the authenticator's response. Shared
+-- between server instances, so a ceremony can finish on a different one than it began.
+CREATE TABLE IF NOT EXISTS webauthn_ceremonies(
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR NOT NULL,
+    session_data JSONB NOT NULL,
+    credential_name VARCHAR NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Credential IDs can be up to 1023 bytes, too long for provider_user_id once encoded.
+ALTER TABLE user_identities ALTER COLUMN provider_user_id TYPE VARCHAR(1400);
+
+-- +goose Down
+DELETE FROM user_identities WHERE provider = 'webauthn';
+ALTER TABLE user_identities ALTER COLUMN provider_user_id TYPE VARCHAR(256);
+DROP TABLE IF EXISTS webauthn_ceremonies;
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- A movie whose last poster asset is deleted has no poster rather than an empty URL.
+ALTER TABLE movies ALTER COLUMN poster_url DROP NOT NULL;
+UPDATE movies SET poster_url = NULL WHERE poster_url = '';
+
+-- +goose Down
+UPDATE movies SET poster_url = '' WHERE poster_url IS NULL;
+ALTER TABLE movies ALTER COLUMN poster_url SET NOT NULL;